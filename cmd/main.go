@@ -3,540 +3,2641 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log/slog"
+	"math/big"
 	"net/http"
 	"os"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/addressbook"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/config"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/eth"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/etherscan"
+	ctshttp "github.com/jrh3k5/cryptonabber-txn-sync/internal/http"
 	ctsio "github.com/jrh3k5/cryptonabber-txn-sync/internal/io"
 	ctsslog "github.com/jrh3k5/cryptonabber-txn-sync/internal/logging/slog"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/prompt"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/selftest"
 	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
 	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction/rpcsource"
 	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
 	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/transfer"
-	"github.com/manifoldco/promptui"
 )
 
 const (
 	rpcNodeURLBase  = "https://mainnet.base.org"
 	usdcAddressBase = "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+	chainIDBase     = etherscan.BaseChainID
 
-	ignoreListFilename = "transaction_hash.ignorelist"
+	ignoreListFilename        = "transaction_hash.ignorelist"
+	tokenDetailsCacheFilename = "token_details.cache"
+	transactionCacheFilename  = "ynab_transactions.cache"
+
+	// expectedCurrencyCode is the currency in which transfer amounts are recorded in YNAB.
+	expectedCurrencyCode = "USD"
+
+	// defaultSinceDays is the default number of days back to look for uncleared YNAB transactions.
+	defaultSinceDays = 7
+
+	diffFormatMarkdown = "markdown"
+	diffFormatJSON     = "json"
+
+	// the actions that --on-corrupt-ignore-list can take when the ignore list cannot be parsed
+	onCorruptAbort          = "abort"
+	onCorruptBackupAndReset = "backup-and-reset"
+
+	// exitCodeUnauthorized is the exit code of a run in which YNAB rejected the access token.
+	exitCodeUnauthorized = 1
+	// exitCodeSelfTestFailed is the exit code of a self-test in which any check failed.
+	exitCodeSelfTestFailed = 2
 )
 
+// errTransfersDumped ends a run in which the parsed transfers were dumped as requested.
+var errTransfersDumped = errors.New("transfers dumped")
+
+// errActionCompleted ends a run in which an action that stands on its own, such as writing
+// a configuration template or editing the ignore list, was completed as requested.
+var errActionCompleted = errors.New("requested action completed")
+
 func main() {
+	os.Exit(run())
+}
+
+// run runs the tool and returns the code with which to exit.
+// Exiting is left to the caller so that the functions deferred here run first.
+func run() int {
 	ctx := context.Background()
 
-	debugMode := isDebug()
-	if debugMode {
-		debugTextHandler := ctsslog.NewHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slog.LevelDebug,
-		})
-		slog.SetDefault(slog.New(debugTextHandler))
+	args, prompter, closeLogFile, err := setUpRun(ctx)
+	defer closeLogFile()
+
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) && !errors.Is(err, errActionCompleted) {
+			slog.ErrorContext(ctx, "Failed to set up run", "error", err)
+		}
 
-		slog.DebugContext(ctx, "Running in debug mode; more detailed logging will be provided")
+		return 0
 	}
 
-	dryRun := isDryRun()
-	if dryRun {
-		slog.InfoContext(ctx, "Running in dry-run mode; no changes will be made to YNAB")
+	if args.selfTest {
+		return runSelfTest(ctx)
 	}
 
-	accountName, err := getAccountName()
+	addressFormat, err := getAddressFormat(args)
 	if err != nil {
-		slog.ErrorContext(ctx, "Failed to get YNAB account name", "error", err)
+		slog.ErrorContext(ctx, "Failed to get address format", "error", err)
 
-		return
+		return 0
+	}
+
+	addressBook, err := readAddressBook(args)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to read address book", "error", err)
+
+		return 0
 	}
 
-	ignoreList, err := readIgnoreList(ctx)
+	ignoreList, err := readIgnoreList(ctx, args)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to read ignore list", "error", err)
 
-		return
+		return 0
 	}
 
 	// Schedule the ignore list to be written
 	defer func() {
-		if err := writeIgnoreList(ignoreList); err != nil {
+		if err := writeIgnoreList(ignoreList, args); err != nil {
 			slog.ErrorContext(ctx, "Failed to write ignore list", "error", err)
 		}
 	}()
 
-	walletAddress, tokenAddress, httpClient, tokenDetails, transfers, err := initRun(
+	summary := &report.RunSummary{}
+
+	wallets, tokenAddress, httpClient, tokenDetails, transfers, err := initRun(
 		ctx,
 		ignoreList,
+		summary,
+		args,
+		addressFormat,
 	)
-	if err != nil {
+	if errors.Is(err, errTransfersDumped) {
+		return 0
+	} else if err != nil {
 		slog.ErrorContext(ctx, "Initialization failed", "error", err)
 
-		return
+		return 0
 	}
 
-	slog.InfoContext(ctx, fmt.Sprintf("Parsed %d transfers", len(transfers)))
+	logSyncTarget(ctx, tokenAddress, wallets, addressFormat)
 
-	slog.InfoContext(
-		ctx,
-		fmt.Sprintf(
-			"Synchronizing transactions for contract '%s' for wallet '%s'",
-			tokenAddress,
-			walletAddress,
-		),
-	)
+	accountName, err := getAccountName(args)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get YNAB account name", "error", err)
 
-	ynabAccessToken, err := getAccessToken()
+		return 0
+	}
+
+	ynabAccessToken, err := getAccessToken(args)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to get YNAB access token", "error", err)
 
-		return
+		return 0
+	}
+
+	if args.diff {
+		if err := runDiff(
+			ctx,
+			httpClient,
+			accountName,
+			tokenDetails,
+			ynabAccessToken,
+			wallets,
+			transfers,
+			args,
+			prompter,
+		); err != nil {
+			return logRunFailure(ctx, "Reconciliation failed", err)
+		}
+
+		return 0
 	}
 
+	exitCode := 0
 	if err := runSync(
 		ctx,
 		httpClient,
 		accountName,
 		tokenDetails,
 		ynabAccessToken,
-		walletAddress,
+		wallets,
 		transfers,
-		dryRun,
+		args,
 		ignoreList,
+		summary,
+		prompter,
+		addressFormat,
+		addressBook,
 	); err != nil {
-		slog.ErrorContext(ctx, "Synchronization failed", "error", err)
-
-		return
+		exitCode = logRunFailure(ctx, "Synchronization failed", err)
 	}
+
+	logRunSummary(ctx, summary, args.compactOutput, args.dryRun)
+
+	writeReports(ctx, summary, args)
+
+	return exitCode
 }
 
-func initRun(
+// logSyncTarget logs the token contract and wallets whose transactions are being synchronized.
+func logSyncTarget(
 	ctx context.Context,
-	ignoreList *transaction.IgnoreList,
-) (
-	string,
-	string,
-	*http.Client,
-	*token.Details,
-	[]*transaction.Transfer,
-	error,
+	tokenAddress string,
+	wallets *transaction.Wallets,
+	addressFormat eth.AddressFormat,
 ) {
-	walletAddress, err := getAddress()
+	slog.InfoContext(
+		ctx,
+		fmt.Sprintf(
+			"Synchronizing transactions for contract '%s' for wallet '%s'",
+			addressFormat.Format(tokenAddress),
+			wallets.Format(addressFormat),
+		),
+	)
+}
+
+// setUpRun parses the command-line arguments, configures logging accordingly,
+// fills in any arguments not given on the command line from the configuration file,
+// and builds the prompter through which the user is asked questions.
+func setUpRun(ctx context.Context) (*arguments, prompt.Prompter, func(), error) {
+	args, err := parseArgs(os.Args[1:])
 	if err != nil {
-		return "", "", nil, nil, nil, fmt.Errorf("failed to get wallet address: %w", err)
+		return nil, nil, func() {}, err
 	}
 
-	tokenAddress := getTokenAddress()
-	slog.InfoContext(ctx, "Using token contract address: "+tokenAddress)
+	closeLogFile, err := configureLogging(ctx, args)
+	if err != nil {
+		return nil, nil, closeLogFile, err
+	}
 
-	rpcURL := getRPCURL()
+	if args.readOnly {
+		slog.InfoContext(
+			ctx,
+			"Running in read-only mode; no changes will be made to YNAB, "+
+				"the ignore list, the decision journal or the cache files",
+		)
+	} else if args.dryRun {
+		slog.InfoContext(ctx, "Running in dry-run mode; no changes will be made to YNAB")
+	}
 
-	httpClient := http.DefaultClient
+	if args.initConfigPath != "" {
+		if err := writeConfigTemplate(ctx, args); err != nil {
+			return nil, nil, closeLogFile, err
+		}
 
-	slog.InfoContext(ctx, fmt.Sprintf("Retrieving token details for contract '%s'", tokenAddress))
+		return nil, nil, closeLogFile, errActionCompleted
+	}
 
-	tokenDetailsService := token.NewRPCDetailsService(httpClient, rpcURL)
+	if args.unignoreHash != "" || args.listIgnored {
+		if err := manageIgnoreList(ctx, args); err != nil {
+			return nil, nil, closeLogFile, err
+		}
 
-	tokenDetails, err := tokenDetailsService.GetTokenDetails(ctx, tokenAddress)
+		return nil, nil, closeLogFile, errActionCompleted
+	}
+
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return nil, nil, closeLogFile, fmt.Errorf("failed to load configuration file: %w", err)
+	}
+
+	args.applyConfig(cfg)
+
+	args.prices, err = loadPrices(args)
 	if err != nil {
-		return "", "", nil, nil, nil, fmt.Errorf("failed to retrieve token details: %w", err)
+		return nil, nil, closeLogFile, err
 	}
 
-	transfers, err := getTransfers(ctx, tokenDetails)
+	args.accountType, err = transaction.ParseAccountType(args.accountTypeName)
 	if err != nil {
-		return "", "", nil, nil, nil, fmt.Errorf("failed to get transfers: %w", err)
+		return nil, nil, closeLogFile, fmt.Errorf("invalid --account-type value: %w", err)
 	}
 
-	transfers = filterIgnoredTransfers(ignoreList, transfers)
+	prompter, err := newPrompter(args)
+	if err != nil {
+		return nil, nil, closeLogFile, err
+	}
 
-	return walletAddress, tokenAddress, httpClient, tokenDetails, transfers, nil
+	return args, prompter, closeLogFile, nil
 }
 
-func runSync(
+// configureLogging directs log output to the file given by --log-file, if any, so that it is not
+// interleaved with prompts, and enables debug logging if requested.
+// The returned function closes the log file; it does nothing if there is none.
+func configureLogging(ctx context.Context, args *arguments) (func(), error) {
+	logOutput := io.Writer(os.Stdout)
+	closeLogFile := func() {}
+
+	if args.logFile != "" {
+		file, err := os.Create(args.logFile) //nolint:gosec
+		if err != nil {
+			return closeLogFile, fmt.Errorf("failed to create log file: %w", err)
+		}
+
+		logOutput = file
+		closeLogFile = func() { _ = file.Close() }
+	} else if !args.debug {
+		return closeLogFile, nil
+	}
+
+	level := slog.LevelInfo
+	if args.debug {
+		level = slog.LevelDebug
+	}
+
+	slog.SetDefault(slog.New(ctsslog.NewHandler(logOutput, &slog.HandlerOptions{Level: level})))
+
+	slog.DebugContext(ctx, "Running in debug mode; more detailed logging will be provided")
+
+	return closeLogFile, nil
+}
+
+func initRun(
 	ctx context.Context,
-	httpClient *http.Client,
-	accountName string,
-	tokenDetails *token.Details,
-	ynabAccessToken string,
-	walletAddress string,
-	transfers []*transaction.Transfer,
-	dryRun bool,
 	ignoreList *transaction.IgnoreList,
-) error {
-	budget, chosenAccountID, err := selectAccount(ctx, httpClient, ynabAccessToken, accountName)
+	summary *report.RunSummary,
+	args *arguments,
+	addressFormat eth.AddressFormat,
+) (
+	*transaction.Wallets,
+	string,
+	ctshttp.Doer,
+	*token.Details,
+	[]*transaction.Transfer,
+	error,
+) {
+	wallets, err := getWallets(args)
 	if err != nil {
-		return fmt.Errorf("failed to select an account: %w", err)
+		return nil, "", nil, nil, nil, fmt.Errorf("failed to get wallet addresses: %w", err)
 	}
 
-	unclearedTransactions, err := retrieveUnclearedTransactions(
-		ctx,
-		httpClient,
-		ynabAccessToken,
-		budget.ID,
-		chosenAccountID,
-		time.Now().Add(-7*24*time.Hour),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to retrieve uncleared transactions: %w", err)
+	tokenAddress := args.tokenAddress
+	slog.InfoContext(ctx, "Using token contract address: "+tokenAddress)
+
+	var httpClient ctshttp.Doer = http.DefaultClient
+	if args.readOnly {
+		httpClient = ctshttp.NewReadOnlyDoer(httpClient)
 	}
 
-	slog.DebugContext(
-		ctx,
-		fmt.Sprintf("Retrieved %d uncleared transactions", len(unclearedTransactions)),
-	)
+	var tokenDetails *token.Details
+	if err := summary.TimePhase(report.PhaseTokenDetails, func() error {
+		var err error
+		tokenDetails, err = getTokenDetails(ctx, tokenAddress, args)
 
-	for _, unclearedTransaction := range unclearedTransactions {
-		slog.DebugContext(
-			ctx,
-			fmt.Sprintf(
-				"  - %s %s %s with description '%s'",
-				unclearedTransaction.GetFormattedAmount(),
-				transaction.ResolveDirection(unclearedTransaction.IsOutbound()),
-				unclearedTransaction.Payee,
-				unclearedTransaction.Description,
-			),
-		)
+		return err
+	}); err != nil {
+		return nil, "", nil, nil, nil, fmt.Errorf("failed to retrieve token details: %w", err)
 	}
 
-	remainingTransfers, err := processUnclearedTransactions(
+	transfers, err := readTransfers(
 		ctx,
 		httpClient,
-		ynabAccessToken,
-		budget.ID,
-		walletAddress,
+		tokenAddress,
+		wallets,
 		tokenDetails,
-		transfers,
-		unclearedTransactions,
-		dryRun,
-		ignoreList,
+		summary,
+		args,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to process uncleared transactions: %w", err)
+		return nil, "", nil, nil, nil, err
 	}
 
-	err = transaction.ImportRemainingTransfers(
-		ctx,
-		httpClient,
-		ynabAccessToken,
-		budget.ID,
-		chosenAccountID,
-		remainingTransfers,
-		tokenDetails,
-		walletAddress,
-		ignoreList,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to import remaining transfers: %w", err)
+	if args.dedupeReport {
+		err := report.WriteDeduplicatedTransfers(summary.Deduplicated, tokenDetails.Name, os.Stdout)
+		if err != nil {
+			return nil, "", nil, nil, nil, err
+		}
 	}
 
-	return nil
-}
-
-func filterUncleared(transactions []*client.Transaction) []*client.Transaction {
-	var out []*client.Transaction
-	for _, txn := range transactions {
-		if !txn.Cleared {
-			out = append(out, txn)
+	if args.dumpTransfers {
+		err := report.WriteTransferDump(transfers, tokenDetails.Decimals, os.Stdout)
+		if err != nil {
+			return nil, "", nil, nil, nil, fmt.Errorf("failed to dump transfers: %w", err)
 		}
+
+		return nil, "", nil, nil, nil, errTransfersDumped
 	}
 
-	return out
-}
+	transfers, err = filterTransfers(ctx, transfers, wallets, ignoreList, summary, args)
+	if err != nil {
+		return nil, "", nil, nil, nil, err
+	}
 
-func chooseBudget(ctx context.Context, budgets []*client.Budget) (*client.Budget, error) {
-	switch len(budgets) {
-	case 0:
-		return nil, errors.New("no YNAB budgets found; at least one budget is required")
-	case 1:
-		return budgets[0], nil
-	default:
-		// If multiple budgets are available, prompt the user to select one.
-		var items []string
-		for _, b := range budgets {
-			items = append(items, fmt.Sprintf("%s (%s)", b.Name, b.ID))
-		}
+	slog.InfoContext(ctx, fmt.Sprintf("Parsed %d transfers", len(transfers)))
 
-		prompt := promptui.Select{
-			Label: "Select a YNAB budget",
-			Items: items,
+	if args.preview {
+		err := report.WriteTransferPreview(
+			transfers,
+			wallets,
+			tokenDetails,
+			addressFormat,
+			os.Stdout,
+		)
+		if err != nil {
+			return nil, "", nil, nil, nil, err
 		}
+	}
 
-		i, _, err := prompt.Run()
-		if err != nil {
-			// If the user canceled the prompt (Ctrl-C/Ctrl-D), exit with an error so the program stops.
-			if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
-				return nil, errors.New("budget selection canceled")
-			}
+	summary.TokenName = tokenDetails.Name
+	summary.ParsedTransferCount = len(transfers)
 
-			// Otherwise, if the prompt fails for a non-interactive reason, log a warning and fall back to the first budget.
-			slog.WarnContext(
-				ctx,
-				"Budget selection prompt failed; defaulting to first budget",
-				"error",
-				err,
-			)
+	// only YNAB requests are retried from here on
+	ynabHTTPClient := client.NewRetryingDoer(httpClient, client.WithMaxRetries(args.ynabMaxRetries))
 
-			return budgets[0], nil
-		}
+	return wallets, tokenAddress, ynabHTTPClient, tokenDetails, transfers, nil
+}
 
-		selected := budgets[i]
+// getTokenDetails looks up the details of the token with the given contract address.
+// Details given by --token-decimals and --token-name take precedence over those of the RPC node,
+// which is not contacted at all if both were given. Details are cached unless decimals were given.
+// If --rpc-url is empty, no RPC node is contacted, and the decimals not given by --token-decimals
+// are read from the CSV file.
+func getTokenDetails(
+	ctx context.Context,
+	tokenAddress string,
+	args *arguments,
+) (*token.Details, error) {
+	hasDecimals := args.setFlags["token-decimals"]
+	if hasDecimals && args.tokenName != "" {
 		slog.InfoContext(
 			ctx,
-			"Selected budget",
-			"budgetName",
-			selected.Name,
-			"budgetID",
-			selected.ID,
+			"Using the token details given by --token-decimals and --token-name",
 		)
 
-		return selected, nil
+		return &token.Details{Name: args.tokenName, Decimals: args.tokenDecimals}, nil
 	}
-}
 
-func chooseTransfer(
-	ctx context.Context,
-	tokenDetails *token.Details,
-	transfers []*transaction.Transfer,
-	walletAddress string,
-	promptText string,
-) (*transaction.Transfer, error) {
-	sortedTransfers := make([]*transaction.Transfer, len(transfers))
-	copy(sortedTransfers, transfers)
-	// Sort transfers by amount and then execution time for easier selection.
-	// Earlier transfers will appear first in the list.
-	sort.Slice(sortedTransfers, func(i, j int) bool {
-		byAmount := sortedTransfers[i].Amount.Cmp(sortedTransfers[j].Amount)
-		if byAmount != 0 {
-			return byAmount < 0
-		}
+	if args.rpcURL == "" {
+		return getOfflineTokenDetails(ctx, args)
+	}
 
-		return sortedTransfers[i].ExecutionTime.Before(sortedTransfers[j].ExecutionTime)
-	})
+	slog.InfoContext(ctx, fmt.Sprintf("Retrieving token details for contract '%s'", tokenAddress))
 
-	// If multiple budgets are available, prompt the user to select one.
-	items := make([]string, 0, len(sortedTransfers)+1)
-	items = append(items, "Skip match")
+	rpcOpts := []token.RPCDetailsOption{token.WithBlockObjectParam(args.rpcBlockObject)}
+	if hasDecimals {
+		rpcOpts = append(rpcOpts, token.WithDecimals(args.tokenDecimals))
+	}
 
-	for _, xfr := range sortedTransfers {
-		amountSign := ""
-		if strings.EqualFold(xfr.FromAddress, walletAddress) {
-			amountSign = "-"
+	// JSON-RPC calls are always POSTs, but eth_call cannot change any state,
+	// so the RPC node is exempt from read-only mode
+	var tokenDetailsService token.DetailsService = token.NewRPCDetailsService(
+		http.DefaultClient,
+		args.rpcURL,
+		rpcOpts...,
+	)
+
+	var tokenDetailsCache *token.DetailsCache
+	if !hasDecimals {
+		var err error
+		tokenDetailsCache, err = readTokenDetailsCache()
+		if err != nil {
+			return nil, err
 		}
 
-		items = append(
-			items,
-			fmt.Sprintf(
-				"%s%s %s on %s (%s)",
-				amountSign,
-				xfr.FormatAmount(tokenDetails.Decimals),
-				tokenDetails.Name,
-				xfr.ExecutionTime.Format(time.RFC3339),
-				xfr.TransactionHash,
-			),
+		tokenDetailsService = token.NewCachingDetailsService(
+			tokenDetailsService,
+			tokenDetailsCache,
+			args.chainID,
+			token.WithForcedRefresh(args.refreshTokenDetails),
 		)
 	}
 
-	prompt := promptui.Select{
-		Label: promptText,
-		Items: items,
+	tokenDetails, err := tokenDetailsService.GetTokenDetails(ctx, tokenAddress)
+	if errors.Is(err, token.ErrImplausibleDecimals) {
+		return nil, fmt.Errorf(
+			"%w; if the token's decimals are known, provide them with --token-decimals",
+			err,
+		)
+	} else if err != nil {
+		return nil, err
 	}
 
-	i, _, err := prompt.Run()
-	if err != nil {
-		// If the user canceled the prompt (Ctrl-C/Ctrl-D), exit with an error so the program stops.
-		if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
-			return nil, errors.New("transfer selection canceled")
-		}
-
-		return nil, fmt.Errorf("transfer selection prompt failed: %w", err)
+	if tokenDetails == nil {
+		return nil, fmt.Errorf(
+			"token contract '%s' returned no decimals; provide them with --token-decimals",
+			tokenAddress,
+		)
 	}
 
-	if i == 0 {
-		slog.DebugContext(ctx, "User opted to skip matching")
+	if tokenDetailsCache != nil && !args.readOnly {
+		if err := writeTokenDetailsCache(tokenDetailsCache); err != nil {
+			slog.WarnContext(ctx, "Failed to write token details cache", "error", err)
+		}
+	}
 
-		return nil, nil
+	if args.tokenName != "" {
+		tokenDetails.Name = args.tokenName
 	}
 
-	return sortedTransfers[i-1], nil
+	return tokenDetails, nil
 }
 
-// filterIgnoredTransfers removes any transfers from the input slice that are present in the ignore list.
-func filterIgnoredTransfers(
-	ignoreList *transaction.IgnoreList,
+// getOfflineTokenDetails determines the details of the token without an RPC node:
+// the decimals are those given by --token-decimals or, failing that, those given by the CSV file,
+// and the name is that given by --token-name or, failing that, the token symbol in the CSV file.
+func getOfflineTokenDetails(ctx context.Context, args *arguments) (*token.Details, error) {
+	if args.setFlags["token-decimals"] {
+		return &token.Details{Name: args.tokenName, Decimals: args.tokenDecimals}, nil
+	}
+
+	if args.etherscanAPIKey != "" || args.rpcTransfers {
+		return nil, errors.New(
+			"without --rpc-url, the token decimals must be provided with --token-decimals",
+		)
+	}
+
+	csvFile, err := getCSVFile(args)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(csvFile) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file to read the token decimals: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	hint, err := transaction.DecimalsHintFromEtherscanCSV(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the token decimals from the CSV file: %w", err)
+	}
+
+	if hint == nil {
+		return nil, errors.New(
+			"without --rpc-url, the token decimals must be provided with --token-decimals, " +
+				"as the CSV file has no token decimals column",
+		)
+	}
+
+	slog.InfoContext(
+		ctx,
+		fmt.Sprintf("Using %d token decimals, as given by the CSV %s", hint.Decimals, hint.Source),
+	)
+
+	tokenName := args.tokenName
+	if tokenName == "" {
+		tokenName = hint.Symbol
+	}
+
+	return &token.Details{Name: tokenName, Decimals: hint.Decimals}, nil
+}
+
+// filterTransfers drops the parsed transfers that are not to be processed: those up to the
+// transaction from which to resume, those that are too old, those of failed transactions unless
+// they are to be included, those with an excluded counterparty and, unless running a diff,
+// those already in the ignore list.
+func filterTransfers(
+	ctx context.Context,
 	transfers []*transaction.Transfer,
-) []*transaction.Transfer {
-	filteredTransfers := make([]*transaction.Transfer, 0, len(transfers))
-	for _, xfr := range transfers {
-		if ignoreList.IsHashIgnored(xfr.TransactionHash) {
-			continue
+	wallets *transaction.Wallets,
+	ignoreList *transaction.IgnoreList,
+	summary *report.RunSummary,
+	args *arguments,
+) ([]*transaction.Transfer, error) {
+	if sinceHash := args.sinceHash; sinceHash != "" {
+		parsedCount := len(transfers)
+
+		var err error
+
+		transfers, err = transaction.TransfersSinceHash(transfers, sinceHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resume from transaction hash: %w", err)
 		}
 
-		filteredTransfers = append(filteredTransfers, xfr)
+		slog.InfoContext(
+			ctx,
+			fmt.Sprintf(
+				"Resuming after transaction hash %s; skipping %d earlier transfers",
+				sinceHash,
+				parsedCount-len(transfers),
+			),
+		)
 	}
 
-	return filteredTransfers
+	if maxAgeDays := args.maxAgeDays; maxAgeDays > 0 {
+		recentTransfers := transaction.TransfersExecutedSince(
+			transfers,
+			time.Now().AddDate(0, 0, -maxAgeDays),
+		)
+		if oldCount := len(transfers) - len(recentTransfers); oldCount > 0 {
+			slog.InfoContext(
+				ctx,
+				fmt.Sprintf(
+					"Skipping %d transfers executed more than %d days ago",
+					oldCount,
+					maxAgeDays,
+				),
+			)
+		}
+
+		transfers = recentTransfers
+	}
+
+	if !args.includeFailed {
+		succeededTransfers := transaction.ExcludeFailedTransfers(transfers)
+		if failedCount := len(transfers) - len(succeededTransfers); failedCount > 0 {
+			slog.InfoContext(
+				ctx,
+				fmt.Sprintf("Skipping %d transfers from failed transactions", failedCount),
+			)
+		}
+
+		transfers = succeededTransfers
+	}
+
+	includedTransfers := transaction.ExcludeCounterparties(
+		transfers,
+		wallets,
+		args.excludeAddresses,
+	)
+	if excludedCount := len(transfers) - len(includedTransfers); excludedCount > 0 {
+		slog.InfoContext(
+			ctx,
+			fmt.Sprintf("Skipping %d transfers with an excluded counterparty", excludedCount),
+		)
+
+		summary.ExcludedCount = excludedCount
+	}
+
+	transfers = includedTransfers
+
+	// a diff compares every transfer, including those that were already processed or ignored
+	if !args.diff {
+		transfers = filterIgnoredTransfers(ignoreList, transfers)
+	}
+
+	return transfers, nil
 }
 
-func findAccountID(accounts []*client.Account, name string) (string, error) {
-	for _, acct := range accounts {
-		if acct.Name == name {
-			return acct.ID, nil
+// readTransfers reads the transfers to be synchronized, either from the Etherscan API if an API key was provided
+// or from the provided CSV file.
+func readTransfers(
+	ctx context.Context,
+	httpClient ctshttp.Doer,
+	tokenAddress string,
+	wallets *transaction.Wallets,
+	tokenDetails *token.Details,
+	summary *report.RunSummary,
+	args *arguments,
+) ([]*transaction.Transfer, error) {
+	var transfers []*transaction.Transfer
+	if args.etherscanAPIKey != "" {
+		slog.InfoContext(
+			ctx,
+			fmt.Sprintf(
+				"Retrieving transfers from Etherscan for chain ID %d",
+				args.etherscanChainID,
+			),
+		)
+
+		etherscanClient := etherscan.NewHTTPClient(
+			httpClient,
+			args.etherscanAPIKey,
+			args.etherscanChainID,
+		)
+		if err := summary.TimePhase(report.PhaseParse, func() error {
+			// a transfer is only listed for the wallets it involves, so each is fetched separately
+			transfersByWallet := make([][]*transaction.Transfer, 0, wallets.Len())
+			for _, walletAddress := range wallets.Addresses() {
+				walletTransfers, err := etherscan.GetTransfers(
+					ctx,
+					etherscanClient,
+					tokenAddress,
+					walletAddress,
+					etherscan.DefaultPageSize,
+				)
+				if err != nil {
+					return fmt.Errorf(
+						"failed to get transfers of wallet '%s': %w",
+						walletAddress,
+						err,
+					)
+				}
+
+				transfersByWallet = append(transfersByWallet, walletTransfers)
+			}
+
+			var duplicates []*transaction.Transfer
+			transfers, duplicates = transaction.MergeTransfers(transfersByWallet...)
+			summary.AddDeduplicated(duplicates, tokenDetails.Decimals)
+
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to get transfers from Etherscan: %w", err)
+		}
+	} else if args.rpcTransfers {
+		if err := summary.TimePhase(report.PhaseParse, func() error {
+			var err error
+			transfers, err = getRPCTransfers(
+				ctx,
+				tokenAddress,
+				wallets,
+				tokenDetails,
+				summary,
+				args,
+			)
+
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("failed to get transfers from the RPC node: %w", err)
+		}
+	} else {
+		csvFile, err := getCSVFile(args)
+		if err != nil {
+			return nil, err
+		}
+
+		verifyCSVDecimals(ctx, csvFile, tokenDetails)
+
+		if err := summary.TimePhase(report.PhaseParse, func() error {
+			var err error
+			transfers, err = getTransfers(ctx, csvFile, args, tokenDetails)
+
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("failed to get transfers: %w", err)
+		}
+	}
+
+	// the transfers are numbered before any are filtered out, so that their import IDs
+	// do not depend on which of them are left
+	transaction.NumberTransfers(transfers)
+
+	return transfers, nil
+}
+
+// getRPCTransfers reads the transfers of each wallet from the Transfer event logs of the token,
+// as returned by the RPC node for the blocks given by --from-block and --to-block.
+func getRPCTransfers(
+	ctx context.Context,
+	tokenAddress string,
+	wallets *transaction.Wallets,
+	tokenDetails *token.Details,
+	summary *report.RunSummary,
+	args *arguments,
+) ([]*transaction.Transfer, error) {
+	if args.rpcURL == "" {
+		return nil, errors.New("--rpc-transfers requires --rpc-url")
+	}
+
+	toBlock := args.toBlock
+	transfersByWallet := make([][]*transaction.Transfer, 0, wallets.Len())
+	for _, walletAddress := range wallets.Addresses() {
+		// eth_getLogs cannot change any state, so, like eth_call, it is exempt from read-only mode
+		source, err := rpcsource.NewRPCTransferSource(
+			http.DefaultClient,
+			args.rpcURL,
+			tokenAddress,
+			walletAddress,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// every wallet is searched up to the same block
+		if toBlock == 0 {
+			toBlock, err = source.LatestBlockNumber(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		slog.InfoContext(
+			ctx,
+			fmt.Sprintf(
+				"Retrieving transfers of wallet '%s' in blocks %d to %d from the RPC node",
+				walletAddress,
+				args.fromBlock,
+				toBlock,
+			),
+		)
+
+		walletTransfers, err := source.GetTransfers(ctx, args.fromBlock, toBlock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transfers of wallet '%s': %w", walletAddress, err)
+		}
+
+		transfersByWallet = append(transfersByWallet, walletTransfers)
+	}
+
+	transfers, duplicates := transaction.MergeTransfers(transfersByWallet...)
+	summary.AddDeduplicated(duplicates, tokenDetails.Decimals)
+
+	return transfers, nil
+}
+
+func runSync(
+	ctx context.Context,
+	httpClient ctshttp.Doer,
+	accountName string,
+	tokenDetails *token.Details,
+	ynabAccessToken string,
+	wallets *transaction.Wallets,
+	transfers []*transaction.Transfer,
+	args *arguments,
+	ignoreList *transaction.IgnoreList,
+	summary *report.RunSummary,
+	prompter prompt.Prompter,
+	addressFormat eth.AddressFormat,
+	addressBook *addressbook.AddressBook,
+) error {
+	minimumAmount, err := getMinimumAmount(args, tokenDetails)
+	if err != nil {
+		return err
+	}
+
+	budget, chosenAccountID, err := selectAccount(
+		ctx,
+		httpClient,
+		ynabAccessToken,
+		accountName,
+		args,
+		prompter,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to select an account: %w", err)
+	}
+
+	unclearedTransactions, err := retrieveUnclearedTransactions(
+		ctx,
+		httpClient,
+		ynabAccessToken,
+		budget.ID,
+		chosenAccountID,
+		getLookbackCutoff(args),
+		args.readOnly,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve uncleared transactions: %w", err)
+	}
+
+	logUnclearedTransactions(ctx, unclearedTransactions, args.accountType)
+
+	var remainingTransfers []*transaction.Transfer
+	if err := summary.TimePhase(report.PhaseMatch, func() error {
+		var err error
+		remainingTransfers, err = matchUnclearedTransactions(
+			ctx,
+			httpClient,
+			ynabAccessToken,
+			budget.ID,
+			wallets,
+			tokenDetails,
+			transfers,
+			unclearedTransactions,
+			args,
+			ignoreList,
+			summary,
+			prompter,
+			addressBook,
+		)
+
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to process uncleared transactions: %w", err)
+	}
+
+	categories, err := getCategories(ctx, httpClient, ynabAccessToken, budget.ID, args)
+	if err != nil {
+		return err
+	}
+
+	journal, err := readDecisionJournal(args)
+	if err != nil {
+		return err
+	}
+
+	if err := summary.TimePhase(report.PhaseImport, func() error {
+		importResult, err := transaction.ImportRemainingTransfers(
+			ctx,
+			httpClient,
+			ynabAccessToken,
+			budget.ID,
+			chosenAccountID,
+			remainingTransfers,
+			tokenDetails,
+			wallets,
+			ignoreList,
+			transaction.ImportOptions{
+				Prompter:             prompter,
+				SkipOnCancel:         args.skipOnCancel,
+				AddressFormat:        addressFormat,
+				Categories:           categories,
+				AddressBook:          addressBook,
+				DryRun:               args.dryRun,
+				FailFast:             args.failFast,
+				MinimumAmount:        minimumAmount,
+				NoMinimumInbound:     args.noMinimumInbound,
+				IncludeSelfTransfers: args.includeSelf,
+				DefaultInboundPayee:  args.inboundPayee,
+				DefaultOutboundPayee: args.outboundPayee,
+				SelectTransfers:      args.selectTransfers,
+				AutoCreate:           args.autoConfirm,
+				AccountType:          args.accountType,
+				BatchCreate:          args.batchCreate,
+				RoundedDecimalDigits: getRoundedDecimalDigits(args, budget),
+				Journal:              journal,
+				SaveJournal:          getDecisionJournalSaver(args),
+			},
+		)
+		recordImportResult(summary, importResult, tokenDetails, budget.ID, args)
+		finishDecisionJournal(ctx, journal, args, err == nil)
+
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to import remaining transfers: %w", err)
+	}
+
+	return nil
+}
+
+// getCategories retrieves the categories of the given budget to be offered when importing
+// transfers, if --prompt-category was given; otherwise, it returns none.
+func getCategories(
+	ctx context.Context,
+	httpClient ctshttp.Doer,
+	ynabAccessToken string,
+	budgetID string,
+	args *arguments,
+) ([]*client.Category, error) {
+	if !args.promptCategory {
+		return nil, nil
+	}
+
+	categories, err := client.GetCategories(ctx, httpClient, ynabAccessToken, budgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve YNAB categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// recordImportResult adds the given result of importing transfers to the summary of the run
+// and, if --print-links was given, prints a link to each YNAB transaction that was created.
+func recordImportResult(
+	summary *report.RunSummary,
+	importResult *transaction.ImportResult,
+	tokenDetails *token.Details,
+	budgetID string,
+	args *arguments,
+) {
+	summary.AddImportResult(importResult, tokenDetails.Decimals)
+
+	if !args.printLinks || importResult == nil {
+		return
+	}
+
+	for _, imported := range importResult.Created {
+		// transactions only logged in a dry run have no ID to link to
+		if imported.Transaction.ID != "" {
+			printTransactionLink(budgetID, imported.Transaction.ID, "Created")
 		}
 	}
+}
+
+// printTransactionLink prints a link to the YNAB transaction with the given ID,
+// prefixed by what was done to it.
+func printTransactionLink(budgetID string, transactionID string, action string) {
+	_, _ = fmt.Fprintf(
+		os.Stdout,
+		"%s: %s\n",
+		action,
+		client.TransactionURL(budgetID, transactionID),
+	)
+}
+
+// logUnclearedTransactions logs, for debugging, the given uncleared YNAB transactions
+// of an account of the given type.
+func logUnclearedTransactions(
+	ctx context.Context,
+	unclearedTransactions []*client.Transaction,
+	accountType transaction.AccountType,
+) {
+	slog.DebugContext(
+		ctx,
+		fmt.Sprintf("Retrieved %d uncleared transactions", len(unclearedTransactions)),
+	)
+
+	for _, unclearedTransaction := range unclearedTransactions {
+		slog.DebugContext(
+			ctx,
+			fmt.Sprintf(
+				"  - %s %s %s with description '%s'",
+				unclearedTransaction.GetFormattedAmount(),
+				directionOf(unclearedTransaction, accountType),
+				unclearedTransaction.Payee,
+				unclearedTransaction.Description,
+			),
+		)
+	}
+}
+
+// readDecisionJournal reads the decision journal given by --decision-journal, if any.
+// It returns nil if no journal was given, and an empty journal if the file does not exist yet.
+func readDecisionJournal(args *arguments) (*transaction.DecisionJournal, error) {
+	if args.decisionJournalPath == "" {
+		return nil, nil
+	}
+
+	journalExists, err := ctsio.FileExists(args.decisionJournalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for decision journal file: %w", err)
+	}
+
+	if !journalExists {
+		return transaction.NewDecisionJournal(), nil
+	}
 
-	return "", fmt.Errorf("account '%s' not found", name)
+	readHandle, err := os.Open(args.decisionJournalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open decision journal file: %w", err)
+	}
+	defer func() { _ = readHandle.Close() }()
+
+	journal, err := transaction.DecisionJournalFromYAML(readHandle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse decision journal file: %w", err)
+	}
+
+	return journal, nil
 }
 
-func getAccessToken() (string, error) {
-	var accessToken string
-	for _, arg := range os.Args[1:] {
-		parsedToken, hasPrefix := strings.CutPrefix(arg, "--ynab-access-token=")
-		if hasPrefix {
-			accessToken = parsedToken
+// finishDecisionJournal saves the given decision journal so that an interrupted import can be
+// resumed or, once the import has completed, removes the journal file, as its decisions
+// (e.g., to skip a transfer for now) are not meant to outlive the import.
+// Nothing is written in read-only mode.
+func finishDecisionJournal(
+	ctx context.Context,
+	journal *transaction.DecisionJournal,
+	args *arguments,
+	completed bool,
+) {
+	if journal == nil || args.readOnly {
+		return
+	}
+
+	if completed {
+		err := os.Remove(args.decisionJournalPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.WarnContext(ctx, "Failed to remove decision journal file", "error", err)
+		}
+
+		return
+	}
+
+	if err := writeDecisionJournal(journal, args); err != nil {
+		slog.ErrorContext(ctx, "Failed to save decision journal", "error", err)
+
+		return
+	}
+
+	slog.InfoContext(
+		ctx,
+		fmt.Sprintf(
+			"Saved %d import decisions to %s; run again with it to resume the import",
+			journal.Len(),
+			args.decisionJournalPath,
+		),
+	)
+}
+
+// getDecisionJournalSaver returns the function with which the decision journal is saved as each
+// decision is recorded, or nil if there is no journal to save.
+func getDecisionJournalSaver(args *arguments) func(*transaction.DecisionJournal) error {
+	if args.decisionJournalPath == "" || args.readOnly {
+		return nil
+	}
+
+	return func(journal *transaction.DecisionJournal) error {
+		return writeDecisionJournal(journal, args)
+	}
+}
+
+// writeDecisionJournal writes the given decision journal to the file given by --decision-journal,
+// replacing the file only once the journal has been written in full.
+func writeDecisionJournal(journal *transaction.DecisionJournal, args *arguments) error {
+	//nolint:mnd // no need to keep this at 600 or less
+	err := ctsio.WriteFileAtomically(args.decisionJournalPath, 0o644, func(writer io.Writer) error {
+		return transaction.DecisionJournalToYAML(journal, writer)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write decision journal file: %w", err)
+	}
+
+	return nil
+}
+
+// runDiff writes a reconciliation of the given transfers against the YNAB transactions
+// of the chosen account to standard output, without making any changes to YNAB.
+func runDiff(
+	ctx context.Context,
+	httpClient ctshttp.Doer,
+	accountName string,
+	tokenDetails *token.Details,
+	ynabAccessToken string,
+	wallets *transaction.Wallets,
+	transfers []*transaction.Transfer,
+	args *arguments,
+	prompter prompt.Prompter,
+) error {
+	budget, chosenAccountID, err := selectAccount(
+		ctx,
+		httpClient,
+		ynabAccessToken,
+		accountName,
+		args,
+		prompter,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to select an account: %w", err)
+	}
+
+	// look back far enough to cover every transfer, allowing for the day of leeway in matching
+	since := getLookbackCutoff(args)
+	for _, xfr := range transfers {
+		if earliest := xfr.ExecutionTime.Add(-24 * time.Hour); earliest.Before(since) {
+			since = earliest
+		}
+	}
+
+	ynabTransactions, err := client.GetTransactions(
+		ctx,
+		httpClient,
+		ynabAccessToken,
+		budget.ID,
+		chosenAccountID,
+		since,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+
+	reconciliation := report.NewReconciliation(
+		transfer.Reconcile(
+			ynabTransactions,
+			wallets,
+			tokenDetails,
+			transfers,
+			getMatchOptions(args)...,
+		),
+		tokenDetails.Name,
+		tokenDetails.Decimals,
+	)
+
+	if args.diffFormat == diffFormatJSON {
+		return report.WriteReconciliationJSON(reconciliation, os.Stdout)
+	}
+
+	return report.WriteReconciliationMarkdown(reconciliation, os.Stdout)
+}
+
+// matchUnclearedTransactions matches the given uncleared transactions either to individual
+// transfers or, if requested, to daily totals of transfers.
+// It returns any remaining unconsumed transfers.
+func matchUnclearedTransactions(
+	ctx context.Context,
+	httpClient ctshttp.Doer,
+	accessToken string,
+	budgetID string,
+	wallets *transaction.Wallets,
+	tokenDetails *token.Details,
+	transfers []*transaction.Transfer,
+	unclearedTransactions []*client.Transaction,
+	args *arguments,
+	ignoreList *transaction.IgnoreList,
+	summary *report.RunSummary,
+	prompter prompt.Prompter,
+	addressBook *addressbook.AddressBook,
+) ([]*transaction.Transfer, error) {
+	var remainingTransfers []*transaction.Transfer
+	if args.dailyTotals {
+		remainingTransfers = processUnclearedTransactionsByDay(
+			ctx,
+			httpClient,
+			accessToken,
+			budgetID,
+			wallets,
+			tokenDetails,
+			transfers,
+			unclearedTransactions,
+			args,
+			ignoreList,
+			summary,
+			prompter,
+		)
+	} else {
+		var err error
+
+		remainingTransfers, err = processUnclearedTransactions(
+			ctx,
+			httpClient,
+			accessToken,
+			budgetID,
+			wallets,
+			tokenDetails,
+			transfers,
+			unclearedTransactions,
+			args,
+			ignoreList,
+			summary,
+			prompter,
+			addressBook,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// many unmatched transactions suggest that the transfers are out of date, in which case
+	// importing them would only duplicate what is yet to be matched
+	if err := summary.CheckUnmatched(args.maxUnmatched); err != nil {
+		return nil, fmt.Errorf("%w; refresh the transfer export and run again", err)
+	}
+
+	return remainingTransfers, nil
+}
+
+func filterUncleared(transactions []*client.Transaction) []*client.Transaction {
+	var out []*client.Transaction
+	for _, txn := range transactions {
+		if !txn.Cleared {
+			out = append(out, txn)
+		}
+	}
+
+	return out
+}
+
+func chooseBudget(
+	ctx context.Context,
+	prompter prompt.Prompter,
+	budgets []*client.Budget,
+	budgetName string,
+) (*client.Budget, error) {
+	if budgetName != "" {
+		return findBudget(ctx, prompter, budgets, budgetName)
+	}
+
+	switch len(budgets) {
+	case 0:
+		return nil, errors.New("no YNAB budgets found; at least one budget is required")
+	case 1:
+		return budgets[0], nil
+	default:
+		// If multiple budgets are available, prompt the user to select one.
+		var items []string
+		for _, b := range budgets {
+			items = append(items, fmt.Sprintf("%s (%s)", b.Name, b.ID))
+		}
+
+		i, err := prompter.Select("Select a YNAB budget", items)
+		if err != nil {
+			// If the user canceled the prompt (Ctrl-C/Ctrl-D), exit with an error so the program stops.
+			if errors.Is(err, prompt.ErrInterrupt) || errors.Is(err, prompt.ErrEOF) {
+				return nil, errors.New("budget selection canceled")
+			}
+
+			if errors.Is(err, prompt.ErrTimeout) {
+				slog.InfoContext(
+					ctx,
+					"Budget selection prompt timed out; defaulting to first budget",
+					"budgetName",
+					budgets[0].Name,
+				)
+
+				return budgets[0], nil
+			}
+
+			// Otherwise, if the prompt fails for a non-interactive reason, log a warning and fall back to the first budget.
+			slog.WarnContext(
+				ctx,
+				"Budget selection prompt failed; defaulting to first budget",
+				"error",
+				err,
+			)
+
+			return budgets[0], nil
+		}
+
+		selected := budgets[i]
+		slog.InfoContext(
+			ctx,
+			"Selected budget",
+			"budgetName",
+			selected.Name,
+			"budgetID",
+			selected.ID,
+		)
+
+		return selected, nil
+	}
+}
+
+// findBudget returns the one of the given budgets with the given name, matching it loosely if
+// no budget has exactly that name. If several budgets match it, the user is asked which is meant.
+func findBudget(
+	ctx context.Context,
+	prompter prompt.Prompter,
+	budgets []*client.Budget,
+	name string,
+) (*client.Budget, error) {
+	matches := client.FindBudgetsByName(budgets, name)
+	switch len(matches) {
+	case 0:
+		budgetNames := make([]string, 0, len(budgets))
+		for _, b := range budgets {
+			budgetNames = append(budgetNames, b.Name)
+		}
+
+		return nil, fmt.Errorf(
+			"budget '%s' not found among available choices: %s",
+			name,
+			strings.Join(budgetNames, ", "),
+		)
+	case 1:
+		return matches[0], nil
+	}
+
+	items := make([]string, 0, len(matches))
+	for _, b := range matches {
+		items = append(items, fmt.Sprintf("%s (%s)", b.Name, b.ID))
+	}
+
+	i, err := chooseAmbiguousName(ctx, prompter, "budget", name, items)
+	if err != nil {
+		return nil, err
+	}
+
+	return matches[i], nil
+}
+
+func chooseTransfer(
+	ctx context.Context,
+	prompter prompt.Prompter,
+	tokenDetails *token.Details,
+	transfers []*transaction.Transfer,
+	wallets *transaction.Wallets,
+	promptText string,
+	hashPrefixMatch bool,
+) (*transaction.Transfer, error) {
+	sortedTransfers := make([]*transaction.Transfer, len(transfers))
+	copy(sortedTransfers, transfers)
+	// Sort transfers by amount and then execution time for easier selection.
+	// Earlier transfers will appear first in the list.
+	sort.Slice(sortedTransfers, func(i, j int) bool {
+		byAmount := sortedTransfers[i].Amount.Cmp(sortedTransfers[j].Amount)
+		if byAmount != 0 {
+			return byAmount < 0
+		}
+
+		return sortedTransfers[i].ExecutionTime.Before(sortedTransfers[j].ExecutionTime)
+	})
+
+	// If multiple budgets are available, prompt the user to select one.
+	items := make([]string, 0, len(sortedTransfers)+2) //nolint:mnd
+	items = append(items, "Skip match")
+
+	if hashPrefixMatch {
+		items = append(items, "Enter a transaction hash prefix")
+	}
+
+	firstTransferIndex := len(items)
+
+	for _, xfr := range sortedTransfers {
+		amountSign := ""
+		if wallets.Contains(xfr.FromAddress) {
+			amountSign = "-"
+		}
+
+		items = append(
+			items,
+			fmt.Sprintf(
+				"%s%s %s on %s (%s)%s",
+				amountSign,
+				xfr.FormatAmount(tokenDetails.Decimals),
+				tokenDetails.Name,
+				xfr.ExecutionTime.Format(time.RFC3339),
+				xfr.TransactionHash,
+				describeWallet(wallets, xfr),
+			),
+		)
+	}
+
+	for {
+		i, err := prompter.Select(promptText, items)
+		if err != nil {
+			return nil, resolveTransferSelectionError(ctx, err)
+		}
+
+		if i == 0 {
+			slog.DebugContext(ctx, "User opted to skip matching")
+
+			return nil, nil
+		}
+
+		if i >= firstTransferIndex {
+			return sortedTransfers[i-firstTransferIndex], nil
+		}
+
+		hashPrefix, err := prompter.Input("Transaction hash prefix", "")
+		if err != nil {
+			return nil, resolveTransferSelectionError(ctx, err)
+		}
+
+		matchingTransfer, err := transaction.FindTransferByHashPrefix(sortedTransfers, hashPrefix)
+		if err != nil {
+			slog.WarnContext(
+				ctx,
+				fmt.Sprintf("Unable to find a transfer by hash prefix; please try again: %v", err),
+			)
+
+			continue
+		}
+
+		return matchingTransfer, nil
+	}
+}
+
+// resolveTransferSelectionError converts an error from a transfer selection prompt into the error to be returned.
+// A timed-out prompt skips the match and so resolves to no error.
+func resolveTransferSelectionError(ctx context.Context, err error) error {
+	// If the user canceled the prompt (Ctrl-C/Ctrl-D), exit with an error so the program stops.
+	if errors.Is(err, prompt.ErrInterrupt) || errors.Is(err, prompt.ErrEOF) {
+		return errors.New("transfer selection canceled")
+	}
+
+	if errors.Is(err, prompt.ErrTimeout) {
+		slog.InfoContext(ctx, "Transfer selection prompt timed out; skipping match")
+
+		return nil
+	}
+
+	return fmt.Errorf("transfer selection prompt failed: %w", err)
+}
+
+// filterIgnoredTransfers removes any transfers from the input slice that are present in the ignore list.
+func filterIgnoredTransfers(
+	ignoreList *transaction.IgnoreList,
+	transfers []*transaction.Transfer,
+) []*transaction.Transfer {
+	filteredTransfers := make([]*transaction.Transfer, 0, len(transfers))
+	for _, xfr := range transfers {
+		if ignoreList.IsHashIgnored(xfr.TransactionHash) {
+			continue
+		}
+
+		filteredTransfers = append(filteredTransfers, xfr)
+	}
+
+	return filteredTransfers
+}
+
+// findAccountID resolves the given name to the ID of one of the given accounts, matching it
+// loosely if no account has exactly that name. If several accounts match it, the user is asked
+// which is meant. An empty ID is returned if no account matches it.
+func findAccountID(
+	ctx context.Context,
+	prompter prompt.Prompter,
+	accounts []*client.Account,
+	name string,
+) (string, error) {
+	matches := client.FindAccountsByName(accounts, name)
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0].ID, nil
+	}
+
+	items := make([]string, 0, len(matches))
+	for _, acct := range matches {
+		items = append(items, fmt.Sprintf("%s (%s)", acct.Name, acct.ID))
+	}
+
+	i, err := chooseAmbiguousName(ctx, prompter, "account", name, items)
+	if err != nil {
+		return "", err
+	}
+
+	return matches[i].ID, nil
+}
+
+// chooseAmbiguousName asks the user which of the given YNAB budgets or accounts, described by
+// the given items, is meant by the given name, returning the index of the chosen item.
+// As there is no safe default, an unanswered prompt, e.g., in a non-interactive run, fails.
+func chooseAmbiguousName(
+	ctx context.Context,
+	prompter prompt.Prompter,
+	kind string,
+	name string,
+	items []string,
+) (int, error) {
+	i, err := prompter.Select(
+		fmt.Sprintf("Several YNAB %ss match the name '%s'; select one", kind, name),
+		items,
+	)
+	if err != nil {
+		if errors.Is(err, prompt.ErrInterrupt) || errors.Is(err, prompt.ErrEOF) {
+			return 0, fmt.Errorf("%s selection canceled", kind)
+		}
+
+		slog.WarnContext(ctx, fmt.Sprintf("YNAB %s selection prompt failed", kind), "error", err)
+
+		return 0, fmt.Errorf(
+			"%s name '%s' is ambiguous, matching: %s",
+			kind,
+			name,
+			strings.Join(items, ", "),
+		)
+	}
+
+	return i, nil
+}
+
+// arguments holds the values of the command-line arguments.
+type arguments struct {
+	ynabAccessToken     string
+	ynabAccountName     string
+	ynabBudgetName      string
+	walletAddress       string
+	csvFile             string
+	rpcURL              string
+	tokenAddress        string
+	chainID             int64
+	configPath          string
+	dryRun              bool
+	debug               bool
+	logFile             string
+	csvDateLayout       string
+	csvColumns          string
+	skipOnCancel        bool
+	reportMarkdownPath  string
+	appendMarkdown      bool
+	reportPath          string
+	unmatchedOutPath    string
+	promptTimeout       time.Duration
+	memoIncludeLogIndex bool
+	dailyTotals         bool
+	includeFailed       bool
+	addressFormat       string
+	confirmCurrency     string
+	promptCategory      bool
+	hashPrefixMatch     bool
+	failFast            bool
+	sinceHash           string
+	etherscanAPIKey     string
+	etherscanChainID    int64
+	rpcTransfers        bool
+	fromBlock           uint64
+	toBlock             uint64
+	minimumAmount       string
+	noMinimumInbound    bool
+	includeSelf         bool
+	inboundPayee        string
+	outboundPayee       string
+	addressBookPath     string
+	requirePayeeMatch   bool
+	refreshTokenDetails bool
+	groupIgnoredReason  bool
+	diff                bool
+	readOnly            bool
+	amountTolerance     int64
+	matchRefunds        bool
+	matchTimeTolerance  time.Duration
+	diffFormat          string
+	onCorruptIgnore     string
+	ignoreListPath      string
+	preview             bool
+	dedupeReport        bool
+	selectTransfers     bool
+	compactOutput       bool
+	nonInteractive      bool
+	autoConfirm         bool
+	maxAgeDays          int
+	showRoundedAmounts  bool
+	ynabMaxRetries      int
+	dumpTransfers       bool
+	selfTest            bool
+	sinceDays           int
+	tokenPrice          string
+	tokenPricesFile     string
+	decisionJournalPath string
+	batchCreate         bool
+	rpcBlockObject      bool
+	confirmEachClear    bool
+	tokenDecimals       int
+	tokenName           string
+	printLinks          bool
+	initConfigPath      string
+	force               bool
+	unignoreHash        string
+	listIgnored         bool
+	maxUnmatched        int
+	ignoreTTLDays       int
+	excludeAddresses    addressList
+	accountTypeName     string
+
+	// prices holds the prices given by --token-price or --token-prices-file, if any.
+	prices transfer.PriceSource
+	// accountType is the type of account given by --account-type.
+	accountType transaction.AccountType
+
+	// setFlags holds the names of the flags that were given on the command line.
+	setFlags map[string]bool
+}
+
+// addressList collects the values of a flag that may be given more than once.
+type addressList []string
+
+func (l *addressList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *addressList) Set(value string) error {
+	address := strings.TrimSpace(value)
+	if address == "" {
+		return errors.New("no address given")
+	}
+
+	*l = append(*l, address)
+
+	return nil
+}
+
+// parseArgs parses the given command-line arguments.
+// Each flag may be given as either --flag=value or --flag value;
+// --help lists every flag with its default.
+func parseArgs(args []string) (*arguments, error) {
+	parsed := &arguments{
+		setFlags: make(map[string]bool),
+	}
+
+	flagSet := flag.NewFlagSet("cryptonabber-txn-sync", flag.ContinueOnError)
+	defineInputFlags(flagSet, parsed)
+	defineTokenFlags(flagSet, parsed)
+	defineBehaviorFlags(flagSet, parsed)
+	defineMatchFlags(flagSet, parsed)
+	defineOutputFlags(flagSet, parsed)
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, fmt.Errorf("failed to parse arguments: %w", err)
+	}
+
+	if flagSet.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument: '%s'", flagSet.Arg(0))
+	}
+
+	flagSet.Visit(func(f *flag.Flag) {
+		parsed.setFlags[f.Name] = true
+	})
+
+	// --min-amount sets the same argument as --minimum-amount, which the configuration
+	// file must then not override
+	if parsed.setFlags["min-amount"] {
+		parsed.setFlags["minimum-amount"] = true
+	}
+
+	if parsed.chainID <= 0 {
+		return nil, fmt.Errorf("invalid --chain-id value: %d", parsed.chainID)
+	}
+
+	if !parsed.setFlags["etherscan-chain-id"] {
+		parsed.etherscanChainID = parsed.chainID
+	} else if parsed.etherscanChainID <= 0 {
+		return nil, fmt.Errorf("invalid --etherscan-chain-id value: %d", parsed.etherscanChainID)
+	}
+
+	if parsed.diffFormat != diffFormatMarkdown && parsed.diffFormat != diffFormatJSON {
+		return nil, fmt.Errorf(
+			"invalid --diff-format value '%s'; expected '%s' or '%s'",
+			parsed.diffFormat,
+			diffFormatMarkdown,
+			diffFormatJSON,
+		)
+	}
+
+	switch parsed.onCorruptIgnore {
+	case onCorruptAbort, onCorruptBackupAndReset:
+	default:
+		return nil, fmt.Errorf(
+			"invalid --on-corrupt-ignore-list value '%s'; expected '%s' or '%s'",
+			parsed.onCorruptIgnore,
+			onCorruptAbort,
+			onCorruptBackupAndReset,
+		)
+	}
+
+	if parsed.matchTimeTolerance < 0 {
+		return nil, fmt.Errorf(
+			"invalid --match-time-tolerance value: %s",
+			parsed.matchTimeTolerance,
+		)
+	}
+
+	if parsed.amountTolerance < 0 {
+		return nil, fmt.Errorf("invalid --amount-tolerance value: %d", parsed.amountTolerance)
+	}
+
+	if parsed.maxUnmatched < -1 {
+		return nil, fmt.Errorf("invalid --max-unmatched value: %d", parsed.maxUnmatched)
+	}
+
+	if parsed.ignoreTTLDays < 0 {
+		return nil, fmt.Errorf("invalid --ignore-ttl-days value: %d", parsed.ignoreTTLDays)
+	}
+
+	if parsed.maxAgeDays < 0 {
+		return nil, fmt.Errorf("invalid --max-age-days value: %d", parsed.maxAgeDays)
+	}
+
+	if err := validateRPCTransferArgs(parsed); err != nil {
+		return nil, err
+	}
+
+	if parsed.tokenPrice != "" && parsed.tokenPricesFile != "" {
+		return nil, errors.New("--token-price and --token-prices-file cannot be used together")
+	}
+
+	if parsed.setFlags["token-decimals"] && parsed.tokenDecimals < 0 {
+		return nil, fmt.Errorf("invalid --token-decimals value: %d", parsed.tokenDecimals)
+	}
+
+	if parsed.sinceDays <= 0 {
+		return nil, fmt.Errorf("invalid --since-days value: %d", parsed.sinceDays)
+	}
+
+	if parsed.ynabMaxRetries < 0 {
+		return nil, fmt.Errorf("invalid --ynab-max-retries value: %d", parsed.ynabMaxRetries)
+	}
+
+	// read-only mode would reject every change that a sync makes, so it implies a dry run
+	if parsed.readOnly {
+		parsed.dryRun = true
+	}
+
+	// neither dumping transfers nor the self-test asks questions, so they need no terminal
+	if parsed.dumpTransfers || parsed.selfTest {
+		parsed.nonInteractive = true
+	}
+
+	// an unattended run asks no questions, answering them itself where it safely can
+	if parsed.autoConfirm {
+		parsed.nonInteractive = true
+	}
+
+	if parsed.setFlags["prompt-timeout"] && parsed.promptTimeout <= 0 {
+		return nil, fmt.Errorf("--prompt-timeout must be positive, got '%s'", parsed.promptTimeout)
+	}
+
+	return parsed, nil
+}
+
+// validateRPCTransferArgs checks the block range given for --rpc-transfers.
+func validateRPCTransferArgs(parsed *arguments) error {
+	if !parsed.rpcTransfers {
+		if parsed.setFlags["from-block"] || parsed.setFlags["to-block"] {
+			return errors.New("--from-block and --to-block can only be used with --rpc-transfers")
+		}
+
+		return nil
+	}
+
+	if parsed.etherscanAPIKey != "" {
+		return errors.New("--rpc-transfers and --etherscan-api-key cannot be used together")
+	}
+
+	// searching from the genesis block would take millions of requests on most chains
+	if !parsed.setFlags["from-block"] {
+		return errors.New("--from-block is required with --rpc-transfers")
+	}
+
+	if parsed.toBlock != 0 && parsed.fromBlock > parsed.toBlock {
+		return fmt.Errorf(
+			"--from-block %d is after --to-block %d",
+			parsed.fromBlock,
+			parsed.toBlock,
+		)
+	}
+
+	return nil
+}
+
+// defineInputFlags defines the flags describing where transfers and YNAB data come from.
+func defineInputFlags(flagSet *flag.FlagSet, parsed *arguments) {
+	flagSet.StringVar(
+		&parsed.ynabAccessToken,
+		"ynab-access-token",
+		"",
+		"YNAB Personal Access Token used to authenticate requests to the YNAB API (required)",
+	)
+	flagSet.StringVar(
+		&parsed.ynabAccountName,
+		"ynab-account-name",
+		"",
+		"name of the YNAB account to which transactions are to be synchronized (required)",
+	)
+	flagSet.StringVar(
+		&parsed.ynabBudgetName,
+		"ynab-budget-name",
+		"",
+		"name of the YNAB budget containing the account, instead of choosing among several",
+	)
+	flagSet.IntVar(
+		&parsed.sinceDays,
+		"since-days",
+		defaultSinceDays,
+		"number of days back to look for uncleared YNAB transactions to match",
+	)
+	flagSet.IntVar(
+		&parsed.ynabMaxRetries,
+		"ynab-max-retries",
+		client.DefaultMaxRetries,
+		"times a rate-limited or failed YNAB request is retried (0 to disable)",
+	)
+	flagSet.StringVar(
+		&parsed.walletAddress,
+		"wallet-address",
+		"",
+		"comma-separated wallet addresses to match transfers against (required)",
+	)
+	flagSet.StringVar(
+		&parsed.csvFile,
+		"csv-file",
+		"",
+		"path to an Etherscan CSV export (required without --etherscan-api-key or --rpc-transfers)",
+	)
+	flagSet.StringVar(
+		&parsed.configPath,
+		"config",
+		"",
+		"path to a YAML configuration file providing defaults for other arguments",
+	)
+	flagSet.StringVar(
+		&parsed.csvDateLayout,
+		"csv-date-layout",
+		"",
+		"Go time layout tried first when parsing the CSV's dates",
+	)
+	flagSet.BoolVar(
+		&parsed.includeFailed,
+		"include-failed",
+		false,
+		"process transfers from failed transactions",
+	)
+	flagSet.StringVar(
+		&parsed.csvColumns,
+		"csv-columns",
+		"",
+		"positional CSV columns (e.g. hash,from,to,amount,time) used without a recognized header",
+	)
+	flagSet.StringVar(
+		&parsed.sinceHash,
+		"since-hash",
+		"",
+		"resume processing after the given transaction hash",
+	)
+	flagSet.IntVar(
+		&parsed.maxAgeDays,
+		"max-age-days",
+		0,
+		"ignore transfers executed more than this many days ago (0 for no limit)",
+	)
+	flagSet.Var(
+		&parsed.excludeAddresses,
+		"exclude-address",
+		"ignore transfers to or from this counterparty address (may be given more than once)",
+	)
+	flagSet.StringVar(
+		&parsed.etherscanAPIKey,
+		"etherscan-api-key",
+		"",
+		"Etherscan API key used to retrieve transfers instead of reading --csv-file",
+	)
+	flagSet.Int64Var(
+		&parsed.etherscanChainID,
+		"etherscan-chain-id",
+		0,
+		"ID of the chain whose transfers are retrieved from Etherscan (defaults to --chain-id)",
+	)
+	flagSet.BoolVar(
+		&parsed.rpcTransfers,
+		"rpc-transfers",
+		false,
+		"read transfers from the logs of --rpc-url instead of reading --csv-file",
+	)
+	flagSet.Uint64Var(
+		&parsed.fromBlock,
+		"from-block",
+		0,
+		"first block searched for transfers with --rpc-transfers (required with it)",
+	)
+	flagSet.Uint64Var(
+		&parsed.toBlock,
+		"to-block",
+		0,
+		"last block searched for transfers with --rpc-transfers (0 for the latest block)",
+	)
+	flagSet.StringVar(
+		&parsed.addressBookPath,
+		"address-book",
+		"",
+		"path to a YAML address book labeling counterparty addresses",
+	)
+}
+
+// defineTokenFlags defines the flags describing the token to synchronize and how its details
+// are looked up.
+func defineTokenFlags(flagSet *flag.FlagSet, parsed *arguments) {
+	flagSet.StringVar(
+		&parsed.tokenAddress,
+		"token-address",
+		usdcAddressBase,
+		"contract address of the token to synchronize",
+	)
+	flagSet.Int64Var(
+		&parsed.chainID,
+		"chain-id",
+		chainIDBase,
+		"ID of the chain served by --rpc-url",
+	)
+	flagSet.StringVar(
+		&parsed.rpcURL,
+		"rpc-url",
+		rpcNodeURLBase,
+		"JSON-RPC endpoint used to look up token details; if empty, none is used",
+	)
+	flagSet.BoolVar(
+		&parsed.rpcBlockObject,
+		"rpc-block-object",
+		false,
+		`give the block of each eth_call to the RPC node as {"blockNumber":"latest"}`,
+	)
+	flagSet.BoolVar(
+		&parsed.refreshTokenDetails,
+		"refresh-token-details",
+		false,
+		"fetch token details again instead of using the cached details",
+	)
+	flagSet.IntVar(
+		&parsed.tokenDecimals,
+		"token-decimals",
+		0,
+		"decimals of the token, for tokens whose decimals() cannot be called",
+	)
+	flagSet.StringVar(
+		&parsed.tokenName,
+		"token-name",
+		"",
+		"name of the token; with --token-decimals, the RPC node is not contacted",
+	)
+}
+
+// defineBehaviorFlags defines the flags controlling how transfers are matched and imported.
+func defineBehaviorFlags(flagSet *flag.FlagSet, parsed *arguments) {
+	flagSet.BoolVar(&parsed.dryRun, "dry-run", false, "log changes instead of making them in YNAB")
+	flagSet.BoolVar(
+		&parsed.readOnly,
+		"read-only",
+		false,
+		"like --dry-run, but also refuse any non-GET request to YNAB or Etherscan and "+
+			"leave the ignore list, journal and cache files as they are",
+	)
+	flagSet.BoolVar(&parsed.debug, "debug", false, "enable debug logging")
+	flagSet.StringVar(
+		&parsed.logFile,
+		"log-file",
+		"",
+		"file to which logs are written instead of the terminal, keeping prompts readable",
+	)
+	flagSet.BoolVar(
+		&parsed.skipOnCancel,
+		"skip-on-cancel",
+		false,
+		"skip only the current transfer when an import prompt is canceled",
+	)
+	flagSet.StringVar(
+		&parsed.decisionJournalPath,
+		"decision-journal",
+		"",
+		"file in which import decisions are recorded so that an interrupted import can be resumed",
+	)
+	flagSet.BoolVar(
+		&parsed.batchCreate,
+		"batch-create",
+		false,
+		"create the chosen transactions in a single YNAB request once every transfer is handled",
+	)
+	flagSet.DurationVar(
+		&parsed.promptTimeout,
+		"prompt-timeout",
+		0,
+		"duration after which an unanswered prompt is answered with its safe default",
+	)
+	flagSet.StringVar(
+		&parsed.confirmCurrency,
+		"confirm-currency",
+		"",
+		"ISO code of a non-USD budget currency to synchronize anyway",
+	)
+	flagSet.BoolVar(
+		&parsed.promptCategory,
+		"prompt-category",
+		false,
+		"prompt for the category of each imported transfer",
+	)
+	flagSet.BoolVar(
+		&parsed.failFast,
+		"fail-fast",
+		false,
+		"stop as soon as a transfer fails to be imported",
+	)
+	flagSet.BoolVar(
+		&parsed.selectTransfers,
+		"select-transfers",
+		false,
+		"choose the transfers to import from a single checklist",
+	)
+	flagSet.BoolVar(
+		&parsed.nonInteractive,
+		"non-interactive",
+		false,
+		"answer every prompt with its default, e.g., when input is not a terminal",
+	)
+	flagSet.BoolVar(
+		&parsed.autoConfirm,
+		"yes",
+		false,
+		"like --non-interactive, but clear unambiguous matches and import every transfer",
+	)
+	flagSet.StringVar(
+		&parsed.minimumAmount,
+		"minimum-amount",
+		"",
+		"smallest amount, in whole tokens, of a transfer to be imported (defaults to 0.01)",
+	)
+	flagSet.StringVar(&parsed.minimumAmount, "min-amount", "", "shorthand for --minimum-amount")
+	flagSet.BoolVar(
+		&parsed.noMinimumInbound,
+		"no-minimum-inbound",
+		false,
+		"apply the minimum amount only to outbound transfers, importing inbound ones of any amount",
+	)
+	flagSet.BoolVar(
+		&parsed.includeSelf,
+		"include-self-transfers",
+		false,
+		"offer transfers from a wallet to itself for import instead of skipping them",
+	)
+	flagSet.StringVar(
+		&parsed.inboundPayee,
+		"default-inbound-payee",
+		"",
+		"payee offered for imported inbound transfers instead of the sender's address",
+	)
+	flagSet.StringVar(
+		&parsed.outboundPayee,
+		"default-outbound-payee",
+		"",
+		"payee offered for imported outbound transfers instead of the recipient's address",
+	)
+	flagSet.StringVar(
+		&parsed.ignoreListPath,
+		"ignore-list",
+		ignoreListFilename,
+		"path of the ignore list file, written as JSON if it ends in .json and YAML otherwise",
+	)
+	flagSet.StringVar(
+		&parsed.onCorruptIgnore,
+		"on-corrupt-ignore-list",
+		onCorruptAbort,
+		"what to do if the ignore list cannot be parsed: abort or backup-and-reset",
+	)
+}
+
+// defineMatchFlags defines the flags controlling how YNAB transactions are matched to transfers
+// and cleared.
+func defineMatchFlags(flagSet *flag.FlagSet, parsed *arguments) {
+	flagSet.BoolVar(
+		&parsed.memoIncludeLogIndex,
+		"memo-include-logindex",
+		false,
+		"distinguish transfers sharing a transaction hash in the memo",
+	)
+	flagSet.BoolVar(
+		&parsed.dailyTotals,
+		"daily-totals",
+		false,
+		"match transactions against the net total of each day's transfers",
+	)
+	flagSet.BoolVar(
+		&parsed.hashPrefixMatch,
+		"hash-prefix-match",
+		false,
+		"allow matching a transfer by typing a prefix of its transaction hash",
+	)
+	flagSet.Int64Var(
+		&parsed.amountTolerance,
+		"amount-tolerance",
+		0,
+		"largest difference, in YNAB milliunits (1000 = $1), between matching amounts",
+	)
+	flagSet.StringVar(
+		&parsed.tokenPrice,
+		"token-price",
+		"",
+		"match the value of transfers at this price of a whole token instead of their quantity",
+	)
+	flagSet.StringVar(
+		&parsed.tokenPricesFile,
+		"token-prices-file",
+		"",
+		"path to a CSV of date,price rows at which to value transfers when matching",
+	)
+	flagSet.BoolVar(
+		&parsed.requirePayeeMatch,
+		"require-payee-match",
+		false,
+		"only match transfers whose counterparty corresponds to the transaction's payee",
+	)
+	flagSet.BoolVar(
+		&parsed.confirmEachClear,
+		"confirm-each-clear",
+		false,
+		"ask before clearing each matched YNAB transaction",
+	)
+	flagSet.BoolVar(
+		&parsed.matchRefunds,
+		"match-refunds",
+		false,
+		"match unmatched transactions to the net amount of a transfer less a partial refund",
+	)
+	flagSet.DurationVar(
+		&parsed.matchTimeTolerance,
+		"match-time-tolerance",
+		0,
+		"longest time, e.g., 6h, between a matching transfer and the start of a transaction's date",
+	)
+	flagSet.IntVar(
+		&parsed.maxUnmatched,
+		"max-unmatched",
+		-1,
+		"abort before importing if more transactions than this go unmatched (-1 for no limit)",
+	)
+	flagSet.StringVar(
+		&parsed.accountTypeName,
+		"account-type",
+		string(transaction.AccountTypeAsset),
+		"type of the YNAB account: asset, or liability for accounts that spending increases",
+	)
+}
+
+// defineOutputFlags defines the flags controlling what is reported and how.
+func defineOutputFlags(flagSet *flag.FlagSet, parsed *arguments) {
+	flagSet.BoolVar(
+		&parsed.dumpTransfers,
+		"dump-transfers",
+		false,
+		"print the parsed transfers and exit without contacting YNAB",
+	)
+	flagSet.BoolVar(
+		&parsed.preview,
+		"preview",
+		false,
+		"print a table of the transfers to be synchronized before any prompt is shown",
+	)
+	flagSet.BoolVar(
+		&parsed.dedupeReport,
+		"dedupe-report",
+		false,
+		"print the transfers dropped as duplicates of others before any are matched",
+	)
+	flagSet.BoolVar(
+		&parsed.selfTest,
+		"selftest",
+		false,
+		"verify parsing, matching and conversion against built-in data and exit",
+	)
+	flagSet.StringVar(
+		&parsed.initConfigPath,
+		"init-config",
+		"",
+		"path to which a commented template configuration file is written before exiting",
+	)
+	flagSet.BoolVar(
+		&parsed.force,
+		"force",
+		false,
+		"allow --init-config to overwrite an existing file",
+	)
+	flagSet.StringVar(
+		&parsed.unignoreHash,
+		"unignore",
+		"",
+		"remove the given transaction hash from the ignore list and exit",
+	)
+	flagSet.BoolVar(
+		&parsed.listIgnored,
+		"list-ignored",
+		false,
+		"print every hash in the ignore list with the date and reason it was added, and exit",
+	)
+	flagSet.StringVar(
+		&parsed.reportMarkdownPath,
+		"report-markdown",
+		"",
+		"path to which a Markdown report of the run is written",
+	)
+	flagSet.BoolVar(
+		&parsed.appendMarkdown,
+		"report-markdown-append",
+		false,
+		"append the --report-markdown report to the file, keeping the reports of earlier runs",
+	)
+	flagSet.StringVar(
+		&parsed.reportPath,
+		"report",
+		"",
+		"path to which a JSON report of the run is written",
+	)
+	flagSet.StringVar(
+		&parsed.unmatchedOutPath,
+		"unmatched-transactions-out",
+		"",
+		"path to which the uncleared YNAB transactions left unmatched are written as CSV",
+	)
+	flagSet.StringVar(
+		&parsed.addressFormat,
+		"address-format",
+		"",
+		"form in which addresses are shown: lower or checksum",
+	)
+	flagSet.BoolVar(
+		&parsed.compactOutput,
+		"compact-output",
+		false,
+		"print a one-line summary of the run instead of logging each phase's duration",
+	)
+	flagSet.BoolVar(
+		&parsed.showRoundedAmounts,
+		"show-rounded-amounts",
+		false,
+		"also show amounts in import prompts rounded to the budget currency's precision",
+	)
+	flagSet.BoolVar(
+		&parsed.groupIgnoredReason,
+		"group-ignored-reason",
+		false,
+		"store each distinct reason of the ignore list once",
+	)
+	flagSet.IntVar(
+		&parsed.ignoreTTLDays,
+		"ignore-ttl-days",
+		0,
+		"drop processed hashes added to the ignore list over this many days ago (0 to keep all)",
+	)
+	flagSet.BoolVar(
+		&parsed.diff,
+		"diff",
+		false,
+		"print a read-only reconciliation of transfers against YNAB transactions",
+	)
+	flagSet.StringVar(
+		&parsed.diffFormat,
+		"diff-format",
+		diffFormatMarkdown,
+		"format of the --diff output: markdown or json",
+	)
+	flagSet.BoolVar(
+		&parsed.printLinks,
+		"print-links",
+		false,
+		"print a link to each transaction cleared or created in YNAB",
+	)
+}
+
+// applyConfig fills in each argument that was not given on the command line with its value,
+// if any, from the given configuration.
+func (a *arguments) applyConfig(cfg *config.Config) {
+	a.applyConfigValue("ynab-access-token", &a.ynabAccessToken, cfg.YNABAccessToken)
+	a.applyConfigValue("ynab-account-name", &a.ynabAccountName, cfg.YNABAccountName)
+	a.applyConfigValue("wallet-address", &a.walletAddress, cfg.WalletAddress)
+	a.applyConfigValue("rpc-url", &a.rpcURL, cfg.RPCURL)
+	a.applyConfigValue("token-address", &a.tokenAddress, cfg.TokenAddress)
+	a.applyConfigValue("minimum-amount", &a.minimumAmount, cfg.MinimumAmount)
+
+	if !a.setFlags["exclude-address"] && len(cfg.ExcludeAddresses) > 0 {
+		a.excludeAddresses = cfg.ExcludeAddresses
+	}
+}
+
+func (a *arguments) applyConfigValue(flagName string, value *string, configValue string) {
+	if !a.setFlags[flagName] && configValue != "" {
+		*value = configValue
+	}
+}
+
+// writeConfigTemplate writes a template configuration file, listing every setting with its
+// default, to the path given by --init-config. An existing file is only overwritten with --force.
+func writeConfigTemplate(ctx context.Context, args *arguments) error {
+	templatePath := args.initConfigPath
+
+	templateExists, err := ctsio.FileExists(templatePath)
+	if err != nil {
+		return fmt.Errorf("failed to check for configuration file: %w", err)
+	}
+
+	if templateExists && !args.force {
+		return fmt.Errorf(
+			"configuration file '%s' already exists; use --force to overwrite it",
+			templatePath,
+		)
+	}
+
+	defaults := &config.Config{
+		TokenAddress:  usdcAddressBase,
+		RPCURL:        rpcNodeURLBase,
+		MinimumAmount: "0.01",
+	}
+
+	// the file is to hold a YNAB access token, so only its owner may read it
+	err = ctsio.WriteFileAtomically(templatePath, 0o600, func(writer io.Writer) error {
+		return config.WriteTemplate(writer, defaults)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write configuration file '%s': %w", templatePath, err)
+	}
+
+	slog.InfoContext(ctx, "Wrote template configuration file to "+templatePath)
+
+	return nil
+}
+
+// loadConfig loads the configuration file named by the --config argument.
+// If no configuration file was provided, an empty configuration is returned.
+func loadConfig(args *arguments) (*config.Config, error) {
+	if args.configPath != "" {
+		return config.LoadFile(args.configPath)
+	}
+
+	return &config.Config{}, nil
+}
+
+func getAccessToken(args *arguments) (string, error) {
+	if args.ynabAccessToken == "" {
+		return "", errors.New("--ynab-access-token argument is required")
+	}
+
+	return args.ynabAccessToken, nil
+}
+
+func getAccountName(args *arguments) (string, error) {
+	if args.ynabAccountName == "" {
+		return "", errors.New("--ynab-account-name argument is required")
+	}
+
+	return args.ynabAccountName, nil
+}
+
+// getWallets returns the wallets given by --wallet-address as a comma-separated list of addresses.
+func getWallets(args *arguments) (*transaction.Wallets, error) {
+	if strings.TrimSpace(args.walletAddress) == "" {
+		return nil, errors.New("--wallet-address argument is required")
+	}
+
+	return transaction.ParseWallets(args.walletAddress)
+}
+
+// describeWallet describes which of the given wallets the given transfer belongs to,
+// e.g., " (wallet 0xabc)", or returns an empty string if there is only one wallet.
+func describeWallet(wallets *transaction.Wallets, xfr *transaction.Transfer) string {
+	if wallets.Len() < 2 { //nolint:mnd
+		return ""
+	}
+
+	if _, wallet, ok := wallets.Direction(xfr); ok {
+		return fmt.Sprintf(" (wallet %s)", wallet)
+	}
+
+	return ""
+}
+
+// getMatchOptions returns the options with which transfers are matched to YNAB transactions.
+func getMatchOptions(args *arguments) []transfer.MatchOption {
+	opts := []transfer.MatchOption{
+		transfer.WithAmountTolerance(args.amountTolerance),
+		transfer.WithAccountType(args.accountType),
+	}
+	if args.prices != nil {
+		opts = append(opts, transfer.WithPrices(args.prices))
+	}
+
+	if args.matchTimeTolerance > 0 {
+		opts = append(opts, transfer.WithTimeTolerance(args.matchTimeTolerance))
+	}
+
+	return opts
+}
+
+// loadPrices loads the prices at which transfers are valued when matching, if any are given.
+func loadPrices(args *arguments) (transfer.PriceSource, error) {
+	if args.tokenPrice != "" {
+		price, err := transfer.ParsePrice(args.tokenPrice)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --token-price value: %w", err)
+		}
+
+		return transfer.NewFixedPrice(price), nil
+	}
+
+	if args.tokenPricesFile == "" {
+		return nil, nil
+	}
+
+	pricesFile, err := os.Open(args.tokenPricesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open token prices file: %w", err)
+	}
+	defer func() { _ = pricesFile.Close() }()
+
+	prices, err := transfer.ParseDailyPrices(pricesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token prices file: %w", err)
+	}
+
+	return prices, nil
+}
+
+// getLookbackCutoff returns the earliest time from which YNAB transactions are retrieved:
+// the number of days given by --since-days before now.
+func getLookbackCutoff(args *arguments) time.Time {
+	return time.Now().AddDate(0, 0, -args.sinceDays)
+}
+
+// getRoundedDecimalDigits returns the number of decimal digits to which amounts in import prompts
+// are rounded, if requested: those of the budget's currency or, if unknown, those of US dollars.
+func getRoundedDecimalDigits(args *arguments, budget *client.Budget) *int {
+	if !args.showRoundedAmounts {
+		return nil
+	}
+
+	if budget.DecimalDigits != nil {
+		return budget.DecimalDigits
+	}
+
+	usdDecimalDigits := 2
+
+	return &usdDecimalDigits
+}
+
+// getMinimumAmount returns the smallest amount, in the token's base unit, of a transfer to be imported.
+// If no minimum amount was provided, nil is returned so that the importer's default is used.
+func getMinimumAmount(args *arguments, tokenDetails *token.Details) (*big.Int, error) {
+	if args.minimumAmount == "" {
+		return nil, nil
+	}
+
+	baseUnits, err := transaction.ParseTokenAmount(args.minimumAmount, tokenDetails.Decimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --minimum-amount value: %w", err)
+	}
+
+	return baseUnits, nil
+}
+
+func getCSVFile(args *arguments) (string, error) {
+	if args.csvFile == "" {
+		return "", errors.New(
+			"--csv-file argument is required when neither --etherscan-api-key " +
+				"nor --rpc-transfers is provided",
+		)
+	}
+
+	return args.csvFile, nil
+}
+
+func getAddressFormat(args *arguments) (eth.AddressFormat, error) {
+	if args.addressFormat == "" {
+		return eth.AddressFormatUnchanged, nil
+	}
+
+	addressFormat, err := eth.ParseAddressFormat(args.addressFormat)
+	if err != nil {
+		return eth.AddressFormatUnchanged, fmt.Errorf("invalid --address-format value: %w", err)
+	}
+
+	return addressFormat, nil
+}
+
+func getTransfers(
+	ctx context.Context,
+	csvFile string,
+	args *arguments,
+	tokenDetails *token.Details,
+) ([]*transaction.Transfer, error) {
+	file, err := os.Open(csvFile) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
 
-			break
+	var csvOptions []transaction.CSVOption
+	if dateLayout := args.csvDateLayout; dateLayout != "" {
+		if err := transaction.ValidateDateLayout(dateLayout); err != nil {
+			return nil, fmt.Errorf("invalid --csv-date-layout value: %w", err)
 		}
-	}
 
-	if accessToken == "" {
-		return "", errors.New("--ynab-access-token argument is required")
+		csvOptions = append(csvOptions, transaction.WithDateLayout(dateLayout))
 	}
 
-	return accessToken, nil
-}
-
-func getAccountName() (string, error) {
-	var accountName string
-	for _, arg := range os.Args[1:] {
-		parsedName, hasPrefix := strings.CutPrefix(arg, "--ynab-account-name=")
-		if hasPrefix {
-			accountName = parsedName
-
-			break
+	if columnOrder := args.csvColumns; columnOrder != "" {
+		if err := transaction.ValidateColumnOrder(columnOrder); err != nil {
+			return nil, fmt.Errorf("invalid --csv-columns value: %w", err)
 		}
+
+		csvOptions = append(csvOptions, transaction.WithColumnOrder(columnOrder))
 	}
 
-	if accountName == "" {
-		return "", errors.New("--ynab-account-name argument is required")
+	transfers, err := transaction.TransfersFromEtherscanCSV(ctx, tokenDetails, file, csvOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transfers from CSV: %w", err)
 	}
 
-	return accountName, nil
+	return transfers, nil
 }
 
-func getAddress() (string, error) {
-	var address string
-	for _, arg := range os.Args[1:] {
-		parsedAddress, hasPrefix := strings.CutPrefix(arg, "--wallet-address=")
-		if hasPrefix {
-			address = parsedAddress
-
-			break
-		}
-	}
+// verifyCSVDecimals cross-checks the decimals of the given token against any decimals suggested by the CSV file,
+// warning loudly if they conflict.
+func verifyCSVDecimals(ctx context.Context, csvFile string, tokenDetails *token.Details) {
+	file, err := os.Open(csvFile) //nolint:gosec
+	if err != nil {
+		slog.DebugContext(ctx, "Unable to open CSV file to check for a decimals hint", "error", err)
 
-	if address == "" {
-		return "", errors.New("--wallet-address argument is required")
+		return
 	}
+	defer func() { _ = file.Close() }()
 
-	return address, nil
-}
+	hint, err := transaction.DecimalsHintFromEtherscanCSV(file)
+	if err != nil {
+		slog.DebugContext(ctx, "Unable to read a decimals hint from the CSV file", "error", err)
 
-func getRPCURL() string {
-	var rpcURL string
-	for _, arg := range os.Args[1:] {
-		parsedURL, hasPrefix := strings.CutPrefix(arg, "--rpc-url=")
-		if hasPrefix {
-			rpcURL = parsedURL
+		return
+	}
 
-			break
-		}
+	if err := transaction.VerifyDecimalsHint(hint, tokenDetails); err != nil {
+		slog.WarnContext(
+			ctx,
+			"!!! The token decimals suggested by the CSV do not match the token contract; amounts will likely be wrong !!!",
+			"error",
+			err,
+		)
 	}
+}
 
-	if rpcURL == "" {
-		return rpcNodeURLBase
+// readAddressBook reads the address book file named by the --address-book argument.
+// If no address book was provided, an empty address book is returned.
+func readAddressBook(args *arguments) (*addressbook.AddressBook, error) {
+	if args.addressBookPath != "" {
+		return addressbook.LoadFile(args.addressBookPath)
 	}
 
-	return rpcURL
+	return addressbook.NewAddressBook(), nil
 }
 
-func getTokenAddress() string {
-	var tokenAddress string
-	for _, arg := range os.Args[1:] {
-		parsedAddress, hasPrefix := strings.CutPrefix(arg, "--token-address=")
-		if hasPrefix {
-			tokenAddress = parsedAddress
-
-			break
-		}
+// newPrompter creates the prompter used to ask the user for decisions, applying any requested prompt timeout.
+func newPrompter(args *arguments) (prompt.Prompter, error) {
+	prompter, err := prompt.NewPrompter(os.Stdin, args.nonInteractive)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"unable to prompt on standard input (%w); rerun with --non-interactive "+
+				"to answer every prompt with its default",
+			err,
+		)
 	}
 
-	if tokenAddress == "" {
-		return usdcAddressBase
+	if args.promptTimeout > 0 {
+		prompter = prompt.NewTimeoutPrompter(prompter, args.promptTimeout)
 	}
 
-	return tokenAddress
+	return prompter, nil
 }
 
-func getTransfers(
-	ctx context.Context,
-	tokenDetails *token.Details,
-) ([]*transaction.Transfer, error) {
-	var csvFile string
-	for _, arg := range os.Args[1:] {
-		parsedFile, hasPrefix := strings.CutPrefix(arg, "--csv-file=")
-		if hasPrefix {
-			csvFile = parsedFile
+// manageIgnoreList removes the hash given by --unignore from the ignore list and then,
+// if --list-ignored was given, prints the hashes remaining in it.
+func manageIgnoreList(ctx context.Context, args *arguments) error {
+	ignoreList, err := readIgnoreList(ctx, args)
+	if err != nil {
+		return err
+	}
 
-			break
+	if hash := args.unignoreHash; hash != "" {
+		if !ignoreList.RemoveHash(hash) {
+			return fmt.Errorf("transaction hash '%s' is not in the ignore list", hash)
 		}
-	}
 
-	if csvFile == "" {
-		return nil, errors.New("--csv-file argument is required")
-	}
+		if err := writeIgnoreList(ignoreList, args); err != nil {
+			return err
+		}
 
-	file, err := os.Open(csvFile) //nolint:gosec
-	if err != nil {
-		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+		slog.InfoContext(ctx, fmt.Sprintf("Removed transaction hash %s from ignore list", hash))
 	}
-	defer func() { _ = file.Close() }()
 
-	transfers, err := transaction.TransfersFromEtherscanCSV(ctx, tokenDetails, file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse transfers from CSV: %w", err)
+	if args.listIgnored {
+		if err := report.WriteIgnoredHashes(ignoreList.GetHashes(), os.Stdout); err != nil {
+			return fmt.Errorf("failed to list ignored hashes: %w", err)
+		}
 	}
 
-	return transfers, nil
-}
-
-func isDebug() bool {
-	return slices.Contains(os.Args[1:], "--debug")
-}
-
-func isDryRun() bool {
-	return slices.Contains(os.Args[1:], "--dry-run")
+	return nil
 }
 
-// readIgnoreList reads the ignore list from the ignore list file if it exists.
-func readIgnoreList(ctx context.Context) (*transaction.IgnoreList, error) {
-	ignoreFileExists, err := ctsio.FileExists(ignoreListFilename)
+// readIgnoreList reads the ignore list file, if it exists, pruning the processed hashes older
+// than --ignore-ttl-days, if given.
+func readIgnoreList(ctx context.Context, args *arguments) (*transaction.IgnoreList, error) {
+	ignoreFileExists, err := ctsio.FileExists(args.ignoreListPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check for ignore list file: %w", err)
 	}
 
 	var ignoreList *transaction.IgnoreList
 	if ignoreFileExists {
-		readHandle, err := os.Open(ignoreListFilename)
+		readHandle, err := os.Open(args.ignoreListPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open ignore list file: %w", err)
 		}
 		defer func() { _ = readHandle.Close() }()
 
-		ignoreList, err = transaction.FromYAML(readHandle)
+		decode := transaction.FromYAML
+		if isJSONIgnoreList(args.ignoreListPath) {
+			decode = transaction.FromJSON
+		}
+
+		ignoreList, err = decode(readHandle)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse ignore list file: %w", err)
+			if args.onCorruptIgnore != onCorruptBackupAndReset {
+				return nil, fmt.Errorf("failed to parse ignore list file: %w", err)
+			}
+
+			_ = readHandle.Close()
+
+			return resetIgnoreList(ctx, args, err)
 		}
 
 		slog.InfoContext(
@@ -544,6 +2645,18 @@ func readIgnoreList(ctx context.Context) (*transaction.IgnoreList, error) {
 			fmt.Sprintf("Loaded %d entries from ignore list", ignoreList.GetHashCount()),
 		)
 
+		if ttlDays := args.ignoreTTLDays; ttlDays > 0 {
+			pruned := ignoreList.Prune(time.Duration(ttlDays) * 24 * time.Hour) //nolint:mnd
+			slog.InfoContext(
+				ctx,
+				fmt.Sprintf(
+					"Pruned %d processed entries added to the ignore list over %d days ago",
+					pruned,
+					ttlDays,
+				),
+			)
+		}
+
 		return ignoreList, nil
 	}
 
@@ -552,54 +2665,206 @@ func readIgnoreList(ctx context.Context) (*transaction.IgnoreList, error) {
 	return ignoreList, nil
 }
 
+// readTokenDetailsCache reads the token details cache from the cache file if it exists.
+func readTokenDetailsCache() (*token.DetailsCache, error) {
+	cacheFileExists, err := ctsio.FileExists(tokenDetailsCacheFilename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for token details cache file: %w", err)
+	}
+
+	if !cacheFileExists {
+		return token.NewDetailsCache(), nil
+	}
+
+	readHandle, err := os.Open(tokenDetailsCacheFilename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open token details cache file: %w", err)
+	}
+	defer func() { _ = readHandle.Close() }()
+
+	cache, err := token.DetailsCacheFromYAML(readHandle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token details cache file: %w", err)
+	}
+
+	return cache, nil
+}
+
+// writeTokenDetailsCache writes the given token details cache to the cache file.
+func writeTokenDetailsCache(cache *token.DetailsCache) error {
+	//nolint:mnd // no need to keep this at 600 or less
+	err := ctsio.WriteFileAtomically(
+		tokenDetailsCacheFilename,
+		0o644,
+		func(writer io.Writer) error {
+			return token.DetailsCacheToYAML(cache, writer)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write token details cache: %w", err)
+	}
+
+	return nil
+}
+
+// readTransactionCache reads the YNAB transaction cache from the cache file if it exists.
+func readTransactionCache() (*client.TransactionCache, error) {
+	cacheFileExists, err := ctsio.FileExists(transactionCacheFilename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for YNAB transaction cache file: %w", err)
+	}
+
+	if !cacheFileExists {
+		return client.NewTransactionCache(), nil
+	}
+
+	readHandle, err := os.Open(transactionCacheFilename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open YNAB transaction cache file: %w", err)
+	}
+	defer func() { _ = readHandle.Close() }()
+
+	cache, err := client.TransactionCacheFromYAML(readHandle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse YNAB transaction cache file: %w", err)
+	}
+
+	return cache, nil
+}
+
+// writeTransactionCache writes the given YNAB transaction cache to the cache file.
+func writeTransactionCache(cache *client.TransactionCache) error {
+	//nolint:mnd // no need to keep this at 600 or less
+	err := ctsio.WriteFileAtomically(transactionCacheFilename, 0o644, func(writer io.Writer) error {
+		return client.TransactionCacheToYAML(cache, writer)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write YNAB transaction cache file: %w", err)
+	}
+
+	return nil
+}
+
 func selectAccount(
 	ctx context.Context,
-	httpClient *http.Client,
+	httpClient ctshttp.Doer,
 	ynabAccessToken string,
 	accountName string,
+	args *arguments,
+	prompter prompt.Prompter,
 ) (*client.Budget, string, error) {
 	allBudgets, err := client.GetBudgets(ctx, httpClient, ynabAccessToken)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to retrieve YNAB budgets: %w", err)
 	}
 
-	budget, err := chooseBudget(ctx, allBudgets)
+	budget, err := chooseBudget(ctx, prompter, allBudgets, args.ynabBudgetName)
 	if err != nil {
 		return nil, "", err
 	}
 
+	if err := budget.VerifyCurrency(expectedCurrencyCode, args.confirmCurrency); err != nil {
+		return nil, "", fmt.Errorf("budget currency check failed: %w", err)
+	}
+
 	accounts, err := client.GetAccounts(ctx, httpClient, ynabAccessToken, budget.ID)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to retrieve YNAB accounts: %w", err)
 	}
 
-	chosenAccountID, err := findAccountID(accounts, accountName)
+	chosenAccountID, err := findAccountID(ctx, prompter, accounts, accountName)
 	if err != nil {
-		accountNames := make([]string, 0, len(accounts))
-		for _, acct := range accounts {
-			accountNames = append(accountNames, acct.Name)
-		}
+		return nil, "", err
+	}
 
-		return nil, "", fmt.Errorf(
-			"account '%s' not found in budget '%s' among available choices: %s",
-			accountName,
-			budget.Name,
-			strings.Join(accountNames, ", "),
-		)
+	if chosenAccountID == "" {
+		chosenAccountID, err = chooseAccount(ctx, prompter, budget, accounts, accountName)
+		if err != nil {
+			return nil, "", err
+		}
 	}
 
 	return budget, chosenAccountID, nil
 }
 
+// chooseAccount prompts the user to select one of the given accounts of the given budget
+// because none of them matches the configured account name.
+// It returns the ID of the chosen account.
+func chooseAccount(
+	ctx context.Context,
+	prompter prompt.Prompter,
+	budget *client.Budget,
+	accounts []*client.Account,
+	accountName string,
+) (string, error) {
+	accountNames := make([]string, 0, len(accounts))
+	for _, acct := range accounts {
+		accountNames = append(accountNames, acct.Name)
+	}
+
+	notFoundErr := fmt.Errorf(
+		"account '%s' not found in budget '%s' among available choices: %s",
+		accountName,
+		budget.Name,
+		strings.Join(accountNames, ", "),
+	)
+
+	if len(accounts) == 0 {
+		return "", notFoundErr
+	}
+
+	i, err := prompter.Select(
+		fmt.Sprintf("Account '%s' was not found; select a YNAB account", accountName),
+		accountNames,
+	)
+	if err != nil {
+		// If the user canceled the prompt (Ctrl-C/Ctrl-D), exit with an error so the program stops.
+		if errors.Is(err, prompt.ErrInterrupt) || errors.Is(err, prompt.ErrEOF) {
+			return "", errors.New("account selection canceled")
+		}
+
+		// There is no safe default account, so an unanswered prompt fails as if no prompt had been shown.
+		slog.WarnContext(ctx, "Account selection prompt failed", "error", err)
+
+		return "", notFoundErr
+	}
+
+	selected := accounts[i]
+	slog.InfoContext(
+		ctx,
+		"Selected account",
+		"accountName",
+		selected.Name,
+		"accountID",
+		selected.ID,
+	)
+
+	return selected.ID, nil
+}
+
 func retrieveUnclearedTransactions(
 	ctx context.Context,
-	httpClient *http.Client,
+	httpClient ctshttp.Doer,
 	ynabAccessToken string,
 	budgetID string,
 	accountID string,
 	since time.Time,
+	readOnly bool,
 ) ([]*client.Transaction, error) {
-	transactions, err := client.GetTransactions(
+	slog.InfoContext(
+		ctx,
+		fmt.Sprintf(
+			"Looking up uncleared YNAB transactions dated on or after %s",
+			since.Format(time.DateOnly),
+		),
+	)
+
+	transactionCache, err := readTransactionCache()
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := transactionCache.GetTransactions(
 		ctx,
 		httpClient,
 		ynabAccessToken,
@@ -611,6 +2876,12 @@ func retrieveUnclearedTransactions(
 		return nil, fmt.Errorf("failed to get transactions: %w", err)
 	}
 
+	if !readOnly {
+		if err := writeTransactionCache(transactionCache); err != nil {
+			slog.WarnContext(ctx, "Failed to write YNAB transaction cache", "error", err)
+		}
+	}
+
 	return filterUncleared(transactions), nil
 }
 
@@ -618,93 +2889,411 @@ func retrieveUnclearedTransactions(
 // It returns any remaining unconsumed transfers after processing.
 func processUnclearedTransactions(
 	ctx context.Context,
-	httpClient *http.Client,
+	httpClient ctshttp.Doer,
 	accessToken string,
 	budgetID string,
-	walletAddress string,
+	wallets *transaction.Wallets,
 	tokenDetails *token.Details,
 	transfers []*transaction.Transfer,
 	unclearedTransactions []*client.Transaction,
-	dryRun bool,
+	args *arguments,
 	ignoreList *transaction.IgnoreList,
+	summary *report.RunSummary,
+	prompter prompt.Prompter,
+	addressBook *addressbook.AddressBook,
 ) ([]*transaction.Transfer, error) {
 	matchedCount := 0
 	unmatchedCount := 0
 
-	remainingTransfers := make([]*transaction.Transfer, len(transfers))
-	copy(remainingTransfers, transfers)
+	// transfers that were already processed or ignored are never offered as candidates
+	remainingTransfers := filterIgnoredTransfers(ignoreList, transfers)
 
+	var pending []*pendingClearing
 	for _, unclearedTransaction := range unclearedTransactions {
-		matchingTransfer, err := resolveMatchingTransfer(
+		matchingTransfer, refundedTransfer, err := resolveMatch(
 			ctx,
+			prompter,
 			unclearedTransaction,
-			walletAddress,
+			wallets,
 			tokenDetails,
 			remainingTransfers,
+			addressBook,
+			args,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve matching transfer: %w", err)
 		}
 
+		if refundedTransfer != nil {
+			matchedCount++
+			pending = planRefundedClearing(
+				ctx,
+				pending,
+				summary,
+				unclearedTransaction,
+				refundedTransfer,
+				tokenDetails,
+				args,
+			)
+			remainingTransfers = removeTransfers(
+				remainingTransfers,
+				refundedTransfer.Transfer,
+				refundedTransfer.Refund,
+			)
+
+			continue
+		}
+
 		if matchingTransfer == nil {
 			unmatchedCount++
+			summary.AddUnmatched(unclearedTransaction)
 
 			continue
 		}
 
 		matchedCount++
+		summary.Matched = append(summary.Matched, &report.MatchedTransaction{
+			Transaction: report.NewTransaction(unclearedTransaction),
+			Transfer:    report.NewTransfer(matchingTransfer, tokenDetails.Decimals),
+		})
 
 		slog.DebugContext(
 			ctx,
 			fmt.Sprintf(
-				"Matched transfer of %s %s %s to transaction hash %s",
+				"Matched transfer of %s %s %s to transaction hash %s%s",
 				unclearedTransaction.GetFormattedAmount(),
-				transaction.ResolveDirection(unclearedTransaction.IsOutbound()),
+				directionOf(unclearedTransaction, args.accountType),
 				unclearedTransaction.Payee,
 				matchingTransfer.TransactionHash,
+				describeWallet(wallets, matchingTransfer),
+			),
+		)
+
+		var memoDetail string
+		if args.memoIncludeLogIndex {
+			memoDetail = matchingTransfer.DescribeWithinTransaction(
+				transfers,
+				tokenDetails.Decimals,
+			)
+		}
+
+		pending = planClearing(ctx, pending, &pendingClearing{
+			update: client.NewClearingUpdate(
+				unclearedTransaction,
+				[]string{matchingTransfer.TransactionHash},
+				memoDetail,
+			),
+			txHashes:    []string{matchingTransfer.TransactionHash},
+			transaction: unclearedTransaction,
+			matchedTransfer: fmt.Sprintf(
+				"the transfer of %s %s on %s in transaction %s",
+				matchingTransfer.FormatAmount(tokenDetails.Decimals),
+				tokenDetails.Name,
+				matchingTransfer.ExecutionTime.Format(time.RFC3339),
+				matchingTransfer.TransactionHash,
+			),
+		}, args)
+
+		// Remove the matched transfer from remainingTransfers to prevent duplicate matches.
+		remainingTransfers = removeTransfers(remainingTransfers, matchingTransfer)
+	}
+
+	pending = confirmClearings(ctx, prompter, pending, args)
+	clearMatchedTransactions(ctx, httpClient, accessToken, budgetID, pending, ignoreList, args)
+
+	logMatchCounts(ctx, matchedCount, unmatchedCount)
+
+	return remainingTransfers, nil
+}
+
+// logMatchCounts logs how many uncleared transactions were and were not matched to transfers.
+func logMatchCounts(ctx context.Context, matchedCount int, unmatchedCount int) {
+	slog.InfoContext(
+		ctx,
+		fmt.Sprintf("Matched %d transactions", matchedCount),
+	)
+
+	if unmatchedCount > 0 {
+		slog.InfoContext(
+			ctx,
+			fmt.Sprintf(
+				"Unable to match %d transactions; these may need to be manually matched or your CSV import may be out-of-date",
+				unmatchedCount,
 			),
 		)
+	}
+}
+
+// removeTransfers returns the given transfers without those that were matched.
+func removeTransfers(
+	transfers []*transaction.Transfer,
+	matched ...*transaction.Transfer,
+) []*transaction.Transfer {
+	return slices.DeleteFunc(transfers, func(xfr *transaction.Transfer) bool {
+		return slices.Contains(matched, xfr)
+	})
+}
+
+// planRefundedClearing records the match of the given transaction to the net amount of a
+// partially-refunded transfer and plans its clearing; the hashes of both the transfer and its
+// refund are appended to the transaction's memo.
+func planRefundedClearing(
+	ctx context.Context,
+	pending []*pendingClearing,
+	summary *report.RunSummary,
+	unclearedTransaction *client.Transaction,
+	refundedTransfer *transfer.RefundedTransfer,
+	tokenDetails *token.Details,
+	args *arguments,
+) []*pendingClearing {
+	summary.Matched = append(summary.Matched, &report.MatchedTransaction{
+		Transaction: report.NewTransaction(unclearedTransaction),
+		Transfer:    report.NewRefundedTransfer(refundedTransfer, tokenDetails.Decimals),
+	})
+
+	txHashes := []string{
+		refundedTransfer.Transfer.TransactionHash,
+		refundedTransfer.Refund.TransactionHash,
+	}
+
+	slog.DebugContext(
+		ctx,
+		fmt.Sprintf(
+			"Matched transfer of %s %s %s to transaction hash %s less the refund in %s",
+			unclearedTransaction.GetFormattedAmount(),
+			directionOf(unclearedTransaction, args.accountType),
+			unclearedTransaction.Payee,
+			txHashes[0],
+			txHashes[1],
+		),
+	)
+
+	return planClearing(ctx, pending, &pendingClearing{
+		update:      client.NewClearingUpdate(unclearedTransaction, txHashes, ""),
+		txHashes:    txHashes,
+		transaction: unclearedTransaction,
+		matchedTransfer: fmt.Sprintf(
+			"the transfer of %s %s on %s in transaction %s, "+
+				"less the refund of %s in transaction %s",
+			refundedTransfer.Transfer.FormatAmount(tokenDetails.Decimals),
+			tokenDetails.Name,
+			refundedTransfer.Transfer.ExecutionTime.Format(time.RFC3339),
+			txHashes[0],
+			refundedTransfer.Refund.FormatAmount(tokenDetails.Decimals),
+			txHashes[1],
+		),
+	}, args)
+}
+
+// processUnclearedTransactionsByDay attempts to match each uncleared transaction with the net total
+// of a day's transfers, for accounts whose YNAB transactions aggregate a day's activity into one entry.
+// It returns any transfers that were not part of a matched daily total.
+func processUnclearedTransactionsByDay(
+	ctx context.Context,
+	httpClient ctshttp.Doer,
+	accessToken string,
+	budgetID string,
+	wallets *transaction.Wallets,
+	tokenDetails *token.Details,
+	transfers []*transaction.Transfer,
+	unclearedTransactions []*client.Transaction,
+	args *arguments,
+	ignoreList *transaction.IgnoreList,
+	summary *report.RunSummary,
+	prompter prompt.Prompter,
+) []*transaction.Transfer {
+	matchedCount := 0
+	unmatchedCount := 0
+
+	remainingTotals := transaction.SumTransfersByDay(
+		filterIgnoredTransfers(ignoreList, transfers),
+		wallets,
+	)
+	consumedTransfers := make(map[*transaction.Transfer]struct{})
 
-		if !dryRun {
-			if err := handleMatchedTransaction(ctx, httpClient, accessToken, budgetID, unclearedTransaction.ID, matchingTransfer.TransactionHash); err != nil {
-				slog.ErrorContext(
+	var pending []*pendingClearing
+	for _, unclearedTransaction := range unclearedTransactions {
+		matchingTotals := transfer.MatchDailyTotals(
+			unclearedTransaction,
+			tokenDetails,
+			remainingTotals,
+			transfer.WithAccountType(args.accountType),
+		)
+		if len(matchingTotals) != 1 {
+			if len(matchingTotals) > 1 {
+				slog.InfoContext(
 					ctx,
 					fmt.Sprintf(
-						"Failed to mark transaction ID %s as cleared",
-						unclearedTransaction.ID,
+						"Transaction of %s on %s matches %d daily totals; leaving it to be matched manually",
+						unclearedTransaction.GetFormattedAmount(),
+						unclearedTransaction.Date.Format(time.DateOnly),
+						len(matchingTotals),
 					),
-					"error",
-					err,
 				)
 			}
 
-			ignoreList.AddProcessedHash(matchingTransfer.TransactionHash, unclearedTransaction.ID)
+			unmatchedCount++
+			summary.AddUnmatched(unclearedTransaction)
+
+			continue
 		}
 
-		// Remove the matched transfer from remainingTransfers to prevent duplicate matches.
-		for i := len(remainingTransfers) - 1; i >= 0; i-- {
-			if remainingTransfers[i] == matchingTransfer {
-				remainingTransfers = append(remainingTransfers[:i], remainingTransfers[i+1:]...)
-			}
+		matchingTotal := matchingTotals[0]
+		txHashes := matchingTotal.TransactionHashes()
+
+		matchedCount++
+		summary.Matched = append(summary.Matched, &report.MatchedTransaction{
+			Transaction: report.NewTransaction(unclearedTransaction),
+			Transfer:    report.NewDailyTotal(matchingTotal, tokenDetails.Decimals),
+		})
+
+		slog.DebugContext(
+			ctx,
+			fmt.Sprintf(
+				"Matched transaction of %s on %s to the daily total of %d transfers",
+				unclearedTransaction.GetFormattedAmount(),
+				unclearedTransaction.Date.Format(time.DateOnly),
+				len(matchingTotal.Transfers),
+			),
+		)
+
+		pending = planClearing(ctx, pending, &pendingClearing{
+			update:      client.NewClearingUpdate(unclearedTransaction, txHashes, ""),
+			txHashes:    txHashes,
+			transaction: unclearedTransaction,
+			matchedTransfer: fmt.Sprintf(
+				"the total of %s %s from %d transfers on %s",
+				matchingTotal.FormatAmount(tokenDetails.Decimals),
+				tokenDetails.Name,
+				len(matchingTotal.Transfers),
+				matchingTotal.Date.Format(time.DateOnly),
+			),
+		}, args)
+
+		for _, xfr := range matchingTotal.Transfers {
+			consumedTransfers[xfr] = struct{}{}
 		}
+
+		// Remove the matched total to prevent duplicate matches.
+		remainingTotals = slices.DeleteFunc(remainingTotals, func(total *transaction.DailyTotal) bool {
+			return total == matchingTotal
+		})
 	}
 
+	pending = confirmClearings(ctx, prompter, pending, args)
+	clearMatchedTransactions(ctx, httpClient, accessToken, budgetID, pending, ignoreList, args)
+
 	slog.InfoContext(
 		ctx,
-		fmt.Sprintf("Matched %d transactions", matchedCount),
+		fmt.Sprintf("Matched %d transactions to daily totals", matchedCount),
 	)
 
 	if unmatchedCount > 0 {
 		slog.InfoContext(
 			ctx,
 			fmt.Sprintf(
-				"Unable to match %d transactions; these may need to be manually matched or your CSV import may be out-of-date",
+				"Unable to match %d transactions to daily totals; these may need to be manually matched",
 				unmatchedCount,
 			),
 		)
 	}
 
-	return remainingTransfers, nil
+	return slices.DeleteFunc(slices.Clone(transfers), func(xfr *transaction.Transfer) bool {
+		_, consumed := consumedTransfers[xfr]
+
+		return consumed
+	})
+}
+
+// resolveMatch finds the transfer to which the given uncleared transaction is to be matched.
+// If --match-refunds was given and no single transfer matches, it instead looks for a transfer
+// that, less a partial refund, matches the transaction, returning it as the refunded transfer.
+func resolveMatch(
+	ctx context.Context,
+	prompter prompt.Prompter,
+	unclearedTransaction *client.Transaction,
+	wallets *transaction.Wallets,
+	tokenDetails *token.Details,
+	transfers []*transaction.Transfer,
+	addressBook *addressbook.AddressBook,
+	args *arguments,
+) (*transaction.Transfer, *transfer.RefundedTransfer, error) {
+	matchingTransfer, err := resolveMatchingTransfer(
+		ctx,
+		prompter,
+		unclearedTransaction,
+		wallets,
+		tokenDetails,
+		transfers,
+		addressBook,
+		args,
+	)
+	if err != nil || matchingTransfer != nil || !args.matchRefunds {
+		return matchingTransfer, nil, err
+	}
+
+	candidates := transfer.MatchRefundedTransfers(
+		unclearedTransaction,
+		wallets,
+		tokenDetails,
+		transfers,
+		getMatchOptions(args)...,
+	)
+	if len(candidates) == 0 {
+		return nil, nil, nil
+	}
+
+	if args.autoConfirm {
+		return nil, chooseRefundedTransferAutomatically(
+			ctx,
+			unclearedTransaction,
+			candidates,
+			args.accountType,
+		), nil
+	}
+
+	refundedTransfer, err := transfer.ChooseRefundedTransfer(
+		ctx,
+		prompter,
+		unclearedTransaction,
+		args.accountType,
+		tokenDetails,
+		candidates,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("refunded transfer selection failed: %w", err)
+	}
+
+	return nil, refundedTransfer, nil
+}
+
+// chooseRefundedTransferAutomatically chooses, without prompting, the partially-refunded transfer
+// to which the given transaction is to be matched: the only candidate, if there is just one.
+// Should there be several, none is chosen, and the transaction is left unmatched.
+func chooseRefundedTransferAutomatically(
+	ctx context.Context,
+	unclearedTransaction *client.Transaction,
+	candidates []*transfer.RefundedTransfer,
+	accountType transaction.AccountType,
+) *transfer.RefundedTransfer {
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	slog.WarnContext(
+		ctx,
+		fmt.Sprintf(
+			"%d partially-refunded transfers match the transaction of %s %s %s on %s; "+
+				"leaving it unmatched",
+			len(candidates),
+			unclearedTransaction.GetFormattedAmount(),
+			directionOf(unclearedTransaction, accountType),
+			unclearedTransaction.Payee,
+			unclearedTransaction.Date.Format(time.DateOnly),
+		),
+	)
+
+	return nil
 }
 
 // resolveMatchingTransfer finds a matching transfer for the given uncleared transaction.
@@ -712,25 +3301,52 @@ func processUnclearedTransactions(
 // If no matching transfers are found, it logs the absence and returns nil.
 func resolveMatchingTransfer(
 	ctx context.Context,
+	prompter prompt.Prompter,
 	unclearedTransaction *client.Transaction,
-	walletAddress string,
+	wallets *transaction.Wallets,
 	tokenDetails *token.Details,
 	transfers []*transaction.Transfer,
+	addressBook *addressbook.AddressBook,
+	args *arguments,
 ) (*transaction.Transfer, error) {
 	matchingTransfers := transfer.MatchTransfers(
 		unclearedTransaction,
-		walletAddress,
+		wallets,
 		tokenDetails,
 		transfers,
+		getMatchOptions(args)...,
 	)
 
+	if args.requirePayeeMatch && len(matchingTransfers) > 0 {
+		matchingTransfers = transfer.FilterByPayee(
+			unclearedTransaction,
+			wallets,
+			addressBook,
+			matchingTransfers,
+		)
+
+		if len(matchingTransfers) == 0 {
+			slog.InfoContext(
+				ctx,
+				fmt.Sprintf(
+					"No transfer of %s %s %s has a counterparty matching the payee; leaving it unmatched",
+					unclearedTransaction.GetFormattedAmount(),
+					directionOf(unclearedTransaction, args.accountType),
+					unclearedTransaction.Payee,
+				),
+			)
+
+			return nil, nil
+		}
+	}
+
 	if len(matchingTransfers) == 0 {
 		slog.InfoContext(
 			ctx,
 			fmt.Sprintf(
 				"No matching transfer of %s %s %s found",
 				unclearedTransaction.GetFormattedAmount(),
-				transaction.ResolveDirection(unclearedTransaction.IsOutbound()),
+				directionOf(unclearedTransaction, args.accountType),
 				unclearedTransaction.Payee,
 			),
 		)
@@ -742,12 +3358,28 @@ func resolveMatchingTransfer(
 		return matchingTransfers[0], nil
 	}
 
+	if args.autoConfirm {
+		slog.WarnContext(
+			ctx,
+			fmt.Sprintf(
+				"%d transfers match the transaction of %s %s %s on %s; leaving it unmatched",
+				len(matchingTransfers),
+				unclearedTransaction.GetFormattedAmount(),
+				directionOf(unclearedTransaction, args.accountType),
+				unclearedTransaction.Payee,
+				unclearedTransaction.Date.Format(time.DateOnly),
+			),
+		)
+
+		return nil, nil
+	}
+
 	var matchingTransfer *transaction.Transfer
 	if len(matchingTransfers) > 1 {
 		promptText := fmt.Sprintf(
 			"Multiple transfers matched the transfer of %s %s %s with memo '%s' on %s; please select the correct one",
 			unclearedTransaction.GetFormattedAmount(),
-			transaction.ResolveDirection(unclearedTransaction.IsOutbound()),
+			directionOf(unclearedTransaction, args.accountType),
 			unclearedTransaction.Payee,
 			unclearedTransaction.Description,
 			unclearedTransaction.Date.Format(time.DateOnly),
@@ -756,10 +3388,12 @@ func resolveMatchingTransfer(
 		var err error
 		matchingTransfer, err = chooseTransfer(
 			ctx,
+			prompter,
 			tokenDetails,
 			matchingTransfers,
-			walletAddress,
+			wallets,
 			promptText,
+			args.hashPrefixMatch,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("transfer selection failed: %w", err)
@@ -771,7 +3405,7 @@ func resolveMatchingTransfer(
 	promptText := fmt.Sprintf(
 		"No transfers matched the transfer of %s %s %s with memo '%s' on %s; please select one from the list of imported transfers",
 		unclearedTransaction.GetFormattedAmount(),
-		transaction.ResolveDirection(unclearedTransaction.IsOutbound()),
+		directionOf(unclearedTransaction, args.accountType),
 		unclearedTransaction.Payee,
 		unclearedTransaction.Description,
 		unclearedTransaction.Date.Format(time.DateOnly),
@@ -780,10 +3414,12 @@ func resolveMatchingTransfer(
 	var err error
 	matchingTransfer, err = chooseTransfer(
 		ctx,
+		prompter,
 		tokenDetails,
 		transfers,
-		walletAddress,
+		wallets,
 		promptText,
+		args.hashPrefixMatch,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("transfer selection failed: %w", err)
@@ -792,49 +3428,404 @@ func resolveMatchingTransfer(
 	return matchingTransfer, nil
 }
 
-func handleMatchedTransaction(
+// directionOf describes the direction of the given YNAB transaction, e.g., "to" a payee,
+// in an account of the given type.
+func directionOf(txn *client.Transaction, accountType transaction.AccountType) string {
+	return transaction.ResolveDirection(accountType.IsOutbound(txn.Amount))
+}
+
+// pendingClearing is a matched transaction waiting to be cleared,
+// along with the hashes of the transactions to which it was matched.
+type pendingClearing struct {
+	update          client.TransactionUpdate
+	txHashes        []string
+	transaction     *client.Transaction // the matched transaction, as it was before clearing
+	matchedTransfer string              // a description of what the transaction was matched to
+}
+
+// planClearing adds the given clearing to those to be made once matching is done or,
+// in a dry run, logs it instead.
+func planClearing(
 	ctx context.Context,
-	httpClient *http.Client,
-	accessToken, budgetID, transactionID, txHash string,
-) error {
-	if err := client.MarkTransactionClearedAndAppendMemo(ctx, httpClient, accessToken, budgetID, transactionID, txHash); err != nil {
-		return fmt.Errorf("failed to update transaction %s: %w", transactionID, err)
+	pending []*pendingClearing,
+	clearing *pendingClearing,
+	args *arguments,
+) []*pendingClearing {
+	if args.dryRun {
+		slog.InfoContext(
+			ctx,
+			fmt.Sprintf(
+				"Dry run: would mark transaction ID %s as cleared and append transaction hashes %s to its memo",
+				clearing.update.ID,
+				strings.Join(clearing.txHashes, ", "),
+			),
+		)
+
+		return pending
 	}
 
-	return nil
+	return append(pending, clearing)
 }
 
-// writeIgnoreList writes the ignore list to the ignore list file.
-func writeIgnoreList(
+// confirmClearings asks, if --confirm-each-clear was given, whether each of the given matched
+// transactions is to be cleared, returning those that are.
+// Should a prompt be canceled, none of the remaining transactions are cleared.
+func confirmClearings(
+	ctx context.Context,
+	prompter prompt.Prompter,
+	pending []*pendingClearing,
+	args *arguments,
+) []*pendingClearing {
+	if !args.confirmEachClear || args.autoConfirm {
+		return pending
+	}
+
+	confirmed := make([]*pendingClearing, 0, len(pending))
+	for i, p := range pending {
+		ok, err := transfer.ConfirmClearing(
+			ctx,
+			prompter,
+			p.transaction,
+			args.accountType,
+			p.matchedTransfer,
+		)
+		if err != nil {
+			slog.WarnContext(
+				ctx,
+				fmt.Sprintf(
+					"Leaving the %d remaining matched transactions uncleared",
+					len(pending)-i,
+				),
+				"error",
+				err,
+			)
+
+			break
+		}
+
+		if !ok {
+			slog.InfoContext(
+				ctx,
+				fmt.Sprintf("Leaving transaction ID %s uncleared", p.update.ID),
+			)
+
+			continue
+		}
+
+		confirmed = append(confirmed, p)
+	}
+
+	return confirmed
+}
+
+// clearMatchedTransactions clears the given matched transactions and annotates their memos
+// in a single bulk update, then records the transaction hashes of those that YNAB cleared
+// as processed. If the update fails, nothing is recorded, so that they are matched again next run.
+func clearMatchedTransactions(
+	ctx context.Context,
+	httpClient ctshttp.Doer,
+	accessToken string,
+	budgetID string,
+	pending []*pendingClearing,
 	ignoreList *transaction.IgnoreList,
-) error {
-	ignoreFileExists, err := ctsio.FileExists(ignoreListFilename)
+	args *arguments,
+) {
+	if len(pending) == 0 {
+		return
+	}
+
+	updates := make([]client.TransactionUpdate, 0, len(pending))
+	for _, p := range pending {
+		updates = append(updates, p.update)
+	}
+
+	updated, err := client.UpdateTransactions(ctx, httpClient, accessToken, budgetID, updates)
 	if err != nil {
-		return fmt.Errorf("failed to check for ignore list file: %w", err)
+		slog.ErrorContext(
+			ctx,
+			fmt.Sprintf("Failed to mark %d matched transactions as cleared", len(pending)),
+			"error",
+			err,
+		)
+
+		return
 	}
 
-	// Schedule the writing of all ignored entries
-	var writeHandle *os.File
-	if !ignoreFileExists {
-		writeHandle, err = os.Create(ignoreListFilename)
-		if err != nil {
-			return fmt.Errorf("failed to create ignore list file: %w", err)
+	updatedIDs := make(map[string]bool, len(updated))
+	for _, txn := range updated {
+		updatedIDs[txn.ID] = true
+	}
+
+	for _, p := range pending {
+		if !updatedIDs[p.update.ID] {
+			slog.ErrorContext(
+				ctx,
+				fmt.Sprintf("YNAB did not mark transaction ID %s as cleared", p.update.ID),
+			)
+
+			continue
 		}
-	} else {
-		//nolint:gosec,mnd // no need to keep this at 600 or less
-		writeHandle, err = os.OpenFile(ignoreListFilename, os.O_WRONLY|os.O_TRUNC, 0o644)
+
+		for _, txHash := range p.txHashes {
+			ignoreList.AddProcessedHash(txHash, p.update.ID)
+		}
+	}
+
+	slog.InfoContext(
+		ctx,
+		fmt.Sprintf("Marked %d matched transactions as cleared", len(updated)),
+	)
+
+	if args.printLinks {
+		for _, txn := range updated {
+			printTransactionLink(budgetID, txn.ID, "Cleared")
+		}
+	}
+
+	flushIgnoreList(ctx, ignoreList, args)
+}
+
+// runSelfTest runs the self-test, printing the outcome of each of its checks,
+// and returns the exit code of the run.
+func runSelfTest(ctx context.Context) int {
+	if err := selftest.Run(ctx, os.Stdout); err != nil {
+		slog.ErrorContext(ctx, "Self-test failed", "error", err)
+
+		return exitCodeSelfTestFailed
+	}
+
+	slog.InfoContext(ctx, "Self-test passed")
+
+	return 0
+}
+
+// logRunFailure logs the given failure of a run and returns the code with which to exit.
+// A rejected YNAB access token is reported as such, rather than as the failure of whichever
+// request happened to be made first, and is the only failure that yields a non-zero exit code.
+func logRunFailure(ctx context.Context, message string, err error) int {
+	if errors.Is(err, client.ErrUnauthorized) {
+		slog.ErrorContext(
+			ctx,
+			"YNAB access token is invalid or expired; provide a valid one with --ynab-access-token",
+			"error",
+			err,
+		)
+
+		return exitCodeUnauthorized
+	}
+
+	slog.ErrorContext(ctx, message, "error", err)
+
+	return 0
+}
+
+// logRunSummary logs how long each timed phase of the run took or, if compact output was requested,
+// prints the counts of the summary on a single line.
+// At the end of a dry run, a table of what the run would have done is printed first.
+func logRunSummary(ctx context.Context, summary *report.RunSummary, compact bool, dryRun bool) {
+	if dryRun {
+		if err := report.WriteDryRunSummary(summary, os.Stdout); err != nil {
+			slog.ErrorContext(ctx, "Failed to write dry run summary", "error", err)
+		}
+	}
+
+	if compact {
+		if err := report.WriteCompact(summary, os.Stdout); err != nil {
+			slog.ErrorContext(ctx, "Failed to write compact summary", "error", err)
+		}
+
+		return
+	}
+
+	for _, phase := range summary.PhaseDurations {
+		slog.InfoContext(ctx, fmt.Sprintf("Phase '%s' took %s", phase.Name, phase.Duration))
+	}
+}
+
+// writeReports writes the given summary of a run as each of the reports that were requested.
+func writeReports(ctx context.Context, summary *report.RunSummary, args *arguments) {
+	if err := writeMarkdownReport(summary, args, time.Now()); err != nil {
+		slog.ErrorContext(ctx, "Failed to write Markdown report", "error", err)
+	}
+
+	if err := writeJSONReport(summary, args.reportPath, args.dryRun); err != nil {
+		slog.ErrorContext(ctx, "Failed to write JSON report", "error", err)
+	}
+
+	if err := writeUnmatchedCSV(summary, args.unmatchedOutPath); err != nil {
+		slog.ErrorContext(ctx, "Failed to write unmatched transactions", "error", err)
+	}
+}
+
+// writeMarkdownReport writes the given summary as a Markdown report, if a report path was requested.
+// With --report-markdown-append, the report is appended to the file, marked with the given time
+// of the run, and the title of the report is only written to a new or empty file.
+func writeMarkdownReport(summary *report.RunSummary, args *arguments, runTime time.Time) error {
+	if args.reportMarkdownPath == "" {
+		return nil
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if args.appendMarkdown {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	//nolint:gosec,mnd // no need to keep this at 600 or less
+	file, err := os.OpenFile(args.reportMarkdownPath, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open Markdown report file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var opts []report.MarkdownOption
+	if args.appendMarkdown {
+		info, err := file.Stat()
 		if err != nil {
-			return fmt.Errorf("failed to open file for writing ignore list: %w", err)
+			return fmt.Errorf("failed to check the size of the Markdown report file: %w", err)
+		}
+
+		if info.Size() > 0 {
+			opts = append(opts, report.WithoutTitle())
 		}
+
+		opts = append(opts, report.WithRunMarker(runTime))
 	}
-	defer func() {
-		_ = writeHandle.Close()
-	}()
 
-	err = transaction.ToYAML(ignoreList, writeHandle)
+	if err := report.WriteMarkdown(summary, file, opts...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeJSONReport writes the given summary as a JSON report, if a report path was requested.
+func writeJSONReport(summary *report.RunSummary, reportPath string, dryRun bool) error {
+	if reportPath == "" {
+		return nil
+	}
+
+	file, err := os.Create(reportPath) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("failed to create JSON report file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := report.WriteJSON(report.NewJSONReport(summary, dryRun), file); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeUnmatchedCSV writes the unmatched transactions of the given summary as CSV,
+// if an output path was requested.
+func writeUnmatchedCSV(summary *report.RunSummary, outPath string) error {
+	if outPath == "" {
+		return nil
+	}
+
+	file, err := os.Create(outPath) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("failed to create unmatched transactions file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := report.WriteUnmatchedCSV(summary, file); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isJSONIgnoreList determines whether the ignore list file at the given path is written as JSON,
+// as it is if its extension is ".json", rather than as YAML.
+func isJSONIgnoreList(ignoreListPath string) bool {
+	return strings.EqualFold(filepath.Ext(ignoreListPath), ".json")
+}
+
+// resetIgnoreList starts a new, empty ignore list in place of the ignore list file, which failed
+// to be parsed with the given error, first backing up the file unless running in read-only mode.
+func resetIgnoreList(
+	ctx context.Context,
+	args *arguments,
+	parseErr error,
+) (*transaction.IgnoreList, error) {
+	if args.readOnly {
+		slog.WarnContext(
+			ctx,
+			"Ignore list file is corrupt; starting with an empty ignore list without backing it up",
+			"error",
+			parseErr,
+		)
+
+		return transaction.NewIgnoreList(), nil
+	}
+
+	backupPath, err := ctsio.BackUpFile(args.ignoreListPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up corrupt ignore list file: %w", err)
+	}
+
+	slog.WarnContext(
+		ctx,
+		fmt.Sprintf(
+			"Ignore list file is corrupt; backed it up to '%s' and started an empty ignore list",
+			backupPath,
+		),
+		"error",
+		parseErr,
+	)
+
+	return transaction.NewIgnoreList(), nil
+}
+
+// writeIgnoreList writes the ignore list to the ignore list file, unless running in read-only mode.
+func writeIgnoreList(
+	ignoreList *transaction.IgnoreList,
+	args *arguments,
+) error {
+	if args.readOnly {
+		return nil
+	}
+
+	var yamlOptions []transaction.YAMLOption
+	if args.groupIgnoredReason {
+		yamlOptions = append(yamlOptions, transaction.WithGroupedReasons())
+	}
+
+	encode, format := transaction.ToYAML, "YAML"
+	if isJSONIgnoreList(args.ignoreListPath) {
+		encode, format = transaction.ToJSON, "JSON"
+	}
+
+	//nolint:mnd // no need to keep this at 600 or less
+	err := ctsio.WriteFileAtomically(args.ignoreListPath, 0o644, func(writer io.Writer) error {
+		if err := encode(ignoreList, writer, yamlOptions...); err != nil {
+			return fmt.Errorf("failed to write ignore list to %s: %w", format, err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to write ignore list to YAML: %w", err)
+		return fmt.Errorf("failed to write ignore list file: %w", err)
 	}
 
 	return nil
 }
+
+// flushIgnoreList writes the ignore list immediately, so that the transfers processed so far
+// are not processed again should the run fail before the ignore list is written at its end.
+func flushIgnoreList(
+	ctx context.Context,
+	ignoreList *transaction.IgnoreList,
+	args *arguments,
+) {
+	if err := writeIgnoreList(ignoreList, args); err != nil {
+		slog.WarnContext(
+			ctx,
+			"Failed to write ignore list; it will be written again at the end of the run",
+			"error",
+			err,
+		)
+	}
+}
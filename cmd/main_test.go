@@ -0,0 +1,466 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/prompt"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction/rpcsource"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("logRunFailure", func() {
+	var logOutput *bytes.Buffer
+
+	BeforeEach(func() {
+		logOutput = &bytes.Buffer{}
+
+		defaultLogger := slog.Default()
+		slog.SetDefault(slog.New(slog.NewTextHandler(logOutput, nil)))
+		DeferCleanup(func() {
+			slog.SetDefault(defaultLogger)
+		})
+	})
+
+	It("reports a rejected YNAB access token and exits non-zero", func() {
+		err := fmt.Errorf("failed to select an account: %w", client.ErrUnauthorized)
+
+		exitCode := logRunFailure(context.Background(), "Synchronization failed", err)
+		Expect(exitCode).To(Equal(exitCodeUnauthorized))
+		Expect(exitCode).ToNot(BeZero())
+		Expect(logOutput.String()).To(ContainSubstring(
+			"YNAB access token is invalid or expired; provide a valid one with --ynab-access-token",
+		))
+		Expect(logOutput.String()).ToNot(ContainSubstring("Synchronization failed"))
+	})
+
+	It("reports any other failure as given and exits with zero", func() {
+		exitCode := logRunFailure(
+			context.Background(),
+			"Synchronization failed",
+			errors.New("network unreachable"),
+		)
+		Expect(exitCode).To(BeZero())
+		Expect(logOutput.String()).To(ContainSubstring("Synchronization failed"))
+		Expect(logOutput.String()).To(ContainSubstring("network unreachable"))
+	})
+})
+
+var _ = Describe("parseArgs", func() {
+	DescribeTable("validating the block range of --rpc-transfers",
+		func(args []string, expectedError string) {
+			_, err := parseArgs(args)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+
+				return
+			}
+
+			Expect(err).To(MatchError(ContainSubstring(expectedError)))
+		},
+		Entry("a block range", []string{"--rpc-transfers", "--from-block=10", "--to-block=20"}, ""),
+		Entry("no last block", []string{"--rpc-transfers", "--from-block=10"}, ""),
+		Entry("no first block", []string{"--rpc-transfers"}, "--from-block is required"),
+		Entry(
+			"a reversed block range",
+			[]string{"--rpc-transfers", "--from-block=20", "--to-block=10"},
+			"--from-block 20 is after --to-block 10",
+		),
+		Entry(
+			"a block range without --rpc-transfers",
+			[]string{"--from-block=10"},
+			"can only be used with --rpc-transfers",
+		),
+		Entry(
+			"Etherscan as well",
+			[]string{"--rpc-transfers", "--from-block=10", "--etherscan-api-key=key"},
+			"cannot be used together",
+		),
+	)
+})
+
+var _ = Describe("getRPCTransfers", func() {
+	const (
+		rpcURL       = "http://rpc.example.local"
+		tokenAddress = "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913"
+		walletA      = "0x9134fc7112b478e97ee6f0e6a7bf81ecafef19ed"
+		walletB      = "0xcf6cece7bad73e40e033f8fa6b52c712263d1d68"
+		txHash       = "0xb4113e6ccf31511d5907a2dc41826948c8f80212d176ce66868d736e43212bd1"
+	)
+
+	var (
+		args         *arguments
+		summary      *report.RunSummary
+		getLogsCalls []map[string]any
+	)
+
+	// a transfer of 4,157.06 USDC from wallet A to another address, in block 0x259204a
+	transferLog := map[string]any{
+		"address": tokenAddress,
+		"topics": []string{
+			rpcsource.TransferEventTopic,
+			"0x000000000000000000000000" + walletA[2:],
+			"0x0000000000000000000000000000000000000000000000000000000000000001",
+		},
+		"data":            "0x00000000000000000000000000000000000000000000000000000000f7c7b3a0",
+		"blockNumber":     "0x259204a",
+		"transactionHash": txHash,
+		"logIndex":        "0x5",
+		"removed":         false,
+	}
+
+	BeforeEach(func() {
+		args = &arguments{
+			rpcURL:       rpcURL,
+			rpcTransfers: true,
+			fromBlock:    0x2592000,
+		}
+		summary = &report.RunSummary{}
+		getLogsCalls = nil
+
+		httpmock.RegisterResponder(
+			http.MethodPost,
+			rpcURL,
+			func(req *http.Request) (*http.Response, error) {
+				var payload struct {
+					ID     any    `json:"id"`
+					Method string `json:"method"`
+					Params []any  `json:"params"`
+				}
+				Expect(json.NewDecoder(req.Body).Decode(&payload)).To(Succeed())
+
+				var result any
+				switch payload.Method {
+				case "eth_blockNumber":
+					result = "0x2592100"
+				case "eth_getLogs":
+					filter, ok := payload.Params[0].(map[string]any)
+					Expect(ok).To(BeTrue())
+					getLogsCalls = append(getLogsCalls, filter)
+
+					// only the query of wallet A as the sender finds the transfer
+					logs := []any{}
+					topics, _ := filter["topics"].([]any)
+					if len(topics) == 2 && topics[1] == transferLog["topics"].([]string)[1] {
+						logs = append(logs, transferLog)
+					}
+
+					result = logs
+				case "eth_getBlockByNumber":
+					result = map[string]any{"timestamp": "0x69395f33"}
+				default:
+					Fail("unexpected method: " + payload.Method)
+				}
+
+				return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+					"jsonrpc": "2.0",
+					"id":      payload.ID,
+					"result":  result,
+				})
+			},
+		)
+		DeferCleanup(httpmock.Reset)
+	})
+
+	It("reads the transfers of every wallet up to the latest block", func() {
+		transfers, err := getRPCTransfers(
+			context.Background(),
+			tokenAddress,
+			transaction.NewWallets(walletA, walletB),
+			&token.Details{Name: "USDC", Decimals: 6},
+			summary,
+			args,
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(transfers[0].TransactionHash).To(Equal(txHash))
+		Expect(transfers[0].FromAddress).To(Equal(walletA))
+		Expect(transfers[0].Amount).To(Equal(big.NewInt(4157060000)))
+		Expect(transfers[0].ExecutionTime).To(Equal(time.Unix(0x69395f33, 0).UTC()))
+
+		// each wallet is searched as the sender and as the recipient
+		Expect(getLogsCalls).To(HaveLen(4))
+		for _, filter := range getLogsCalls {
+			Expect(filter).To(HaveKeyWithValue("fromBlock", "0x2592000"))
+			Expect(filter).To(HaveKeyWithValue("toBlock", "0x2592100"))
+		}
+	})
+
+	It("stops at the given last block", func() {
+		args.toBlock = 0x2592050
+
+		_, err := getRPCTransfers(
+			context.Background(),
+			tokenAddress,
+			transaction.NewWallets(walletA),
+			&token.Details{Name: "USDC", Decimals: 6},
+			summary,
+			args,
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getLogsCalls).ToNot(BeEmpty())
+		for _, filter := range getLogsCalls {
+			Expect(filter).To(HaveKeyWithValue("toBlock", "0x2592050"))
+		}
+	})
+
+	It("requires an RPC node", func() {
+		args.rpcURL = ""
+
+		_, err := getRPCTransfers(
+			context.Background(),
+			tokenAddress,
+			transaction.NewWallets(walletA),
+			&token.Details{Name: "USDC", Decimals: 6},
+			summary,
+			args,
+		)
+		Expect(err).To(MatchError(ContainSubstring("--rpc-transfers requires --rpc-url")))
+	})
+})
+
+var _ = Describe("writeMarkdownReport", func() {
+	var (
+		args       *arguments
+		summary    *report.RunSummary
+		reportPath string
+	)
+
+	firstRun := time.Date(2025, time.December, 10, 9, 0, 0, 0, time.UTC)
+	secondRun := time.Date(2025, time.December, 11, 9, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		reportPath = filepath.Join(GinkgoT().TempDir(), "report.md")
+		args = &arguments{reportMarkdownPath: reportPath}
+		summary = &report.RunSummary{TokenName: "USDC", ParsedTransferCount: 3}
+	})
+
+	readReport := func() string {
+		contents, err := os.ReadFile(reportPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		return string(contents)
+	}
+
+	It("replaces the report of an earlier run", func() {
+		Expect(writeMarkdownReport(summary, args, firstRun)).To(Succeed())
+		Expect(writeMarkdownReport(summary, args, secondRun)).To(Succeed())
+
+		output := readReport()
+		Expect(strings.Count(output, "# Synchronization Report\n")).To(Equal(1))
+		Expect(strings.Count(output, "## Totals\n")).To(Equal(1))
+		Expect(output).ToNot(ContainSubstring("**Run at"))
+	})
+
+	When("appending", func() {
+		BeforeEach(func() {
+			args.appendMarkdown = true
+		})
+
+		It("writes the title once and marks the start of each run", func() {
+			Expect(writeMarkdownReport(summary, args, firstRun)).To(Succeed())
+			Expect(writeMarkdownReport(summary, args, secondRun)).To(Succeed())
+
+			output := readReport()
+			Expect(strings.Count(output, "# Synchronization Report\n")).To(Equal(1))
+			Expect(output).To(HavePrefix(
+				"# Synchronization Report\n\n---\n\n**Run at 2025-12-10T09:00:00Z**\n\n## Totals\n",
+			))
+			Expect(strings.Count(output, "## Totals\n")).To(Equal(2))
+			Expect(output).To(ContainSubstring(
+				"_None_\n\n---\n\n**Run at 2025-12-11T09:00:00Z**\n\n## Totals\n",
+			))
+		})
+
+		It("writes the title to an empty file", func() {
+			Expect(os.WriteFile(reportPath, nil, 0o600)).To(Succeed())
+
+			Expect(writeMarkdownReport(summary, args, firstRun)).To(Succeed())
+			Expect(readReport()).To(HavePrefix("# Synchronization Report\n\n---\n"))
+		})
+	})
+})
+
+var _ = Describe("clearMatchedTransactions", func() {
+	const updateURL = "https://api.ynab.com/v1/budgets/budget1/transactions"
+
+	var ignoreList *transaction.IgnoreList
+	var args *arguments
+	var pending []*pendingClearing
+
+	BeforeEach(func() {
+		ignoreList = transaction.NewIgnoreList()
+		args = &arguments{
+			ignoreListPath: filepath.Join(GinkgoT().TempDir(), "transaction_hash.ignorelist"),
+		}
+
+		pending = []*pendingClearing{
+			{update: client.TransactionUpdate{ID: "tx1"}, txHashes: []string{"0xhash1"}},
+			{update: client.TransactionUpdate{ID: "tx2"}, txHashes: []string{"0xhash2", "0xhash3"}},
+		}
+
+		DeferCleanup(httpmock.Reset)
+	})
+
+	clearMatched := func() {
+		clearMatchedTransactions(
+			context.Background(),
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			pending,
+			ignoreList,
+			args,
+		)
+	}
+
+	It("records the hashes of only the transactions YNAB cleared", func() {
+		httpmock.RegisterResponder(
+			"PATCH",
+			updateURL,
+			httpmock.NewStringResponder(
+				http.StatusOK,
+				`{"data":{"transaction_ids":["tx2"],`+
+					`"transactions":[{"id":"tx2","amount":1000,"date":"2025-12-01"}]}}`,
+			),
+		)
+
+		clearMatched()
+
+		Expect(ignoreList.IsHashIgnored("0xhash1")).To(BeFalse())
+		Expect(ignoreList.IsHashIgnored("0xhash2")).To(BeTrue())
+		Expect(ignoreList.IsHashIgnored("0xhash3")).To(BeTrue())
+		Expect(args.ignoreListPath).To(BeAnExistingFile())
+	})
+
+	It("records nothing when the bulk update fails", func() {
+		httpmock.RegisterResponder(
+			"PATCH",
+			updateURL,
+			httpmock.NewStringResponder(http.StatusBadRequest, `{"error":{}}`),
+		)
+
+		clearMatched()
+
+		for _, txHash := range []string{"0xhash1", "0xhash2", "0xhash3"} {
+			Expect(ignoreList.IsHashIgnored(txHash)).To(BeFalse(), txHash)
+		}
+
+		Expect(args.ignoreListPath).ToNot(BeAnExistingFile())
+	})
+})
+
+var _ = Describe("chooseAccount", func() {
+	var ctx context.Context
+	var budget *client.Budget
+	var accounts []*client.Account
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		budget = &client.Budget{ID: "budget1", Name: "Household"}
+		accounts = []*client.Account{
+			{ID: "acct1", Name: "Checking"},
+			{ID: "acct2", Name: "Crypto Wallet"},
+		}
+	})
+
+	It("returns the account selected in place of the missing one", func() {
+		prompter := &scriptedPrompter{answers: []scriptedAnswer{{index: 1}}}
+
+		accountID, err := chooseAccount(ctx, prompter, budget, accounts, "Wallet")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(accountID).To(Equal("acct2"))
+		Expect(prompter.labels).To(Equal([]string{
+			"Account 'Wallet' was not found; select a YNAB account",
+		}))
+		Expect(prompter.selectItems).To(Equal([][]string{{"Checking", "Crypto Wallet"}}))
+	})
+
+	It("fails without prompting when the budget has no accounts", func() {
+		prompter := &scriptedPrompter{}
+
+		_, err := chooseAccount(ctx, prompter, budget, nil, "Wallet")
+		Expect(err).To(MatchError(
+			"account 'Wallet' not found in budget 'Household' among available choices: ",
+		))
+		Expect(prompter.labels).To(BeEmpty())
+	})
+
+	It("fails as not found when the prompt fails", func() {
+		prompter := &scriptedPrompter{answers: []scriptedAnswer{
+			{err: errors.New("terminal unavailable")},
+		}}
+
+		_, err := chooseAccount(ctx, prompter, budget, accounts, "Wallet")
+		Expect(err).To(MatchError(
+			"account 'Wallet' not found in budget 'Household' among available choices: " +
+				"Checking, Crypto Wallet",
+		))
+	})
+
+	It("fails when the selection is canceled", func() {
+		prompter := &scriptedPrompter{answers: []scriptedAnswer{{err: prompt.ErrInterrupt}}}
+
+		_, err := chooseAccount(ctx, prompter, budget, accounts, "Wallet")
+		Expect(err).To(MatchError("account selection canceled"))
+	})
+})
+
+// scriptedPrompter answers prompts with the given answers, in order.
+type scriptedPrompter struct {
+	answers []scriptedAnswer
+	labels  []string // the labels of all prompts shown, in order
+
+	selectItems [][]string // the items offered by all select prompts shown, in order
+}
+
+type scriptedAnswer struct {
+	index int
+	text  string
+	err   error
+}
+
+func (s *scriptedPrompter) Select(label string, items []string) (int, error) {
+	s.selectItems = append(s.selectItems, items)
+	answer := s.next(label)
+
+	return answer.index, answer.err
+}
+
+func (s *scriptedPrompter) Input(label string, _ string) (string, error) {
+	answer := s.next(label)
+
+	return answer.text, answer.err
+}
+
+func (s *scriptedPrompter) MultiSelect(label string, _ []string) ([]int, error) {
+	Fail("unexpected multi-select prompt: " + label)
+
+	return nil, nil
+}
+
+func (s *scriptedPrompter) next(label string) scriptedAnswer {
+	s.labels = append(s.labels, label)
+	ExpectWithOffset(2, s.answers).ToNot(BeEmpty(), "unexpected prompt: %s", label)
+
+	answer := s.answers[0]
+	s.answers = s.answers[1:]
+
+	return answer
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCmd(t *testing.T) {
+	t.Parallel()
+
+	BeforeSuite(func() {
+		httpmock.Activate()
+	})
+
+	AfterSuite(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cmd Suite")
+}
@@ -0,0 +1,113 @@
+package addressbook
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// AddressBook maps onchain addresses to human-readable labels, such as the names of YNAB payees.
+type AddressBook struct {
+	entries map[string]*Entry // the entries of the address book, keyed by lowercase address
+}
+
+// Entry describes a single address in an address book.
+type Entry struct {
+	Address    string // the address, encoded in hex
+	Label      string // the human-readable label of the address
+	CategoryID string // the ID of the YNAB category of transactions with the address, if any
+}
+
+// NewAddressBook creates an empty address book.
+func NewAddressBook() *AddressBook {
+	return &AddressBook{
+		entries: make(map[string]*Entry),
+	}
+}
+
+// Add adds the given entry to the address book, replacing any existing entry for the same address.
+func (a *AddressBook) Add(entry *Entry) {
+	a.entries[strings.ToLower(entry.Address)] = entry
+}
+
+// Lookup returns the entry for the given address, compared ignoring case, or nil if there is none.
+func (a *AddressBook) Lookup(address string) *Entry {
+	return a.entries[strings.ToLower(address)]
+}
+
+// ResolveCategoryID returns the ID of the YNAB category of transactions with the given address,
+// or an empty string if the address is not in the address book or has no category.
+func (a *AddressBook) ResolveCategoryID(address string) string {
+	if entry := a.Lookup(address); entry != nil {
+		return entry.CategoryID
+	}
+
+	return ""
+}
+
+// ResolveLabel returns the label of the given address,
+// or the address itself if it is not in the address book.
+func (a *AddressBook) ResolveLabel(address string) string {
+	if entry := a.Lookup(address); entry != nil && entry.Label != "" {
+		return entry.Label
+	}
+
+	return address
+}
+
+// FromYAML reads an AddressBook from a YAML representation.
+func FromYAML(reader io.Reader) (*AddressBook, error) {
+	var ymlBook yamlAddressBook
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&ymlBook); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode address book from YAML: %w", err)
+	}
+
+	addressBook := NewAddressBook()
+	for index, ymlEntry := range ymlBook.Addresses {
+		if ymlEntry.Address == "" {
+			return nil, fmt.Errorf("address book entry %d has no address", index+1)
+		}
+
+		addressBook.Add(&Entry{
+			Address:    ymlEntry.Address,
+			Label:      ymlEntry.Label,
+			CategoryID: ymlEntry.CategoryID,
+		})
+	}
+
+	return addressBook, nil
+}
+
+// LoadFile reads an AddressBook from the YAML file at the given path.
+func LoadFile(filePath string) (*AddressBook, error) {
+	file, err := os.Open(filePath) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to open address book file '%s': %w", filePath, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	addressBook, err := FromYAML(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read address book file '%s': %w", filePath, err)
+	}
+
+	return addressBook, nil
+}
+
+// yamlAddressBook is an internal struct for YAML serialization.
+type yamlAddressBook struct {
+	Addresses []yamlEntry `yaml:"addresses"`
+}
+
+// yamlEntry is an internal struct for YAML serialization.
+type yamlEntry struct {
+	Address    string `yaml:"address"`               // the address, encoded in hex
+	Label      string `yaml:"label"`                 // the human-readable label of the address
+	CategoryID string `yaml:"category_id,omitempty"` // the ID of the address's YNAB category
+}
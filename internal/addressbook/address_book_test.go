@@ -0,0 +1,84 @@
+package addressbook_test
+
+import (
+	"strings"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/addressbook"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AddressBook", func() {
+	Describe("FromYAML", func() {
+		It("reads each entry", func() {
+			addressBook, err := addressbook.FromYAML(strings.NewReader(`
+addresses:
+  - address: "0xAbC"
+    label: "Landlord"
+  - address: "0xdef"
+    label: "Employer"
+    category_id: "category-income"
+`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(addressBook.Lookup("0xabc")).To(Equal(&addressbook.Entry{
+				Address: "0xAbC",
+				Label:   "Landlord",
+			}))
+			Expect(addressBook.Lookup("0xDEF").Label).To(Equal("Employer"))
+			Expect(addressBook.Lookup("0xdef").CategoryID).To(Equal("category-income"))
+		})
+
+		It("reads an empty file as an empty address book", func() {
+			addressBook, err := addressbook.FromYAML(strings.NewReader(""))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(addressBook.Lookup("0xabc")).To(BeNil())
+		})
+
+		It("rejects an entry without an address", func() {
+			_, err := addressbook.FromYAML(strings.NewReader(`
+addresses:
+  - label: "Landlord"
+`))
+			Expect(err).To(MatchError(ContainSubstring("entry 1 has no address")))
+		})
+	})
+
+	Describe("ResolveCategoryID", func() {
+		var addressBook *addressbook.AddressBook
+
+		BeforeEach(func() {
+			addressBook = addressbook.NewAddressBook()
+			addressBook.Add(&addressbook.Entry{Address: "0xabc", CategoryID: "category-rent"})
+			addressBook.Add(&addressbook.Entry{Address: "0xdef", Label: "Employer"})
+		})
+
+		It("returns the category of a known address", func() {
+			Expect(addressBook.ResolveCategoryID("0xABC")).To(Equal("category-rent"))
+		})
+
+		It("returns nothing for an address without a category", func() {
+			Expect(addressBook.ResolveCategoryID("0xdef")).To(BeEmpty())
+		})
+
+		It("returns nothing for an unknown address", func() {
+			Expect(addressBook.ResolveCategoryID("0x123")).To(BeEmpty())
+		})
+	})
+
+	Describe("ResolveLabel", func() {
+		var addressBook *addressbook.AddressBook
+
+		BeforeEach(func() {
+			addressBook = addressbook.NewAddressBook()
+			addressBook.Add(&addressbook.Entry{Address: "0xabc", Label: "Landlord"})
+		})
+
+		It("returns the label of a known address", func() {
+			Expect(addressBook.ResolveLabel("0xABC")).To(Equal("Landlord"))
+		})
+
+		It("returns an unknown address as-is", func() {
+			Expect(addressBook.ResolveLabel("0xDeF")).To(Equal("0xDeF"))
+		})
+	})
+})
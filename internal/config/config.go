@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Config holds settings for a synchronization run that would otherwise be provided as
+// command-line arguments.
+// Any setting that is left empty is treated as not having been provided.
+type Config struct {
+	WalletAddress   string `yaml:"wallet_address"`    // the comma-separated wallets to match
+	TokenAddress    string `yaml:"token_address"`     // the token contract to sync
+	RPCURL          string `yaml:"rpc_url"`           // the JSON-RPC endpoint for token lookups
+	YNABAccountName string `yaml:"ynab_account_name"` // the YNAB account to sync to
+	YNABAccessToken string `yaml:"ynab_access_token"` // the YNAB Personal Access Token
+	MinimumAmount   string `yaml:"minimum_amount"`    // the smallest import, in whole tokens
+
+	// ExcludeAddresses are the counterparties whose transfers are not to be processed.
+	ExcludeAddresses []string `yaml:"exclude_addresses"`
+}
+
+// FromYAML reads a Config from a YAML representation.
+// Unknown keys are rejected so that misspelled settings are not silently ignored.
+func FromYAML(reader io.Reader) (*Config, error) {
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+
+	var cfg Config
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode configuration from YAML: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// LoadFile reads a Config from the YAML file at the given path.
+func LoadFile(filePath string) (*Config, error) {
+	file, err := os.Open(filePath) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to open configuration file '%s': %w", filePath, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	cfg, err := FromYAML(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file '%s': %w", filePath, err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,70 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FromYAML", func() {
+	It("reads every setting", func() {
+		cfg, err := config.FromYAML(strings.NewReader(`
+wallet_address: "0xwallet"
+token_address: "0xtoken"
+rpc_url: "https://rpc.example.com"
+ynab_account_name: "Crypto"
+ynab_access_token: "tokengoeshere"
+minimum_amount: "0.5"
+exclude_addresses:
+  - "0xspam"
+  - "0xdust"
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg).To(Equal(&config.Config{
+			WalletAddress:    "0xwallet",
+			TokenAddress:     "0xtoken",
+			RPCURL:           "https://rpc.example.com",
+			YNABAccountName:  "Crypto",
+			YNABAccessToken:  "tokengoeshere",
+			MinimumAmount:    "0.5",
+			ExcludeAddresses: []string{"0xspam", "0xdust"},
+		}))
+	})
+
+	It("leaves settings that are not provided empty", func() {
+		cfg, err := config.FromYAML(strings.NewReader(`wallet_address: "0xwallet"`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg).To(Equal(&config.Config{WalletAddress: "0xwallet"}))
+	})
+
+	It("reads an empty file as an empty configuration", func() {
+		cfg, err := config.FromYAML(strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg).To(Equal(&config.Config{}))
+	})
+
+	It("rejects unknown settings", func() {
+		_, err := config.FromYAML(strings.NewReader(`walet_address: "0xwallet"`))
+		Expect(err).To(MatchError(ContainSubstring("walet_address")))
+	})
+})
+
+var _ = Describe("LoadFile", func() {
+	It("reads the configuration from the file", func() {
+		filePath := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(filePath, []byte(`ynab_account_name: "Crypto"`), 0o600)).To(Succeed())
+
+		cfg, err := config.LoadFile(filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.YNABAccountName).To(Equal("Crypto"))
+	})
+
+	It("returns an error for a missing file", func() {
+		_, err := config.LoadFile(filepath.Join(GinkgoT().TempDir(), "missing.yaml"))
+		Expect(err).To(MatchError(ContainSubstring("missing.yaml")))
+	})
+})
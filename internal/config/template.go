@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteTemplate writes a commented YAML configuration to the given writer, listing every
+// supported setting with its value in the given defaults, as a starting point for a
+// configuration file.
+func WriteTemplate(writer io.Writer, defaults *Config) error {
+	var sb strings.Builder
+
+	sb.WriteString("# Configuration for cryptonabber-txn-sync, passed with --config.\n")
+	sb.WriteString("# Arguments given on the command line take precedence over these settings;\n")
+	sb.WriteString("# a setting left empty is treated as not having been provided.\n")
+
+	writeTemplateSetting(
+		&sb,
+		"the comma-separated addresses of the wallets to synchronize (required)",
+		"wallet_address",
+		defaults.WalletAddress,
+	)
+	writeTemplateSetting(
+		&sb,
+		"the contract address of the token to synchronize",
+		"token_address",
+		defaults.TokenAddress,
+	)
+	writeTemplateSetting(
+		&sb,
+		"the JSON-RPC endpoint used to look up the token's details",
+		"rpc_url",
+		defaults.RPCURL,
+	)
+	writeTemplateSetting(
+		&sb,
+		"the name of the YNAB account to which transactions are synchronized (required)",
+		"ynab_account_name",
+		defaults.YNABAccountName,
+	)
+	writeTemplateSetting(
+		&sb,
+		"the YNAB Personal Access Token used to authenticate requests (required)",
+		"ynab_access_token",
+		defaults.YNABAccessToken,
+	)
+	writeTemplateSetting(
+		&sb,
+		"the smallest amount, in whole tokens, of a transfer to be offered for import",
+		"minimum_amount",
+		defaults.MinimumAmount,
+	)
+
+	sb.WriteString("\n# the counterparty addresses whose transfers are not processed\n")
+	sb.WriteString("exclude_addresses:\n")
+
+	if len(defaults.ExcludeAddresses) == 0 {
+		sb.WriteString("#  - \"0x000000000000000000000000000000000000dEaD\"\n")
+	}
+
+	for _, address := range defaults.ExcludeAddresses {
+		fmt.Fprintf(&sb, "  - %s\n", strconv.Quote(address))
+	}
+
+	if _, err := io.WriteString(writer, sb.String()); err != nil {
+		return fmt.Errorf("failed to write configuration template: %w", err)
+	}
+
+	return nil
+}
+
+// writeTemplateSetting writes a single setting, preceded by a comment describing it.
+// The value is double-quoted, which YAML reads as it does a Go string literal.
+func writeTemplateSetting(sb *strings.Builder, description string, key string, value string) {
+	fmt.Fprintf(sb, "\n# %s\n%s: %s\n", description, key, strconv.Quote(value))
+}
@@ -0,0 +1,48 @@
+package config_test
+
+import (
+	"bytes"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteTemplate", func() {
+	It("writes a template that reads back as the given defaults", func() {
+		defaults := &config.Config{
+			TokenAddress:  "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913",
+			RPCURL:        "https://mainnet.base.org",
+			MinimumAmount: "0.01",
+		}
+
+		var buf bytes.Buffer
+		Expect(config.WriteTemplate(&buf, defaults)).To(Succeed())
+		Expect(buf.String()).To(HavePrefix("# Configuration for cryptonabber-txn-sync"))
+		Expect(buf.String()).To(ContainSubstring("\nwallet_address: \"\"\n"))
+		Expect(buf.String()).To(ContainSubstring("\nexclude_addresses:\n#  - "))
+
+		cfg, err := config.FromYAML(&buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg).To(Equal(defaults))
+	})
+
+	It("writes every setting so that each reads back", func() {
+		defaults := &config.Config{
+			WalletAddress:    "0xwallet1,0xwallet2",
+			TokenAddress:     "0xtoken",
+			RPCURL:           "https://rpc.example.com",
+			YNABAccountName:  `Crypto "Wallet"`,
+			YNABAccessToken:  "tokengoeshere",
+			MinimumAmount:    "0.5",
+			ExcludeAddresses: []string{"0xspam", "0xdust"},
+		}
+
+		var buf bytes.Buffer
+		Expect(config.WriteTemplate(&buf, defaults)).To(Succeed())
+
+		cfg, err := config.FromYAML(&buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg).To(Equal(defaults))
+	})
+})
@@ -0,0 +1,108 @@
+package eth
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const addressHexLength = 40
+
+// AddressFormat describes how addresses are rendered for display.
+type AddressFormat string
+
+const (
+	// AddressFormatUnchanged renders addresses exactly as they were provided.
+	AddressFormatUnchanged AddressFormat = ""
+	// AddressFormatLower renders addresses in lowercase hex.
+	AddressFormatLower AddressFormat = "lower"
+	// AddressFormatChecksum renders addresses in EIP-55 mixed-case checksum form.
+	AddressFormatChecksum AddressFormat = "checksum"
+)
+
+// ParseAddressFormat parses the given name of an address format.
+func ParseAddressFormat(name string) (AddressFormat, error) {
+	switch format := AddressFormat(strings.ToLower(strings.TrimSpace(name))); format {
+	case AddressFormatLower, AddressFormatChecksum:
+		return format, nil
+	default:
+		return AddressFormatUnchanged, fmt.Errorf(
+			"unsupported address format '%s'; must be one of: %s, %s",
+			name,
+			AddressFormatLower,
+			AddressFormatChecksum,
+		)
+	}
+}
+
+// Format renders the given address in this format.
+// Values that are not valid hex addresses are returned unchanged.
+func (f AddressFormat) Format(address string) string {
+	switch f {
+	case AddressFormatLower:
+		if !IsHexAddress(address) {
+			return address
+		}
+
+		return NormalizeAddress(address)
+	case AddressFormatChecksum:
+		checksummed, err := ChecksumAddress(address)
+		if err != nil {
+			return address
+		}
+
+		return checksummed
+	default:
+		return address
+	}
+}
+
+// IsHexAddress determines whether the given value is a 20-byte hex address, with or without a 0x prefix.
+func IsHexAddress(address string) bool {
+	hexDigits := trimHexPrefix(strings.TrimSpace(address))
+	if len(hexDigits) != addressHexLength {
+		return false
+	}
+
+	_, err := hex.DecodeString(hexDigits)
+
+	return err == nil
+}
+
+// NormalizeAddress returns the given address in lowercase with a 0x prefix.
+func NormalizeAddress(address string) string {
+	return "0x" + strings.ToLower(trimHexPrefix(strings.TrimSpace(address)))
+}
+
+// ChecksumAddress returns the given address in EIP-55 mixed-case checksum form.
+func ChecksumAddress(address string) (string, error) {
+	if !IsHexAddress(address) {
+		return "", fmt.Errorf("'%s' is not a valid hex address", address)
+	}
+
+	lowerHex := strings.ToLower(trimHexPrefix(strings.TrimSpace(address)))
+
+	hasher := sha3.NewLegacyKeccak256()
+	_, _ = hasher.Write([]byte(lowerHex))
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	checksummed := []byte(lowerHex)
+	for i, c := range checksummed {
+		// letters are uppercased when the corresponding nibble of the hash is 8 or greater
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+
+	return "0x" + string(checksummed), nil
+}
+
+func trimHexPrefix(value string) string {
+	if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+		return value[2:]
+	}
+
+	return value
+}
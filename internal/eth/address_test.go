@@ -0,0 +1,57 @@
+package eth_test
+
+import (
+	"strings"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/eth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Address", func() {
+	DescribeTable("ChecksumAddress", func(expected string) {
+		checksummed, err := eth.ChecksumAddress(strings.ToLower(expected))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(checksummed).To(Equal(expected))
+	},
+		Entry("all caps", "0x52908400098527886E0F7030069857D2E4169EE7"),
+		Entry("all lower", "0xde709f2102306220921060314715629080e2fb77"),
+		Entry("mixed case 1", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"),
+		Entry("mixed case 2", "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"),
+		Entry("mixed case 3", "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB"),
+		Entry("mixed case 4", "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb"),
+	)
+
+	It("rejects a value that is not an address", func() {
+		_, err := eth.ChecksumAddress("0x1234")
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("AddressFormat.Format", func(format eth.AddressFormat, input, expected string) {
+		Expect(format.Format(input)).To(Equal(expected))
+	},
+		Entry("unchanged", eth.AddressFormatUnchanged,
+			"0x5AAEB6053f3e94c9b9a09f33669435e7ef1beaed", "0x5AAEB6053f3e94c9b9a09f33669435e7ef1beaed"),
+		Entry("lower", eth.AddressFormatLower,
+			"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"),
+		Entry("checksum", eth.AddressFormatChecksum,
+			"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"),
+		Entry("checksum of a non-address", eth.AddressFormatChecksum, "not-an-address", "not-an-address"),
+	)
+
+	DescribeTable("ParseAddressFormat", func(name string, expected eth.AddressFormat, expectValid bool) {
+		format, err := eth.ParseAddressFormat(name)
+		if !expectValid {
+			Expect(err).To(HaveOccurred())
+
+			return
+		}
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(format).To(Equal(expected))
+	},
+		Entry("lower", "lower", eth.AddressFormatLower, true),
+		Entry("checksum", "Checksum", eth.AddressFormatChecksum, true),
+		Entry("unsupported", "upper", eth.AddressFormatUnchanged, false),
+	)
+})
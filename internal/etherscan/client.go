@@ -0,0 +1,179 @@
+package etherscan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	ctshttp "github.com/jrh3k5/cryptonabber-txn-sync/internal/http"
+)
+
+// DefaultAPIURL is the URL of the Etherscan V2 API.
+const DefaultAPIURL = "https://api.etherscan.io/v2/api"
+
+// BaseChainID is the chain ID of Base mainnet.
+const BaseChainID int64 = 8453
+
+// noTransactionsFoundMessage is the message of the Etherscan response returned when there are no transactions.
+const noTransactionsFoundMessage = "No transactions found"
+
+// ERC20TokenTransferTransaction describes a single ERC-20 token transfer as reported by Etherscan.
+type ERC20TokenTransferTransaction struct {
+	TransactionHash string    // the hash of the transaction containing the transfer
+	FromAddress     string    // the address that sent the tokens
+	ToAddress       string    // the address that received the tokens
+	Amount          *big.Int  // the amount of tokens transferred, in the token's base unit
+	TransferTime    time.Time // the time of the block in which the transfer was executed
+}
+
+// Client retrieves token transfers from Etherscan.
+type Client interface {
+	// GetERC20TokenTransferTransactions retrieves a page of the transfers of the given token into and out of
+	// the given wallet, ordered from oldest to newest. Pages are numbered from 1, and offset is the size of a page.
+	GetERC20TokenTransferTransactions(
+		ctx context.Context,
+		contractAddress string,
+		walletAddress string,
+		page int,
+		offset int,
+	) ([]*ERC20TokenTransferTransaction, error)
+}
+
+// HTTPClient is a Client that calls the Etherscan V2 API.
+type HTTPClient struct {
+	doer    ctshttp.Doer
+	apiURL  string
+	apiKey  string
+	chainID int64
+}
+
+var _ Client = (*HTTPClient)(nil)
+
+// NewHTTPClient creates a client of the Etherscan V2 API that authenticates with the given API key
+// and reads transfers on the chain with the given ID.
+func NewHTTPClient(doer ctshttp.Doer, apiKey string, chainID int64) *HTTPClient {
+	return &HTTPClient{
+		doer:    doer,
+		apiURL:  DefaultAPIURL,
+		apiKey:  apiKey,
+		chainID: chainID,
+	}
+}
+
+// GetERC20TokenTransferTransactions retrieves a page of token transfers using the "tokentx" action.
+// If Etherscan finds no transactions, an empty slice is returned.
+func (c *HTTPClient) GetERC20TokenTransferTransactions(
+	ctx context.Context,
+	contractAddress string,
+	walletAddress string,
+	page int,
+	offset int,
+) ([]*ERC20TokenTransferTransaction, error) {
+	query := url.Values{}
+	query.Set("chainid", strconv.FormatInt(c.chainID, 10))
+	query.Set("module", "account")
+	query.Set("action", "tokentx")
+	query.Set("contractaddress", contractAddress)
+	query.Set("address", walletAddress)
+	query.Set("page", strconv.Itoa(page))
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("sort", "asc")
+	query.Set("apikey", c.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build token transfer request: %w", err)
+	}
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute token transfer request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("etherscan API returned status %d", resp.StatusCode)
+	}
+
+	var envelope struct {
+		Status  string          `json:"status"`
+		Message string          `json:"message"`
+		Result  json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode token transfer response: %w", err)
+	}
+
+	if envelope.Status != "1" {
+		if strings.EqualFold(envelope.Message, noTransactionsFoundMessage) {
+			return []*ERC20TokenTransferTransaction{}, nil
+		}
+
+		// on failure, the result is a string describing the error
+		var detail string
+		_ = json.Unmarshal(envelope.Result, &detail)
+
+		return nil, fmt.Errorf("etherscan API returned an error: %s: %s", envelope.Message, detail)
+	}
+
+	var rows []tokenTransferRow
+	if err := json.Unmarshal(envelope.Result, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode token transfers: %w", err)
+	}
+
+	transfers := make([]*ERC20TokenTransferTransaction, 0, len(rows))
+	for _, row := range rows {
+		xfr, err := row.toTransfer()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to parse token transfer in transaction '%s': %w",
+				row.Hash,
+				err,
+			)
+		}
+
+		transfers = append(transfers, xfr)
+	}
+
+	return transfers, nil
+}
+
+// tokenTransferRow is a single row of a "tokentx" response.
+type tokenTransferRow struct {
+	TimeStamp string `json:"timeStamp"`
+	Hash      string `json:"hash"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Value     string `json:"value"`
+}
+
+func (r tokenTransferRow) toTransfer() (*ERC20TokenTransferTransaction, error) {
+	if r.Hash == "" {
+		return nil, errors.New("transaction hash is missing")
+	}
+
+	amount, ok := new(big.Int).SetString(r.Value, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid value '%s'", r.Value)
+	}
+
+	unixSeconds, err := strconv.ParseInt(r.TimeStamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp '%s': %w", r.TimeStamp, err)
+	}
+
+	return &ERC20TokenTransferTransaction{
+		TransactionHash: r.Hash,
+		FromAddress:     r.From,
+		ToAddress:       r.To,
+		Amount:          amount,
+		TransferTime:    time.Unix(unixSeconds, 0).UTC(),
+	}, nil
+}
@@ -0,0 +1,115 @@
+package etherscan_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/etherscan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTPClient", func() {
+	var ctx context.Context
+	var client *etherscan.HTTPClient
+
+	registerResponse := func(body string) {
+		httpmock.RegisterResponder(
+			"GET",
+			etherscan.DefaultAPIURL,
+			func(req *http.Request) (*http.Response, error) {
+				query := req.URL.Query()
+				Expect(query.Get("chainid")).To(Equal("8453"))
+				Expect(query.Get("module")).To(Equal("account"))
+				Expect(query.Get("action")).To(Equal("tokentx"))
+				Expect(query.Get("contractaddress")).To(Equal("0xtoken"))
+				Expect(query.Get("address")).To(Equal("0xwallet"))
+				Expect(query.Get("page")).To(Equal("2"))
+				Expect(query.Get("offset")).To(Equal("100"))
+				Expect(query.Get("apikey")).To(Equal("apikeygoeshere"))
+
+				return httpmock.NewStringResponse(http.StatusOK, body), nil
+			},
+		)
+	}
+
+	getTransfers := func() ([]*etherscan.ERC20TokenTransferTransaction, error) {
+		return client.GetERC20TokenTransferTransactions(ctx, "0xtoken", "0xwallet", 2, 100)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		client = etherscan.NewHTTPClient(
+			http.DefaultClient,
+			"apikeygoeshere",
+			etherscan.BaseChainID,
+		)
+		httpmock.Reset()
+	})
+
+	It("maps each transfer in the response", func() {
+		registerResponse(`{"status":"1","message":"OK","result":[{
+			"blockNumber":"39233024",
+			"timeStamp":"1765367603",
+			"hash":"0xhash1",
+			"from":"0xfrom",
+			"to":"0xwallet",
+			"value":"101500000",
+			"tokenDecimal":"6"
+		}]}`)
+
+		transfers, err := getTransfers()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+
+		xfr := transfers[0]
+		Expect(xfr.TransactionHash).To(Equal("0xhash1"))
+		Expect(xfr.FromAddress).To(Equal("0xfrom"))
+		Expect(xfr.ToAddress).To(Equal("0xwallet"))
+		Expect(xfr.Amount).To(Equal(big.NewInt(101500000)))
+		Expect(xfr.TransferTime).To(
+			Equal(time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC)),
+		)
+	})
+
+	It("returns an empty slice when no transactions are found", func() {
+		registerResponse(`{"status":"0","message":"No transactions found","result":[]}`)
+
+		transfers, err := getTransfers()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).ToNot(BeNil())
+		Expect(transfers).To(BeEmpty())
+	})
+
+	It("returns the error described by a failed response", func() {
+		registerResponse(`{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+
+		_, err := getTransfers()
+		Expect(err).To(MatchError(ContainSubstring("Invalid API Key")))
+	})
+
+	It("rejects a transfer with an invalid value", func() {
+		registerResponse(`{"status":"1","message":"OK","result":[{
+			"timeStamp":"1765367603",
+			"hash":"0xhash1",
+			"value":"not-a-number"
+		}]}`)
+
+		_, err := getTransfers()
+		Expect(err).To(MatchError(ContainSubstring("invalid value")))
+	})
+
+	It("returns an error on a non-200 response", func() {
+		httpmock.RegisterResponder(
+			"GET",
+			etherscan.DefaultAPIURL,
+			httpmock.NewStringResponder(http.StatusBadGateway, ""),
+		)
+
+		_, err := getTransfers()
+		Expect(err).To(MatchError(ContainSubstring("status 502")))
+	})
+})
@@ -0,0 +1,24 @@
+package etherscan_test
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestEtherscan(t *testing.T) {
+	t.Parallel()
+
+	BeforeSuite(func() {
+		httpmock.Activate()
+	})
+
+	AfterSuite(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Etherscan Suite")
+}
@@ -0,0 +1,53 @@
+package etherscan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+)
+
+// DefaultPageSize is the number of transfers requested from Etherscan per page.
+const DefaultPageSize = 1000
+
+// GetTransfers retrieves every transfer of the given token into and out of the given wallet,
+// requesting pages of the given size until a page that is not full is returned.
+func GetTransfers(
+	ctx context.Context,
+	client Client,
+	contractAddress string,
+	walletAddress string,
+	pageSize int,
+) ([]*transaction.Transfer, error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("page size must be positive: %d", pageSize)
+	}
+
+	var transfers []*transaction.Transfer
+	for page := 1; ; page++ {
+		pageTransfers, err := client.GetERC20TokenTransferTransactions(
+			ctx,
+			contractAddress,
+			walletAddress,
+			page,
+			pageSize,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve page %d of token transfers: %w", page, err)
+		}
+
+		for _, xfr := range pageTransfers {
+			transfers = append(transfers, &transaction.Transfer{
+				FromAddress:     xfr.FromAddress,
+				ToAddress:       xfr.ToAddress,
+				Amount:          xfr.Amount,
+				ExecutionTime:   xfr.TransferTime,
+				TransactionHash: xfr.TransactionHash,
+			})
+		}
+
+		if len(pageTransfers) < pageSize {
+			return transfers, nil
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package etherscan_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/etherscan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetTransfers", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("pages through the transfers until a page is not full", func() {
+		client := &pagedClient{transferCount: 5}
+
+		transfers, err := etherscan.GetTransfers(ctx, client, "0xtoken", "0xwallet", 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.requestedPages).To(Equal([]int{1, 2, 3}))
+		Expect(transfers).To(HaveLen(5))
+
+		for index, xfr := range transfers {
+			Expect(xfr.TransactionHash).To(Equal(fmt.Sprintf("0xhash%d", index)))
+			Expect(xfr.Amount).To(Equal(big.NewInt(int64(index))))
+		}
+	})
+
+	It("requests one more page when the last page is full", func() {
+		client := &pagedClient{transferCount: 4}
+
+		transfers, err := etherscan.GetTransfers(ctx, client, "0xtoken", "0xwallet", 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.requestedPages).To(Equal([]int{1, 2, 3}))
+		Expect(transfers).To(HaveLen(4))
+	})
+
+	It("returns the error of a failed page", func() {
+		client := &pagedClient{transferCount: 5, err: errors.New("rate limited")}
+
+		_, err := etherscan.GetTransfers(ctx, client, "0xtoken", "0xwallet", 2)
+		Expect(err).To(MatchError(ContainSubstring("rate limited")))
+	})
+})
+
+// pagedClient is an etherscan.Client that serves the given number of generated transfers in pages.
+type pagedClient struct {
+	transferCount  int
+	err            error
+	requestedPages []int
+}
+
+func (p *pagedClient) GetERC20TokenTransferTransactions(
+	_ context.Context,
+	_ string,
+	_ string,
+	page int,
+	offset int,
+) ([]*etherscan.ERC20TokenTransferTransaction, error) {
+	p.requestedPages = append(p.requestedPages, page)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	var transfers []*etherscan.ERC20TokenTransferTransaction
+	for index := (page - 1) * offset; index < min(page*offset, p.transferCount); index++ {
+		transfers = append(transfers, &etherscan.ERC20TokenTransferTransaction{
+			TransactionHash: fmt.Sprintf("0xhash%d", index),
+			Amount:          big.NewInt(int64(index)),
+			TransferTime:    time.Unix(int64(index), 0).UTC(),
+		})
+	}
+
+	return transfers, nil
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrMutatingRequest is returned by a read-only Doer for a request that could change data.
+var ErrMutatingRequest = errors.New("request method is not allowed in read-only mode")
+
+// ReadOnlyDoer is a Doer that sends only GET requests, rejecting requests of any other method
+// before they are sent.
+type ReadOnlyDoer struct {
+	delegate Doer
+}
+
+// NewReadOnlyDoer returns a Doer that sends GET requests through the given Doer
+// and rejects all others.
+func NewReadOnlyDoer(delegate Doer) *ReadOnlyDoer {
+	return &ReadOnlyDoer{delegate: delegate}
+}
+
+// Do sends the given request if it is a GET request; otherwise, it returns an error wrapping
+// ErrMutatingRequest without sending the request.
+func (r *ReadOnlyDoer) Do(req *http.Request) (*http.Response, error) {
+	// an empty method means GET
+	if req.Method != "" && req.Method != http.MethodGet {
+		return nil, fmt.Errorf(
+			"%w: refusing to send %s %s",
+			ErrMutatingRequest,
+			req.Method,
+			req.URL,
+		)
+	}
+
+	return r.delegate.Do(req)
+}
@@ -0,0 +1,58 @@
+package http_test
+
+import (
+	"net/http"
+
+	ctshttp "github.com/jrh3k5/cryptonabber-txn-sync/internal/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReadOnlyDoer", func() {
+	var (
+		delegate *recordingDoer
+		doer     *ctshttp.ReadOnlyDoer
+	)
+
+	BeforeEach(func() {
+		delegate = &recordingDoer{}
+		doer = ctshttp.NewReadOnlyDoer(delegate)
+	})
+
+	It("sends GET requests", func() {
+		req, err := http.NewRequest(http.MethodGet, "https://example.local/budgets", nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		resp, err := doer.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(delegate.requests).To(ConsistOf(req))
+	})
+
+	DescribeTable("blocks mutating requests", func(method string) {
+		req, err := http.NewRequest(method, "https://example.local/transactions", nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		resp, err := doer.Do(req)
+		Expect(err).To(MatchError(ctshttp.ErrMutatingRequest))
+		Expect(err.Error()).To(ContainSubstring(method))
+		Expect(resp).To(BeNil())
+		Expect(delegate.requests).To(BeEmpty())
+	},
+		Entry("POST", http.MethodPost),
+		Entry("PUT", http.MethodPut),
+		Entry("PATCH", http.MethodPatch),
+		Entry("DELETE", http.MethodDelete),
+	)
+})
+
+// recordingDoer is a Doer that records the requests it is given and responds with 200 OK.
+type recordingDoer struct {
+	requests []*http.Request
+}
+
+func (r *recordingDoer) Do(req *http.Request) (*http.Response, error) {
+	r.requests = append(r.requests, req)
+
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
@@ -2,7 +2,10 @@ package io
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"time"
 )
 
 // FileExists checks to see if a file exists at the given path.
@@ -21,3 +24,89 @@ func FileExists(filePath string) (bool, error) {
 		)
 	}
 }
+
+// backupTimestampFormat is the format of the timestamp in the name of a backup made
+// when the plain backup name is taken.
+const backupTimestampFormat = "20060102T150405"
+
+// BackUpFile renames the file at the given path to the same path with a ".bak" suffix
+// and returns the path to which it was renamed.
+// An earlier backup is never replaced: if that path is taken, the backup is named with
+// the current time before the suffix, e.g., "file.20251210T090000.bak", and if that is taken too,
+// the file is not backed up.
+func BackUpFile(filePath string) (string, error) {
+	backupPath := filePath + ".bak"
+
+	exists, err := FileExists(backupPath)
+	if err != nil {
+		return "", err
+	}
+
+	if exists {
+		backupPath = filePath + "." + time.Now().Format(backupTimestampFormat) + ".bak"
+
+		exists, err = FileExists(backupPath)
+		if err != nil {
+			return "", err
+		}
+
+		if exists {
+			return "", fmt.Errorf(
+				"failed to back up '%s': '%s' already exists",
+				filePath,
+				backupPath,
+			)
+		}
+	}
+
+	if err := os.Rename(filePath, backupPath); err != nil {
+		return "", fmt.Errorf("failed to back up '%s' to '%s': %w", filePath, backupPath, err)
+	}
+
+	return backupPath, nil
+}
+
+// WriteFileAtomically replaces the contents of the file at the given path with whatever
+// the given function writes, creating the file with the given permissions if it does not exist.
+// The contents are written to a temporary file in the same directory that is then renamed
+// over the original, so the file is never left partially written.
+func WriteFileAtomically(filePath string, perm os.FileMode, write func(io.Writer) error) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for '%s': %w", filePath, err)
+	}
+
+	tempPath := tempFile.Name()
+	renamed := false
+
+	defer func() {
+		if !renamed {
+			_ = tempFile.Close()
+			_ = os.Remove(tempPath)
+		}
+	}()
+
+	if err := write(tempFile); err != nil {
+		return err
+	}
+
+	if err := tempFile.Sync(); err != nil {
+		return fmt.Errorf("failed to flush temporary file for '%s': %w", filePath, err)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file for '%s': %w", filePath, err)
+	}
+
+	if err := os.Chmod(tempPath, perm); err != nil {
+		return fmt.Errorf("failed to set permissions of temporary file for '%s': %w", filePath, err)
+	}
+
+	if err := os.Rename(tempPath, filePath); err != nil {
+		return fmt.Errorf("failed to replace '%s': %w", filePath, err)
+	}
+
+	renamed = true
+
+	return nil
+}
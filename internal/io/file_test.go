@@ -0,0 +1,136 @@
+package io_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	iopkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/io"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteFileAtomically", func() {
+	var (
+		dir      string
+		filePath string
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		filePath = filepath.Join(dir, "output.yaml")
+	})
+
+	writeString := func(contents string) func(io.Writer) error {
+		return func(writer io.Writer) error {
+			_, err := io.WriteString(writer, contents)
+
+			return err
+		}
+	}
+
+	It("creates the file if it does not exist", func() {
+		Expect(iopkg.WriteFileAtomically(filePath, 0o600, writeString("created"))).To(Succeed())
+
+		contents, err := os.ReadFile(filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("created"))
+	})
+
+	It("replaces the contents of an existing file", func() {
+		Expect(os.WriteFile(filePath, []byte("a much longer original"), 0o600)).To(Succeed())
+
+		Expect(iopkg.WriteFileAtomically(filePath, 0o600, writeString("replaced"))).To(Succeed())
+
+		contents, err := os.ReadFile(filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("replaced"))
+	})
+
+	It("leaves the original file untouched and cleans up when writing fails", func() {
+		Expect(os.WriteFile(filePath, []byte("original"), 0o600)).To(Succeed())
+
+		writeErr := errors.New("write failed")
+		err := iopkg.WriteFileAtomically(filePath, 0o600, func(writer io.Writer) error {
+			_, _ = io.WriteString(writer, "partial")
+
+			return writeErr
+		})
+		Expect(err).To(MatchError(writeErr))
+
+		contents, err := os.ReadFile(filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("original"))
+
+		entries, err := os.ReadDir(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("BackUpFile", func() {
+	var filePath string
+
+	BeforeEach(func() {
+		filePath = filepath.Join(GinkgoT().TempDir(), "transaction_hash.ignorelist")
+	})
+
+	It("renames the file with a .bak suffix", func() {
+		Expect(os.WriteFile(filePath, []byte("corrupt"), 0o600)).To(Succeed())
+
+		backupPath, err := iopkg.BackUpFile(filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backupPath).To(Equal(filePath + ".bak"))
+
+		contents, err := os.ReadFile(backupPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("corrupt"))
+
+		exists, err := iopkg.FileExists(filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("keeps an earlier backup, naming the new one with the time", func() {
+		Expect(os.WriteFile(filePath+".bak", []byte("earlier"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filePath, []byte("later"), 0o600)).To(Succeed())
+
+		backupPath, err := iopkg.BackUpFile(filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backupPath).To(MatchRegexp(`^%s\.\d{8}T\d{6}\.bak$`, regexp.QuoteMeta(filePath)))
+
+		contents, err := os.ReadFile(backupPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("later"))
+
+		contents, err = os.ReadFile(filePath + ".bak")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("earlier"))
+	})
+
+	It("refuses to replace an earlier timestamped backup", func() {
+		Expect(os.WriteFile(filePath+".bak", []byte("earlier"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filePath, []byte("later"), 0o600)).To(Succeed())
+
+		// the backup is named with the time it is made, so cover a second passing in between
+		for _, offset := range []time.Duration{0, time.Second} {
+			stamped := filePath + "." + time.Now().Add(offset).Format("20060102T150405") + ".bak"
+			Expect(os.WriteFile(stamped, []byte("earlier"), 0o600)).To(Succeed())
+		}
+
+		_, err := iopkg.BackUpFile(filePath)
+		Expect(err).To(MatchError(ContainSubstring("already exists")))
+
+		contents, err := os.ReadFile(filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("later"))
+	})
+
+	It("fails if the file does not exist", func() {
+		_, err := iopkg.BackUpFile(filePath)
+		Expect(err).To(MatchError(os.ErrNotExist))
+	})
+})
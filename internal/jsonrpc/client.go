@@ -0,0 +1,106 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	ctshttp "github.com/jrh3k5/cryptonabber-txn-sync/internal/http"
+)
+
+// Client performs JSON-RPC 2.0 calls against an RPC node over HTTP.
+type Client struct {
+	doer   ctshttp.Doer
+	rpcURL string
+	lastID atomic.Int64
+}
+
+// NewClient returns a Client that uses the provided HTTP client to call the RPC node at the given URL.
+func NewClient(doer ctshttp.Doer, rpcURL string) *Client {
+	return &Client{doer: doer, rpcURL: rpcURL}
+}
+
+// CodeInvalidParams is the error code with which an RPC node rejects the parameters of a call.
+const CodeInvalidParams = -32602
+
+// Error is an error returned by the RPC node in the response to a call.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error: %d %s", e.Code, e.Message)
+}
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Call invokes the given method with the given parameters and decodes its result into the given value.
+// If the node returns an error, it is returned as an *Error.
+// If the node returns no result, the given value is left untouched.
+func (c *Client) Call(ctx context.Context, method string, params []any, result any) error {
+	if params == nil {
+		params = []any{}
+	}
+
+	reqBody, err := json.Marshal(request{
+		JSONRPC: "2.0",
+		ID:      c.lastID.Add(1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal rpc request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		c.rpcURL,
+		bytes.NewReader(reqBody),
+	)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doer.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("rpc call: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var rpcResp response
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("decode rpc response: %w", err)
+	}
+
+	if rpcResp.Error != nil {
+		return rpcResp.Error
+	}
+
+	if len(rpcResp.Result) == 0 || result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("decode rpc result of %s: %w", method, err)
+	}
+
+	return nil
+}
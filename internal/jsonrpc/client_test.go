@@ -0,0 +1,73 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/jsonrpc"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	const rpcURL = "http://example.local"
+
+	var ctx context.Context
+	var client *jsonrpc.Client
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		client = jsonrpc.NewClient(http.DefaultClient, rpcURL)
+	})
+
+	It("sends the method and parameters and decodes the result", func() {
+		var payloads []map[string]any
+		httpmock.RegisterResponder("POST", rpcURL, func(req *http.Request) (*http.Response, error) {
+			Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+
+			body, _ := io.ReadAll(req.Body)
+			var payload map[string]any
+			Expect(json.Unmarshal(body, &payload)).To(Succeed())
+			payloads = append(payloads, payload)
+
+			return httpmock.NewStringResponse(
+				200,
+				`{"jsonrpc":"2.0","id":1,"result":["a","b"]}`,
+			), nil
+		})
+
+		var result []string
+		Expect(client.Call(ctx, "eth_test", []any{"first", 2}, &result)).To(Succeed())
+		Expect(result).To(Equal([]string{"a", "b"}))
+
+		Expect(client.Call(ctx, "eth_test", []any{}, &result)).To(Succeed())
+
+		Expect(payloads).To(HaveLen(2))
+		Expect(payloads[0]["jsonrpc"]).To(Equal("2.0"))
+		Expect(payloads[0]["method"]).To(Equal("eth_test"))
+		Expect(payloads[0]["params"]).To(Equal([]any{"first", float64(2)}))
+		Expect(payloads[0]["id"]).To(Equal(float64(1)))
+		Expect(payloads[1]["id"]).To(Equal(float64(2)))
+	})
+
+	It("returns the node's error", func() {
+		httpmock.RegisterResponder(
+			"POST",
+			rpcURL,
+			httpmock.NewStringResponder(
+				200,
+				`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`,
+			),
+		)
+
+		var result string
+		err := client.Call(ctx, "eth_missing", nil, &result)
+
+		var rpcErr *jsonrpc.Error
+		Expect(err).To(BeAssignableToTypeOf(rpcErr))
+		Expect(err).To(MatchError("rpc error: -32601 method not found"))
+	})
+})
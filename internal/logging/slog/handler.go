@@ -4,15 +4,22 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"iter"
 	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
-// Handler formats logs like the default slog output: "YYYY/MM/DD HH:MM:SS LEVEL Message"
+// Handler formats logs like the default slog output: "YYYY/MM/DD HH:MM:SS LEVEL Message",
+// followed by the attributes of the record, and those added to the handler, as key=value pairs.
+// The keys of attributes in groups are prefixed with the names of their groups, e.g., "group.key".
 type Handler struct {
-	out   io.Writer
-	opts  *slog.HandlerOptions
-	attrs []slog.Attr
+	out         io.Writer
+	opts        *slog.HandlerOptions
+	attrs       []slog.Attr // the attributes added to the handler, their keys already prefixed
+	groupPrefix string      // the prefix of the keys of attributes added to the current group
 }
 
 func NewHandler(out io.Writer, opts *slog.HandlerOptions) slog.Handler {
@@ -32,9 +39,23 @@ func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
 }
 
 func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	var sb strings.Builder
+
 	ts := r.Time.Format("2006/01/02 15:04:05")
 	lvl := strings.ToUpper(r.Level.String())
-	_, err := fmt.Fprintf(h.out, "%s %s %s\n", ts, lvl, r.Message)
+	fmt.Fprintf(&sb, "%s %s %s", ts, lvl, r.Message)
+
+	for _, attr := range h.attrs {
+		writeAttr(&sb, attr)
+	}
+
+	for _, attr := range prefixAttrs(h.groupPrefix, r.Attrs) {
+		writeAttr(&sb, attr)
+	}
+
+	sb.WriteString("\n")
+
+	_, err := io.WriteString(h.out, sb.String())
 	if err != nil {
 		return fmt.Errorf("unable to write log record: %w", err)
 	}
@@ -44,9 +65,70 @@ func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
 
 func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	copyLogger := *h
-	copyLogger.attrs = append(copyLogger.attrs, attrs...)
+	copyLogger.attrs = slices.Concat(h.attrs, prefixAttrs(h.groupPrefix, slices.Values(attrs)))
 
 	return &copyLogger
 }
 
-func (h *Handler) WithGroup(name string) slog.Handler { return h }
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	copyLogger := *h
+	copyLogger.groupPrefix += name + "."
+
+	return &copyLogger
+}
+
+// prefixAttrs resolves the given attributes, flattening groups into attributes whose keys are
+// prefixed with their groups' names, and prefixes every key with the given prefix.
+// Attributes with empty keys, other than groups, and empty groups are left out, as by slog.
+func prefixAttrs(prefix string, attrs iter.Seq[slog.Attr]) []slog.Attr {
+	var prefixed []slog.Attr
+
+	for attr := range attrs {
+		attr.Value = attr.Value.Resolve()
+
+		if attr.Value.Kind() != slog.KindGroup {
+			if attr.Key != "" {
+				prefixed = append(prefixed, slog.Attr{Key: prefix + attr.Key, Value: attr.Value})
+			}
+
+			continue
+		}
+
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix += attr.Key + "."
+		}
+
+		prefixed = append(
+			prefixed,
+			prefixAttrs(groupPrefix, slices.Values(attr.Value.Group()))...,
+		)
+	}
+
+	return prefixed
+}
+
+// writeAttr writes the given attribute as " key=value", quoting the value if it is empty or
+// contains spaces, quotes, equals signs or unprintable characters.
+func writeAttr(sb *strings.Builder, attr slog.Attr) {
+	value := attr.Value.String()
+	if needsQuoting(value) {
+		value = strconv.Quote(value)
+	}
+
+	fmt.Fprintf(sb, " %s=%s", attr.Key, value)
+}
+
+func needsQuoting(value string) bool {
+	if value == "" {
+		return true
+	}
+
+	return strings.ContainsFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r)
+	})
+}
@@ -0,0 +1,112 @@
+package slog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	ctsslog "github.com/jrh3k5/cryptonabber-txn-sync/internal/logging/slog"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Handler", func() {
+	var (
+		ctx    context.Context
+		buf    *bytes.Buffer
+		logger *slog.Logger
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		buf = &bytes.Buffer{}
+		logger = slog.New(ctsslog.NewHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	})
+
+	// logged returns what was logged after the timestamp.
+	logged := func() string {
+		line := strings.TrimSuffix(buf.String(), "\n")
+		Expect(line).ToNot(ContainSubstring("\n"))
+
+		fields := strings.SplitN(line, " ", 3)
+		Expect(fields).To(HaveLen(3))
+
+		return fields[2]
+	}
+
+	It("writes the level and message", func() {
+		logger.InfoContext(ctx, "Selected budget")
+
+		Expect(logged()).To(Equal("INFO Selected budget"))
+	})
+
+	It("writes the attributes of the record as key=value pairs", func() {
+		logger.WarnContext(
+			ctx,
+			"Prompt failed",
+			"error",
+			errors.New("prompt timed out"),
+			"budgetID",
+			"abc-123",
+			"count",
+			3,
+		)
+
+		Expect(logged()).To(Equal(
+			`WARN Prompt failed error="prompt timed out" budgetID=abc-123 count=3`,
+		))
+	})
+
+	It("writes the attributes added to the handler before those of the record", func() {
+		logger.With("budgetID", "abc-123").DebugContext(ctx, "Loaded accounts", "count", 2)
+
+		Expect(logged()).To(Equal("DEBUG Loaded accounts budgetID=abc-123 count=2"))
+	})
+
+	It("quotes empty values and values with quotes or equals signs", func() {
+		logger.InfoContext(ctx, "Message", "empty", "", "quoted", `a"b`, "equals", "a=b")
+
+		Expect(logged()).To(Equal(`INFO Message empty="" quoted="a\"b" equals="a=b"`))
+	})
+
+	It("prefixes the keys of grouped attributes with their groups' names", func() {
+		logger.With("run", 1).
+			WithGroup("ynab").
+			With("budgetID", "abc-123").
+			WithGroup("account").
+			InfoContext(ctx, "Selected account", "name", "Crypto", slog.Group("ids", "id", "def"))
+
+		Expect(logged()).To(Equal(
+			"INFO Selected account run=1 ynab.budgetID=abc-123 ynab.account.name=Crypto " +
+				"ynab.account.ids.id=def",
+		))
+	})
+
+	It("leaves out empty groups and ignores an empty group name", func() {
+		logger.WithGroup("").InfoContext(ctx, "Message", slog.Group("empty"), "key", "value")
+
+		Expect(logged()).To(Equal("INFO Message key=value"))
+	})
+
+	It("does not share attributes between derived loggers", func() {
+		parent := logger.With("a", 1)
+		first := parent.With("b", 2)
+		second := parent.With("c", 3)
+
+		first.InfoContext(ctx, "First")
+		Expect(logged()).To(Equal("INFO First a=1 b=2"))
+
+		buf.Reset()
+		second.InfoContext(ctx, "Second")
+		Expect(logged()).To(Equal("INFO Second a=1 c=3"))
+	})
+
+	It("does not write records below the minimum level", func() {
+		infoLogger := slog.New(ctsslog.NewHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		infoLogger.DebugContext(ctx, "Hidden", "key", "value")
+
+		Expect(buf.String()).To(BeEmpty())
+	})
+})
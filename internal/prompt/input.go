@@ -0,0 +1,159 @@
+package prompt
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// inputChunkSize is the most input read from the underlying reader at once.
+const inputChunkSize = 256
+
+// stdin is the input shared by the prompts presented on the terminal.
+var stdin = NewSharedReader(os.Stdin)
+
+// SharedReader hands out the input of a reader to one prompt at a time.
+// A prompt that is canceled stops reading at once, and input read after it was canceled
+// is kept for the next prompt rather than consumed by the canceled one.
+type SharedReader struct {
+	input     io.Reader
+	startOnce sync.Once
+	chunks    chan inputChunk
+
+	mutex   sync.Mutex
+	pending []byte // input read from the underlying reader but not yet handed out
+	err     error  // the error that ended the reading of the underlying reader, if any
+}
+
+type inputChunk struct {
+	data []byte
+	err  error
+}
+
+// NewSharedReader returns a SharedReader of the given input.
+// The input is not read until a reader returned by NewReader is first read.
+func NewSharedReader(input io.Reader) *SharedReader {
+	return &SharedReader{
+		input:  input,
+		chunks: make(chan inputChunk),
+	}
+}
+
+// NewReader returns a reader of the shared input for a single prompt.
+// Once the given channel is closed, or the returned reader is closed, reading it fails with io.EOF;
+// a nil channel never cancels the reader.
+func (s *SharedReader) NewReader(cancel <-chan struct{}) io.ReadCloser {
+	s.startOnce.Do(func() {
+		go s.readInput()
+	})
+
+	return &sharedInputReader{
+		shared: s,
+		cancel: cancel,
+		closed: make(chan struct{}),
+	}
+}
+
+// readInput reads the underlying input until it fails, handing each chunk read to whichever reader
+// asks for input next.
+func (s *SharedReader) readInput() {
+	for {
+		buffer := make([]byte, inputChunkSize)
+		n, err := s.input.Read(buffer)
+		s.chunks <- inputChunk{data: buffer[:n], err: err}
+
+		if err != nil {
+			close(s.chunks)
+
+			return
+		}
+	}
+}
+
+// takePending copies into the given buffer the input that was read but not yet handed out, if any,
+// or returns the error that ended the input once all of it was handed out.
+// It reports whether there was anything to return.
+func (s *SharedReader) takePending(buffer []byte) (int, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.pending) == 0 {
+		return 0, s.err != nil, s.err
+	}
+
+	n := copy(buffer, s.pending)
+	s.pending = s.pending[n:]
+
+	return n, true, nil
+}
+
+// keep holds on to the given input, read but not handed out, for the next reader.
+func (s *SharedReader) keep(chunk inputChunk) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.pending = append(s.pending, chunk.data...)
+	if chunk.err != nil {
+		s.err = chunk.err
+	}
+}
+
+type sharedInputReader struct {
+	shared    *SharedReader
+	cancel    <-chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (r *sharedInputReader) Read(buffer []byte) (int, error) {
+	if r.isDone() {
+		return 0, io.EOF
+	}
+
+	if n, ok, err := r.shared.takePending(buffer); ok {
+		return n, err
+	}
+
+	select {
+	case chunk, isOpen := <-r.shared.chunks:
+		if isOpen {
+			r.shared.keep(chunk)
+		}
+
+		// a reader canceled while its input was being read leaves that input to the next reader
+		if r.isDone() {
+			return 0, io.EOF
+		}
+
+		n, ok, err := r.shared.takePending(buffer)
+		if !ok && !isOpen {
+			return 0, io.EOF
+		}
+
+		return n, err
+	case <-r.cancel:
+		return 0, io.EOF
+	case <-r.closed:
+		return 0, io.EOF
+	}
+}
+
+func (r *sharedInputReader) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.closed)
+	})
+
+	return nil
+}
+
+// isDone determines whether the reader was canceled or closed.
+func (r *sharedInputReader) isDone() bool {
+	select {
+	case <-r.cancel:
+		return true
+	case <-r.closed:
+		return true
+	default:
+		return false
+	}
+}
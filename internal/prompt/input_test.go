@@ -0,0 +1,84 @@
+package prompt_test
+
+import (
+	"io"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/prompt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SharedReader", func() {
+	var (
+		inputWriter  *io.PipeWriter
+		sharedReader *prompt.SharedReader
+	)
+
+	BeforeEach(func() {
+		var inputReader *io.PipeReader
+		inputReader, inputWriter = io.Pipe()
+		sharedReader = prompt.NewSharedReader(inputReader)
+
+		DeferCleanup(func() {
+			_ = inputWriter.Close()
+		})
+	})
+
+	It("hands the input to the reader", func() {
+		reader := sharedReader.NewReader(nil)
+		go func() {
+			_, _ = inputWriter.Write([]byte("answer"))
+		}()
+
+		buffer := make([]byte, 16)
+		n, err := reader.Read(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(buffer[:n])).To(Equal("answer"))
+	})
+
+	It("stops a canceled reader while it waits for input", func() {
+		cancel := make(chan struct{})
+		reader := sharedReader.NewReader(cancel)
+
+		readErr := make(chan error, 1)
+		go func() {
+			_, err := reader.Read(make([]byte, 16))
+			readErr <- err
+		}()
+
+		close(cancel)
+		Eventually(readErr).Should(Receive(MatchError(io.EOF)))
+	})
+
+	It("leaves the input read after a reader is canceled to the next reader", func() {
+		cancel := make(chan struct{})
+		canceledReader := sharedReader.NewReader(cancel)
+
+		readErr := make(chan error, 1)
+		go func() {
+			_, err := canceledReader.Read(make([]byte, 16))
+			readErr <- err
+		}()
+
+		close(cancel)
+		Eventually(readErr).Should(Receive(MatchError(io.EOF)))
+
+		go func() {
+			_, _ = inputWriter.Write([]byte("answer"))
+		}()
+
+		nextReader := sharedReader.NewReader(nil)
+		buffer := make([]byte, 16)
+		n, err := nextReader.Read(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(buffer[:n])).To(Equal("answer"))
+	})
+
+	It("fails to read from a closed reader", func() {
+		reader := sharedReader.NewReader(nil)
+		Expect(reader.Close()).To(Succeed())
+
+		_, err := reader.Read(make([]byte, 16))
+		Expect(err).To(MatchError(io.EOF))
+	})
+})
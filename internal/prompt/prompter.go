@@ -0,0 +1,146 @@
+package prompt
+
+import (
+	"fmt"
+
+	"github.com/manifoldco/promptui"
+)
+
+var (
+	// ErrInterrupt is returned by a Prompter when the user interrupts a prompt (e.g., Ctrl-C).
+	ErrInterrupt = promptui.ErrInterrupt
+	// ErrEOF is returned by a Prompter when the input is closed during a prompt (e.g., Ctrl-D).
+	ErrEOF = promptui.ErrEOF
+)
+
+// Prompter presents prompts to the user and collects their answers.
+type Prompter interface {
+	// Select asks the user to choose one of the given items and returns the index of the chosen item.
+	Select(label string, items []string) (int, error)
+	// Input asks the user to enter free-form text, offering the given default value.
+	Input(label string, defaultValue string) (string, error)
+	// MultiSelect asks the user to choose any number of the given items
+	// and returns the indexes of the chosen items in ascending order.
+	MultiSelect(label string, items []string) ([]int, error)
+}
+
+// CancelablePrompter is a Prompter whose prompts can be canceled while they await an answer.
+type CancelablePrompter interface {
+	Prompter
+	// WithCancel returns a Prompter presenting the same prompts, which stop reading input
+	// and fail once the given channel is closed.
+	WithCancel(cancel <-chan struct{}) Prompter
+}
+
+// NewTerminalPrompter returns a Prompter that presents prompts on the terminal.
+// The returned Prompter is a CancelablePrompter.
+func NewTerminalPrompter() Prompter {
+	return &terminalPrompter{input: stdin}
+}
+
+type terminalPrompter struct {
+	input  *SharedReader
+	cancel <-chan struct{} // closed to cancel the prompts; nil if they are never canceled
+}
+
+func (t *terminalPrompter) WithCancel(cancel <-chan struct{}) Prompter {
+	return &terminalPrompter{
+		input:  t.input,
+		cancel: cancel,
+	}
+}
+
+func (t *terminalPrompter) Select(label string, items []string) (int, error) {
+	input := t.input.NewReader(t.cancel)
+	defer func() { _ = input.Close() }()
+
+	selector := promptui.Select{
+		Label: label,
+		Items: items,
+		Stdin: input,
+	}
+
+	selIdx, _, err := selector.Run()
+	if err != nil {
+		return 0, fmt.Errorf("select prompt failed: %w", err)
+	}
+
+	return selIdx, nil
+}
+
+func (t *terminalPrompter) Input(label string, defaultValue string) (string, error) {
+	input := t.input.NewReader(t.cancel)
+	defer func() { _ = input.Close() }()
+
+	inputPrompt := promptui.Prompt{
+		Label:   label,
+		Default: defaultValue,
+		Stdin:   input,
+	}
+
+	value, err := inputPrompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("input prompt failed: %w", err)
+	}
+
+	return value, nil
+}
+
+// multiSelectDoneOption is listed after the items of a multi-select prompt to finish the selection.
+const multiSelectDoneOption = "Done"
+
+// MultiSelect presents the items as a checklist in which choosing an item toggles it;
+// promptui has no checkbox prompt, so the checklist is shown again after each toggle
+// until the user chooses to finish.
+func (t *terminalPrompter) MultiSelect(label string, items []string) ([]int, error) {
+	const listSize = 10
+
+	chosen := make([]bool, len(items))
+	cursorPos := 0
+
+	for {
+		options := make([]string, 0, len(items)+1)
+		for i, item := range items {
+			checkbox := "[ ]"
+			if chosen[i] {
+				checkbox = "[x]"
+			}
+
+			options = append(options, checkbox+" "+item)
+		}
+
+		options = append(options, multiSelectDoneOption)
+
+		input := t.input.NewReader(t.cancel)
+		selector := promptui.Select{
+			Label: label + " (choose an entry to toggle it)",
+			Items: options,
+			Size:  listSize,
+			Stdin: input,
+		}
+
+		selIdx, _, err := selector.RunCursorAt(cursorPos, max(0, cursorPos-listSize+1))
+		_ = input.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("multi-select prompt failed: %w", err)
+		}
+
+		if selIdx == len(items) {
+			break
+		}
+
+		chosen[selIdx] = !chosen[selIdx]
+		cursorPos = selIdx
+	}
+
+	var indexes []int
+
+	for i, isChosen := range chosen {
+		if isChosen {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes, nil
+}
@@ -0,0 +1,62 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNotTerminal is returned by NewPrompter when prompts cannot be answered
+// because the input is not a terminal.
+var ErrNotTerminal = errors.New("input is not a terminal")
+
+// IsTerminal reports whether the given file is a terminal, rather than, e.g., a pipe or a regular file.
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// NewPrompter returns the Prompter for prompts to be answered on the given input.
+// If running non-interactively, every prompt goes unanswered, as by NewNonInteractivePrompter;
+// otherwise, prompts are presented on the terminal, and ErrNotTerminal is returned
+// if the input is not a terminal on which they could be answered.
+func NewPrompter(input *os.File, nonInteractive bool) (Prompter, error) {
+	if nonInteractive {
+		return NewNonInteractivePrompter(), nil
+	}
+
+	if !IsTerminal(input) {
+		return nil, fmt.Errorf("%w: %s", ErrNotTerminal, input.Name())
+	}
+
+	return NewTerminalPrompter(), nil
+}
+
+// NewNonInteractivePrompter returns a Prompter for unattended runs that presents no prompts.
+// Every prompt fails immediately with ErrTimeout, as if it had gone unanswered,
+// so that callers fall back to the same safe defaults as when a prompt times out.
+func NewNonInteractivePrompter() Prompter {
+	return &nonInteractivePrompter{}
+}
+
+type nonInteractivePrompter struct{}
+
+func (*nonInteractivePrompter) Select(label string, _ []string) (int, error) {
+	return 0, unansweredError(label)
+}
+
+func (*nonInteractivePrompter) Input(label string, _ string) (string, error) {
+	return "", unansweredError(label)
+}
+
+func (*nonInteractivePrompter) MultiSelect(label string, _ []string) ([]int, error) {
+	return nil, unansweredError(label)
+}
+
+func unansweredError(label string) error {
+	return fmt.Errorf("%w: running non-interactively, so '%s' was not asked", ErrTimeout, label)
+}
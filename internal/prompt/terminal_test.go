@@ -0,0 +1,63 @@
+package prompt_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/prompt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Terminal detection", func() {
+	var pipeReader *os.File
+
+	BeforeEach(func() {
+		var pipeWriter *os.File
+
+		var err error
+
+		pipeReader, pipeWriter, err = os.Pipe()
+		Expect(err).ToNot(HaveOccurred())
+
+		DeferCleanup(func() {
+			_ = pipeReader.Close()
+			_ = pipeWriter.Close()
+		})
+	})
+
+	Context("IsTerminal", func() {
+		It("does not treat a pipe as a terminal", func() {
+			Expect(prompt.IsTerminal(pipeReader)).To(BeFalse())
+		})
+
+		It("does not treat a regular file as a terminal", func() {
+			file, err := os.Create(filepath.Join(GinkgoT().TempDir(), "input.txt"))
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(func() { _ = file.Close() })
+
+			Expect(prompt.IsTerminal(file)).To(BeFalse())
+		})
+	})
+
+	Context("NewPrompter", func() {
+		It("refuses to prompt on input that is not a terminal", func() {
+			_, err := prompt.NewPrompter(pipeReader, false)
+			Expect(err).To(MatchError(prompt.ErrNotTerminal))
+		})
+
+		It("leaves every prompt unanswered when running non-interactively", func() {
+			prompter, err := prompt.NewPrompter(pipeReader, true)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = prompter.Select("label", []string{"a", "b"})
+			Expect(err).To(MatchError(prompt.ErrTimeout))
+
+			_, err = prompter.Input("label", "default")
+			Expect(err).To(MatchError(prompt.ErrTimeout))
+
+			_, err = prompter.MultiSelect("label", []string{"a", "b"})
+			Expect(err).To(MatchError(prompt.ErrTimeout))
+		})
+	})
+})
@@ -0,0 +1,92 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is returned by a Prompter created by NewTimeoutPrompter when a prompt is not answered
+// in time, and for every prompt by a Prompter created by NewNonInteractivePrompter.
+// Callers are expected to fall back to a safe default when they receive it.
+var ErrTimeout = errors.New("prompt timed out")
+
+// NewTimeoutPrompter returns a Prompter that delegates to the given Prompter but gives up on a prompt,
+// returning ErrTimeout, if it has not been answered within the given timeout.
+//
+// If the given Prompter is a CancelablePrompter, a prompt that times out is canceled, so that it
+// stops reading input before the next prompt is presented; otherwise, it is abandoned to finish
+// in the background.
+func NewTimeoutPrompter(delegate Prompter, timeout time.Duration) Prompter {
+	return &timeoutPrompter{
+		delegate: delegate,
+		timeout:  timeout,
+	}
+}
+
+type timeoutPrompter struct {
+	delegate Prompter
+	timeout  time.Duration
+}
+
+func (t *timeoutPrompter) Select(label string, items []string) (int, error) {
+	return runWithTimeout(t.delegate, t.timeout, func(delegate Prompter) (int, error) {
+		return delegate.Select(label, items)
+	})
+}
+
+func (t *timeoutPrompter) Input(label string, defaultValue string) (string, error) {
+	return runWithTimeout(t.delegate, t.timeout, func(delegate Prompter) (string, error) {
+		return delegate.Input(label, defaultValue)
+	})
+}
+
+func (t *timeoutPrompter) MultiSelect(label string, items []string) ([]int, error) {
+	return runWithTimeout(t.delegate, t.timeout, func(delegate Prompter) ([]int, error) {
+		return delegate.MultiSelect(label, items)
+	})
+}
+
+type promptResult[T any] struct {
+	value T
+	err   error
+}
+
+// runWithTimeout runs the given prompt of the given Prompter in a goroutine, returning ErrTimeout
+// if it does not finish in time. A cancelable prompt that times out is canceled and waited for.
+func runWithTimeout[T any](
+	delegate Prompter,
+	timeout time.Duration,
+	runPrompt func(Prompter) (T, error),
+) (T, error) {
+	cancel := make(chan struct{})
+
+	cancelable, isCancelable := delegate.(CancelablePrompter)
+	if isCancelable {
+		delegate = cancelable.WithCancel(cancel)
+	}
+
+	resultChan := make(chan promptResult[T], 1)
+	go func() {
+		value, err := runPrompt(delegate)
+		resultChan <- promptResult[T]{value: value, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-resultChan:
+		return result.value, result.err
+	case <-timer.C:
+		if isCancelable {
+			close(cancel)
+			// the prompt must let go of the input before the next prompt reads it
+			<-resultChan
+		}
+
+		var zero T
+
+		return zero, fmt.Errorf("%w after %s", ErrTimeout, timeout)
+	}
+}
@@ -0,0 +1,175 @@
+package prompt_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/prompt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TimeoutPrompter", func() {
+	var delegate *delayedPrompter
+
+	BeforeEach(func() {
+		delegate = &delayedPrompter{
+			selectIndex:    2,
+			inputValue:     "answer",
+			multiSelection: []int{0, 2},
+		}
+	})
+
+	When("the prompt is answered in time", func() {
+		It("returns the selected index", func() {
+			prompter := prompt.NewTimeoutPrompter(delegate, time.Second)
+
+			index, err := prompter.Select("label", []string{"a", "b", "c"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(index).To(Equal(2))
+		})
+
+		It("returns the entered text", func() {
+			prompter := prompt.NewTimeoutPrompter(delegate, time.Second)
+
+			value, err := prompter.Input("label", "default")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(value).To(Equal("answer"))
+		})
+
+		It("returns the chosen indexes", func() {
+			prompter := prompt.NewTimeoutPrompter(delegate, time.Second)
+
+			indexes, err := prompter.MultiSelect("label", []string{"a", "b", "c"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(indexes).To(Equal([]int{0, 2}))
+		})
+	})
+
+	When("the prompt is not answered in time", func() {
+		BeforeEach(func() {
+			delegate.delay = time.Second
+		})
+
+		It("returns a timeout error from a selection", func() {
+			prompter := prompt.NewTimeoutPrompter(delegate, 10*time.Millisecond)
+
+			_, err := prompter.Select("label", []string{"a", "b", "c"})
+			Expect(err).To(MatchError(prompt.ErrTimeout))
+		})
+
+		It("returns a timeout error from an input", func() {
+			prompter := prompt.NewTimeoutPrompter(delegate, 10*time.Millisecond)
+
+			_, err := prompter.Input("label", "default")
+			Expect(err).To(MatchError(prompt.ErrTimeout))
+		})
+
+		It("returns a timeout error from a multi-selection", func() {
+			prompter := prompt.NewTimeoutPrompter(delegate, 10*time.Millisecond)
+
+			_, err := prompter.MultiSelect("label", []string{"a", "b", "c"})
+			Expect(err).To(MatchError(prompt.ErrTimeout))
+		})
+
+		When("the prompt can be canceled", func() {
+			var cancelable *cancelablePrompter
+
+			BeforeEach(func() {
+				cancelable = &cancelablePrompter{}
+			})
+
+			It("cancels a selection and waits for it to stop", func() {
+				prompter := prompt.NewTimeoutPrompter(cancelable, 10*time.Millisecond)
+
+				_, err := prompter.Select("label", []string{"a", "b", "c"})
+				Expect(err).To(MatchError(prompt.ErrTimeout))
+				Expect(cancelable.stopped.Load()).To(BeTrue())
+			})
+
+			It("cancels an input and waits for it to stop", func() {
+				prompter := prompt.NewTimeoutPrompter(cancelable, 10*time.Millisecond)
+
+				_, err := prompter.Input("label", "default")
+				Expect(err).To(MatchError(prompt.ErrTimeout))
+				Expect(cancelable.stopped.Load()).To(BeTrue())
+			})
+
+			It("cancels a multi-selection and waits for it to stop", func() {
+				prompter := prompt.NewTimeoutPrompter(cancelable, 10*time.Millisecond)
+
+				_, err := prompter.MultiSelect("label", []string{"a", "b", "c"})
+				Expect(err).To(MatchError(prompt.ErrTimeout))
+				Expect(cancelable.stopped.Load()).To(BeTrue())
+			})
+		})
+	})
+})
+
+// delayedPrompter is a prompt.Prompter that answers every prompt with fixed values after a delay.
+type delayedPrompter struct {
+	delay          time.Duration
+	selectIndex    int
+	inputValue     string
+	multiSelection []int
+}
+
+func (d *delayedPrompter) Select(string, []string) (int, error) {
+	time.Sleep(d.delay)
+
+	return d.selectIndex, nil
+}
+
+func (d *delayedPrompter) Input(string, string) (string, error) {
+	time.Sleep(d.delay)
+
+	return d.inputValue, nil
+}
+
+func (d *delayedPrompter) MultiSelect(string, []string) ([]int, error) {
+	time.Sleep(d.delay)
+
+	return d.multiSelection, nil
+}
+
+// cancelablePrompter is a prompt.CancelablePrompter whose prompts are never answered,
+// but fail once they are canceled.
+type cancelablePrompter struct {
+	delayedPrompter
+	stopped atomic.Bool // whether a canceled prompt has stopped
+}
+
+func (c *cancelablePrompter) WithCancel(cancel <-chan struct{}) prompt.Prompter {
+	return &canceledPrompter{
+		cancel:  cancel,
+		stopped: &c.stopped,
+	}
+}
+
+// canceledPrompter is a prompt.Prompter whose prompts wait until they are canceled.
+type canceledPrompter struct {
+	cancel  <-chan struct{}
+	stopped *atomic.Bool
+}
+
+func (c *canceledPrompter) Select(string, []string) (int, error) {
+	return 0, c.awaitCancel()
+}
+
+func (c *canceledPrompter) Input(string, string) (string, error) {
+	return "", c.awaitCancel()
+}
+
+func (c *canceledPrompter) MultiSelect(string, []string) ([]int, error) {
+	return nil, c.awaitCancel()
+}
+
+func (c *canceledPrompter) awaitCancel() error {
+	<-c.cancel
+	// stop a little after the cancellation, so that a timeout that does not wait is caught
+	time.Sleep(10 * time.Millisecond)
+	c.stopped.Store(true)
+
+	return errors.New("canceled")
+}
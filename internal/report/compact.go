@@ -0,0 +1,25 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteCompact writes a single-line rendering of the counts of the given summary to the given writer,
+// e.g., "parsed=40 matched=12 created=5 ignored=3 skipped=0 unmatched=20".
+func WriteCompact(summary *RunSummary, writer io.Writer) error {
+	if _, err := fmt.Fprintf(
+		writer,
+		"parsed=%d matched=%d created=%d ignored=%d skipped=%d unmatched=%d\n",
+		summary.ParsedTransferCount,
+		len(summary.Matched),
+		len(summary.Created),
+		len(summary.Ignored),
+		len(summary.Skipped),
+		len(summary.Unmatched),
+	); err != nil {
+		return fmt.Errorf("failed to write compact summary: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package report_test
+
+import (
+	"bytes"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteCompact", func() {
+	It("renders the counts on a single line", func() {
+		summary := &report.RunSummary{
+			ParsedTransferCount: 40,
+			Matched:             make([]*report.MatchedTransaction, 12),
+			Created:             make([]*report.CreatedTransaction, 5),
+			Ignored:             make([]*report.Transfer, 3),
+			Skipped:             make([]*report.Transfer, 2),
+			Unmatched:           make([]*report.Transaction, 20),
+		}
+
+		var buf bytes.Buffer
+		Expect(report.WriteCompact(summary, &buf)).To(Succeed())
+		Expect(buf.String()).To(
+			Equal("parsed=40 matched=12 created=5 ignored=3 skipped=2 unmatched=20\n"),
+		)
+	})
+
+	It("renders zero counts for an empty summary", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteCompact(&report.RunSummary{}, &buf)).To(Succeed())
+		Expect(buf.String()).To(
+			Equal("parsed=0 matched=0 created=0 ignored=0 skipped=0 unmatched=0\n"),
+		)
+	})
+})
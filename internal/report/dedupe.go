@@ -0,0 +1,44 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// WriteDeduplicatedTransfers writes a table of the given transfers of the token with the given
+// name, dropped as duplicates of others that were kept, to the given writer, one row per
+// transfer, or a line saying that there were none.
+func WriteDeduplicatedTransfers(transfers []*Transfer, tokenName string, writer io.Writer) error {
+	if len(transfers) == 0 {
+		if _, err := fmt.Fprintln(writer, "No transfers were dropped as duplicates"); err != nil {
+			return fmt.Errorf("failed to write deduplicated transfers: %w", err)
+		}
+
+		return nil
+	}
+
+	table := NewTable(
+		Column{Header: "HASH"},
+		Column{Header: "FROM"},
+		Column{Header: "TO"},
+		Column{Header: "AMOUNT (" + tokenName + ")", Align: AlignRight},
+		Column{Header: "EXECUTED (UTC)"},
+	)
+
+	for _, xfr := range transfers {
+		table.AddRow(
+			xfr.TransactionHash,
+			xfr.FromAddress,
+			xfr.ToAddress,
+			xfr.Amount,
+			xfr.ExecutionTime.UTC().Format(time.DateTime),
+		)
+	}
+
+	if err := table.Write(writer); err != nil {
+		return fmt.Errorf("failed to write deduplicated transfers: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package report_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteDeduplicatedTransfers", func() {
+	It("writes a row for each deduplicated transfer", func() {
+		transfers := []*report.Transfer{
+			{
+				TransactionHash: "0xhash1",
+				FromAddress:     "0xwallet1",
+				ToAddress:       "0xwallet2",
+				Amount:          "3.25",
+				ExecutionTime:   time.Date(2025, time.December, 3, 12, 0, 0, 0, time.UTC),
+			},
+			{
+				TransactionHash: "0xhash22",
+				FromAddress:     "0xwallet2",
+				ToAddress:       "0xwallet1",
+				Amount:          "100",
+				ExecutionTime:   time.Date(2025, time.December, 4, 8, 30, 0, 0, time.UTC),
+			},
+		}
+
+		var buf bytes.Buffer
+		Expect(report.WriteDeduplicatedTransfers(transfers, "USDC", &buf)).To(Succeed())
+		Expect(buf.String()).To(Equal("" +
+			"HASH      FROM       TO         AMOUNT (USDC)  EXECUTED (UTC)\n" +
+			"0xhash1   0xwallet1  0xwallet2           3.25  2025-12-03 12:00:00\n" +
+			"0xhash22  0xwallet2  0xwallet1            100  2025-12-04 08:30:00\n"))
+	})
+
+	It("says so when no transfers were deduplicated", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteDeduplicatedTransfers(nil, "USDC", &buf)).To(Succeed())
+		Expect(buf.String()).To(Equal("No transfers were dropped as duplicates\n"))
+	})
+})
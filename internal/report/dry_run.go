@@ -0,0 +1,51 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+)
+
+// WriteDryRunSummary writes a table of what the given summary of a dry run would have done to
+// the given writer: how many YNAB transactions would have been cleared or left unmatched,
+// how many transfers would have been imported or were below the minimum amount, and the net
+// amount of the transactions that would have been cleared or created.
+func WriteDryRunSummary(summary *RunSummary, writer io.Writer) error {
+	//nolint:mnd
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+
+	matchedTotal := summary.MatchedTotal()
+	createdTotal := summary.CreatedTotal()
+
+	rows := [][]string{
+		{"OUTCOME", "COUNT", "AMOUNT"},
+		{
+			"Matched (would be cleared)",
+			strconv.Itoa(len(summary.Matched)),
+			client.FormatMilliunits(matchedTotal),
+		},
+		{"Unmatched", strconv.Itoa(len(summary.Unmatched)), "-"},
+		{
+			"Would be created",
+			strconv.Itoa(len(summary.Created)),
+			client.FormatMilliunits(createdTotal),
+		},
+		{"Skipped (below minimum)", strconv.Itoa(len(summary.BelowMinimum)), "-"},
+		{"Net amount", "-", client.FormatMilliunits(matchedTotal + createdTotal)},
+	}
+
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(tabWriter, "%s\t%s\t%s\n", row[0], row[1], row[2]); err != nil {
+			return fmt.Errorf("failed to write dry run summary: %w", err)
+		}
+	}
+
+	if err := tabWriter.Flush(); err != nil {
+		return fmt.Errorf("failed to flush dry run summary: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package report_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteDryRunSummary", func() {
+	It("writes the count and amount of each outcome and the net amount", func() {
+		summary := &report.RunSummary{
+			Matched: []*report.MatchedTransaction{
+				{Transaction: &report.Transaction{Amount: 1500}},
+				{Transaction: &report.Transaction{Amount: -500}},
+			},
+			Unmatched: make([]*report.Transaction, 3),
+			Created: []*report.CreatedTransaction{
+				{Transaction: &report.Transaction{Amount: 2250}},
+			},
+			BelowMinimum: make([]*report.Transfer, 4),
+		}
+
+		var buf bytes.Buffer
+		Expect(report.WriteDryRunSummary(summary, &buf)).To(Succeed())
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		Expect(lines).To(HaveLen(6))
+		Expect(strings.Fields(lines[0])).To(Equal([]string{"OUTCOME", "COUNT", "AMOUNT"}))
+		Expect(lines[1]).To(MatchRegexp(`^Matched \(would be cleared\)\s+2\s+\$1\.00$`))
+		Expect(lines[2]).To(MatchRegexp(`^Unmatched\s+3\s+-$`))
+		Expect(lines[3]).To(MatchRegexp(`^Would be created\s+1\s+\$2\.25$`))
+		Expect(lines[4]).To(MatchRegexp(`^Skipped \(below minimum\)\s+4\s+-$`))
+		Expect(lines[5]).To(MatchRegexp(`^Net amount\s+-\s+\$3\.25$`))
+	})
+})
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+)
+
+// WriteTransferDump writes a table of the given transfers to the given writer, one row per
+// transfer, showing each transfer's amount both in the token's base units and in whole tokens
+// given the token's number of decimals.
+// It is meant for troubleshooting how transfers were parsed.
+func WriteTransferDump(transfers []*transaction.Transfer, decimals int, writer io.Writer) error {
+	//nolint:mnd
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+
+	if _, err := fmt.Fprintln(
+		tabWriter,
+		"HASH\tLOG INDEX\tFROM\tTO\tAMOUNT (BASE UNITS)\tAMOUNT\tEXECUTED (UTC)\tFAILED",
+	); err != nil {
+		return fmt.Errorf("failed to write transfer dump header: %w", err)
+	}
+
+	for _, xfr := range transfers {
+		logIndex := "-"
+		if xfr.LogIndex != nil {
+			logIndex = strconv.Itoa(*xfr.LogIndex)
+		}
+
+		baseUnits := "0"
+		if xfr.Amount != nil {
+			baseUnits = xfr.Amount.String()
+		}
+
+		if _, err := fmt.Fprintf(
+			tabWriter,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%t\n",
+			xfr.TransactionHash,
+			logIndex,
+			xfr.FromAddress,
+			xfr.ToAddress,
+			baseUnits,
+			xfr.FormatAmount(decimals),
+			xfr.ExecutionTime.UTC().Format(time.RFC3339),
+			xfr.Failed,
+		); err != nil {
+			return fmt.Errorf("failed to write transfer '%s': %w", xfr.TransactionHash, err)
+		}
+	}
+
+	if err := tabWriter.Flush(); err != nil {
+		return fmt.Errorf("failed to flush transfer dump: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package report_test
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteTransferDump", func() {
+	It("writes a row for each transfer", func() {
+		logIndex := 7
+		transfers := []*transaction.Transfer{
+			{
+				TransactionHash: "0xhash1",
+				LogIndex:        &logIndex,
+				FromAddress:     "0xfrom",
+				ToAddress:       "0xto",
+				Amount:          big.NewInt(1234500),
+				ExecutionTime:   time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC),
+			},
+			{
+				TransactionHash: "0xhash2",
+				FromAddress:     "0xto",
+				ToAddress:       "0xfrom",
+				Amount:          big.NewInt(1),
+				ExecutionTime: time.Date(
+					2025, time.December, 11, 8, 0, 0, 0, time.FixedZone("EST", -5*60*60),
+				),
+				Failed: true,
+			},
+		}
+
+		var buf bytes.Buffer
+		Expect(report.WriteTransferDump(transfers, 6, &buf)).To(Succeed())
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		Expect(lines).To(HaveLen(3))
+		Expect(strings.Fields(lines[0])).To(Equal([]string{
+			"HASH", "LOG", "INDEX", "FROM", "TO", "AMOUNT", "(BASE", "UNITS)", "AMOUNT",
+			"EXECUTED", "(UTC)", "FAILED",
+		}))
+		Expect(strings.Fields(lines[1])).To(Equal([]string{
+			"0xhash1", "7", "0xfrom", "0xto", "1234500", "1.2345", "2025-12-10T11:53:23Z", "false",
+		}))
+		Expect(strings.Fields(lines[2])).To(Equal([]string{
+			"0xhash2", "-", "0xto", "0xfrom", "1", "0.000001", "2025-12-11T13:00:00Z", "true",
+		}))
+	})
+
+	It("writes only the header when there are no transfers", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteTransferDump(nil, 6, &buf)).To(Succeed())
+		Expect(strings.Count(buf.String(), "\n")).To(Equal(1))
+		Expect(buf.String()).To(HavePrefix("HASH"))
+	})
+})
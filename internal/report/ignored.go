@@ -0,0 +1,45 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+)
+
+// WriteIgnoredHashes writes a table of the given ignored transaction hashes to the given writer,
+// one row per hash, showing the date on which each was added to the ignore list, whether it was
+// processed or ignored by the user, and its reason.
+func WriteIgnoredHashes(hashes []transaction.IgnoredHash, writer io.Writer) error {
+	//nolint:mnd
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+
+	if _, err := fmt.Fprintln(tabWriter, "HASH\tADDED ON\tKIND\tREASON"); err != nil {
+		return fmt.Errorf("failed to write ignored hashes header: %w", err)
+	}
+
+	for _, ignoredHash := range hashes {
+		addedOn := ignoredHash.AddedOn
+		if addedOn == "" {
+			addedOn = "-"
+		}
+
+		if _, err := fmt.Fprintf(
+			tabWriter,
+			"%s\t%s\t%s\t%s\n",
+			ignoredHash.Hash,
+			addedOn,
+			ignoredHash.Kind,
+			ignoredHash.Reason,
+		); err != nil {
+			return fmt.Errorf("failed to write ignored hash '%s': %w", ignoredHash.Hash, err)
+		}
+	}
+
+	if err := tabWriter.Flush(); err != nil {
+		return fmt.Errorf("failed to flush ignored hashes: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package report_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteIgnoredHashes", func() {
+	It("writes a row for each ignored hash", func() {
+		ignoreList, err := transaction.FromYAML(strings.NewReader(`ignored_hashes:
+  - hash: "0xabc"
+    reason: "Marked as ignored on 2025-12-10"
+    added_on: "2025-12-10"
+  - hash: "0xdef"
+    reason: "Spam"
+  - hash: "0x123"
+    reason: "Processed for transaction ID tx-1 on 2025-12-11"
+    added_on: "2025-12-11"
+`))
+		Expect(err).ToNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		Expect(report.WriteIgnoredHashes(ignoreList.GetHashes(), &buf)).To(Succeed())
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		Expect(lines).To(HaveLen(4))
+		Expect(strings.Fields(lines[0])).To(HaveExactElements("HASH", "ADDED", "ON", "KIND", "REASON"))
+		Expect(strings.Fields(lines[1])).To(Equal([]string{
+			"0xabc", "2025-12-10", "ignored", "Marked", "as", "ignored", "on", "2025-12-10",
+		}))
+		Expect(strings.Fields(lines[2])).To(Equal([]string{"0xdef", "-", "ignored", "Spam"}))
+		Expect(strings.Fields(lines[3])).To(HaveExactElements(
+			"0x123", "2025-12-11", "processed", "Processed", "for", "transaction", "ID", "tx-1",
+			"on", "2025-12-11",
+		))
+	})
+})
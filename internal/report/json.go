@@ -0,0 +1,74 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// JSONReport is a machine-readable description of the outcome of a synchronization run.
+// Its field names are stable, so that other tooling can rely on them.
+type JSONReport struct {
+	TokenName string `json:"token_name"` // the name of the token that was synchronized
+	DryRun    bool   `json:"dry_run"`    // true if the run made no changes to YNAB
+	// UnclearedTransactions are the uncleared YNAB transactions, matched or not.
+	UnclearedTransactions []*UnclearedTransaction `json:"uncleared_transactions"`
+	// ImportedTransfers are the transfers for which a YNAB transaction was created.
+	ImportedTransfers []*CreatedTransaction `json:"imported_transfers"`
+	// IgnoredTransfers are the transfers the user chose to ignore permanently.
+	IgnoredTransfers []*Transfer `json:"ignored_transfers"`
+	// DeduplicatedTransfers are the transfers dropped as duplicates of others that were kept.
+	DeduplicatedTransfers []*Transfer `json:"deduplicated_transfers"`
+}
+
+// UnclearedTransaction describes an uncleared YNAB transaction and whether it was matched.
+type UnclearedTransaction struct {
+	Transaction *Transaction `json:"transaction"`
+	Matched     bool         `json:"matched"` // true if a transfer was matched to the transaction
+	// MatchedTransferHash is the transaction hash of the transfer matched to the transaction;
+	// empty if none was.
+	MatchedTransferHash string `json:"matched_transfer_hash,omitempty"`
+}
+
+// NewJSONReport describes the given summary of a run as a JSON report.
+// The uncleared transactions are listed in order of date, whether or not they were matched.
+func NewJSONReport(summary *RunSummary, dryRun bool) *JSONReport {
+	uncleared := make([]*UnclearedTransaction, 0, len(summary.Matched)+len(summary.Unmatched))
+	for _, matched := range summary.Matched {
+		uncleared = append(uncleared, &UnclearedTransaction{
+			Transaction:         matched.Transaction,
+			Matched:             true,
+			MatchedTransferHash: matched.Transfer.TransactionHash,
+		})
+	}
+
+	for _, unmatched := range summary.Unmatched {
+		uncleared = append(uncleared, &UnclearedTransaction{Transaction: unmatched})
+	}
+
+	slices.SortStableFunc(uncleared, func(a, b *UnclearedTransaction) int {
+		return a.Transaction.Date.Compare(b.Transaction.Date)
+	})
+
+	return &JSONReport{
+		TokenName:             summary.TokenName,
+		DryRun:                dryRun,
+		UnclearedTransactions: uncleared,
+		ImportedTransfers:     append(make([]*CreatedTransaction, 0), summary.Created...),
+		IgnoredTransfers:      append(make([]*Transfer, 0), summary.Ignored...),
+		DeduplicatedTransfers: append(make([]*Transfer, 0), summary.Deduplicated...),
+	}
+}
+
+// WriteJSON writes the given JSON report to the given writer.
+func WriteJSON(jsonReport *JSONReport, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(jsonReport); err != nil {
+		return fmt.Errorf("failed to write JSON report: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSONReport", func() {
+	It("lists every uncleared transaction in order of date with its matched transfer", func() {
+		summary := &report.RunSummary{
+			TokenName: "USDC",
+			Matched: []*report.MatchedTransaction{
+				{
+					Transaction: &report.Transaction{
+						ID:     "tx2",
+						Date:   time.Date(2025, time.December, 2, 0, 0, 0, 0, time.UTC),
+						Amount: 1000,
+					},
+					Transfer: &report.Transfer{TransactionHash: "0xhash1"},
+				},
+			},
+			Unmatched: []*report.Transaction{
+				{ID: "tx1", Date: time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)},
+			},
+			Created: []*report.CreatedTransaction{
+				{
+					Transaction: &report.Transaction{ID: "tx3"},
+					Transfer:    &report.Transfer{TransactionHash: "0xhash2"},
+				},
+			},
+			Ignored: []*report.Transfer{{TransactionHash: "0xhash3"}},
+		}
+
+		var buf bytes.Buffer
+		Expect(report.WriteJSON(report.NewJSONReport(summary, true), &buf)).To(Succeed())
+
+		var written map[string]any
+		Expect(json.Unmarshal(buf.Bytes(), &written)).To(Succeed())
+		Expect(written["token_name"]).To(Equal("USDC"))
+		Expect(written["dry_run"]).To(BeTrue())
+
+		Expect(written["uncleared_transactions"]).To(HaveExactElements(
+			And(
+				HaveKeyWithValue("transaction", HaveKeyWithValue("id", "tx1")),
+				HaveKeyWithValue("matched", false),
+				Not(HaveKey("matched_transfer_hash")),
+			),
+			And(
+				HaveKeyWithValue("transaction", HaveKeyWithValue("id", "tx2")),
+				HaveKeyWithValue("matched", true),
+				HaveKeyWithValue("matched_transfer_hash", "0xhash1"),
+			),
+		))
+
+		Expect(written["imported_transfers"]).To(ConsistOf(And(
+			HaveKeyWithValue("transaction", HaveKeyWithValue("id", "tx3")),
+			HaveKeyWithValue("transfer", HaveKeyWithValue("transaction_hash", "0xhash2")),
+		)))
+
+		Expect(written["ignored_transfers"]).To(
+			ConsistOf(HaveKeyWithValue("transaction_hash", "0xhash3")),
+		)
+	})
+
+	It("lists the deduplicated transfers", func() {
+		summary := &report.RunSummary{
+			Deduplicated: []*report.Transfer{{TransactionHash: "0xduplicate", Amount: "3"}},
+		}
+
+		var buf bytes.Buffer
+		Expect(report.WriteJSON(report.NewJSONReport(summary, false), &buf)).To(Succeed())
+
+		var written map[string]any
+		Expect(json.Unmarshal(buf.Bytes(), &written)).To(Succeed())
+		Expect(written["deduplicated_transfers"]).To(ConsistOf(And(
+			HaveKeyWithValue("transaction_hash", "0xduplicate"),
+			HaveKeyWithValue("amount", "3"),
+		)))
+	})
+
+	It("writes empty lists rather than null for an empty summary", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteJSON(report.NewJSONReport(&report.RunSummary{}, false), &buf)).
+			To(Succeed())
+		Expect(buf.String()).To(ContainSubstring(`"uncleared_transactions": []`))
+		Expect(buf.String()).To(ContainSubstring(`"imported_transfers": []`))
+		Expect(buf.String()).To(ContainSubstring(`"ignored_transfers": []`))
+		Expect(buf.String()).To(ContainSubstring(`"deduplicated_transfers": []`))
+	})
+})
@@ -0,0 +1,177 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+)
+
+// MarkdownOption configures how a Markdown report is written.
+type MarkdownOption func(*markdownOptions)
+
+type markdownOptions struct {
+	omitTitle bool      // whether the title of the report is left out
+	runTime   time.Time // the time of the run given in a marker ahead of its report; zero for none
+}
+
+// WithoutTitle leaves the title out of the report, e.g., when appending to a report that has one.
+func WithoutTitle() MarkdownOption {
+	return func(opts *markdownOptions) {
+		opts.omitTitle = true
+	}
+}
+
+// WithRunMarker starts the report of the run with a marker giving the time of the run,
+// to set it apart from the reports of other runs in the same file.
+func WithRunMarker(runTime time.Time) MarkdownOption {
+	return func(opts *markdownOptions) {
+		opts.runTime = runTime
+	}
+}
+
+// WriteMarkdown writes a human-readable Markdown rendering of the given summary to the given writer.
+func WriteMarkdown(summary *RunSummary, writer io.Writer, opts ...MarkdownOption) error {
+	options := &markdownOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var sb strings.Builder
+
+	if !options.omitTitle {
+		sb.WriteString("# Synchronization Report\n\n")
+	}
+
+	if !options.runTime.IsZero() {
+		// without a title, the marker follows an earlier report, whose last line it would
+		// otherwise turn into a heading
+		if options.omitTitle {
+			sb.WriteString("\n")
+		}
+
+		fmt.Fprintf(&sb, "---\n\n**Run at %s**\n\n", options.runTime.Format(time.RFC3339))
+	}
+
+	sb.WriteString("## Totals\n\n")
+	sb.WriteString("| Category | Count | Amount |\n")
+	sb.WriteString("| --- | ---: | ---: |\n")
+	fmt.Fprintf(&sb, "| Parsed transfers | %d | |\n", summary.ParsedTransferCount)
+	fmt.Fprintf(&sb, "| Excluded transfers | %d | |\n", summary.ExcludedCount)
+	fmt.Fprintf(&sb, "| Deduplicated transfers | %d | |\n", len(summary.Deduplicated))
+	fmt.Fprintf(
+		&sb,
+		"| Matched transactions | %d | %s |\n",
+		len(summary.Matched),
+		client.FormatMilliunits(summary.MatchedTotal()),
+	)
+	fmt.Fprintf(&sb, "| Unmatched transactions | %d | |\n", len(summary.Unmatched))
+	fmt.Fprintf(
+		&sb,
+		"| Created transactions | %d | %s |\n",
+		len(summary.Created),
+		client.FormatMilliunits(summary.CreatedTotal()),
+	)
+	fmt.Fprintf(&sb, "| Ignored transfers | %d | |\n", len(summary.Ignored))
+	fmt.Fprintf(&sb, "| Skipped transfers | %d | |\n", len(summary.Skipped))
+
+	sb.WriteString("\n## Matched Transactions\n\n")
+	if len(summary.Matched) == 0 {
+		sb.WriteString("_None_\n")
+	} else {
+		sb.WriteString("| Date | Payee | Amount | Transaction Hash |\n")
+		sb.WriteString("| --- | --- | ---: | --- |\n")
+		for _, matched := range summary.Matched {
+			writeTransactionRow(&sb, matched.Transaction, matched.Transfer.TransactionHash)
+		}
+	}
+
+	sb.WriteString("\n## Created Transactions\n\n")
+	if len(summary.Created) == 0 {
+		sb.WriteString("_None_\n")
+	} else {
+		sb.WriteString("| Date | Payee | Amount | Transaction Hash |\n")
+		sb.WriteString("| --- | --- | ---: | --- |\n")
+		for _, created := range summary.Created {
+			writeTransactionRow(&sb, created.Transaction, created.Transfer.TransactionHash)
+		}
+	}
+
+	sb.WriteString("\n## Unmatched Transactions\n\n")
+	if len(summary.Unmatched) == 0 {
+		sb.WriteString("_None_\n")
+	} else {
+		sb.WriteString("| Date | Payee | Amount | Memo |\n")
+		sb.WriteString("| --- | --- | ---: | --- |\n")
+		for _, unmatched := range summary.Unmatched {
+			writeTransactionRow(&sb, unmatched, unmatched.Memo)
+		}
+	}
+
+	sb.WriteString("\n## Ignored Transfers\n\n")
+	writeTransferList(&sb, summary.Ignored, summary.TokenName)
+
+	sb.WriteString("\n## Skipped Transfers\n\n")
+	writeTransferList(&sb, summary.Skipped, summary.TokenName)
+
+	sb.WriteString("\n## Deduplicated Transfers\n\n")
+	writeTransferList(&sb, summary.Deduplicated, summary.TokenName)
+
+	sb.WriteString("\n## Phase Durations\n\n")
+	if len(summary.PhaseDurations) == 0 {
+		sb.WriteString("_None_\n")
+	} else {
+		sb.WriteString("| Phase | Duration |\n")
+		sb.WriteString("| --- | ---: |\n")
+		for _, phase := range summary.PhaseDurations {
+			fmt.Fprintf(&sb, "| %s | %s |\n", phase.Name, phase.Duration)
+		}
+	}
+
+	if _, err := io.WriteString(writer, sb.String()); err != nil {
+		return fmt.Errorf("failed to write Markdown report: %w", err)
+	}
+
+	return nil
+}
+
+func writeTransactionRow(sb *strings.Builder, txn *Transaction, lastColumn string) {
+	fmt.Fprintf(
+		sb,
+		"| %s | %s | %s | %s |\n",
+		txn.Date.Format(time.DateOnly),
+		escapeMarkdownCell(txn.Payee),
+		client.FormatMilliunits(txn.Amount),
+		escapeMarkdownCell(lastColumn),
+	)
+}
+
+func writeTransferList(sb *strings.Builder, transfers []*Transfer, tokenName string) {
+	if len(transfers) == 0 {
+		sb.WriteString("_None_\n")
+
+		return
+	}
+
+	for _, xfr := range transfers {
+		fmt.Fprintf(
+			sb,
+			"- `%s`: %s %s from `%s` to `%s` on %s\n",
+			xfr.TransactionHash,
+			xfr.Amount,
+			tokenName,
+			xfr.FromAddress,
+			xfr.ToAddress,
+			xfr.ExecutionTime.Format(time.RFC3339),
+		)
+	}
+}
+
+// escapeMarkdownCell escapes characters that would otherwise break the layout of a Markdown table cell.
+func escapeMarkdownCell(value string) string {
+	escaped := strings.ReplaceAll(value, "|", "\\|")
+
+	return strings.ReplaceAll(escaped, "\n", " ")
+}
@@ -0,0 +1,137 @@
+package report_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteMarkdown", func() {
+	var summary *report.RunSummary
+
+	BeforeEach(func() {
+		date := time.Date(2025, time.December, 10, 0, 0, 0, 0, time.UTC)
+
+		summary = &report.RunSummary{
+			TokenName:           "USDC",
+			ParsedTransferCount: 4,
+			ExcludedCount:       2,
+			Matched: []*report.MatchedTransaction{
+				{
+					Transaction: &report.Transaction{
+						ID:     "txn-1",
+						Date:   date,
+						Payee:  "Coffee | Shop",
+						Amount: -5000,
+					},
+					Transfer: &report.Transfer{TransactionHash: "0xmatched"},
+				},
+			},
+			Unmatched: []*report.Transaction{
+				{ID: "txn-2", Date: date, Payee: "Landlord", Memo: "rent", Amount: -1200000},
+			},
+			Created: []*report.CreatedTransaction{
+				{
+					Transaction: &report.Transaction{
+						ID:     "txn-3",
+						Date:   date,
+						Payee:  "Employer",
+						Amount: 2500000,
+					},
+					Transfer: &report.Transfer{TransactionHash: "0xcreated"},
+				},
+			},
+			PhaseDurations: []*report.PhaseDuration{
+				{Name: report.PhaseParse, Duration: 1500 * time.Millisecond},
+			},
+			Ignored: []*report.Transfer{
+				{
+					TransactionHash: "0xignored",
+					FromAddress:     "0xfrom",
+					ToAddress:       "0xto",
+					Amount:          "0.5",
+					ExecutionTime:   date,
+				},
+			},
+			Deduplicated: []*report.Transfer{
+				{
+					TransactionHash: "0xduplicate",
+					FromAddress:     "0xwallet1",
+					ToAddress:       "0xwallet2",
+					Amount:          "3",
+					ExecutionTime:   date,
+				},
+			},
+		}
+	})
+
+	It("renders the report headers", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteMarkdown(summary, &buf)).To(Succeed())
+
+		output := buf.String()
+		Expect(output).To(HavePrefix("# Synchronization Report\n"))
+		Expect(output).To(ContainSubstring("\n## Totals\n"))
+		Expect(output).To(ContainSubstring("\n## Matched Transactions\n"))
+		Expect(output).To(ContainSubstring("\n## Created Transactions\n"))
+		Expect(output).To(ContainSubstring("\n## Unmatched Transactions\n"))
+		Expect(output).To(ContainSubstring("\n## Ignored Transfers\n"))
+		Expect(output).To(ContainSubstring("\n## Skipped Transfers\n\n_None_\n"))
+		Expect(output).To(ContainSubstring("\n## Deduplicated Transfers\n"))
+		Expect(output).To(ContainSubstring("\n## Phase Durations\n"))
+	})
+
+	It("renders the totals", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteMarkdown(summary, &buf)).To(Succeed())
+
+		output := buf.String()
+		Expect(output).To(ContainSubstring("| Parsed transfers | 4 | |\n"))
+		Expect(output).To(ContainSubstring("| Excluded transfers | 2 | |\n"))
+		Expect(output).To(ContainSubstring("| Deduplicated transfers | 1 | |\n"))
+		Expect(output).To(ContainSubstring("| Matched transactions | 1 | -$5.00 |\n"))
+		Expect(output).To(ContainSubstring("| Unmatched transactions | 1 | |\n"))
+		Expect(output).To(ContainSubstring("| Created transactions | 1 | $2500.00 |\n"))
+		Expect(output).To(ContainSubstring("| Ignored transfers | 1 | |\n"))
+		Expect(output).To(ContainSubstring("| Skipped transfers | 0 | |\n"))
+	})
+
+	It("renders the table rows", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteMarkdown(summary, &buf)).To(Succeed())
+
+		output := buf.String()
+		Expect(output).To(ContainSubstring("| 2025-12-10 | Coffee \\| Shop | -$5.00 | 0xmatched |\n"))
+		Expect(output).To(ContainSubstring("| 2025-12-10 | Employer | $2500.00 | 0xcreated |\n"))
+		Expect(output).To(ContainSubstring("| 2025-12-10 | Landlord | -$1200.00 | rent |\n"))
+		Expect(output).To(ContainSubstring("| parse | 1.5s |\n"))
+		Expect(
+			output,
+		).To(ContainSubstring("- `0xignored`: 0.5 USDC from `0xfrom` to `0xto` on 2025-12-10T00:00:00Z\n"))
+		Expect(output).To(ContainSubstring(
+			"- `0xduplicate`: 3 USDC from `0xwallet1` to `0xwallet2` on 2025-12-10T00:00:00Z\n",
+		))
+	})
+
+	It("starts the report with a marker giving the time of the run", func() {
+		runTime := time.Date(2025, time.December, 11, 8, 30, 0, 0, time.UTC)
+
+		var buf bytes.Buffer
+		Expect(report.WriteMarkdown(summary, &buf, report.WithRunMarker(runTime))).To(Succeed())
+
+		Expect(buf.String()).To(HavePrefix(
+			"# Synchronization Report\n\n---\n\n**Run at 2025-12-11T08:30:00Z**\n\n## Totals\n",
+		))
+	})
+
+	It("leaves out the title", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteMarkdown(summary, &buf, report.WithoutTitle())).To(Succeed())
+
+		Expect(buf.String()).To(HavePrefix("## Totals\n"))
+		Expect(buf.String()).ToNot(ContainSubstring("# Synchronization Report"))
+	})
+})
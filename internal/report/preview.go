@@ -0,0 +1,63 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/eth"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+)
+
+// previewHashLength is the number of characters to which transaction hashes are truncated
+// in a transfer preview.
+const previewHashLength = 13
+
+// WriteTransferPreview writes a table of the given transfers of a token to the given writer,
+// one row per transfer, showing when each was executed, its amount, whether it was sent to or
+// received from its counterparty, the counterparty's address in the given format, and its
+// transaction hash, truncated. Amounts are right-justified so that they line up.
+func WriteTransferPreview(
+	transfers []*transaction.Transfer,
+	wallets *transaction.Wallets,
+	tokenDetails *token.Details,
+	addressFormat eth.AddressFormat,
+	writer io.Writer,
+) error {
+	table := NewTable(
+		Column{Header: "EXECUTED (UTC)"},
+		Column{Header: "AMOUNT (" + tokenDetails.Name + ")", Align: AlignRight},
+		Column{Header: "DIRECTION"},
+		Column{Header: "COUNTERPARTY"},
+		Column{Header: "HASH"},
+	)
+
+	for _, xfr := range transfers {
+		direction, counterparty := "-", "-"
+		if isOutbound, _, ok := wallets.Direction(xfr); ok {
+			direction = transaction.ResolveDirection(isOutbound)
+
+			counterparty = xfr.FromAddress
+			if isOutbound {
+				counterparty = xfr.ToAddress
+			}
+
+			counterparty = addressFormat.Format(counterparty)
+		}
+
+		table.AddRow(
+			xfr.ExecutionTime.UTC().Format(time.DateTime),
+			xfr.FormatAmount(tokenDetails.Decimals),
+			direction,
+			counterparty,
+			TruncateHash(xfr.TransactionHash, previewHashLength),
+		)
+	}
+
+	if err := table.Write(writer); err != nil {
+		return fmt.Errorf("failed to write transfer preview: %w", err)
+	}
+
+	return nil
+}
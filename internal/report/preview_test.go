@@ -0,0 +1,58 @@
+package report_test
+
+import (
+	"bytes"
+	"math/big"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/eth"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteTransferPreview", func() {
+	It("writes an aligned row for each transfer", func() {
+		wallets := transaction.NewWallets("0xwallet")
+		transfers := []*transaction.Transfer{
+			{
+				TransactionHash: "0x3fe67569dfcce1fe4afca588192612717c7",
+				FromAddress:     "0xwallet",
+				ToAddress:       "0xMERCHANT",
+				Amount:          big.NewInt(101500000),
+				ExecutionTime:   time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC),
+			},
+			{
+				TransactionHash: "0xb4113e6ccf31511d5907a2dc418e43212bd1",
+				FromAddress:     "0xemployer",
+				ToAddress:       "0xwallet",
+				Amount:          big.NewInt(4157060000),
+				ExecutionTime:   time.Date(2025, time.December, 12, 23, 11, 3, 0, time.UTC),
+			},
+			{
+				TransactionHash: "0xabc",
+				FromAddress:     "0xelsewhere",
+				ToAddress:       "0xnowhere",
+				Amount:          big.NewInt(500000),
+				ExecutionTime:   time.Date(2025, time.December, 13, 0, 0, 0, 0, time.UTC),
+			},
+		}
+
+		var buf bytes.Buffer
+		Expect(report.WriteTransferPreview(
+			transfers,
+			wallets,
+			&token.Details{Name: "USDC", Decimals: 6},
+			eth.AddressFormatUnchanged,
+			&buf,
+		)).To(Succeed())
+
+		Expect(buf.String()).To(Equal("" +
+			"EXECUTED (UTC)       AMOUNT (USDC)  DIRECTION  COUNTERPARTY  HASH\n" +
+			"2025-12-10 11:53:23          101.5  to         0xMERCHANT    0x3fe6…2717c7\n" +
+			"2025-12-12 23:11:03        4157.06  from       0xemployer    0xb411…212bd1\n" +
+			"2025-12-13 00:00:00            0.5  -          -             0xabc\n"))
+	})
+})
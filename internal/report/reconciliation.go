@@ -0,0 +1,106 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/transfer"
+)
+
+// Reconciliation describes the differences between transfers and YNAB transactions.
+type Reconciliation struct {
+	TokenName             string                `json:"token_name"`             // the name of the token
+	Matched               []*MatchedTransaction `json:"matched"`                // YNAB transactions paired with a transfer
+	UnmatchedTransfers    []*Transfer           `json:"unmatched_transfers"`    // transfers with no YNAB transaction
+	UnmatchedTransactions []*Transaction        `json:"unmatched_transactions"` // YNAB transactions with no transfer
+}
+
+// NewReconciliation describes the given reconciliation of transfers of the given token.
+func NewReconciliation(
+	reconciliation *transfer.Reconciliation,
+	tokenName string,
+	decimals int,
+) *Reconciliation {
+	described := &Reconciliation{
+		TokenName:             tokenName,
+		Matched:               make([]*MatchedTransaction, 0, len(reconciliation.Matched)),
+		UnmatchedTransfers:    make([]*Transfer, 0, len(reconciliation.UnmatchedTransfers)),
+		UnmatchedTransactions: make([]*Transaction, 0, len(reconciliation.UnmatchedTransactions)),
+	}
+
+	for _, pair := range reconciliation.Matched {
+		described.Matched = append(described.Matched, &MatchedTransaction{
+			Transaction: NewTransaction(pair.Transaction),
+			Transfer:    NewTransfer(pair.Transfer, decimals),
+		})
+	}
+
+	for _, xfr := range reconciliation.UnmatchedTransfers {
+		described.UnmatchedTransfers = append(
+			described.UnmatchedTransfers,
+			NewTransfer(xfr, decimals),
+		)
+	}
+
+	for _, txn := range reconciliation.UnmatchedTransactions {
+		described.UnmatchedTransactions = append(
+			described.UnmatchedTransactions,
+			NewTransaction(txn),
+		)
+	}
+
+	return described
+}
+
+// WriteReconciliationMarkdown writes a human-readable Markdown rendering
+// of the given reconciliation to the given writer.
+func WriteReconciliationMarkdown(reconciliation *Reconciliation, writer io.Writer) error {
+	var sb strings.Builder
+
+	sb.WriteString("# Reconciliation Report\n\n")
+
+	sb.WriteString("## Transfers Without a YNAB Transaction\n\n")
+	writeTransferList(&sb, reconciliation.UnmatchedTransfers, reconciliation.TokenName)
+
+	sb.WriteString("\n## YNAB Transactions Without a Transfer\n\n")
+	if len(reconciliation.UnmatchedTransactions) == 0 {
+		sb.WriteString("_None_\n")
+	} else {
+		sb.WriteString("| Date | Payee | Amount | Memo |\n")
+		sb.WriteString("| --- | --- | ---: | --- |\n")
+		for _, unmatched := range reconciliation.UnmatchedTransactions {
+			writeTransactionRow(&sb, unmatched, unmatched.Memo)
+		}
+	}
+
+	sb.WriteString("\n## Matched Pairs\n\n")
+	if len(reconciliation.Matched) == 0 {
+		sb.WriteString("_None_\n")
+	} else {
+		sb.WriteString("| Date | Payee | Amount | Transaction Hash |\n")
+		sb.WriteString("| --- | --- | ---: | --- |\n")
+		for _, matched := range reconciliation.Matched {
+			writeTransactionRow(&sb, matched.Transaction, matched.Transfer.TransactionHash)
+		}
+	}
+
+	if _, err := io.WriteString(writer, sb.String()); err != nil {
+		return fmt.Errorf("failed to write Markdown reconciliation report: %w", err)
+	}
+
+	return nil
+}
+
+// WriteReconciliationJSON writes a JSON rendering of the given reconciliation to the given writer.
+func WriteReconciliationJSON(reconciliation *Reconciliation, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(reconciliation); err != nil {
+		return fmt.Errorf("failed to write JSON reconciliation report: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/transfer"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reconciliation", func() {
+	var reconciliation *report.Reconciliation
+
+	BeforeEach(func() {
+		date := time.Date(2025, time.December, 10, 0, 0, 0, 0, time.UTC)
+
+		reconciliation = report.NewReconciliation(&transfer.Reconciliation{
+			Matched: []*transfer.ReconciledPair{
+				{
+					Transaction: &client.Transaction{
+						ID:     "txn-matched",
+						Date:   date,
+						Payee:  "Coffee Shop",
+						Amount: -5000,
+					},
+					Transfer: &transaction.Transfer{
+						TransactionHash: "0xmatched",
+						Amount:          big.NewInt(5000000),
+						ExecutionTime:   date,
+					},
+				},
+			},
+			UnmatchedTransfers: []*transaction.Transfer{
+				{
+					TransactionHash: "0xmissing",
+					FromAddress:     "0xfrom",
+					ToAddress:       "0xto",
+					Amount:          big.NewInt(1500000),
+					ExecutionTime:   date,
+				},
+			},
+			UnmatchedTransactions: []*client.Transaction{
+				{
+					ID:          "txn-unmatched",
+					Date:        date,
+					Payee:       "Landlord",
+					Description: "rent",
+					Amount:      -1200000,
+				},
+			},
+		}, "USDC", 6)
+	})
+
+	It("describes each section of the reconciliation", func() {
+		Expect(reconciliation.TokenName).To(Equal("USDC"))
+		Expect(reconciliation.Matched).To(HaveLen(1))
+		Expect(reconciliation.Matched[0].Transaction.ID).To(Equal("txn-matched"))
+		Expect(reconciliation.Matched[0].Transfer.TransactionHash).To(Equal("0xmatched"))
+		Expect(reconciliation.UnmatchedTransfers).To(HaveLen(1))
+		Expect(reconciliation.UnmatchedTransfers[0].TransactionHash).To(Equal("0xmissing"))
+		Expect(reconciliation.UnmatchedTransfers[0].Amount).To(Equal("1.5"))
+		Expect(reconciliation.UnmatchedTransactions).To(HaveLen(1))
+		Expect(reconciliation.UnmatchedTransactions[0].ID).To(Equal("txn-unmatched"))
+	})
+
+	Context("WriteReconciliationMarkdown", func() {
+		It("lists each entry under its section", func() {
+			var buf bytes.Buffer
+			Expect(report.WriteReconciliationMarkdown(reconciliation, &buf)).To(Succeed())
+
+			output := buf.String()
+			missingSection, rest, found := strings.Cut(
+				output,
+				"## YNAB Transactions Without a Transfer",
+			)
+			Expect(found).To(BeTrue())
+			unmatchedSection, matchedSection, found := strings.Cut(rest, "## Matched Pairs")
+			Expect(found).To(BeTrue())
+
+			Expect(missingSection).To(ContainSubstring("## Transfers Without a YNAB Transaction"))
+			Expect(missingSection).To(ContainSubstring("`0xmissing`: 1.5 USDC"))
+			Expect(unmatchedSection).To(
+				ContainSubstring("| 2025-12-10 | Landlord | -$1200.00 | rent |"),
+			)
+			Expect(matchedSection).To(
+				ContainSubstring("| 2025-12-10 | Coffee Shop | -$5.00 | 0xmatched |"),
+			)
+		})
+
+		It("marks empty sections", func() {
+			var buf bytes.Buffer
+			Expect(
+				report.WriteReconciliationMarkdown(&report.Reconciliation{}, &buf),
+			).To(Succeed())
+			Expect(strings.Count(buf.String(), "_None_")).To(Equal(3))
+		})
+	})
+
+	Context("WriteReconciliationJSON", func() {
+		It("writes each section as a JSON array", func() {
+			var buf bytes.Buffer
+			Expect(report.WriteReconciliationJSON(reconciliation, &buf)).To(Succeed())
+
+			var decoded struct {
+				Matched []struct {
+					Transaction struct {
+						ID string `json:"id"`
+					} `json:"transaction"`
+					Transfer struct {
+						TransactionHash string `json:"transaction_hash"`
+					} `json:"transfer"`
+				} `json:"matched"`
+				UnmatchedTransfers []struct {
+					TransactionHash string `json:"transaction_hash"`
+				} `json:"unmatched_transfers"`
+				UnmatchedTransactions []struct {
+					ID string `json:"id"`
+				} `json:"unmatched_transactions"`
+			}
+			Expect(json.Unmarshal(buf.Bytes(), &decoded)).To(Succeed())
+
+			Expect(decoded.Matched).To(HaveLen(1))
+			Expect(decoded.Matched[0].Transaction.ID).To(Equal("txn-matched"))
+			Expect(decoded.Matched[0].Transfer.TransactionHash).To(Equal("0xmatched"))
+			Expect(decoded.UnmatchedTransfers).To(HaveLen(1))
+			Expect(decoded.UnmatchedTransfers[0].TransactionHash).To(Equal("0xmissing"))
+			Expect(decoded.UnmatchedTransactions).To(HaveLen(1))
+			Expect(decoded.UnmatchedTransactions[0].ID).To(Equal("txn-unmatched"))
+		})
+	})
+})
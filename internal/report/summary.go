@@ -0,0 +1,207 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/transfer"
+)
+
+// RunSummary describes the outcome of a synchronization run.
+type RunSummary struct {
+	TokenName           string                // the name of the token that was synchronized
+	ParsedTransferCount int                   // the number of transfers parsed from the input
+	ExcludedCount       int                   // the number of transfers with an excluded counterparty
+	Deduplicated        []*Transfer           // transfers dropped as duplicates of transfers kept
+	Matched             []*MatchedTransaction // uncleared YNAB transactions that were matched to a transfer
+	Unmatched           []*Transaction        // uncleared YNAB transactions that could not be matched to a transfer
+	Created             []*CreatedTransaction // YNAB transactions created from transfers
+	Ignored             []*Transfer           // transfers the user chose to ignore permanently
+	Skipped             []*Transfer           // transfers the user chose to skip for now
+	BelowMinimum        []*Transfer           // transfers too small to be offered for import
+	PhaseDurations      []*PhaseDuration      // the durations of the timed phases, in the order run
+}
+
+// The names of the phases of a synchronization run that are timed.
+const (
+	PhaseTokenDetails = "token details"
+	PhaseParse        = "parse"
+	PhaseMatch        = "match"
+	PhaseImport       = "import"
+)
+
+// PhaseDuration describes how long a phase of a synchronization run took.
+type PhaseDuration struct {
+	Name     string        // the name of the phase
+	Duration time.Duration // how long the phase took
+}
+
+// Transaction describes a YNAB transaction.
+type Transaction struct {
+	ID     string    `json:"id"`     // the ID of the transaction in YNAB
+	Date   time.Time `json:"date"`   // the date of the transaction
+	Payee  string    `json:"payee"`  // the name of the payee of the transaction
+	Memo   string    `json:"memo"`   // the memo of the transaction
+	Amount int64     `json:"amount"` // the amount of the transaction, in YNAB milliunits
+}
+
+// Transfer describes an onchain transfer.
+type Transfer struct {
+	TransactionHash string    `json:"transaction_hash"` // the hash of the transaction containing the transfer
+	FromAddress     string    `json:"from_address"`     // the address that sent the tokens
+	ToAddress       string    `json:"to_address"`       // the address that received the tokens
+	Amount          string    `json:"amount"`           // the amount of tokens transferred, in whole tokens
+	ExecutionTime   time.Time `json:"execution_time"`   // the time at which the transfer was executed
+}
+
+// MatchedTransaction pairs an uncleared YNAB transaction with the transfer it was matched to.
+type MatchedTransaction struct {
+	Transaction *Transaction `json:"transaction"`
+	Transfer    *Transfer    `json:"transfer"`
+}
+
+// CreatedTransaction pairs a YNAB transaction with the transfer from which it was created.
+type CreatedTransaction struct {
+	Transaction *Transaction `json:"transaction"`
+	Transfer    *Transfer    `json:"transfer"`
+}
+
+// NewTransaction describes the given YNAB transaction.
+func NewTransaction(txn *client.Transaction) *Transaction {
+	return &Transaction{
+		ID:     txn.ID,
+		Date:   txn.Date,
+		Payee:  txn.Payee,
+		Memo:   txn.Description,
+		Amount: txn.Amount,
+	}
+}
+
+// NewTransfer describes the given transfer of a token with the given number of decimals.
+func NewTransfer(xfr *transaction.Transfer, decimals int) *Transfer {
+	return &Transfer{
+		TransactionHash: xfr.TransactionHash,
+		FromAddress:     xfr.FromAddress,
+		ToAddress:       xfr.ToAddress,
+		Amount:          xfr.FormatAmount(decimals),
+		ExecutionTime:   xfr.ExecutionTime,
+	}
+}
+
+// NewDailyTotal describes the given daily total of a token with the given number of decimals as a single transfer
+// whose transaction hash lists the hashes of all of the total's constituent transfers.
+func NewDailyTotal(total *transaction.DailyTotal, decimals int) *Transfer {
+	return &Transfer{
+		TransactionHash: strings.Join(total.TransactionHashes(), ", "),
+		Amount:          total.FormatAmount(decimals),
+		ExecutionTime:   total.Date,
+	}
+}
+
+// NewRefundedTransfer describes the given partially-refunded transfer of a token with the given
+// number of decimals as a single transfer of its net amount whose transaction hash lists
+// the hashes of both the transfer and its refund.
+func NewRefundedTransfer(refunded *transfer.RefundedTransfer, decimals int) *Transfer {
+	described := NewTransfer(refunded.Transfer, decimals)
+	described.TransactionHash += ", " + refunded.Refund.TransactionHash
+	described.Amount = (&transaction.Transfer{Amount: refunded.NetAmount()}).FormatAmount(decimals)
+
+	return described
+}
+
+// AddImportResult records the outcome of importing transfers of a token with the given number of decimals.
+func (s *RunSummary) AddImportResult(result *transaction.ImportResult, decimals int) {
+	if result == nil {
+		return
+	}
+
+	for _, imported := range result.Created {
+		s.Created = append(s.Created, &CreatedTransaction{
+			Transaction: NewTransaction(imported.Transaction),
+			Transfer:    NewTransfer(imported.Transfer, decimals),
+		})
+	}
+
+	for _, xfr := range result.Ignored {
+		s.Ignored = append(s.Ignored, NewTransfer(xfr, decimals))
+	}
+
+	for _, xfr := range result.Skipped {
+		s.Skipped = append(s.Skipped, NewTransfer(xfr, decimals))
+	}
+
+	for _, xfr := range result.BelowMinimum {
+		s.BelowMinimum = append(s.BelowMinimum, NewTransfer(xfr, decimals))
+	}
+}
+
+// AddUnmatched records the given uncleared YNAB transaction as one that could not be matched
+// to a transfer.
+func (s *RunSummary) AddUnmatched(txn *client.Transaction) {
+	s.Unmatched = append(s.Unmatched, NewTransaction(txn))
+}
+
+// AddDeduplicated records the given transfers of a token with the given number of decimals
+// as having been dropped as duplicates of others that were kept.
+func (s *RunSummary) AddDeduplicated(duplicates []*transaction.Transfer, decimals int) {
+	for _, xfr := range duplicates {
+		s.Deduplicated = append(s.Deduplicated, NewTransfer(xfr, decimals))
+	}
+}
+
+// ErrTooManyUnmatched is returned by CheckUnmatched if more YNAB transactions were left unmatched
+// than allowed.
+var ErrTooManyUnmatched = errors.New("too many unmatched transactions")
+
+// CheckUnmatched returns an error wrapping ErrTooManyUnmatched if more than the given maximum
+// number of uncleared YNAB transactions were left unmatched, which suggests that the transfers
+// were read from an export that is out of date. A negative maximum imposes no limit.
+func (s *RunSummary) CheckUnmatched(maxUnmatched int) error {
+	if maxUnmatched < 0 || len(s.Unmatched) <= maxUnmatched {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: %d uncleared YNAB transactions were left unmatched, more than the maximum of %d",
+		ErrTooManyUnmatched,
+		len(s.Unmatched),
+		maxUnmatched,
+	)
+}
+
+// TimePhase runs the given phase and records how long it took under the given name.
+// The duration is recorded even if the phase fails; the phase's error is returned as-is.
+func (s *RunSummary) TimePhase(name string, phase func() error) error {
+	start := time.Now()
+	err := phase()
+	s.PhaseDurations = append(s.PhaseDurations, &PhaseDuration{
+		Name:     name,
+		Duration: time.Since(start),
+	})
+
+	return err
+}
+
+// MatchedTotal returns the sum of the amounts, in YNAB milliunits, of all matched transactions.
+func (s *RunSummary) MatchedTotal() int64 {
+	var total int64
+	for _, matched := range s.Matched {
+		total += matched.Transaction.Amount
+	}
+
+	return total
+}
+
+// CreatedTotal returns the sum of the amounts, in YNAB milliunits, of all created transactions.
+func (s *RunSummary) CreatedTotal() int64 {
+	var total int64
+	for _, created := range s.Created {
+		total += created.Transaction.Amount
+	}
+
+	return total
+}
@@ -0,0 +1,140 @@
+package report_test
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	clientpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RunSummary", func() {
+	Describe("AddDeduplicated", func() {
+		It("records each duplicate transfer with its amount in whole tokens", func() {
+			logIndex := 2
+			executed := time.Date(2025, time.December, 3, 12, 0, 0, 0, time.UTC)
+			summary := &report.RunSummary{}
+			summary.AddDeduplicated([]*transaction.Transfer{
+				{
+					TransactionHash: "0xduplicate",
+					LogIndex:        &logIndex,
+					FromAddress:     "0xwallet1",
+					ToAddress:       "0xwallet2",
+					Amount:          big.NewInt(3250000),
+					ExecutionTime:   executed,
+				},
+			}, 6)
+
+			Expect(summary.Deduplicated).To(Equal([]*report.Transfer{
+				{
+					TransactionHash: "0xduplicate",
+					FromAddress:     "0xwallet1",
+					ToAddress:       "0xwallet2",
+					Amount:          "3.25",
+					ExecutionTime:   executed,
+				},
+			}))
+		})
+
+		It("records nothing given no duplicates", func() {
+			summary := &report.RunSummary{}
+			summary.AddDeduplicated(nil, 6)
+
+			Expect(summary.Deduplicated).To(BeEmpty())
+		})
+	})
+
+	Describe("AddUnmatched", func() {
+		It("records each unmatched transaction in the order added", func() {
+			date := time.Date(2025, time.December, 3, 0, 0, 0, 0, time.UTC)
+			summary := &report.RunSummary{}
+			summary.AddUnmatched(&clientpkg.Transaction{
+				ID:          "txn-1",
+				Date:        date,
+				Amount:      -12340,
+				Payee:       "Coffee Shop",
+				Description: "Latte",
+			})
+			summary.AddUnmatched(&clientpkg.Transaction{ID: "txn-2", Date: date, Amount: 5000})
+
+			Expect(summary.Unmatched).To(Equal([]*report.Transaction{
+				{ID: "txn-1", Date: date, Payee: "Coffee Shop", Memo: "Latte", Amount: -12340},
+				{ID: "txn-2", Date: date, Amount: 5000},
+			}))
+			Expect(summary.CheckUnmatched(1)).To(MatchError(report.ErrTooManyUnmatched))
+		})
+	})
+
+	Describe("CheckUnmatched", func() {
+		var summary *report.RunSummary
+
+		BeforeEach(func() {
+			summary = &report.RunSummary{
+				Unmatched: []*report.Transaction{{ID: "txn-1"}, {ID: "txn-2"}},
+			}
+		})
+
+		It("fails if more transactions were left unmatched than the maximum", func() {
+			err := summary.CheckUnmatched(1)
+			Expect(err).To(MatchError(report.ErrTooManyUnmatched))
+			Expect(err).To(MatchError(ContainSubstring("2 uncleared YNAB transactions")))
+		})
+
+		It("succeeds if no more transactions were left unmatched than the maximum", func() {
+			Expect(summary.CheckUnmatched(2)).To(Succeed())
+		})
+
+		It("imposes no limit given a negative maximum", func() {
+			Expect(summary.CheckUnmatched(-1)).To(Succeed())
+		})
+	})
+
+	Describe("TimePhase", func() {
+		var summary *report.RunSummary
+
+		BeforeEach(func() {
+			summary = &report.RunSummary{}
+		})
+
+		It("records the duration of each phase in the order run", func() {
+			phaseNames := []string{
+				report.PhaseTokenDetails,
+				report.PhaseParse,
+				report.PhaseMatch,
+				report.PhaseImport,
+			}
+
+			var ran []string
+			for _, phaseName := range phaseNames {
+				Expect(summary.TimePhase(phaseName, func() error {
+					ran = append(ran, phaseName)
+
+					return nil
+				})).To(Succeed())
+			}
+
+			Expect(ran).To(Equal(phaseNames))
+			Expect(summary.PhaseDurations).To(HaveLen(len(phaseNames)))
+			for index, phase := range summary.PhaseDurations {
+				Expect(phase.Name).To(Equal(phaseNames[index]))
+				Expect(phase.Duration).To(BeNumerically(">=", 0))
+			}
+		})
+
+		It("records the duration of a failed phase and returns its error", func() {
+			phaseErr := errors.New("phase failed")
+
+			err := summary.TimePhase(report.PhaseImport, func() error {
+				return phaseErr
+			})
+			Expect(err).To(MatchError(phaseErr))
+			Expect(summary.PhaseDurations).To(HaveLen(1))
+			Expect(summary.PhaseDurations[0].Name).To(Equal(report.PhaseImport))
+			Expect(summary.PhaseDurations[0].Duration).To(BeNumerically(">=", 0))
+		})
+	})
+})
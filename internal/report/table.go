@@ -0,0 +1,102 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Alignment describes how the cells of a table column are aligned.
+type Alignment int
+
+const (
+	// AlignLeft pads the cells of a column on the right.
+	AlignLeft Alignment = iota
+	// AlignRight pads the cells of a column on the left, e.g., to line up amounts.
+	AlignRight
+)
+
+// Column describes a column of a Table.
+type Column struct {
+	Header string    // the header of the column
+	Align  Alignment // how the header and cells of the column are aligned
+}
+
+// Table renders rows of cells as plain text in columns padded to the width of their widest cell,
+// separated by two spaces. Widths are measured in runes.
+type Table struct {
+	columns []Column
+	rows    [][]string
+}
+
+// NewTable creates a table with the given columns and no rows.
+func NewTable(columns ...Column) *Table {
+	return &Table{columns: columns}
+}
+
+// AddRow adds a row with the given cells, one per column. Missing cells are left blank
+// and extra cells are ignored.
+func (t *Table) AddRow(cells ...string) {
+	row := make([]string, len(t.columns))
+	copy(row, cells)
+	t.rows = append(t.rows, row)
+}
+
+// Write writes the header and rows of the table to the given writer, one line each.
+func (t *Table) Write(writer io.Writer) error {
+	headers := make([]string, len(t.columns))
+	widths := make([]int, len(t.columns))
+
+	for i, column := range t.columns {
+		headers[i] = column.Header
+		widths[i] = utf8.RuneCountInString(column.Header)
+	}
+
+	for _, row := range t.rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	for _, row := range append([][]string{headers}, t.rows...) {
+		if _, err := io.WriteString(writer, t.formatRow(row, widths)+"\n"); err != nil {
+			return fmt.Errorf("failed to write table row: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// formatRow pads each of the given cells to the width of its column, trimming the padding
+// at the end of the line.
+func (t *Table) formatRow(row []string, widths []int) string {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		if t.columns[i].Align == AlignRight {
+			cells[i] = padding + cell
+		} else {
+			cells[i] = cell + padding
+		}
+	}
+
+	return strings.TrimRight(strings.Join(cells, "  "), " ")
+}
+
+// TruncateHash shortens the given transaction hash to at most the given number of runes
+// by replacing its middle with an ellipsis, keeping its start and end, e.g., "0x3fe6…2717c7".
+// A hash no longer than that, or a length too short to keep both ends, leaves it as-is.
+func TruncateHash(hash string, length int) string {
+	runes := []rune(hash)
+	//nolint:mnd // at least one rune of each end on either side of the ellipsis
+	if len(runes) <= length || length < 3 {
+		return hash
+	}
+
+	kept := length - 1
+	tail := kept / 2 //nolint:mnd
+	head := kept - tail
+
+	return string(runes[:head]) + "…" + string(runes[len(runes)-tail:])
+}
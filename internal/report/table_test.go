@@ -0,0 +1,76 @@
+package report_test
+
+import (
+	"bytes"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+	It("pads each column to its widest cell and right-justifies right-aligned columns", func() {
+		table := report.NewTable(
+			report.Column{Header: "NAME"},
+			report.Column{Header: "AMOUNT", Align: report.AlignRight},
+			report.Column{Header: "NOTE"},
+		)
+		table.AddRow("coffee", "4.5", "latte")
+		table.AddRow("rent", "1250", "")
+		table.AddRow("refund", "-12.25", "partial")
+
+		var buf bytes.Buffer
+		Expect(table.Write(&buf)).To(Succeed())
+		Expect(buf.String()).To(Equal("" +
+			"NAME    AMOUNT  NOTE\n" +
+			"coffee     4.5  latte\n" +
+			"rent      1250\n" +
+			"refund  -12.25  partial\n"))
+	})
+
+	It("measures widths in runes", func() {
+		table := report.NewTable(report.Column{Header: "A"}, report.Column{Header: "B"})
+		table.AddRow("0x12…ab", "x")
+		table.AddRow("0x1234", "y")
+
+		var buf bytes.Buffer
+		Expect(table.Write(&buf)).To(Succeed())
+		Expect(buf.String()).To(Equal("" +
+			"A        B\n" +
+			"0x12…ab  x\n" +
+			"0x1234   y\n"))
+	})
+
+	It("leaves missing cells blank and ignores extra cells", func() {
+		table := report.NewTable(report.Column{Header: "A"}, report.Column{Header: "B"})
+		table.AddRow("1")
+		table.AddRow("2", "3", "4")
+
+		var buf bytes.Buffer
+		Expect(table.Write(&buf)).To(Succeed())
+		Expect(buf.String()).To(Equal("A  B\n1\n2  3\n"))
+	})
+
+	It("writes only the header when there are no rows", func() {
+		table := report.NewTable(report.Column{Header: "HASH"}, report.Column{Header: "AMOUNT"})
+
+		var buf bytes.Buffer
+		Expect(table.Write(&buf)).To(Succeed())
+		Expect(buf.String()).To(Equal("HASH  AMOUNT\n"))
+	})
+})
+
+var _ = DescribeTable("TruncateHash",
+	func(hash string, length int, expected string) {
+		Expect(report.TruncateHash(hash, length)).To(Equal(expected))
+	},
+	Entry(
+		"keeps the start and end of a long hash",
+		"0x3fe67569dfcce1fe4afca58819da01f423b2cb67d61ee3ba1ed413d2612717c7",
+		13,
+		"0x3fe6…2717c7",
+	),
+	Entry("keeps one more rune of the start given an even length", "0123456789", 8, "0123…789"),
+	Entry("leaves a hash no longer than the length as-is", "0xabc", 5, "0xabc"),
+	Entry("leaves a hash as-is given too short a length", "0x3fe675", 2, "0x3fe675"),
+)
@@ -0,0 +1,40 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+)
+
+// WriteUnmatchedCSV writes the uncleared YNAB transactions of the given summary that could not be
+// matched to a transfer to the given writer as CSV, one row per transaction, for manual review.
+// The columns are the transaction's ID, date, amount, payee and memo.
+func WriteUnmatchedCSV(summary *RunSummary, writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+
+	if err := csvWriter.Write([]string{"id", "date", "amount", "payee", "memo"}); err != nil {
+		return fmt.Errorf("failed to write unmatched transactions header: %w", err)
+	}
+
+	for _, unmatched := range summary.Unmatched {
+		if err := csvWriter.Write([]string{
+			unmatched.ID,
+			unmatched.Date.Format(time.DateOnly),
+			client.FormatMilliunits(unmatched.Amount),
+			unmatched.Payee,
+			unmatched.Memo,
+		}); err != nil {
+			return fmt.Errorf("failed to write unmatched transaction '%s': %w", unmatched.ID, err)
+		}
+	}
+
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return fmt.Errorf("failed to flush unmatched transactions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/report"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteUnmatchedCSV", func() {
+	It("writes a row for each unmatched transaction", func() {
+		summary := &report.RunSummary{
+			Unmatched: []*report.Transaction{
+				{
+					ID:     "txn-1",
+					Date:   time.Date(2025, time.December, 3, 0, 0, 0, 0, time.UTC),
+					Payee:  "Coffee Shop",
+					Memo:   "Latte, large",
+					Amount: -12340,
+				},
+				{
+					ID:     "txn-2",
+					Date:   time.Date(2025, time.December, 5, 0, 0, 0, 0, time.UTC),
+					Amount: 5000,
+				},
+			},
+		}
+
+		var buf bytes.Buffer
+		Expect(report.WriteUnmatchedCSV(summary, &buf)).To(Succeed())
+
+		Expect(buf.String()).To(Equal("id,date,amount,payee,memo\n" +
+			"txn-1,2025-12-03,-$12.34,Coffee Shop,\"Latte, large\"\n" +
+			"txn-2,2025-12-05,$5.00,,\n"))
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(HaveLen(3))
+		Expect(records[1][4]).To(Equal("Latte, large"))
+	})
+
+	It("writes only the header when every transaction was matched", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteUnmatchedCSV(&report.RunSummary{}, &buf)).To(Succeed())
+		Expect(buf.String()).To(Equal("id,date,amount,payee,memo\n"))
+	})
+})
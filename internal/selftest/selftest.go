@@ -0,0 +1,276 @@
+package selftest
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/transfer"
+)
+
+// fixtureCSV is an Etherscan export of two outbound USDC transfers from fixtureWallet.
+//
+//go:embed etherscan_usdc_export.csv
+var fixtureCSV string
+
+// fixtureWallet is the wallet from which the transfers of fixtureCSV were sent.
+const fixtureWallet = "0x9134fc7112b478e97eE6F0E6A7bf81EcAfef19ED"
+
+// expectedTransfer describes a transfer of fixtureCSV and the YNAB amount it corresponds to.
+type expectedTransfer struct {
+	hash       string
+	amount     *big.Int // in the token's base unit
+	date       time.Time
+	milliunits int64 // the amount of the corresponding YNAB transaction
+}
+
+// ErrFailed is returned by Run if any of its checks failed.
+var ErrFailed = errors.New("self-test failed")
+
+// check is a named step of the self-test, returning an error describing how it failed.
+type check struct {
+	name string
+	run  func(ctx context.Context, state *state) error
+}
+
+// state is carried from one check to the next.
+type state struct {
+	tokenDetails *token.Details
+	wallets      *transaction.Wallets
+	expected     []expectedTransfer
+	transfers    []*transaction.Transfer // the transfers parsed from fixtureCSV
+}
+
+// Run verifies the parsing of an Etherscan CSV, the matching of YNAB transactions to its
+// transfers and the conversion of its transfers to YNAB transactions against embedded fixture
+// data, writing whether each check passed to the given writer.
+// No network requests are made. If any check fails, an error wrapping ErrFailed is returned.
+func Run(ctx context.Context, writer io.Writer) error {
+	checks := []check{
+		{name: "parse Etherscan CSV", run: checkParse},
+		{name: "match YNAB transactions to transfers", run: checkMatch},
+		{name: "convert transfers to YNAB transactions", run: checkConvert},
+	}
+
+	runState := &state{
+		tokenDetails: &token.Details{Name: "USDC", Decimals: 6}, //nolint:mnd
+		wallets:      transaction.NewWallets(fixtureWallet),
+		expected:     fixtureTransfers(),
+	}
+
+	failed := 0
+
+	for _, c := range checks {
+		err := c.run(ctx, runState)
+		if err != nil {
+			failed++
+
+			_, _ = fmt.Fprintf(writer, "FAIL: %s: %v\n", c.name, err)
+
+			continue
+		}
+
+		_, _ = fmt.Fprintf(writer, "PASS: %s\n", c.name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d checks failed", ErrFailed, failed, len(checks))
+	}
+
+	return nil
+}
+
+// fixtureTransfers returns the transfers of fixtureCSV, in order.
+//
+//nolint:mnd
+func fixtureTransfers() []expectedTransfer {
+	return []expectedTransfer{
+		{
+			hash:       "0x3fe67569dfcce1fe4afca58819da01f423b2cb67d61ee3ba1ed413d2612717c7",
+			amount:     big.NewInt(101500000),
+			date:       time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC),
+			milliunits: -101500,
+		},
+		{
+			hash:       "0xb4113e6ccf31511d5907a2dc41826948c8f80212d176ce66868d736e43212bd1",
+			amount:     big.NewInt(4157060000),
+			date:       time.Date(2025, time.December, 12, 23, 11, 3, 0, time.UTC),
+			milliunits: -4157060,
+		},
+	}
+}
+
+// checkParse verifies that the transfers of the fixture CSV are parsed as expected.
+func checkParse(ctx context.Context, runState *state) error {
+	transfers, err := transaction.TransfersFromEtherscanCSV(
+		ctx,
+		runState.tokenDetails,
+		strings.NewReader(fixtureCSV),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to parse CSV: %w", err)
+	}
+
+	if len(transfers) != len(runState.expected) {
+		return fmt.Errorf("expected %d transfers, got %d", len(runState.expected), len(transfers))
+	}
+
+	for i, expected := range runState.expected {
+		xfr := transfers[i]
+
+		switch {
+		case xfr.TransactionHash != expected.hash:
+			return fmt.Errorf(
+				"transfer %d: expected hash %s, got %s",
+				i,
+				expected.hash,
+				xfr.TransactionHash,
+			)
+		case xfr.Amount.Cmp(expected.amount) != 0:
+			return fmt.Errorf(
+				"transfer %d: expected amount %s, got %s",
+				i,
+				expected.amount,
+				xfr.Amount,
+			)
+		case !xfr.ExecutionTime.Equal(expected.date):
+			return fmt.Errorf(
+				"transfer %d: expected execution time %s, got %s",
+				i,
+				expected.date.Format(time.RFC3339),
+				xfr.ExecutionTime.Format(time.RFC3339),
+			)
+		}
+	}
+
+	runState.transfers = transfers
+
+	return nil
+}
+
+// checkMatch verifies that a YNAB transaction for each transfer of the fixture CSV is matched
+// to that transfer alone.
+func checkMatch(_ context.Context, runState *state) error {
+	if len(runState.transfers) == 0 {
+		return errors.New("no transfers were parsed")
+	}
+
+	for i, expected := range runState.expected {
+		ynabTransaction := &client.Transaction{
+			ID:     fmt.Sprintf("selftest%d", i),
+			Amount: expected.milliunits,
+			Date:   expected.date.Truncate(24 * time.Hour), //nolint:mnd
+		}
+
+		matches := transfer.MatchTransfers(
+			ynabTransaction,
+			runState.wallets,
+			runState.tokenDetails,
+			runState.transfers,
+		)
+		if len(matches) != 1 || matches[0].TransactionHash != expected.hash {
+			return fmt.Errorf(
+				"expected the transaction of %s to match only transfer %s, got %d matches",
+				client.FormatMilliunits(expected.milliunits),
+				expected.hash,
+				len(matches),
+			)
+		}
+	}
+
+	return nil
+}
+
+// checkConvert verifies that, in a dry run of an import, the YNAB transaction for each transfer
+// of the fixture CSV has the expected amount and date.
+func checkConvert(ctx context.Context, runState *state) error {
+	if len(runState.transfers) == 0 {
+		return errors.New("no transfers were parsed")
+	}
+
+	result, err := transaction.ImportRemainingTransfers(
+		ctx,
+		&offlineDoer{},
+		"",
+		"selftest",
+		"selftest",
+		runState.transfers,
+		runState.tokenDetails,
+		runState.wallets,
+		transaction.NewIgnoreList(),
+		transaction.ImportOptions{
+			Prompter: &acceptingPrompter{},
+			DryRun:   true,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to import transfers: %w", err)
+	}
+
+	if len(result.Created) != len(runState.expected) {
+		return fmt.Errorf(
+			"expected %d transactions, got %d",
+			len(runState.expected),
+			len(result.Created),
+		)
+	}
+
+	for i, expected := range runState.expected {
+		created := result.Created[i].Transaction
+		if created.Amount != expected.milliunits {
+			return fmt.Errorf(
+				"transaction %d: expected amount %s, got %s",
+				i,
+				client.FormatMilliunits(expected.milliunits),
+				client.FormatMilliunits(created.Amount),
+			)
+		}
+
+		if !created.Date.Equal(expected.date) {
+			return fmt.Errorf(
+				"transaction %d: expected date %s, got %s",
+				i,
+				expected.date.Format(time.DateOnly),
+				created.Date.Format(time.DateOnly),
+			)
+		}
+	}
+
+	return nil
+}
+
+// acceptingPrompter answers every prompt with its first choice or default value.
+type acceptingPrompter struct{}
+
+func (*acceptingPrompter) Select(string, []string) (int, error) {
+	return 0, nil
+}
+
+func (*acceptingPrompter) Input(_ string, defaultValue string) (string, error) {
+	return defaultValue, nil
+}
+
+func (*acceptingPrompter) MultiSelect(_ string, items []string) ([]int, error) {
+	chosen := make([]int, len(items))
+	for i := range items {
+		chosen[i] = i
+	}
+
+	return chosen, nil
+}
+
+// offlineDoer fails every request, as the self-test is not to make any.
+type offlineDoer struct{}
+
+func (*offlineDoer) Do(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("the self-test makes no requests, but one was made to %s", req.URL)
+}
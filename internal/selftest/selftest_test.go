@@ -0,0 +1,23 @@
+package selftest_test
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/selftest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Run", func() {
+	It("passes every check against the embedded fixtures", func() {
+		var output bytes.Buffer
+
+		Expect(selftest.Run(context.Background(), &output)).To(Succeed())
+		Expect(output.String()).To(Equal(
+			"PASS: parse Etherscan CSV\n" +
+				"PASS: match YNAB transactions to transfers\n" +
+				"PASS: convert transfers to YNAB transactions\n",
+		))
+	})
+})
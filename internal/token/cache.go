@@ -0,0 +1,224 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultCacheTTL is the default length of time for which cached token details are used.
+const DefaultCacheTTL = 30 * 24 * time.Hour
+
+// DetailsCache holds previously-fetched token details, keyed by chain and contract address.
+type DetailsCache struct {
+	entries map[string]*cachedDetails
+}
+
+type cachedDetails struct {
+	chainID         int64
+	contractAddress string
+	details         *Details
+	fetchedAt       time.Time
+}
+
+// NewDetailsCache creates an empty token details cache.
+func NewDetailsCache() *DetailsCache {
+	return &DetailsCache{
+		entries: make(map[string]*cachedDetails),
+	}
+}
+
+// Get returns the cached details of the given token on the given chain, or nil if the token
+// is not cached or its details were fetched longer than the given TTL before the given time.
+func (c *DetailsCache) Get(
+	chainID int64,
+	contractAddress string,
+	now time.Time,
+	ttl time.Duration,
+) *Details {
+	entry, ok := c.entries[cacheKey(chainID, contractAddress)]
+	if !ok || now.Sub(entry.fetchedAt) > ttl {
+		return nil
+	}
+
+	return entry.details
+}
+
+// Put caches the given details of the given token on the given chain
+// as having been fetched at the given time.
+func (c *DetailsCache) Put(
+	chainID int64,
+	contractAddress string,
+	details *Details,
+	fetchedAt time.Time,
+) {
+	c.entries[cacheKey(chainID, contractAddress)] = &cachedDetails{
+		chainID:         chainID,
+		contractAddress: contractAddress,
+		details:         details,
+		fetchedAt:       fetchedAt,
+	}
+}
+
+func cacheKey(chainID int64, contractAddress string) string {
+	return strconv.FormatInt(chainID, 10) + ":" + strings.ToLower(contractAddress)
+}
+
+// CachingDetailsService is a DetailsService that serves token details from a cache,
+// fetching them from another DetailsService when they are not cached or have expired.
+type CachingDetailsService struct {
+	delegate DetailsService
+	cache    *DetailsCache
+	chainID  int64
+	ttl      time.Duration
+	refresh  bool
+	now      func() time.Time
+}
+
+var _ DetailsService = (*CachingDetailsService)(nil)
+
+// CachingOption configures a CachingDetailsService.
+type CachingOption func(*CachingDetailsService)
+
+// WithCacheTTL sets the length of time for which cached token details are used.
+func WithCacheTTL(ttl time.Duration) CachingOption {
+	return func(service *CachingDetailsService) {
+		service.ttl = ttl
+	}
+}
+
+// WithForcedRefresh, if refresh is true, causes token details to always be fetched
+// and the cache to be updated.
+func WithForcedRefresh(refresh bool) CachingOption {
+	return func(service *CachingDetailsService) {
+		service.refresh = refresh
+	}
+}
+
+// WithClock sets the function used to determine the current time.
+func WithClock(now func() time.Time) CachingOption {
+	return func(service *CachingDetailsService) {
+		service.now = now
+	}
+}
+
+// NewCachingDetailsService creates a service that caches, in the given cache, the details of tokens
+// on the chain with the given ID as fetched by the given delegate.
+func NewCachingDetailsService(
+	delegate DetailsService,
+	cache *DetailsCache,
+	chainID int64,
+	opts ...CachingOption,
+) *CachingDetailsService {
+	service := &CachingDetailsService{
+		delegate: delegate,
+		cache:    cache,
+		chainID:  chainID,
+		ttl:      DefaultCacheTTL,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// GetTokenDetails returns the cached details of the given token if they have not expired;
+// otherwise, it fetches and caches them.
+func (c *CachingDetailsService) GetTokenDetails(
+	ctx context.Context,
+	contractAddress string,
+) (*Details, error) {
+	now := c.now()
+	if !c.refresh {
+		if details := c.cache.Get(c.chainID, contractAddress, now, c.ttl); details != nil {
+			return details, nil
+		}
+	}
+
+	details, err := c.delegate.GetTokenDetails(ctx, contractAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if details != nil {
+		c.cache.Put(c.chainID, contractAddress, details, now)
+	}
+
+	return details, nil
+}
+
+// DetailsCacheFromYAML reads a DetailsCache from a YAML representation.
+func DetailsCacheFromYAML(reader io.Reader) (*DetailsCache, error) {
+	var ymlCache yamlDetailsCache
+	if err := yaml.NewDecoder(reader).Decode(&ymlCache); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode token details cache from YAML: %w", err)
+	}
+
+	cache := NewDetailsCache()
+	for _, ymlEntry := range ymlCache.Tokens {
+		cache.Put(ymlEntry.ChainID, ymlEntry.ContractAddress, &Details{
+			Name:     ymlEntry.Name,
+			Symbol:   ymlEntry.Symbol,
+			Decimals: ymlEntry.Decimals,
+		}, ymlEntry.FetchedAt)
+	}
+
+	return cache, nil
+}
+
+// DetailsCacheToYAML writes a DetailsCache to a YAML representation.
+func DetailsCacheToYAML(cache *DetailsCache, writer io.Writer) error {
+	var ymlCache yamlDetailsCache
+	for _, entry := range cache.entries {
+		ymlCache.Tokens = append(ymlCache.Tokens, yamlCachedDetails{
+			ChainID:         entry.chainID,
+			ContractAddress: entry.contractAddress,
+			Name:            entry.details.Name,
+			Symbol:          entry.details.Symbol,
+			Decimals:        entry.details.Decimals,
+			FetchedAt:       entry.fetchedAt,
+		})
+	}
+
+	// write the entries in a stable order
+	slices.SortFunc(ymlCache.Tokens, func(a, b yamlCachedDetails) int {
+		return strings.Compare(
+			cacheKey(a.ChainID, a.ContractAddress),
+			cacheKey(b.ChainID, b.ContractAddress),
+		)
+	})
+
+	encoder := yaml.NewEncoder(writer)
+	defer func() { _ = encoder.Close() }()
+
+	if err := encoder.Encode(&ymlCache); err != nil {
+		return fmt.Errorf("failed to encode token details cache to YAML: %w", err)
+	}
+
+	return nil
+}
+
+// yamlDetailsCache is an internal struct for YAML serialization.
+type yamlDetailsCache struct {
+	Tokens []yamlCachedDetails `yaml:"tokens"`
+}
+
+// yamlCachedDetails is an internal struct for YAML serialization.
+type yamlCachedDetails struct {
+	ChainID         int64     `yaml:"chain_id"`
+	ContractAddress string    `yaml:"contract_address"`
+	Name            string    `yaml:"name"`
+	Symbol          string    `yaml:"symbol,omitempty"`
+	Decimals        int       `yaml:"decimals"`
+	FetchedAt       time.Time `yaml:"fetched_at"`
+}
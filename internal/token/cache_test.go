@@ -0,0 +1,146 @@
+package token_test
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	tokenpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CachingDetailsService", func() {
+	const (
+		chainID         = int64(8453)
+		contractAddress = "0xToken"
+	)
+
+	var ctx context.Context
+	var now time.Time
+	var cache *tokenpkg.DetailsCache
+	var delegate *countingDetailsService
+
+	newService := func(opts ...tokenpkg.CachingOption) *tokenpkg.CachingDetailsService {
+		opts = append(opts, tokenpkg.WithClock(func() time.Time { return now }))
+
+		return tokenpkg.NewCachingDetailsService(delegate, cache, chainID, opts...)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Date(2025, time.December, 10, 0, 0, 0, 0, time.UTC)
+		cache = tokenpkg.NewDetailsCache()
+		delegate = &countingDetailsService{
+			details: &tokenpkg.Details{Name: "USD Coin", Decimals: 6},
+		}
+	})
+
+	It("fetches and caches details that are not cached", func() {
+		details, err := newService().GetTokenDetails(ctx, contractAddress)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(details).To(Equal(delegate.details))
+		Expect(delegate.calls).To(Equal(1))
+
+		Expect(cache.Get(chainID, "0xtoken", now, time.Hour)).To(Equal(delegate.details))
+	})
+
+	It("serves cached details without fetching them", func() {
+		cache.Put(
+			chainID,
+			contractAddress,
+			&tokenpkg.Details{Name: "Cached", Decimals: 18},
+			now.Add(-time.Hour),
+		)
+
+		details, err := newService().GetTokenDetails(ctx, contractAddress)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(details.Name).To(Equal("Cached"))
+		Expect(delegate.calls).To(BeZero())
+	})
+
+	It("does not serve details cached for another chain", func() {
+		cache.Put(1, contractAddress, &tokenpkg.Details{Name: "Mainnet", Decimals: 18}, now)
+
+		details, err := newService().GetTokenDetails(ctx, contractAddress)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(details.Name).To(Equal("USD Coin"))
+		Expect(delegate.calls).To(Equal(1))
+	})
+
+	It("fetches details again once the cached details expire", func() {
+		cache.Put(
+			chainID,
+			contractAddress,
+			&tokenpkg.Details{Name: "Stale", Decimals: 18},
+			now.Add(-2*time.Hour),
+		)
+
+		details, err := newService(
+			tokenpkg.WithCacheTTL(time.Hour),
+		).GetTokenDetails(ctx, contractAddress)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(details.Name).To(Equal("USD Coin"))
+		Expect(delegate.calls).To(Equal(1))
+		Expect(cache.Get(chainID, contractAddress, now, time.Hour).Name).To(Equal("USD Coin"))
+	})
+
+	It("fetches details again when a refresh is forced", func() {
+		cache.Put(chainID, contractAddress, &tokenpkg.Details{Name: "Cached", Decimals: 18}, now)
+
+		details, err := newService(
+			tokenpkg.WithForcedRefresh(true),
+		).GetTokenDetails(ctx, contractAddress)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(details.Name).To(Equal("USD Coin"))
+		Expect(delegate.calls).To(Equal(1))
+		Expect(cache.Get(chainID, contractAddress, now, time.Hour).Name).To(Equal("USD Coin"))
+	})
+})
+
+var _ = Describe("DetailsCache YAML", func() {
+	It("round-trips the cached details", func() {
+		fetchedAt := time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC)
+
+		cache := tokenpkg.NewDetailsCache()
+		cache.Put(
+			8453,
+			"0xtoken",
+			&tokenpkg.Details{Name: "USD Coin", Symbol: "USDC", Decimals: 6},
+			fetchedAt,
+		)
+		cache.Put(1, "0xother", &tokenpkg.Details{Name: "Other", Decimals: 18}, fetchedAt)
+
+		var buf bytes.Buffer
+		Expect(tokenpkg.DetailsCacheToYAML(cache, &buf)).To(Succeed())
+
+		readCache, err := tokenpkg.DetailsCacheFromYAML(&buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readCache.Get(8453, "0xtoken", fetchedAt, time.Hour)).
+			To(Equal(&tokenpkg.Details{Name: "USD Coin", Symbol: "USDC", Decimals: 6}))
+		Expect(readCache.Get(1, "0xother", fetchedAt, time.Hour)).
+			To(Equal(&tokenpkg.Details{Name: "Other", Decimals: 18}))
+	})
+
+	It("reads an empty file as an empty cache", func() {
+		cache, err := tokenpkg.DetailsCacheFromYAML(bytes.NewReader(nil))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cache.Get(8453, "0xtoken", time.Now(), time.Hour)).To(BeNil())
+	})
+})
+
+// countingDetailsService is a token.DetailsService that returns fixed details
+// and counts how often it is called.
+type countingDetailsService struct {
+	details *tokenpkg.Details
+	calls   int
+}
+
+func (c *countingDetailsService) GetTokenDetails(
+	_ context.Context,
+	_ string,
+) (*tokenpkg.Details, error) {
+	c.calls++
+
+	return c.details, nil
+}
@@ -3,5 +3,6 @@ package token
 // Details describes the details of a token.
 type Details struct {
 	Name     string // the name of the token, e.g., "USD Coin"
+	Symbol   string // the symbol of the token, e.g., "USDC"; empty if the token has none
 	Decimals int    // the power of ten to use when representing the "whole" unit of the token from its base value
 }
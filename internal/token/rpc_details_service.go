@@ -1,52 +1,77 @@
 package token
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"math/big"
-	"net/http"
 	"strings"
+	"unicode/utf8"
 
 	ctshttp "github.com/jrh3k5/cryptonabber-txn-sync/internal/http"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/jsonrpc"
 )
 
+// MaxDecimals is the largest number of decimals accepted from a token's `decimals()` method.
+// Real tokens use far fewer; more suggests a broken or malicious contract.
+const MaxDecimals = 36
+
+// ErrImplausibleDecimals is returned by RPCDetailsService.GetTokenDetails if the token's
+// `decimals()` method returns more than MaxDecimals.
+var ErrImplausibleDecimals = errors.New("implausible decimals value")
+
 // RPCDetailsService implements DetailsService by calling an RPC node.
 type RPCDetailsService struct {
-	doer   ctshttp.Doer
-	rpcURL string
+	rpcClient   *jsonrpc.Client
+	blockObject bool // whether the block of an eth_call is given as an object rather than a string
+	decimals    *int // the decimals of the token, if known without calling decimals()
 }
 
-// NewRPCDetailsService returns a DetailsService that uses the provided HTTP client
-// and RPC node URL to perform JSON-RPC calls.
-func NewRPCDetailsService(client ctshttp.Doer, rpcURL string) *RPCDetailsService {
-	return &RPCDetailsService{doer: client, rpcURL: rpcURL}
-}
+// RPCDetailsOption configures an RPCDetailsService.
+type RPCDetailsOption func(*RPCDetailsService)
 
-type rpcRequest struct {
-	JSONRPC string `json:"jsonrpc"`
-	ID      int    `json:"id"`
-	Method  string `json:"method"`
-	Params  []any  `json:"params"`
+// WithBlockObjectParam, if given true, causes the block of each `eth_call` to be given as an
+// object (i.e., {"blockNumber":"latest"}) rather than as the bare string "latest", as some
+// RPC nodes require.
+// Without it, the object form is still used once the node rejects the string form
+// as invalid parameters.
+func WithBlockObjectParam(blockObject bool) RPCDetailsOption {
+	return func(service *RPCDetailsService) {
+		service.blockObject = blockObject
+	}
 }
 
-type rpcError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+// WithDecimals sets the decimals of the token, for tokens whose `decimals()` method reverts or
+// is missing. The method is then not called; the name and symbol are still fetched.
+func WithDecimals(decimals int) RPCDetailsOption {
+	return func(service *RPCDetailsService) {
+		service.decimals = &decimals
+	}
 }
 
-type rpcResponse struct {
-	JSONRPC string    `json:"jsonrpc"`
-	ID      int       `json:"id"`
-	Result  string    `json:"result,omitempty"`
-	Error   *rpcError `json:"error,omitempty"`
+// NewRPCDetailsService returns a DetailsService that uses the provided HTTP client
+// and RPC node URL to perform JSON-RPC calls.
+func NewRPCDetailsService(
+	client ctshttp.Doer,
+	rpcURL string,
+	opts ...RPCDetailsOption,
+) *RPCDetailsService {
+	service := &RPCDetailsService{rpcClient: jsonrpc.NewClient(client, rpcURL)}
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
 }
 
 // GetTokenDetails fetches the token decimals by calling the `decimals()` ERC20 method
-// using `eth_call` on the RPC node. If no result is returned, it returns (nil, nil).
+// using `eth_call` on the RPC node, unless they were given with WithDecimals.
+// If no result is returned, it returns (nil, nil).
+// The token's name and symbol are fetched with the `name()` and `symbol()` methods;
+// as some tokens omit these, a failure to fetch either leaves it empty rather than failing.
 func (r *RPCDetailsService) GetTokenDetails(
 	ctx context.Context,
 	contractAddress string,
@@ -55,50 +80,74 @@ func (r *RPCDetailsService) GetTokenDetails(
 	decimalsData := "0x313ce567"
 	// name() selector
 	nameData := "0x06fdde03"
+	// symbol() selector
+	symbolData := "0x95d89b41"
 
-	// prepare params: call object and block param for decimals
-	callObjDecimals := map[string]string{
-		"to":   contractAddress,
-		"data": decimalsData,
+	var details *Details
+	if r.decimals != nil {
+		details = &Details{Decimals: *r.decimals}
+	} else {
+		decimalsResult, err := r.ethCall(ctx, contractAddress, decimalsData)
+		if err != nil {
+			return nil, err
+		}
+
+		details, err = r.parseDecimalsFromResult(ctx, decimalsResult)
+		if err != nil || details == nil {
+			return details, err
+		}
 	}
-	reqBodyDecimals := rpcRequest{
-		JSONRPC: "2.0",
-		ID:      1,
-		Method:  "eth_call",
-		Params:  []any{callObjDecimals, "latest"},
+
+	nameResult, err := r.ethCall(ctx, contractAddress, nameData)
+	if err == nil && nameResult != "" && nameResult != "0x" {
+		details.Name = parseMetadataString(ctx, contractAddress, "name", nameResult)
 	}
-	rpcRespDecimals, err := r.doRPC(ctx, reqBodyDecimals)
+
+	symbolResult, err := r.ethCall(ctx, contractAddress, symbolData)
 	if err != nil {
-		return nil, err
-	}
-	details, err := r.parseDecimalsFromResult(ctx, rpcRespDecimals.Result)
-	if err != nil || details == nil {
-		return details, err
+		slog.DebugContext(
+			ctx,
+			fmt.Sprintf(
+				"Unable to fetch the symbol of token '%s'; leaving it empty",
+				contractAddress,
+			),
+			"error",
+			err,
+		)
+	} else if symbolResult != "" && symbolResult != "0x" {
+		details.Symbol = parseMetadataString(ctx, contractAddress, "symbol", symbolResult)
 	}
 
-	// prepare params for name
-	callObjName := map[string]string{
-		"to":   contractAddress,
-		"data": nameData,
-	}
-	reqBodyName := rpcRequest{
-		JSONRPC: "2.0",
-		ID:      2, //nolint:mnd
-		Method:  "eth_call",
-		Params:  []any{callObjName, "latest"},
-	}
-	rpcRespName, err := r.doRPC(ctx, reqBodyName)
-	if err == nil && rpcRespName.Result != "" && rpcRespName.Result != "0x" {
-		if name, nerr := parseNameFromResult(rpcRespName.Result); nerr == nil {
-			details.Name = name
-		}
+	return details, nil
+}
+
+// parseMetadataString decodes the given result of the given ERC20 metadata method (e.g., name()),
+// returning an empty string, with a debug log, if it cannot be decoded.
+func parseMetadataString(ctx context.Context, contractAddress, method, res string) string {
+	value, err := parseStringFromResult(res)
+	if err != nil {
+		slog.DebugContext(
+			ctx,
+			fmt.Sprintf(
+				"Unable to decode the %s of token '%s'; leaving it empty",
+				method,
+				contractAddress,
+			),
+			"error",
+			err,
+		)
+
+		return ""
 	}
 
-	return details, nil
+	return value
 }
 
-// parseNameFromResult decodes the ERC20 name() result (dynamic string)
-func parseNameFromResult(res string) (string, error) {
+// parseStringFromResult decodes an ERC20 name() or symbol() result.
+// The result is expected to be a dynamic string but, as some older tokens return their
+// metadata as a bytes32 instead, a result that is not a valid dynamic string
+// is decoded as a right-padded bytes32.
+func parseStringFromResult(res string) (string, error) {
 	if res == "" || res == "0x" {
 		return "", nil
 	}
@@ -106,54 +155,113 @@ func parseNameFromResult(res string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("decode hex result: %w", err)
 	}
-	// ERC20 name() returns a dynamic string: offset (32 bytes), length (32 bytes), then utf-8 bytes
-	//nolint:mnd
+
+	value, dynamicErr := decodeDynamicString(b)
+	if dynamicErr == nil {
+		return value, nil
+	}
+
+	value, bytes32Err := decodeBytes32String(b)
+	if bytes32Err == nil {
+		return value, nil
+	}
+
+	return "", errors.Join(
+		fmt.Errorf("decode as dynamic string: %w", dynamicErr),
+		fmt.Errorf("decode as bytes32: %w", bytes32Err),
+	)
+}
+
+// decodeDynamicString decodes an ABI-encoded dynamic string:
+// an offset (32 bytes) to its length (32 bytes), followed by its UTF-8 bytes.
+// The offset and length are checked against the length of the given bytes before being used.
+//
+//nolint:mnd
+func decodeDynamicString(b []byte) (string, error) {
 	if len(b) < 64 {
-		return "", errors.New("result too short for ERC20 name")
+		return "", errors.New("result too short for ERC20 string")
 	}
-	strlen := new(big.Int).SetBytes(b[32:64]).Int64()
-	if int64(len(b)) < 64+strlen {
-		return "", errors.New("result too short for name length")
+
+	offset := new(big.Int).SetBytes(b[:32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(b)-32) {
+		return "", fmt.Errorf("string offset %s exceeds result length %d", offset, len(b))
+	}
+
+	start := int(offset.Uint64()) + 32
+	strlen := new(big.Int).SetBytes(b[start-32 : start])
+	if !strlen.IsUint64() || strlen.Uint64() > uint64(len(b)-start) {
+		return "", errors.New("result too short for string length")
+	}
+
+	stringBytes := b[start : start+int(strlen.Uint64())]
+	if !utf8.Valid(stringBytes) {
+		return "", errors.New("string is not valid UTF-8")
 	}
-	nameBytes := b[64 : 64+strlen]
 
-	return string(nameBytes), nil
+	return string(stringBytes), nil
 }
 
-// doRPC sends a JSON-RPC request and decodes the response.
-func (r *RPCDetailsService) doRPC(ctx context.Context, reqBody rpcRequest) (*rpcResponse, error) {
-	b, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("marshal rpc request: %w", err)
+// decodeBytes32String decodes a string returned as a bytes32, right-padded with zero bytes.
+func decodeBytes32String(b []byte) (string, error) {
+	//nolint:mnd
+	if len(b) != 32 {
+		return "", fmt.Errorf("result of %d bytes is not a bytes32", len(b))
 	}
 
-	httpReq, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodPost,
-		r.rpcURL,
-		strings.NewReader(string(b)),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+	stringBytes := bytes.TrimRight(b, "\x00")
+	if bytes.IndexByte(stringBytes, 0) >= 0 || !utf8.Valid(stringBytes) {
+		return "", errors.New("bytes32 is not a right-padded UTF-8 string")
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := r.doer.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("rpc call: %w", err)
+	return string(stringBytes), nil
+}
+
+// ethCall invokes `eth_call` against the latest block with the given call data and returns the hex result.
+// If the node rejects the block given as a string as invalid parameters, the call is retried
+// with the block given as an object, which is then used for every later call.
+func (r *RPCDetailsService) ethCall(
+	ctx context.Context,
+	contractAddress,
+	data string,
+) (string, error) {
+	callObj := map[string]string{
+		"to":   contractAddress,
+		"data": data,
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	var rpcResp rpcResponse
-	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
-		return nil, fmt.Errorf("decode rpc response: %w", err)
+	var result string
+
+	err := r.rpcClient.Call(ctx, "eth_call", ethCallParams(callObj, r.blockObject), &result)
+
+	var rpcErr *jsonrpc.Error
+	if !r.blockObject && errors.As(err, &rpcErr) && rpcErr.Code == jsonrpc.CodeInvalidParams {
+		slog.DebugContext(
+			ctx,
+			"RPC node rejected the block parameter as a string; retrying with it as an object",
+			"error",
+			err,
+		)
+
+		r.blockObject = true
+		err = r.rpcClient.Call(ctx, "eth_call", ethCallParams(callObj, r.blockObject), &result)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("eth_call for data %s: %w", data, err)
 	}
 
-	if rpcResp.Error != nil {
-		return nil, fmt.Errorf("rpc error: %d %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	return result, nil
+}
+
+// ethCallParams returns the parameters of an `eth_call` of the given call object against
+// the latest block, given either as the string "latest" or, if blockObject is true,
+// as the object {"blockNumber":"latest"}.
+func ethCallParams(callObj map[string]string, blockObject bool) []any {
+	if blockObject {
+		return []any{callObj, map[string]string{"blockNumber": "latest"}}
 	}
 
-	return &rpcResp, nil
+	return []any{callObj, "latest"}
 }
 
 // parseDecimalsFromResult interprets the RPC result string and converts it
@@ -205,9 +313,14 @@ func (r *RPCDetailsService) parseDecimalsFromResult(
 		return nil, nil
 	}
 
-	// convert to int safely
-	if bi.Cmp(big.NewInt(int64(^uint(0)>>1))) == 1 { // bigger than max int
-		return nil, fmt.Errorf("decimals value too large: %s", bi.String())
+	// a 32-byte result can hold a number of up to 78 digits, so check it before converting it
+	if !bi.IsInt64() || bi.Int64() > MaxDecimals {
+		return nil, fmt.Errorf(
+			"%w %s returned by decimals(); at most %d are supported",
+			ErrImplausibleDecimals,
+			bi.String(),
+			MaxDecimals,
+		)
 	}
 	decimals := int(bi.Int64())
 
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/jarcoal/httpmock"
 	tokenpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
@@ -69,6 +70,74 @@ var _ = Describe("rpcDetailsService", func() {
 		Expect(tokenDetails.Name).To(Equal("USD Coin"))
 	})
 
+	When("the token provides a symbol", func() {
+		It("returns the token symbol from the symbol() response", func() {
+			// offset: 0x20, length: 0x04, value: "USDC"
+			symbolHex := "0x" +
+				"0000000000000000000000000000000000000000000000000000000000000020" +
+				"0000000000000000000000000000000000000000000000000000000000000004" +
+				"55534443"
+			httpmock.RegisterResponder(
+				"POST",
+				rpcURL,
+				func(req *http.Request) (*http.Response, error) {
+					body, _ := io.ReadAll(req.Body)
+					switch {
+					case strings.Contains(string(body), "0x313ce567"):
+						return httpmock.NewStringResponse(
+							200,
+							`{"jsonrpc":"2.0","id":1,"result":"0x06"}`,
+						), nil
+					case strings.Contains(string(body), "0x95d89b41"):
+						return httpmock.NewStringResponse(
+							200,
+							`{"jsonrpc":"2.0","id":3,"result":"`+symbolHex+`"}`,
+						), nil
+					default:
+						return httpmock.NewStringResponse(
+							200,
+							`{"jsonrpc":"2.0","id":2,"result":"0x"}`,
+						), nil
+					}
+				},
+			)
+
+			tokenDetails, err := detailsService.GetTokenDetails(ctx, "0xdeadbeef")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokenDetails).ToNot(BeNil())
+			Expect(tokenDetails.Symbol).To(Equal("USDC"))
+		})
+	})
+
+	When("the symbol() call reverts", func() {
+		It("returns the token details with an empty symbol", func() {
+			httpmock.RegisterResponder(
+				"POST",
+				rpcURL,
+				func(req *http.Request) (*http.Response, error) {
+					body, _ := io.ReadAll(req.Body)
+					if strings.Contains(string(body), "0x95d89b41") {
+						return httpmock.NewStringResponse(
+							200,
+							`{"jsonrpc":"2.0","id":3,"error":{"code":3,"message":"execution reverted"}}`,
+						), nil
+					}
+
+					return httpmock.NewStringResponse(
+						200,
+						`{"jsonrpc":"2.0","id":1,"result":"0x06"}`,
+					), nil
+				},
+			)
+
+			tokenDetails, err := detailsService.GetTokenDetails(ctx, "0xdeadbeef")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokenDetails).ToNot(BeNil())
+			Expect(tokenDetails.Decimals).To(Equal(6))
+			Expect(tokenDetails.Symbol).To(BeEmpty())
+		})
+	})
+
 	When("result is 0x", func() {
 		It("returns nil", func() {
 			res := `{"jsonrpc":"2.0","id":1,"result":"0x"}`
@@ -102,6 +171,116 @@ var _ = Describe("rpcDetailsService", func() {
 		})
 	})
 
+	When("the decimals are given", func() {
+		It("does not call decimals() but still fetches the name", func() {
+			nameHex := "0x" +
+				"0000000000000000000000000000000000000000000000000000000000000020" +
+				"0000000000000000000000000000000000000000000000000000000000000008" +
+				"55534420436f696e"
+
+			var calledData []string
+			httpmock.RegisterResponder(
+				"POST",
+				rpcURL,
+				func(req *http.Request) (*http.Response, error) {
+					body, _ := io.ReadAll(req.Body)
+					result := "0x"
+					switch {
+					case strings.Contains(string(body), "0x313ce567"):
+						calledData = append(calledData, "decimals")
+					case strings.Contains(string(body), "0x06fdde03"):
+						calledData = append(calledData, "name")
+						result = nameHex
+					}
+
+					return httpmock.NewStringResponse(
+						200,
+						`{"jsonrpc":"2.0","id":1,"result":"`+result+`"}`,
+					), nil
+				},
+			)
+
+			detailsService = tokenpkg.NewRPCDetailsService(
+				http.DefaultClient,
+				rpcURL,
+				tokenpkg.WithDecimals(9),
+			)
+
+			tokenDetails, err := detailsService.GetTokenDetails(ctx, "0xdeadbeef")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokenDetails.Decimals).To(Equal(9))
+			Expect(tokenDetails.Name).To(Equal("USD Coin"))
+			Expect(calledData).To(Equal([]string{"name"}))
+		})
+	})
+
+	Context("malformed name() and symbol() results", func() {
+		var nameHex, symbolHex string
+
+		BeforeEach(func() {
+			nameHex = "0x"
+			symbolHex = "0x"
+
+			httpmock.RegisterResponder(
+				"POST",
+				rpcURL,
+				func(req *http.Request) (*http.Response, error) {
+					body, _ := io.ReadAll(req.Body)
+					result := "0x06"
+					switch {
+					case strings.Contains(string(body), "0x06fdde03"):
+						result = nameHex
+					case strings.Contains(string(body), "0x95d89b41"):
+						result = symbolHex
+					}
+
+					return httpmock.NewStringResponse(
+						200,
+						`{"jsonrpc":"2.0","id":1,"result":"`+result+`"}`,
+					), nil
+				},
+			)
+		})
+
+		It("leaves the name empty when the dynamic string is truncated", func() {
+			// offset: 0x20, length: 0x40, but only 4 bytes follow
+			nameHex = "0x" +
+				"0000000000000000000000000000000000000000000000000000000000000020" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"55534443"
+
+			tokenDetails, err := detailsService.GetTokenDetails(ctx, "0xdeadbeef")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokenDetails.Decimals).To(Equal(6))
+			Expect(tokenDetails.Name).To(BeEmpty())
+		})
+
+		It("leaves the name empty when the offset or length overflows", func() {
+			nameHex = "0x" +
+				"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+				"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+			symbolHex = "0x" +
+				"0000000000000000000000000000000000000000000000000000000000000020" +
+				"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+
+			tokenDetails, err := detailsService.GetTokenDetails(ctx, "0xdeadbeef")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokenDetails.Name).To(BeEmpty())
+			Expect(tokenDetails.Symbol).To(BeEmpty())
+		})
+
+		It("decodes a name and symbol returned as bytes32", func() {
+			// "Maker" and "MKR", right-padded with zero bytes
+			nameHex = "0x4d616b6572000000000000000000000000000000000000000000000000000000"
+			symbolHex = "0x4d4b520000000000000000000000000000000000000000000000000000000000"
+
+			tokenDetails, err := detailsService.GetTokenDetails(ctx, "0xdeadbeef")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokenDetails.Name).To(Equal("Maker"))
+			Expect(tokenDetails.Symbol).To(Equal("MKR"))
+		})
+	})
+
 	It("verifies JSON-RPC request payload contains correct method selector and to address", func() {
 		contract := "0xdeadbeef"
 		callCount := 0
@@ -117,7 +296,7 @@ var _ = Describe("rpcDetailsService", func() {
 			callObj, ok := params[0].(map[string]any)
 			Expect(ok).To(BeTrue())
 			Expect(callObj["to"]).To(Equal(contract))
-			// Accept either decimals or name selector depending on call order
+			// Accept the decimals, name, or symbol selector depending on call order
 			if callCount == 1 {
 				Expect(callObj["data"]).To(Equal("0x313ce567"))
 
@@ -125,6 +304,13 @@ var _ = Describe("rpcDetailsService", func() {
 					200,
 					`{"jsonrpc":"2.0","id":1,"result":"0x12"}`,
 				), nil
+			} else if callCount == 3 {
+				Expect(callObj["data"]).To(Equal("0x95d89b41"))
+
+				return httpmock.NewStringResponse(
+					200,
+					`{"jsonrpc":"2.0","id":3,"result":"0x"}`,
+				), nil
 			} else {
 				Expect(callObj["data"]).To(Equal("0x06fdde03"))
 				// Return a minimal valid ERC20 name() response for the second call
@@ -144,18 +330,88 @@ var _ = Describe("rpcDetailsService", func() {
 		Expect(tokenDetails.Name).To(Equal("USD Coin"))
 	})
 
-	When("decimals value exceeds max int", func() {
-		It("returns an error", func() {
-			// 0x8000000000000000 is 2^63 which exceeds int64 max on 64-bit
-			res := `{"jsonrpc":"2.0","id":1,"result":"0x8000000000000000"}`
-			httpmock.RegisterResponder("POST", rpcURL, httpmock.NewStringResponder(200, res))
+	Context("block parameter", func() {
+		var blockParams []any
+		var rejectStringBlock bool
+
+		BeforeEach(func() {
+			blockParams = nil
+			rejectStringBlock = false
 
+			httpmock.RegisterResponder("POST", rpcURL, func(req *http.Request) (*http.Response, error) {
+				var payload struct {
+					Params []any `json:"params"`
+				}
+				Expect(json.NewDecoder(req.Body).Decode(&payload)).To(Succeed())
+				Expect(payload.Params).To(HaveLen(2))
+				blockParams = append(blockParams, payload.Params[1])
+
+				if _, isString := payload.Params[1].(string); isString && rejectStringBlock {
+					return httpmock.NewStringResponse(
+						200,
+						`{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid params"}}`,
+					), nil
+				}
+
+				return httpmock.NewStringResponse(
+					200,
+					`{"jsonrpc":"2.0","id":1,"result":"0x12"}`,
+				), nil
+			})
+		})
+
+		It("gives the block as a string by default", func() {
 			_, err := detailsService.GetTokenDetails(ctx, "0xdeadbeef")
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("decimals value too large"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockParams).ToNot(BeEmpty())
+			Expect(blockParams).To(HaveEach(Equal("latest")))
+		})
+
+		It("gives the block as an object when asked to", func() {
+			detailsService = tokenpkg.NewRPCDetailsService(
+				http.DefaultClient,
+				rpcURL,
+				tokenpkg.WithBlockObjectParam(true),
+			)
+
+			_, err := detailsService.GetTokenDetails(ctx, "0xdeadbeef")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockParams).ToNot(BeEmpty())
+			Expect(blockParams).To(HaveEach(Equal(map[string]any{"blockNumber": "latest"})))
+		})
+
+		It("falls back to the object form when the node rejects the string form", func() {
+			rejectStringBlock = true
+
+			tokenDetails, err := detailsService.GetTokenDetails(ctx, "0xdeadbeef")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokenDetails.Decimals).To(Equal(18))
+
+			// the string form is only tried once
+			Expect(blockParams).To(HaveLen(4))
+			Expect(blockParams[0]).To(Equal("latest"))
+			Expect(blockParams[1:]).To(HaveEach(Equal(map[string]any{"blockNumber": "latest"})))
 		})
 	})
 
+	DescribeTable("implausibly large decimals values",
+		func(resultHex string) {
+			res := `{"jsonrpc":"2.0","id":1,"result":"` + resultHex + `"}`
+			httpmock.RegisterResponder("POST", rpcURL, httpmock.NewStringResponder(200, res))
+
+			_, err := detailsService.GetTokenDetails(ctx, "0xdeadbeef")
+			Expect(err).To(MatchError(tokenpkg.ErrImplausibleDecimals))
+			Expect(err).To(MatchError(ContainSubstring("at most 36 are supported")))
+		},
+		Entry("just over the maximum", "0x25"),
+		// 0x8000000000000000 is 2^63, which exceeds the maximum int64
+		Entry("over the maximum int64", "0x8000000000000000"),
+		Entry(
+			"every bit of 32 bytes set",
+			"0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		),
+	)
+
 	When("HTTP response is non-200", func() {
 		It("returns an error", func() {
 			httpmock.RegisterResponder(
@@ -199,6 +455,7 @@ var _ = Describe("rpcDetailsService", func() {
 		Expect(tokenDetails.Decimals).To(Equal(expectedDecimals))
 	},
 		Entry("single byte", "0x12", 18),
+		Entry("the maximum", "0x24", tokenpkg.MaxDecimals),
 		Entry("padded two bytes", "0x0012", 18),
 		Entry(
 			"padded 32 bytes",
@@ -0,0 +1,50 @@
+package transaction
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AccountType determines how the sign of an amount in a YNAB account relates to the direction
+// of the transfer it records.
+type AccountType string
+
+const (
+	// AccountTypeAsset is an account, such as a checking account, whose balance is what the
+	// wallets hold: transfers from the wallets are recorded as outflows, with negative amounts.
+	AccountTypeAsset AccountType = "asset"
+	// AccountTypeLiability is an account, such as a credit card, whose balance grows as it is
+	// spent from: transfers from the wallets are recorded with positive amounts.
+	AccountTypeLiability AccountType = "liability"
+)
+
+// ParseAccountType parses the given name of an account type.
+func ParseAccountType(name string) (AccountType, error) {
+	switch accountType := AccountType(strings.ToLower(strings.TrimSpace(name))); accountType {
+	case AccountTypeAsset, AccountTypeLiability:
+		return accountType, nil
+	default:
+		return AccountTypeAsset, fmt.Errorf(
+			"unsupported account type '%s'; must be one of: %s, %s",
+			name,
+			AccountTypeAsset,
+			AccountTypeLiability,
+		)
+	}
+}
+
+// IsOutbound determines whether the given amount, in YNAB milliunits, records a transfer from
+// the wallets in an account of this type. Any type but AccountTypeLiability is an asset.
+func (t AccountType) IsOutbound(milliunits int64) bool {
+	if t == AccountTypeLiability {
+		return milliunits > 0
+	}
+
+	return milliunits < 0
+}
+
+// IsNegative determines whether the amount recording a transfer in the given direction
+// is negative in an account of this type.
+func (t AccountType) IsNegative(isOutbound bool) bool {
+	return isOutbound != (t == AccountTypeLiability)
+}
@@ -0,0 +1,46 @@
+package transaction_test
+
+import (
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AccountType", func() {
+	DescribeTable("ParseAccountType",
+		func(name string, expected transaction.AccountType, expectValid bool) {
+			accountType, err := transaction.ParseAccountType(name)
+			if !expectValid {
+				Expect(err).To(HaveOccurred())
+
+				return
+			}
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(accountType).To(Equal(expected))
+		},
+		Entry("asset", "asset", transaction.AccountTypeAsset, true),
+		Entry("liability", " Liability ", transaction.AccountTypeLiability, true),
+		Entry("unsupported", "credit", transaction.AccountTypeAsset, false),
+	)
+
+	It("treats negative amounts of an asset account as outbound", func() {
+		Expect(transaction.AccountTypeAsset.IsOutbound(-1000)).To(BeTrue())
+		Expect(transaction.AccountTypeAsset.IsOutbound(1000)).To(BeFalse())
+		Expect(transaction.AccountTypeAsset.IsNegative(true)).To(BeTrue())
+		Expect(transaction.AccountTypeAsset.IsNegative(false)).To(BeFalse())
+	})
+
+	It("treats positive amounts of a liability account as outbound", func() {
+		Expect(transaction.AccountTypeLiability.IsOutbound(1000)).To(BeTrue())
+		Expect(transaction.AccountTypeLiability.IsOutbound(-1000)).To(BeFalse())
+		Expect(transaction.AccountTypeLiability.IsNegative(true)).To(BeFalse())
+		Expect(transaction.AccountTypeLiability.IsNegative(false)).To(BeTrue())
+	})
+
+	It("treats an unset account type as an asset account", func() {
+		var accountType transaction.AccountType
+		Expect(accountType.IsOutbound(-1000)).To(BeTrue())
+		Expect(accountType.IsNegative(true)).To(BeTrue())
+	})
+})
@@ -0,0 +1,100 @@
+package transaction
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DailyTotal is the net amount of tokens moved into or out of a wallet over a single UTC day.
+type DailyTotal struct {
+	Date      time.Time   // the day of the transfers, at midnight UTC
+	NetAmount *big.Int    // the net amount transferred, in the token's base unit; negative if more left the wallet than entered it
+	Transfers []*Transfer // the transfers that make up the total, in the order they were given
+}
+
+// TransactionHashes returns the transaction hashes of the transfers making up the total, without duplicates.
+func (d *DailyTotal) TransactionHashes() []string {
+	var hashes []string
+	seen := make(map[string]struct{}, len(d.Transfers))
+	for _, xfr := range d.Transfers {
+		key := strings.ToLower(xfr.TransactionHash)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		hashes = append(hashes, xfr.TransactionHash)
+	}
+
+	return hashes
+}
+
+// FormatAmount formats the net amount of the total in whole tokens.
+func (d *DailyTotal) FormatAmount(decimals int) string {
+	if d.NetAmount == nil {
+		return "0"
+	}
+
+	formatted := (&Transfer{Amount: new(big.Int).Abs(d.NetAmount)}).FormatAmount(decimals)
+	if d.NetAmount.Sign() < 0 {
+		return "-" + formatted
+	}
+
+	return formatted
+}
+
+// SumTransfersByDay groups the given transfers by the UTC day on which they were executed
+// and sums them into a net amount for the given wallets: transfers into a wallet
+// are added and transfers out of a wallet are subtracted.
+// Transfers that neither send to nor receive from a wallet are omitted, as are transfers
+// between wallets.
+// The returned totals are sorted by date, earliest first.
+func SumTransfersByDay(transfers []*Transfer, wallets *Wallets) []*DailyTotal {
+	totalsByDay := make(map[time.Time]*DailyTotal)
+	for _, xfr := range transfers {
+		if xfr.Amount == nil {
+			continue
+		}
+
+		isInbound := wallets.Contains(xfr.ToAddress)
+		isOutbound := wallets.Contains(xfr.FromAddress)
+		if isInbound == isOutbound {
+			// either unrelated to the wallets or a transfer between them,
+			// neither of which changes the total
+			continue
+		}
+
+		utcTime := xfr.ExecutionTime.UTC()
+		day := time.Date(utcTime.Year(), utcTime.Month(), utcTime.Day(), 0, 0, 0, 0, time.UTC)
+
+		total, ok := totalsByDay[day]
+		if !ok {
+			total = &DailyTotal{
+				Date:      day,
+				NetAmount: new(big.Int),
+			}
+			totalsByDay[day] = total
+		}
+
+		if isInbound {
+			total.NetAmount.Add(total.NetAmount, xfr.Amount)
+		} else {
+			total.NetAmount.Sub(total.NetAmount, xfr.Amount)
+		}
+
+		total.Transfers = append(total.Transfers, xfr)
+	}
+
+	totals := make([]*DailyTotal, 0, len(totalsByDay))
+	for _, total := range totalsByDay {
+		totals = append(totals, total)
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Date.Before(totals[j].Date)
+	})
+
+	return totals
+}
@@ -0,0 +1,109 @@
+package transaction_test
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SumTransfersByDay", func() {
+	const walletAddress = "0xWallet"
+
+	dayOne := time.Date(2025, time.December, 10, 0, 0, 0, 0, time.UTC)
+	dayTwo := time.Date(2025, time.December, 11, 0, 0, 0, 0, time.UTC)
+
+	newTransfer := func(
+		from, to string,
+		amount int64,
+		executionTime time.Time,
+		hash string,
+	) *transaction.Transfer {
+		return &transaction.Transfer{
+			FromAddress:     from,
+			ToAddress:       to,
+			Amount:          big.NewInt(amount),
+			ExecutionTime:   executionTime,
+			TransactionHash: hash,
+		}
+	}
+
+	It("nets inbound and outbound transfers per day", func() {
+		inbound := newTransfer("0xother", "0xwallet", 5000000, dayOne.Add(time.Hour), "0xhash1")
+		outbound := newTransfer("0xWALLET", "0xother", 1500000, dayOne.Add(2*time.Hour), "0xhash2")
+		nextDay := newTransfer("0xwallet", "0xother", 2000000, dayTwo.Add(time.Hour), "0xhash3")
+
+		totals := transaction.SumTransfersByDay(
+			[]*transaction.Transfer{nextDay, inbound, outbound},
+			transaction.NewWallets(walletAddress),
+		)
+		Expect(totals).To(HaveLen(2))
+
+		Expect(totals[0].Date).To(Equal(dayOne))
+		Expect(totals[0].NetAmount).To(Equal(big.NewInt(3500000)))
+		Expect(totals[0].Transfers).To(Equal([]*transaction.Transfer{inbound, outbound}))
+
+		Expect(totals[1].Date).To(Equal(dayTwo))
+		Expect(totals[1].NetAmount).To(Equal(big.NewInt(-2000000)))
+		Expect(totals[1].FormatAmount(6)).To(Equal("-2"))
+	})
+
+	It("groups by UTC day regardless of the execution time's location", func() {
+		location := time.FixedZone("UTC-5", -5*60*60)
+		// 2025-12-10 22:00 at UTC-5 is 2025-12-11 03:00 UTC
+		lateEvening := newTransfer(
+			"0xother",
+			"0xwallet",
+			1000000,
+			time.Date(2025, time.December, 10, 22, 0, 0, 0, location),
+			"0xhash1",
+		)
+
+		totals := transaction.SumTransfersByDay(
+			[]*transaction.Transfer{lateEvening},
+			transaction.NewWallets(walletAddress),
+		)
+		Expect(totals).To(HaveLen(1))
+		Expect(totals[0].Date).To(Equal(dayTwo))
+	})
+
+	It("omits transfers that do not move tokens into or out of the wallet", func() {
+		unrelated := newTransfer("0xother", "0xanother", 1000000, dayOne, "0xhash1")
+		selfTransfer := newTransfer("0xwallet", "0xwallet", 1000000, dayOne, "0xhash2")
+
+		totals := transaction.SumTransfersByDay(
+			[]*transaction.Transfer{unrelated, selfTransfer},
+			transaction.NewWallets(walletAddress),
+		)
+		Expect(totals).To(BeEmpty())
+	})
+
+	It("nets the transfers of several wallets, omitting transfers between them", func() {
+		inbound := newTransfer("0xother", "0xwallet", 5000000, dayOne, "0xhash1")
+		outbound := newTransfer("0xsecond", "0xother", 2000000, dayOne, "0xhash2")
+		between := newTransfer("0xwallet", "0xsecond", 1000000, dayOne, "0xhash3")
+
+		totals := transaction.SumTransfersByDay(
+			[]*transaction.Transfer{inbound, outbound, between},
+			transaction.NewWallets(walletAddress, "0xSecond"),
+		)
+		Expect(totals).To(HaveLen(1))
+		Expect(totals[0].NetAmount).To(Equal(big.NewInt(3000000)))
+		Expect(totals[0].Transfers).To(Equal([]*transaction.Transfer{inbound, outbound}))
+	})
+
+	It("lists each transaction hash once", func() {
+		first := newTransfer("0xother", "0xwallet", 1000000, dayOne, "0xhash1")
+		second := newTransfer("0xother", "0xwallet", 2000000, dayOne, "0xHASH1")
+		third := newTransfer("0xother", "0xwallet", 3000000, dayOne, "0xhash2")
+
+		totals := transaction.SumTransfersByDay(
+			[]*transaction.Transfer{first, second, third},
+			transaction.NewWallets(walletAddress),
+		)
+		Expect(totals).To(HaveLen(1))
+		Expect(totals[0].TransactionHashes()).To(Equal([]string{"0xhash1", "0xhash2"}))
+	})
+})
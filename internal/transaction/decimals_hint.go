@@ -0,0 +1,131 @@
+package transaction
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	ctsio "github.com/jrh3k5/cryptonabber-txn-sync/internal/io"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+)
+
+// decimalsColumns are the (lowercased) names of columns that may carry the number of decimals of the token.
+var decimalsColumns = []string{"tokendecimal", "token decimal", "token decimals", "decimals"}
+
+// symbolColumns are the (lowercased) names of columns that may carry the symbol of the token.
+var symbolColumns = []string{"tokensymbol", "token symbol", "symbol"}
+
+// knownTokenDecimals maps the symbols of well-known tokens to the number of decimals they use.
+var knownTokenDecimals = map[string]int{
+	"USDC": 6,  //nolint:mnd
+	"USDT": 6,  //nolint:mnd
+	"EURC": 6,  //nolint:mnd
+	"DAI":  18, //nolint:mnd
+	"WETH": 18, //nolint:mnd
+}
+
+// DecimalsHint describes the number of decimals that a CSV export suggests its token uses.
+type DecimalsHint struct {
+	Decimals int    // the number of decimals suggested by the CSV
+	Source   string // a description of where in the CSV the suggestion came from
+	Symbol   string // the token symbol given alongside the suggestion; empty if the CSV has none
+}
+
+// DecimalsHintFromEtherscanCSV reads the given CSV and returns the number of decimals it suggests for its token.
+// The hint is taken from a token decimals column if present; otherwise, a token symbol column is
+// compared against a list of well-known tokens. If the CSV offers no hint, nil is returned.
+func DecimalsHintFromEtherscanCSV(csvReader io.Reader) (*DecimalsHint, error) {
+	r := csv.NewReader(ctsio.StripUTF8BOM(csvReader))
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the first line of the CSV: %w", err)
+	}
+
+	decimalsIdx := findColumn(header, decimalsColumns)
+	symbolIdx := findColumn(header, symbolColumns)
+	if decimalsIdx < 0 && symbolIdx < 0 {
+		return nil, nil
+	}
+
+	for {
+		record, err := r.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("read CSV record: %w", err)
+		}
+
+		if hint := decimalsHintFromRecord(header, record, decimalsIdx, symbolIdx); hint != nil {
+			return hint, nil
+		}
+	}
+}
+
+// VerifyDecimalsHint returns an error if the given hint conflicts with the decimals of the given token.
+// A nil hint is never considered to be in conflict.
+func VerifyDecimalsHint(hint *DecimalsHint, tokenDetails *token.Details) error {
+	if hint == nil || tokenDetails == nil || hint.Decimals == tokenDetails.Decimals {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"the CSV %s suggests a token with %d decimals, but the token contract reports %d decimals; verify that --token-address refers to the token in the CSV",
+		hint.Source,
+		hint.Decimals,
+		tokenDetails.Decimals,
+	)
+}
+
+func decimalsHintFromRecord(
+	header []string,
+	record []string,
+	decimalsIdx int,
+	symbolIdx int,
+) *DecimalsHint {
+	var symbol string
+	if symbolIdx >= 0 && symbolIdx < len(record) {
+		symbol = strings.TrimSpace(record[symbolIdx])
+	}
+
+	if decimalsIdx >= 0 && decimalsIdx < len(record) {
+		decimals, err := strconv.Atoi(strings.TrimSpace(record[decimalsIdx]))
+		if err == nil {
+			return &DecimalsHint{
+				Decimals: decimals,
+				Source:   fmt.Sprintf("column '%s'", header[decimalsIdx]),
+				Symbol:   symbol,
+			}
+		}
+	}
+
+	upperSymbol := strings.ToUpper(symbol)
+	if decimals, isKnown := knownTokenDecimals[upperSymbol]; isKnown {
+		return &DecimalsHint{
+			Decimals: decimals,
+			Source:   fmt.Sprintf("column '%s' (token symbol %s)", header[symbolIdx], upperSymbol),
+			Symbol:   symbol,
+		}
+	}
+
+	return nil
+}
+
+// findColumn returns the index of the first header matching any of the given lowercased names, or -1 if none match.
+func findColumn(header []string, names []string) int {
+	for _, name := range names {
+		for i, h := range header {
+			if strings.TrimSpace(strings.ToLower(h)) == name {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,86 @@
+package transaction_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DecimalsHintFromEtherscanCSV", func() {
+	It("reads the decimals from a token decimals column", func() {
+		csvData := "Transaction Hash,From,To,Amount,DateTime (UTC),TokenDecimal\n" +
+			"0xhash,0xfrom,0xto,1.5,2025-12-10 11:53:23,6\n"
+
+		hint, err := transaction.DecimalsHintFromEtherscanCSV(strings.NewReader(csvData))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hint).ToNot(BeNil())
+		Expect(hint.Decimals).To(Equal(6))
+		Expect(hint.Source).To(ContainSubstring("TokenDecimal"))
+	})
+
+	It("reads the token symbol given alongside the decimals", func() {
+		csvData := "Transaction Hash,From,To,Amount,DateTime (UTC),TokenSymbol,TokenDecimal\n" +
+			"0xhash,0xfrom,0xto,1.5,2025-12-10 11:53:23,XYZ,8\n"
+
+		hint, err := transaction.DecimalsHintFromEtherscanCSV(strings.NewReader(csvData))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hint).ToNot(BeNil())
+		Expect(hint.Decimals).To(Equal(8))
+		Expect(hint.Symbol).To(Equal("XYZ"))
+
+		transfers, err := transaction.TransfersFromEtherscanCSV(
+			context.Background(),
+			&token.Details{Name: hint.Symbol, Decimals: hint.Decimals},
+			strings.NewReader(csvData),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(transfers[0].Amount).To(Equal(big.NewInt(150000000)))
+	})
+
+	It("infers the decimals from a well-known token symbol", func() {
+		csvData := "Transaction Hash,From,To,Amount,DateTime (UTC),Token Symbol\n" +
+			"0xhash,0xfrom,0xto,1.5,2025-12-10 11:53:23,DAI\n"
+
+		hint, err := transaction.DecimalsHintFromEtherscanCSV(strings.NewReader(csvData))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hint).ToNot(BeNil())
+		Expect(hint.Decimals).To(Equal(18))
+	})
+
+	It("returns nil when the CSV offers no hint", func() {
+		hint, err := transaction.DecimalsHintFromEtherscanCSV(
+			strings.NewReader(etherscanUSDCExportCSV),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hint).To(BeNil())
+	})
+})
+
+var _ = Describe("VerifyDecimalsHint", func() {
+	It("accepts a hint matching the token decimals", func() {
+		hint := &transaction.DecimalsHint{Decimals: 6, Source: "column 'TokenDecimal'"}
+
+		Expect(transaction.VerifyDecimalsHint(hint, &token.Details{Decimals: 6})).To(Succeed())
+	})
+
+	It("rejects a hint conflicting with the token decimals", func() {
+		hint := &transaction.DecimalsHint{Decimals: 18, Source: "column 'TokenDecimal'"}
+
+		err := transaction.VerifyDecimalsHint(hint, &token.Details{Decimals: 6})
+		Expect(err).To(MatchError(And(
+			ContainSubstring("column 'TokenDecimal'"),
+			ContainSubstring("18 decimals"),
+			ContainSubstring("6 decimals"),
+		)))
+	})
+
+	It("accepts a missing hint", func() {
+		Expect(transaction.VerifyDecimalsHint(nil, &token.Details{Decimals: 6})).To(Succeed())
+	})
+})
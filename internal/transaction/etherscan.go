@@ -8,29 +8,208 @@ import (
 	"io"
 	"log/slog"
 	"math/big"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	ctsbig "github.com/jrh3k5/cryptonabber-txn-sync/internal/big"
 	ctsio "github.com/jrh3k5/cryptonabber-txn-sync/internal/io"
 	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
 )
 
+// defaultExecutionTimeLayouts are the layouts tried, in order, when parsing the execution time of a transfer.
+var defaultExecutionTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+}
+
+// CSVOption configures how an Etherscan CSV is parsed.
+type CSVOption func(*csvOptions)
+
+type csvOptions struct {
+	dateLayout  string // a custom layout to try before the default layouts
+	columnOrder string // a positional column mapping used when the header is absent or unrecognized
+}
+
+// The names of the columns that can be given in a column order.
+const (
+	CSVColumnHash   = "hash"
+	CSVColumnFrom   = "from"
+	CSVColumnTo     = "to"
+	CSVColumnAmount = "amount"
+	CSVColumnTime   = "time"
+)
+
+// csvColumnNames are the names of the columns that must each appear once in a column order.
+var csvColumnNames = []string{
+	CSVColumnHash,
+	CSVColumnFrom,
+	CSVColumnTo,
+	CSVColumnAmount,
+	CSVColumnTime,
+}
+
+// CSVColumnLogIndex is the name given in a CSVParseError to the optional log index column.
+const CSVColumnLogIndex = "log index"
+
+// CSVParseError describes a line of an Etherscan CSV that could not be parsed.
+type CSVParseError struct {
+	Line   int    // the line of the CSV, counting from 1, at which the failure occurred
+	Column string // the name of the column that could not be parsed; empty if not specific to one
+	Err    error  // the cause of the failure
+}
+
+func (e *CSVParseError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+
+	return fmt.Sprintf("line %d, column %s: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *CSVParseError) Unwrap() error {
+	return e.Err
+}
+
+// newCSVParseError returns a CSVParseError for the given failure to parse the given column,
+// or for the whole record if column is empty, at the given line.
+func newCSVParseError(line int, column string, err error) *CSVParseError {
+	return &CSVParseError{Line: line, Column: column, Err: err}
+}
+
+// WithDateLayout sets a custom Go time layout that is tried before the built-in layouts
+// when parsing the execution time of each transfer.
+func WithDateLayout(layout string) CSVOption {
+	return func(opts *csvOptions) {
+		opts.dateLayout = layout
+	}
+}
+
+// WithColumnOrder sets a comma-separated, positional mapping of the CSV's columns
+// (e.g., "hash,from,to,amount,time") that is used when the CSV has no header row
+// or its header row is not recognized.
+// Each of the CSVColumn* names must appear exactly once; a blank entry marks a column to be ignored.
+func WithColumnOrder(columnOrder string) CSVOption {
+	return func(opts *csvOptions) {
+		opts.columnOrder = columnOrder
+	}
+}
+
+// ValidateColumnOrder verifies that the given column order names each required column exactly once.
+func ValidateColumnOrder(columnOrder string) error {
+	_, err := parseColumnOrder(columnOrder)
+
+	return err
+}
+
+// parseColumnOrder resolves the given column order to the index of each named column.
+func parseColumnOrder(columnOrder string) (map[string]int, error) {
+	positions := make(map[string]int)
+	for i, name := range strings.Split(columnOrder, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+
+		if !slices.Contains(csvColumnNames, name) {
+			return nil, fmt.Errorf("unknown column %q in column order %q", name, columnOrder)
+		}
+
+		if _, duplicate := positions[name]; duplicate {
+			return nil, fmt.Errorf(
+				"column %q appears more than once in column order %q",
+				name,
+				columnOrder,
+			)
+		}
+
+		positions[name] = i
+	}
+
+	for _, name := range csvColumnNames {
+		if _, ok := positions[name]; !ok {
+			return nil, fmt.Errorf("column order %q is missing column %q", columnOrder, name)
+		}
+	}
+
+	return positions, nil
+}
+
+// ValidateDateLayout verifies that the given layout is a usable Go time layout.
+// It does so by formatting a reference time with the layout and parsing the result back.
+func ValidateDateLayout(layout string) error {
+	if strings.TrimSpace(layout) == "" {
+		return errors.New("date layout must not be blank")
+	}
+
+	referenceTime := time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC)
+	formatted := referenceTime.Format(layout)
+	if formatted == layout {
+		return fmt.Errorf("date layout %q does not contain any time elements", layout)
+	}
+
+	if _, err := time.Parse(layout, formatted); err != nil {
+		return fmt.Errorf("date layout %q cannot parse its own output %q: %w", layout, formatted, err)
+	}
+
+	return nil
+}
+
 // TransfersFromEtherscanCSV parses the given Etherscan CSV data representing activity for the given token details and returns a slice of Transfers.
 // It expects the given reader to contain a CSV with the following columns in no given order:
 // - Transaction Hash, which is the hash of the transaction in hex
 // - From, which is the address that sent the token in hex
 // - To, which is the address that received the token in hex
-// - Amount, which is the amount of tokens transferred in the token's base unit
+// - Amount (or Value or TokenValue), which is the amount of tokens transferred in the token's base unit
 // - DateTime (UTC), which is the time the transaction was executed in UTC
+// In the absence of DateTime (UTC), the execution time can be split across a Date and a Time
+// column, whose values are joined with a space before being parsed, or, failing those, be read
+// from a UnixTimestamp column as seconds since the Unix epoch.
+// Currency symbols (e.g., "$") around an amount are ignored, as is a leading "-", and the amount
+// may be written in scientific notation (e.g., "1.015e2").
+// It also reads the following optional columns, if present:
+// - Log Index (or LogIndex), which is the index of the transfer's log entry within the transaction
+// - Status, which marks the transfer as failed if it starts with "Error" or "Fail"
+// - isError, which marks the transfer as failed if it is "1" or "true"
+// If a column order is given with WithColumnOrder and the first row is not a recognized header,
+// the columns are instead located by position, and the first row is read as data
+// if it parses as a transfer.
 func TransfersFromEtherscanCSV(
 	ctx context.Context,
 	tokenDetails *token.Details,
 	csvReader io.Reader,
+	opts ...CSVOption,
 ) ([]*Transfer, error) {
+	options := &csvOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	timeLayouts := defaultExecutionTimeLayouts
+	if options.dateLayout != "" {
+		if err := ValidateDateLayout(options.dateLayout); err != nil {
+			return nil, fmt.Errorf("invalid custom date layout: %w", err)
+		}
+
+		timeLayouts = append([]string{options.dateLayout}, defaultExecutionTimeLayouts...)
+	}
+
+	var columnPositions map[string]int
+	if options.columnOrder != "" {
+		var err error
+		columnPositions, err = parseColumnOrder(options.columnOrder)
+		if err != nil {
+			return nil, fmt.Errorf("invalid column order: %w", err)
+		}
+	}
+
 	// wrap the reader to strip a leading UTF-8 BOM (U+FEFF) if present
 	r := csv.NewReader(ctsio.StripUTF8BOM(csvReader))
 	r.TrimLeadingSpace = true
+	// some exports contain bare quotes within unquoted fields, which would otherwise fail to parse
+	r.LazyQuotes = true
 
 	// read header
 	header, err := r.Read()
@@ -38,12 +217,47 @@ func TransfersFromEtherscanCSV(
 		return nil, fmt.Errorf("failed to read the first line of the CSV: %w", err)
 	}
 
-	txIdx, fromIdx, toIdx, amountIdx, timeIdx, err := parseHeader(header)
+	headerLine, _ := r.FieldPos(0)
+
+	optionalIdxs := optionalColumns{
+		logIndex: findOptionalColumn(header, "log index", "logindex"),
+		status:   findOptionalColumn(header, "status"),
+		isError:  findOptionalColumn(header, "iserror"),
+	}
+
+	columns, err := parseHeader(header, headerLine)
 	if err != nil {
-		return nil, err
+		if columnPositions == nil {
+			return nil, err
+		}
+
+		columns = requiredColumns{
+			hash:     columnPositions[CSVColumnHash],
+			from:     columnPositions[CSVColumnFrom],
+			to:       columnPositions[CSVColumnTo],
+			amount:   columnPositions[CSVColumnAmount],
+			dateTime: columnPositions[CSVColumnTime],
+			time:     -1,
+		}
+		optionalIdxs = optionalColumns{logIndex: -1, status: -1, isError: -1}
+	}
+
+	parse := func(record []string, line int) (*Transfer, error) {
+		return parseRecord(record, line, columns, optionalIdxs, tokenDetails, timeLayouts)
 	}
 
 	var transfers []*Transfer
+	if err != nil {
+		// sniff whether the unrecognized first row is data or a header to be skipped
+		if t, parseErr := parse(header, headerLine); parseErr == nil {
+			slog.DebugContext(ctx, "CSV has no header row; reading columns by position")
+
+			transfers = append(transfers, t)
+		} else {
+			slog.DebugContext(ctx, "CSV header row is not recognized; reading columns by position")
+		}
+	}
+
 	for {
 		record, err := r.Read()
 		if err != nil {
@@ -51,7 +265,15 @@ func TransfersFromEtherscanCSV(
 				break
 			}
 
-			return nil, fmt.Errorf("read CSV record: %w", err)
+			err = fmt.Errorf("read CSV record: %w", err)
+
+			// report malformed CSV at its line, like any other parse failure
+			var csvErr *csv.ParseError
+			if errors.As(err, &csvErr) {
+				return nil, newCSVParseError(csvErr.Line, "", err)
+			}
+
+			return nil, err
 		}
 
 		// skip empty records
@@ -61,7 +283,9 @@ func TransfersFromEtherscanCSV(
 			continue
 		}
 
-		t, err := parseRecord(record, txIdx, fromIdx, toIdx, amountIdx, timeIdx, tokenDetails)
+		line, _ := r.FieldPos(0)
+
+		t, err := parse(record, line)
 		if err != nil {
 			return nil, err
 		}
@@ -72,59 +296,172 @@ func TransfersFromEtherscanCSV(
 	return transfers, nil
 }
 
-func requiredColumn(hdrIdx map[string]int, header []string, name string) (int, error) {
-	idx, ok := hdrIdx[name]
-	if !ok {
-		return 0, fmt.Errorf(
-			"CSV is missing required column: %s from available columns: [%s]",
-			name,
-			strings.Join(header, ", "),
-		)
+func requiredColumn(
+	hdrIdx map[string]int,
+	header []string,
+	headerLine int,
+	name string,
+	aliases ...string,
+) (int, error) {
+	for _, candidate := range append([]string{name}, aliases...) {
+		if idx, ok := hdrIdx[candidate]; ok {
+			return idx, nil
+		}
+	}
+
+	missing := name
+	if len(aliases) > 0 {
+		missing += " (or " + strings.Join(aliases, ", ") + ")"
 	}
 
-	return idx, nil
+	return 0, newCSVParseError(headerLine, name, fmt.Errorf(
+		"CSV is missing required column: %s from available columns: [%s]",
+		missing,
+		strings.Join(header, ", "),
+	))
 }
 
-func parseHeader(header []string) (int, int, int, int, int, error) {
+// requiredColumns holds the indexes of the CSV columns from which every transfer is read.
+type requiredColumns struct {
+	hash     int
+	from     int
+	to       int
+	amount   int
+	dateTime int // the column holding the execution time or, if time is set, only its date
+	time     int // the column holding the time of day of the execution time; -1 if not split
+	// unix is whether the dateTime column holds the execution time as seconds since the Unix epoch
+	unix bool
+}
+
+// parseHeader locates the required columns in the given header, read from the given line.
+func parseHeader(header []string, headerLine int) (requiredColumns, error) {
 	hdrIdx := make(map[string]int)
 	for i, h := range header {
 		key := strings.TrimSpace(strings.ToLower(h))
 		hdrIdx[key] = i
 	}
 
-	txIdx, err := requiredColumn(hdrIdx, header, "transaction hash")
-	if err != nil {
-		return 0, 0, 0, 0, 0, err
+	column := func(name string, aliases ...string) (int, error) {
+		return requiredColumn(hdrIdx, header, headerLine, name, aliases...)
 	}
 
-	fromIdx, err := requiredColumn(hdrIdx, header, "from")
-	if err != nil {
-		return 0, 0, 0, 0, 0, err
+	var columns requiredColumns
+
+	var err error
+
+	if columns.hash, err = column("transaction hash"); err != nil {
+		return requiredColumns{}, err
 	}
 
-	toIdx, err := requiredColumn(hdrIdx, header, "to")
-	if err != nil {
-		return 0, 0, 0, 0, 0, err
+	if columns.from, err = column("from"); err != nil {
+		return requiredColumns{}, err
 	}
 
-	amountIdx, err := requiredColumn(hdrIdx, header, "amount")
-	if err != nil {
-		return 0, 0, 0, 0, 0, err
+	if columns.to, err = column("to"); err != nil {
+		return requiredColumns{}, err
+	}
+
+	// newer token transfer exports label the amount "Value" or "TokenValue"
+	if columns.amount, err = column("amount", "value", "tokenvalue"); err != nil {
+		return requiredColumns{}, err
+	}
+
+	columns.time = -1
+
+	// prefer a single date-time column over a split date and time, and both over a Unix timestamp
+	if dateTimeIdx, hasDateTime := hdrIdx["datetime (utc)"]; hasDateTime {
+		columns.dateTime = dateTimeIdx
+
+		return columns, nil
+	}
+
+	dateIdx, hasDate := hdrIdx["date"]
+	timeIdx, hasTime := hdrIdx["time"]
+	if hasDate && hasTime {
+		columns.dateTime = dateIdx
+		columns.time = timeIdx
+
+		return columns, nil
+	}
+
+	if unixIdx, hasUnix := hdrIdx["unixtimestamp"]; hasUnix {
+		columns.dateTime = unixIdx
+		columns.unix = true
+
+		return columns, nil
+	}
+
+	if columns.dateTime, err = column("datetime (utc)"); err != nil {
+		return requiredColumns{}, err
+	}
+
+	return columns, nil
+}
+
+// optionalColumns holds the indexes of the optional CSV columns; an index of -1 means the column is absent.
+type optionalColumns struct {
+	logIndex int
+	status   int
+	isError  int
+}
+
+// findOptionalColumn returns the index of the first column matching any of the given lowercase names,
+// or -1 if the header has no such column.
+func findOptionalColumn(header []string, names ...string) int {
+	for i, h := range header {
+		if slices.Contains(names, strings.TrimSpace(strings.ToLower(h))) {
+			return i
+		}
 	}
 
-	timeIdx, err := requiredColumn(hdrIdx, header, "datetime (utc)")
+	return -1
+}
+
+// ParseTokenAmount converts the given amount of whole tokens (e.g., "0.5") to the base unit of a token
+// with the given number of decimals.
+func ParseTokenAmount(amount string, decimals int) (*big.Int, error) {
+	baseUnits, err := parseAmount(amount, decimals, "")
 	if err != nil {
-		return 0, 0, 0, 0, 0, err
+		return nil, fmt.Errorf("invalid token amount %q: %w", amount, err)
+	}
+
+	if baseUnits.Sign() < 0 {
+		return nil, fmt.Errorf("invalid token amount %q: must not be negative", amount)
 	}
 
-	return txIdx, fromIdx, toIdx, amountIdx, timeIdx, nil
+	return baseUnits, nil
 }
 
+// maxAmountExponent is the largest exponent, positive or negative, accepted in an amount
+// written in scientific notation.
+const maxAmountExponent = 100
+
+// parseAmount converts the given amount of whole tokens, which may be written in scientific
+// notation (e.g., "1.015e2") and may have a leading sign, to the base unit of a token with
+// the given number of decimals; a negative amount is returned as such.
 func parseAmount(amountStr string, decimals int, txHash string) (*big.Int, error) {
 	if amountStr == "" {
 		return nil, fmt.Errorf("transaction hash %q has empty amount field", txHash)
 	}
 
+	unsignedStr, isNegative := strings.CutPrefix(amountStr, "-")
+	if !isNegative {
+		unsignedStr = strings.TrimPrefix(unsignedStr, "+")
+	}
+
+	if strings.HasPrefix(unsignedStr, "-") || strings.HasPrefix(unsignedStr, "+") {
+		return nil, fmt.Errorf(
+			"token amount %q for transaction hash %q has more than one sign",
+			amountStr,
+			txHash,
+		)
+	}
+
+	amountStr, err := expandScientificNotation(unsignedStr, txHash)
+	if err != nil {
+		return nil, err
+	}
+
 	totalAmount := new(big.Int)
 	wholeTokens, fracTokens, fracTokensLength, err := splitAmountParts(amountStr, txHash)
 	if err != nil {
@@ -156,9 +493,55 @@ func parseAmount(amountStr string, decimals int, txHash string) (*big.Int, error
 		totalAmount = totalAmount.Add(totalAmount, fracBaseUnits)
 	}
 
+	if isNegative {
+		totalAmount.Neg(totalAmount)
+	}
+
 	return totalAmount, nil
 }
 
+// expandScientificNotation rewrites the given unsigned amount, if written in scientific notation
+// (e.g., "1.015e2" or "1E-3"), as a plain decimal (e.g., "101.5" or "0.001"), without loss of
+// precision. Other amounts are returned as given.
+func expandScientificNotation(amountStr, txHash string) (string, error) {
+	mantissa, exponentStr, isScientific := strings.Cut(strings.ToLower(amountStr), "e")
+	if !isScientific {
+		return amountStr, nil
+	}
+
+	exponent, err := strconv.Atoi(exponentStr)
+	if err != nil || exponent > maxAmountExponent || exponent < -maxAmountExponent {
+		return "", fmt.Errorf(
+			"invalid exponent in token amount %q for transaction hash %q",
+			amountStr,
+			txHash,
+		)
+	}
+
+	wholeDigits, fracDigits, _ := strings.Cut(mantissa, ".")
+	digits := wholeDigits + fracDigits
+	isNotDigit := func(r rune) bool { return r < '0' || r > '9' }
+	if digits == "" || strings.ContainsFunc(digits, isNotDigit) {
+		return "", fmt.Errorf(
+			"invalid mantissa in token amount %q for transaction hash %q",
+			amountStr,
+			txHash,
+		)
+	}
+
+	// the position of the decimal point within the digits once the exponent is applied
+	point := len(wholeDigits) + exponent
+
+	switch {
+	case point <= 0:
+		return "0." + strings.Repeat("0", -point) + digits, nil
+	case point >= len(digits):
+		return digits + strings.Repeat("0", point-len(digits)), nil
+	default:
+		return digits[:point] + "." + digits[point:], nil
+	}
+}
+
 func splitAmountParts(amountStr, txHash string) (*big.Int, *big.Int, int, error) {
 	var wholeTokens *big.Int
 	fracTokens := new(big.Int)
@@ -210,45 +593,99 @@ func splitAmountParts(amountStr, txHash string) (*big.Int, *big.Int, int, error)
 	return wholeTokens, fracTokens, fracTokensLength, nil
 }
 
-func parseExecutionTime(timeStr, txHash string) (time.Time, error) {
-	executionTime, err := time.Parse("2006-01-02 15:04:05", timeStr)
+// trimAmountDecorations removes the spaces, stray quotes and currency symbols (e.g., "$")
+// with which some exports surround an amount.
+func trimAmountDecorations(amountStr string) string {
+	return strings.TrimFunc(amountStr, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || unicode.Is(unicode.Sc, r)
+	})
+}
+
+// parseExecutionTime parses the given time using the first of the given layouts that accepts it.
+func parseExecutionTime(timeStr, txHash string, layouts []string) (time.Time, error) {
+	var parseErrs []error
+	for _, layout := range layouts {
+		executionTime, err := time.Parse(layout, timeStr)
+		if err == nil {
+			return executionTime, nil
+		}
+
+		parseErrs = append(parseErrs, err)
+	}
+
+	return time.Time{}, fmt.Errorf(
+		"parse execution time %q for transaction hash %q: %w",
+		timeStr,
+		txHash,
+		errors.Join(parseErrs...),
+	)
+}
+
+// parseUnixTimestamp parses the given number of seconds since the Unix epoch as a time in UTC.
+func parseUnixTimestamp(timestampStr, txHash string) (time.Time, error) {
+	seconds, err := strconv.ParseInt(timestampStr, 10, 64) //nolint:mnd
 	if err != nil {
 		return time.Time{}, fmt.Errorf(
-			"parse execution time %q for transaction hash %q: %w",
-			timeStr,
+			"parse Unix timestamp %q for transaction hash %q: %w",
+			timestampStr,
 			txHash,
 			err,
 		)
 	}
 
-	return executionTime, nil
+	return time.Unix(seconds, 0).UTC(), nil
 }
 
+// parseRecord parses the transfer in the given record, read from the given line.
+// A failure is returned as a *CSVParseError.
 func parseRecord(
 	record []string,
-	txIdx, fromIdx, toIdx, amountIdx, timeIdx int,
+	line int,
+	columns requiredColumns,
+	optionalIdxs optionalColumns,
 	tokenDetails *token.Details,
+	timeLayouts []string,
 ) (*Transfer, error) {
-	if txIdx >= len(record) || fromIdx >= len(record) || toIdx >= len(record) ||
-		amountIdx >= len(record) ||
-		timeIdx >= len(record) {
-		return nil, fmt.Errorf("malformed csv record: %v", record)
+	if columns.hash >= len(record) || columns.from >= len(record) || columns.to >= len(record) ||
+		columns.amount >= len(record) ||
+		columns.dateTime >= len(record) ||
+		columns.time >= len(record) {
+		return nil, newCSVParseError(line, "", fmt.Errorf("malformed csv record: %v", record))
 	}
 
-	txHash := strings.TrimSpace(record[txIdx])
-	from := strings.TrimSpace(record[fromIdx])
-	to := strings.TrimSpace(record[toIdx])
-	amountStr := strings.TrimSpace(record[amountIdx])
-	timeStr := strings.TrimSpace(record[timeIdx])
+	txHash := strings.TrimSpace(record[columns.hash])
+	from := strings.TrimSpace(record[columns.from])
+	to := strings.TrimSpace(record[columns.to])
+	amountStr := trimAmountDecorations(record[columns.amount])
+
+	timeStr := strings.TrimSpace(record[columns.dateTime])
+	if columns.time >= 0 {
+		timeStr += " " + strings.TrimSpace(record[columns.time])
+	}
 
 	totalAmount, err := parseAmount(amountStr, tokenDetails.Decimals, txHash)
 	if err != nil {
-		return nil, err
+		return nil, newCSVParseError(line, CSVColumnAmount, err)
+	}
+
+	// some exports mark outgoing amounts with a leading "-"; the direction of a transfer
+	// is given by its addresses, so only the magnitude of its amount is kept
+	totalAmount.Abs(totalAmount)
+
+	var executionTime time.Time
+	if columns.unix {
+		executionTime, err = parseUnixTimestamp(timeStr, txHash)
+	} else {
+		executionTime, err = parseExecutionTime(timeStr, txHash, timeLayouts)
 	}
 
-	executionTime, err := parseExecutionTime(timeStr, txHash)
 	if err != nil {
-		return nil, err
+		return nil, newCSVParseError(line, CSVColumnTime, err)
+	}
+
+	logIndex, err := parseLogIndex(record, optionalIdxs.logIndex, txHash)
+	if err != nil {
+		return nil, newCSVParseError(line, CSVColumnLogIndex, err)
 	}
 
 	return &Transfer{
@@ -257,5 +694,46 @@ func parseRecord(
 		Amount:          totalAmount,
 		ExecutionTime:   executionTime,
 		TransactionHash: txHash,
+		LogIndex:        logIndex,
+		Failed:          isFailedRecord(record, optionalIdxs),
 	}, nil
 }
+
+// isFailedRecord determines whether the optional status columns of a record mark it as a failed transaction.
+func isFailedRecord(record []string, optionalIdxs optionalColumns) bool {
+	if idx := optionalIdxs.status; idx >= 0 && idx < len(record) {
+		status := strings.ToLower(strings.TrimSpace(record[idx]))
+		if strings.HasPrefix(status, "error") || strings.HasPrefix(status, "fail") {
+			return true
+		}
+	}
+
+	if idx := optionalIdxs.isError; idx >= 0 && idx < len(record) {
+		isError := strings.ToLower(strings.TrimSpace(record[idx]))
+		if isError == "1" || isError == "true" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseLogIndex parses the optional log index of a record.
+// It returns nil if the CSV has no log index column or the record leaves it blank.
+func parseLogIndex(record []string, logIndexIdx int, txHash string) (*int, error) {
+	if logIndexIdx < 0 || logIndexIdx >= len(record) {
+		return nil, nil
+	}
+
+	logIndexStr := strings.TrimSpace(record[logIndexIdx])
+	if logIndexStr == "" {
+		return nil, nil
+	}
+
+	logIndex, err := strconv.Atoi(logIndexStr)
+	if err != nil {
+		return nil, fmt.Errorf("parse log index %q for transaction hash %q: %w", logIndexStr, txHash, err)
+	}
+
+	return &logIndex, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	_ "embed"
 	"encoding/csv"
+	"errors"
 	"math/big"
 	"strings"
 	"time"
@@ -113,4 +114,587 @@ var _ = Describe("TransfersFromEtherscanCSV", func() {
 		Entry("Amount", "Amount"),
 		Entry("DateTime (UTC)", "DateTime (UTC)"),
 	)
+
+	DescribeTable("amount column aliases", func(amountColumn string, amount string) {
+		csvData := "Transaction Hash,From,To," + amountColumn + ",DateTime (UTC),Value (USD)\n" +
+			"0xhash,0xfrom,0xto," + amount + ",2025-12-10 11:53:23,$9.99\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(transfers[0].Amount).To(Equal(big.NewInt(1234500000)))
+	},
+		Entry("Amount", "Amount", "1234.5"),
+		Entry("Value", "Value", "1234.5"),
+		Entry("TokenValue", "TokenValue", "1234.5"),
+		Entry("quote-wrapped with a currency symbol", "Value", `"$1,234.50"`),
+	)
+
+	It("lists the amount column aliases when none is present", func() {
+		csvData := "Transaction Hash,From,To,Quantity,DateTime (UTC)\n" +
+			"0xhash,0xfrom,0xto,1.5,2025-12-10 11:53:23\n"
+
+		_, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).To(MatchError(ContainSubstring(
+			"missing required column: amount (or value, tokenvalue) from available columns: " +
+				"[Transaction Hash, From, To, Quantity, DateTime (UTC)]",
+		)))
+	})
+})
+
+var _ = Describe("log index column", func() {
+	var usdcDetails *token.Details
+
+	BeforeEach(func() {
+		usdcDetails = &token.Details{
+			Decimals: 6,
+		}
+	})
+
+	It("parses the optional log index", func() {
+		csvData := "Transaction Hash,From,To,Amount,DateTime (UTC),Log Index\n" +
+			"0xhash,0xfrom,0xto,1.5,2025-12-10 11:53:23,7\n" +
+			"0xhash,0xfrom,0xto,2.5,2025-12-10 11:53:23,\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(2))
+		Expect(transfers[0].LogIndex).To(HaveValue(Equal(7)))
+		Expect(transfers[1].LogIndex).To(BeNil())
+	})
+
+	It("rejects a non-numeric log index", func() {
+		csvData := "Transaction Hash,From,To,Amount,DateTime (UTC),LogIndex\n" +
+			"0xhash,0xfrom,0xto,1.5,2025-12-10 11:53:23,abc\n"
+
+		_, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).To(MatchError(ContainSubstring("parse log index")))
+	})
+})
+
+var _ = Describe("malformed quoting", func() {
+	var usdcDetails *token.Details
+
+	BeforeEach(func() {
+		usdcDetails = &token.Details{
+			Decimals: 6,
+		}
+	})
+
+	It("tolerates bare quotes within unquoted fields", func() {
+		csvData := "Transaction Hash,Method,From,To,Amount,DateTime (UTC)\n" +
+			"0xhash1,Transfer \"USDC\",0xfrom,0xto,1.5,2025-12-10 11:53:23\n" +
+			"0xhash2,Transfer,0xfrom,0xto,2.5,2025-12-10 11:53:23\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(2))
+		Expect(transfers[0].TransactionHash).To(Equal("0xhash1"))
+		Expect(transfers[0].Amount).To(Equal(big.NewInt(1500000)))
+		Expect(transfers[1].TransactionHash).To(Equal("0xhash2"))
+	})
+})
+
+var _ = Describe("failed transactions", func() {
+	var usdcDetails *token.Details
+
+	BeforeEach(func() {
+		usdcDetails = &token.Details{
+			Decimals: 6,
+		}
+	})
+
+	It("marks rows with a failed status", func() {
+		csvData := "Transaction Hash,Status,From,To,Amount,DateTime (UTC)\n" +
+			"0xhash1,Success,0xfrom,0xto,1.5,2025-12-10 11:53:23\n" +
+			"0xhash2,Error(0),0xfrom,0xto,2.5,2025-12-10 11:53:23\n" +
+			"0xhash3,Failed,0xfrom,0xto,3.5,2025-12-10 11:53:23\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(3))
+		Expect(transfers[0].Failed).To(BeFalse())
+		Expect(transfers[1].Failed).To(BeTrue())
+		Expect(transfers[2].Failed).To(BeTrue())
+	})
+
+	It("marks rows flagged by isError", func() {
+		csvData := "Transaction Hash,From,To,Amount,DateTime (UTC),isError\n" +
+			"0xhash1,0xfrom,0xto,1.5,2025-12-10 11:53:23,0\n" +
+			"0xhash2,0xfrom,0xto,2.5,2025-12-10 11:53:23,1\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(2))
+
+		succeeded := transactionpkg.ExcludeFailedTransfers(transfers)
+		Expect(succeeded).To(HaveLen(1))
+		Expect(succeeded[0].TransactionHash).To(Equal("0xhash1"))
+	})
+})
+
+var _ = Describe("custom date layouts", func() {
+	var usdcDetails *token.Details
+
+	BeforeEach(func() {
+		usdcDetails = &token.Details{
+			Decimals: 6,
+		}
+	})
+
+	It("parses execution times using the custom layout", func() {
+		csvData := "Transaction Hash,From,To,Amount,DateTime (UTC)\n" +
+			"0xhash,0xfrom,0xto,1.5,12/10/2025 11:53\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+			transactionpkg.WithDateLayout("01/02/2006 15:04"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(
+			transfers[0].ExecutionTime,
+		).To(Equal(time.Date(2025, time.December, 10, 11, 53, 0, 0, time.UTC)))
+	})
+
+	It("falls back to the built-in layouts when the custom layout does not match", func() {
+		csvData := "Transaction Hash,From,To,Amount,DateTime (UTC)\n" +
+			"0xhash,0xfrom,0xto,1.5,2025-12-10 11:53:23\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+			transactionpkg.WithDateLayout("01/02/2006 15:04"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(
+			transfers[0].ExecutionTime,
+		).To(Equal(time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC)))
+	})
+
+	It("rejects an invalid custom layout", func() {
+		csvData := "Transaction Hash,From,To,Amount,DateTime (UTC)\n" +
+			"0xhash,0xfrom,0xto,1.5,2025-12-10 11:53:23\n"
+
+		_, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+			transactionpkg.WithDateLayout("not a layout"),
+		)
+		Expect(err).To(MatchError(ContainSubstring("invalid custom date layout")))
+	})
+
+	DescribeTable("ValidateDateLayout", func(layout string, expectValid bool) {
+		err := transactionpkg.ValidateDateLayout(layout)
+		if expectValid {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(err).To(HaveOccurred())
+		}
+	},
+		Entry("date and time layout", "01/02/2006 15:04", true),
+		Entry("RFC3339", time.RFC3339, true),
+		Entry("layout without time elements", "not a layout", false),
+		Entry("blank layout", "  ", false),
+	)
+})
+
+var _ = Describe("split date and time columns", func() {
+	var usdcDetails *token.Details
+
+	BeforeEach(func() {
+		usdcDetails = &token.Details{
+			Decimals: 6,
+		}
+	})
+
+	It("combines the date and time columns into the execution time", func() {
+		csvData := "Transaction Hash,Date,Time,From,To,Amount\n" +
+			"0xhash,2025-12-10,11:53:23,0xfrom,0xto,1.5\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(
+			transfers[0].ExecutionTime,
+		).To(Equal(time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC)))
+	})
+
+	It("parses the combined columns using a custom layout", func() {
+		csvData := "Transaction Hash,From,To,Amount,Date,Time\n" +
+			"0xhash,0xfrom,0xto,1.5,12/10/2025,11:53\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+			transactionpkg.WithDateLayout("01/02/2006 15:04"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(
+			transfers[0].ExecutionTime,
+		).To(Equal(time.Date(2025, time.December, 10, 11, 53, 0, 0, time.UTC)))
+	})
+
+	It("prefers a single date-time column over the split columns", func() {
+		csvData := "Transaction Hash,From,To,Amount,DateTime (UTC),Date,Time\n" +
+			"0xhash,0xfrom,0xto,1.5,2025-12-09 00:00:00,2025-12-10,11:53:23\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(
+			transfers[0].ExecutionTime,
+		).To(Equal(time.Date(2025, time.December, 9, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("requires a date-time column when only one of the split columns is present", func() {
+		csvData := "Transaction Hash,From,To,Amount,Date\n" +
+			"0xhash,0xfrom,0xto,1.5,2025-12-10\n"
+
+		_, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).To(MatchError(ContainSubstring("missing required column: datetime (utc)")))
+	})
+})
+
+var _ = Describe("Unix timestamp column", func() {
+	var usdcDetails *token.Details
+
+	BeforeEach(func() {
+		usdcDetails = &token.Details{
+			Decimals: 6,
+		}
+	})
+
+	It("reads the execution time from the Unix timestamp", func() {
+		csvData := "Transaction Hash,UnixTimestamp,From,To,Amount\n" +
+			"0xhash,1765367603,0xfrom,0xto,1.5\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(
+			transfers[0].ExecutionTime,
+		).To(Equal(time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC)))
+	})
+
+	It("prefers a date-time column over the Unix timestamp", func() {
+		csvData := "Transaction Hash,UnixTimestamp,DateTime (UTC),From,To,Amount\n" +
+			"0xhash,0,2025-12-10 11:53:23,0xfrom,0xto,1.5\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(
+			transfers[0].ExecutionTime,
+		).To(Equal(time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC)))
+	})
+
+	It("fails on a timestamp that is not a whole number of seconds", func() {
+		csvData := "Transaction Hash,UnixTimestamp,From,To,Amount\n" +
+			"0xhash,2025-12-10,0xfrom,0xto,1.5\n"
+
+		_, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+		Expect(err).To(MatchError(ContainSubstring("parse Unix timestamp \"2025-12-10\"")))
+	})
+})
+
+var _ = Describe("positional columns", func() {
+	var usdcDetails *token.Details
+
+	BeforeEach(func() {
+		usdcDetails = &token.Details{
+			Decimals: 6,
+		}
+	})
+
+	It("parses a headerless CSV by position", func() {
+		csvData := "2025-12-10 11:53:23,0xhash1,0xfrom,0xto,1.5\n" +
+			"2025-12-11 08:00:00,0xhash2,0xto,0xfrom,2\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+			transactionpkg.WithColumnOrder("time,hash,from,to,amount"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(2))
+		Expect(transfers[0].TransactionHash).To(Equal("0xhash1"))
+		Expect(transfers[0].FromAddress).To(Equal("0xfrom"))
+		Expect(transfers[0].ToAddress).To(Equal("0xto"))
+		Expect(transfers[0].Amount).To(Equal(big.NewInt(1500000)))
+		Expect(
+			transfers[0].ExecutionTime,
+		).To(Equal(time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC)))
+		Expect(transfers[1].TransactionHash).To(Equal("0xhash2"))
+	})
+
+	It("skips ignored columns", func() {
+		csvData := "ignored,0xhash,0xfrom,0xto,1.5,2025-12-10 11:53:23\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+			transactionpkg.WithColumnOrder(",hash,from,to,amount,time"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(transfers[0].TransactionHash).To(Equal("0xhash"))
+	})
+
+	It("skips an unrecognized header row", func() {
+		csvData := "Txn,Sender,Recipient,Value,When\n" +
+			"0xhash,0xfrom,0xto,1.5,2025-12-10 11:53:23\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+			transactionpkg.WithColumnOrder("hash,from,to,amount,time"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(transfers[0].TransactionHash).To(Equal("0xhash"))
+	})
+
+	It("prefers a recognized header over the column order", func() {
+		csvData := "From,Transaction Hash,To,Amount,DateTime (UTC)\n" +
+			"0xfrom,0xhash,0xto,1.5,2025-12-10 11:53:23\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+			transactionpkg.WithColumnOrder("hash,from,to,amount,time"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(transfers[0].TransactionHash).To(Equal("0xhash"))
+		Expect(transfers[0].FromAddress).To(Equal("0xfrom"))
+	})
+
+	It("rejects an invalid column order", func() {
+		_, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader("0xhash,0xfrom,0xto,1.5\n"),
+			transactionpkg.WithColumnOrder("hash,from,to,amount"),
+		)
+		Expect(err).To(MatchError(ContainSubstring("invalid column order")))
+	})
+
+	DescribeTable("ValidateColumnOrder", func(columnOrder string, expectValid bool) {
+		err := transactionpkg.ValidateColumnOrder(columnOrder)
+		if expectValid {
+			Expect(err).ToNot(HaveOccurred())
+		} else {
+			Expect(err).To(HaveOccurred())
+		}
+	},
+		Entry("all columns", "hash,from,to,amount,time", true),
+		Entry("mixed case with ignored columns", "Hash, ,From,To,Amount,Time", true),
+		Entry("missing column", "hash,from,to,amount", false),
+		Entry("duplicate column", "hash,from,to,amount,time,hash", false),
+		Entry("unknown column", "hash,from,to,amount,time,fee", false),
+	)
+})
+
+var _ = Describe("CSV parse errors", func() {
+	var usdcDetails *token.Details
+
+	parseCSV := func(csvData string) *transactionpkg.CSVParseError {
+		_, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			usdcDetails,
+			strings.NewReader(csvData),
+		)
+
+		var parseErr *transactionpkg.CSVParseError
+		Expect(errors.As(err, &parseErr)).To(BeTrue(), "unexpected error: %v", err)
+
+		return parseErr
+	}
+
+	BeforeEach(func() {
+		usdcDetails = &token.Details{
+			Decimals: 6,
+		}
+	})
+
+	It("reports the line and column of an invalid amount", func() {
+		parseErr := parseCSV("Transaction Hash,From,To,Amount,DateTime (UTC)\n" +
+			"0xhash1,0xfrom,0xto,1.5,2025-12-10 11:53:23\n" +
+			"0xhash2,0xfrom,0xto,abc,2025-12-10 11:53:23\n")
+		Expect(parseErr.Line).To(Equal(3))
+		Expect(parseErr.Column).To(Equal(transactionpkg.CSVColumnAmount))
+		Expect(parseErr).To(MatchError(HavePrefix("line 3, column amount: ")))
+	})
+
+	It("counts lines within quoted fields when reporting an invalid execution time", func() {
+		parseErr := parseCSV("Transaction Hash,From,To,Amount,DateTime (UTC)\n" +
+			"0xhash1,\"0x\nfrom\",0xto,1.5,2025-12-10 11:53:23\n" +
+			"0xhash2,0xfrom,0xto,2.5,yesterday\n")
+		Expect(parseErr.Line).To(Equal(4))
+		Expect(parseErr.Column).To(Equal(transactionpkg.CSVColumnTime))
+	})
+
+	It("reports the line of an invalid log index", func() {
+		parseErr := parseCSV("Transaction Hash,From,To,Amount,DateTime (UTC),Log Index\n" +
+			"0xhash1,0xfrom,0xto,1.5,2025-12-10 11:53:23,abc\n")
+		Expect(parseErr.Line).To(Equal(2))
+		Expect(parseErr.Column).To(Equal(transactionpkg.CSVColumnLogIndex))
+	})
+
+	It("reports the line of a record with too few fields", func() {
+		parseErr := parseCSV("Transaction Hash,From,To,Amount,DateTime (UTC)\n" +
+			"0xhash1,0xfrom,0xto,1.5,2025-12-10 11:53:23\n" +
+			"0xhash2,0xfrom\n")
+		Expect(parseErr.Line).To(Equal(3))
+		Expect(parseErr.Column).To(BeEmpty())
+		Expect(parseErr).To(MatchError(csv.ErrFieldCount))
+	})
+
+	It("reports the header line of a missing required column", func() {
+		parseErr := parseCSV("Transaction Hash,From,To,DateTime (UTC)\n" +
+			"0xhash1,0xfrom,0xto,2025-12-10 11:53:23\n")
+		Expect(parseErr.Line).To(Equal(1))
+		Expect(parseErr.Column).To(Equal("amount"))
+	})
+})
+
+var _ = Describe("ParseTokenAmount", func() {
+	DescribeTable("converts whole tokens to base units", func(amount string, expected int64) {
+		baseUnits, err := transactionpkg.ParseTokenAmount(amount, 6)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(baseUnits).To(Equal(big.NewInt(expected)))
+	},
+		Entry("whole tokens", "2", int64(2000000)),
+		Entry("fractional tokens", "0.5", int64(500000)),
+		Entry("whole and fractional tokens", "1.25", int64(1250000)),
+		Entry("zero, so that dust is imported", "0", int64(0)),
+	)
+
+	It("converts to the base units of a token with many decimals", func() {
+		baseUnits, err := transactionpkg.ParseTokenAmount("10.5", 18)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(baseUnits.String()).To(Equal("10500000000000000000"))
+	})
+
+	It("rejects an invalid amount", func() {
+		_, err := transactionpkg.ParseTokenAmount("abc", 6)
+		Expect(err).To(MatchError(ContainSubstring("invalid token amount")))
+	})
+
+	It("rejects a negative amount", func() {
+		_, err := transactionpkg.ParseTokenAmount("-1", 6)
+		Expect(err).To(MatchError(ContainSubstring("must not be negative")))
+	})
+})
+
+var _ = Describe("amount notations", func() {
+	parseAmount := func(amount string) (*big.Int, error) {
+		csvData := "Transaction Hash,From,To,Amount,DateTime (UTC)\n" +
+			"0xhash,0xfrom,0xto," + amount + ",2025-12-10 11:53:23\n"
+
+		transfers, err := transactionpkg.TransfersFromEtherscanCSV(
+			context.Background(),
+			&token.Details{Decimals: 6},
+			strings.NewReader(csvData),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		Expect(transfers).To(HaveLen(1))
+
+		return transfers[0].Amount, nil
+	}
+
+	DescribeTable("converts the amount to base units", func(amount string, expected int64) {
+		baseUnits, err := parseAmount(amount)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(baseUnits).To(Equal(big.NewInt(expected)))
+	},
+		Entry("scientific notation", "1.015e2", int64(101500000)),
+		Entry("a negative exponent", "1E-3", int64(1000)),
+		Entry("an explicit positive exponent", "2.5e+1", int64(25000000)),
+		Entry("a leading minus sign", "-5.0", int64(5000000)),
+		Entry("a negative amount in scientific notation", "-1.5e0", int64(1500000)),
+	)
+
+	DescribeTable("rejects a malformed amount", func(amount string, expectedError string) {
+		_, err := parseAmount(amount)
+		Expect(err).To(MatchError(And(
+			ContainSubstring(expectedError),
+			ContainSubstring(`transaction hash "0xhash"`),
+		)))
+	},
+		Entry("a missing exponent", "1.5e", "invalid exponent"),
+		Entry("a fractional exponent", "1e1.5", "invalid exponent"),
+		Entry("an overly large exponent", "1e1000", "invalid exponent"),
+		Entry("a missing mantissa", "e5", "invalid mantissa"),
+		Entry("a mantissa with two decimal points", "1.2.3e4", "invalid mantissa"),
+		Entry("two signs", "--5", "more than one sign"),
+		Entry("too many decimal places", "1e-7", "more decimal places than token supports"),
+	)
 })
@@ -0,0 +1,38 @@
+package transaction
+
+import (
+	"slices"
+	"strings"
+)
+
+// ExcludeCounterparties returns the transfers, in their given order, whose counterparty is none of
+// the given addresses; addresses are compared ignoring case.
+// The counterparty of a transfer is whichever of its sender and recipient is not one of the
+// given wallets.
+func ExcludeCounterparties(
+	transfers []*Transfer,
+	wallets *Wallets,
+	addresses []string,
+) []*Transfer {
+	if len(addresses) == 0 {
+		return transfers
+	}
+
+	isExcluded := func(address string) bool {
+		return !wallets.Contains(address) &&
+			slices.ContainsFunc(addresses, func(excluded string) bool {
+				return strings.EqualFold(excluded, address)
+			})
+	}
+
+	kept := make([]*Transfer, 0, len(transfers))
+	for _, xfr := range transfers {
+		if isExcluded(xfr.FromAddress) || isExcluded(xfr.ToAddress) {
+			continue
+		}
+
+		kept = append(kept, xfr)
+	}
+
+	return kept
+}
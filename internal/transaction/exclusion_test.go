@@ -0,0 +1,63 @@
+package transaction_test
+
+import (
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExcludeCounterparties", func() {
+	const wallet = "0xwallet"
+
+	var wallets *transaction.Wallets
+
+	BeforeEach(func() {
+		wallets = transaction.NewWallets(wallet)
+	})
+
+	It("drops the transfers to or from an excluded address, ignoring case", func() {
+		toSpam := &transaction.Transfer{
+			TransactionHash: "0xtospam",
+			FromAddress:     wallet,
+			ToAddress:       "0xSpam",
+		}
+		fromSpam := &transaction.Transfer{
+			TransactionHash: "0xfromspam",
+			FromAddress:     "0xSPAM",
+			ToAddress:       wallet,
+		}
+		kept := &transaction.Transfer{
+			TransactionHash: "0xkept",
+			FromAddress:     "0xemployer",
+			ToAddress:       wallet,
+		}
+
+		Expect(transaction.ExcludeCounterparties(
+			[]*transaction.Transfer{toSpam, kept, fromSpam},
+			wallets,
+			[]string{"0xspam"},
+		)).To(Equal([]*transaction.Transfer{kept}))
+	})
+
+	It("does not treat one of the wallets as a counterparty", func() {
+		xfr := &transaction.Transfer{
+			TransactionHash: "0xhash",
+			FromAddress:     wallet,
+			ToAddress:       "0xmerchant",
+		}
+
+		Expect(transaction.ExcludeCounterparties(
+			[]*transaction.Transfer{xfr},
+			wallets,
+			[]string{"0xWALLET"},
+		)).To(Equal([]*transaction.Transfer{xfr}))
+	})
+
+	It("returns every transfer when no addresses are excluded", func() {
+		transfers := []*transaction.Transfer{{TransactionHash: "0xhash"}}
+
+		Expect(
+			transaction.ExcludeCounterparties(transfers, wallets, nil),
+		).To(Equal(transfers))
+	})
+})
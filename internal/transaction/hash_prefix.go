@@ -0,0 +1,54 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoHashPrefixMatch is returned when no transfer's transaction hash starts with a given prefix.
+var ErrNoHashPrefixMatch = errors.New("no transfer matches the transaction hash prefix")
+
+// ErrAmbiguousHashPrefix is returned when more than one transfer's transaction hash starts with a given prefix.
+var ErrAmbiguousHashPrefix = errors.New("multiple transfers match the transaction hash prefix")
+
+// FindTransferByHashPrefix returns the only transfer among the given transfers whose transaction hash
+// starts with the given prefix. The comparison ignores case, and the "0x" prefix of the hash is optional.
+func FindTransferByHashPrefix(transfers []*Transfer, prefix string) (*Transfer, error) {
+	normalizedPrefix := normalizeHashPrefix(prefix)
+	if normalizedPrefix == "" {
+		return nil, errors.New("transaction hash prefix must not be empty")
+	}
+
+	var matches []*Transfer
+	for _, xfr := range transfers {
+		if strings.HasPrefix(normalizeHashPrefix(xfr.TransactionHash), normalizedPrefix) {
+			matches = append(matches, xfr)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w '%s'", ErrNoHashPrefixMatch, prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		hashes := make([]string, len(matches))
+		for i, match := range matches {
+			hashes[i] = match.TransactionHash
+		}
+
+		return nil, fmt.Errorf(
+			"%w '%s': %s",
+			ErrAmbiguousHashPrefix,
+			prefix,
+			strings.Join(hashes, ", "),
+		)
+	}
+}
+
+func normalizeHashPrefix(hash string) string {
+	normalized := strings.ToLower(strings.TrimSpace(hash))
+
+	return strings.TrimPrefix(normalized, "0x")
+}
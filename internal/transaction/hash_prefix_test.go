@@ -0,0 +1,47 @@
+package transaction_test
+
+import (
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FindTransferByHashPrefix", func() {
+	var transfers []*transaction.Transfer
+
+	BeforeEach(func() {
+		transfers = []*transaction.Transfer{
+			{TransactionHash: "0x3fe67569dfcce1fe4afca58819da01f423b2cb67d61ee3ba1ed413d2612717c7"},
+			{TransactionHash: "0xb4113e6ccf31511d5907a2dc41826948c8f80212d176ce66868d736e43212bd1"},
+			{TransactionHash: "0xb4a2c7e1f3b5d6a8c9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2"},
+		}
+	})
+
+	DescribeTable("unique prefixes", func(prefix string, expectedIndex int) {
+		xfr, err := transaction.FindTransferByHashPrefix(transfers, prefix)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(xfr).To(BeIdenticalTo(transfers[expectedIndex]))
+	},
+		Entry("with the 0x prefix", "0x3fe6", 0),
+		Entry("without the 0x prefix", "b411", 1),
+		Entry("in a different case", "0XB4A2", 2),
+		Entry("with surrounding whitespace", " 0xb4a ", 2),
+	)
+
+	It("rejects an ambiguous prefix", func() {
+		_, err := transaction.FindTransferByHashPrefix(transfers, "0xb4")
+		Expect(err).To(MatchError(transaction.ErrAmbiguousHashPrefix))
+		Expect(err).To(MatchError(ContainSubstring(transfers[1].TransactionHash)))
+		Expect(err).To(MatchError(ContainSubstring(transfers[2].TransactionHash)))
+	})
+
+	It("rejects a prefix that matches nothing", func() {
+		_, err := transaction.FindTransferByHashPrefix(transfers, "0xdead")
+		Expect(err).To(MatchError(transaction.ErrNoHashPrefixMatch))
+	})
+
+	It("rejects an empty prefix", func() {
+		_, err := transaction.FindTransferByHashPrefix(transfers, "0x")
+		Expect(err).To(MatchError(ContainSubstring("must not be empty")))
+	})
+})
@@ -1,8 +1,10 @@
 package transaction
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,6 +21,21 @@ func NewIgnoreList() *IgnoreList {
 	return &IgnoreList{}
 }
 
+// processedReasonPrefix starts the reason of each processed hash, followed by the ID of the YNAB
+// transaction for which it was processed.
+const processedReasonPrefix = "Processed for transaction ID "
+
+// IgnoredHashKind describes why a transaction hash was added to the ignore list.
+type IgnoredHashKind string
+
+const (
+	// IgnoredHashKindProcessed is the kind of a hash added because a YNAB transaction was matched
+	// to or created for its transfers.
+	IgnoredHashKindProcessed IgnoredHashKind = "processed"
+	// IgnoredHashKindIgnored is the kind of a hash that the user chose to ignore.
+	IgnoredHashKindIgnored IgnoredHashKind = "ignored"
+)
+
 // AddProcessedHash adds a record of a transaction hash being associated to a particular transaction in YNAB.
 func (i *IgnoreList) AddProcessedHash(transactionHash string, ynabTransactionID string) {
 	if i.IsHashIgnored(transactionHash) {
@@ -28,16 +45,19 @@ func (i *IgnoreList) AddProcessedHash(transactionHash string, ynabTransactionID
 	ignoredHash := IgnoredHash{
 		Hash: transactionHash,
 		Reason: fmt.Sprintf(
-			"Processed for transaction ID %s on %s",
+			processedReasonPrefix+"%s on %s",
 			ynabTransactionID,
 			time.Now().Format(time.DateOnly),
 		),
-		addedOn: time.Now().Format(time.DateOnly),
+		AddedOn:           time.Now().Format(time.DateOnly),
+		Kind:              IgnoredHashKindProcessed,
+		YNABTransactionID: ynabTransactionID,
 	}
 	i.hashes = append(i.hashes, ignoredHash)
 }
 
 // AddIgnoredHash adds a transaction hash to the ignore list with a reason.
+// Unlike a processed hash, it is ignored permanently: it is never pruned.
 func (i *IgnoreList) AddIgnoredHash(transactionHash string) {
 	if i.IsHashIgnored(transactionHash) {
 		return
@@ -46,7 +66,8 @@ func (i *IgnoreList) AddIgnoredHash(transactionHash string) {
 	ignoredHash := IgnoredHash{
 		Hash:    transactionHash,
 		Reason:  "Marked as ignored on " + time.Now().Format(time.DateOnly),
-		addedOn: time.Now().Format(time.DateOnly),
+		AddedOn: time.Now().Format(time.DateOnly),
+		Kind:    IgnoredHashKindIgnored,
 	}
 	i.hashes = append(i.hashes, ignoredHash)
 }
@@ -62,6 +83,37 @@ func (i *IgnoreList) IsHashIgnored(transactionHash string) bool {
 	return false
 }
 
+// RemoveHash removes a transaction hash from the ignore list, e.g., one that was ignored by
+// mistake, so that its transfers are processed again. Hashes are compared ignoring case.
+// It returns whether the hash was in the ignore list.
+func (i *IgnoreList) RemoveHash(transactionHash string) bool {
+	count := len(i.hashes)
+	i.hashes = slices.DeleteFunc(i.hashes, func(ignoredHash IgnoredHash) bool {
+		return strings.EqualFold(ignoredHash.Hash, transactionHash)
+	})
+
+	return len(i.hashes) < count
+}
+
+// Prune removes the processed hashes that were added to the ignore list longer ago than the given
+// duration, returning the number removed. Hashes that were ignored permanently, and those whose
+// date of addition is not known, are kept.
+func (i *IgnoreList) Prune(olderThan time.Duration) int {
+	cutoff := time.Now().Add(-olderThan)
+	count := len(i.hashes)
+	i.hashes = slices.DeleteFunc(i.hashes, func(ignoredHash IgnoredHash) bool {
+		if ignoredHash.Kind != IgnoredHashKindProcessed {
+			return false
+		}
+
+		addedOn, err := time.Parse(time.DateOnly, ignoredHash.AddedOn)
+
+		return err == nil && addedOn.Before(cutoff)
+	})
+
+	return count - len(i.hashes)
+}
+
 // GetHashCount returns the number of ignored transaction hashes.
 func (i *IgnoreList) GetHashCount() int {
 	return len(i.hashes)
@@ -77,12 +129,33 @@ func (i *IgnoreList) GetHashes() []IgnoredHash {
 
 // IgnoredHash represents an ignored transaction hash.
 type IgnoredHash struct {
-	Hash    string // transaction hash
-	Reason  string // reason for ignoring the transaction
-	addedOn string // date this hash was added to the ignore list (for serialization only)
+	Hash    string          // transaction hash
+	Reason  string          // human-readable reason for ignoring the transaction
+	AddedOn string          // date, e.g., "2025-12-10", it was added to the ignore list, if known
+	Kind    IgnoredHashKind // why the hash was added to the ignore list
+	// YNABTransactionID is the ID of the YNAB transaction for which a processed hash was processed,
+	// if known; it is empty for a hash the user chose to ignore.
+	YNABTransactionID string
+}
+
+// YAMLOption configures how an IgnoreList is written to YAML.
+type YAMLOption func(*yamlOptions)
+
+type yamlOptions struct {
+	groupReasons bool
+}
+
+// WithGroupedReasons causes each distinct reason to be written once, in a table of reasons,
+// with each ignored hash referring to its reason by ID rather than repeating it.
+func WithGroupedReasons() YAMLOption {
+	return func(options *yamlOptions) {
+		options.groupReasons = true
+	}
 }
 
 // FromYAML reads an IgnoreList from a YAML representation.
+// Each ignored hash may either state its reason inline or refer to a reason
+// in the table of reasons.
 func FromYAML(reader io.Reader) (*IgnoreList, error) {
 	var ymlList yamlIgnoreList
 	decoder := yaml.NewDecoder(reader)
@@ -90,35 +163,87 @@ func FromYAML(reader io.Reader) (*IgnoreList, error) {
 		return nil, fmt.Errorf("failed to decode ignore list from YAML: %w", err)
 	}
 
+	return fromYAMLIgnoreList(&ymlList)
+}
+
+// FromJSON reads an IgnoreList from a JSON representation, which has the same structure
+// as the YAML representation read by FromYAML.
+func FromJSON(reader io.Reader) (*IgnoreList, error) {
+	var ymlList yamlIgnoreList
+	if err := json.NewDecoder(reader).Decode(&ymlList); err != nil {
+		return nil, fmt.Errorf("failed to decode ignore list from JSON: %w", err)
+	}
+
+	return fromYAMLIgnoreList(&ymlList)
+}
+
+// fromYAMLIgnoreList builds an IgnoreList from its serialized form, resolving the reasons
+// referred to by ID.
+func fromYAMLIgnoreList(ymlList *yamlIgnoreList) (*IgnoreList, error) {
+	reasonsByID := make(map[int]string, len(ymlList.Reasons))
+	for _, ymlReason := range ymlList.Reasons {
+		reasonsByID[ymlReason.ID] = ymlReason.Reason
+	}
+
 	ignoreList := NewIgnoreList()
 	for _, ymlHash := range ymlList.IgnoredHashes {
+		reason := ymlHash.Reason
+		if ymlHash.ReasonID != nil {
+			referencedReason, ok := reasonsByID[*ymlHash.ReasonID]
+			if !ok {
+				return nil, fmt.Errorf(
+					"ignored hash '%s' refers to unknown reason ID %d",
+					ymlHash.Hash,
+					*ymlHash.ReasonID,
+				)
+			}
+
+			reason = referencedReason
+		}
+
 		ignoredHash := &IgnoredHash{
-			Hash:    ymlHash.Hash,
-			Reason:  ymlHash.Reason,
-			addedOn: ymlHash.AddedOn,
+			Hash:              ymlHash.Hash,
+			Reason:            reason,
+			AddedOn:           ymlHash.AddedOn,
+			Kind:              ymlHash.Kind,
+			YNABTransactionID: ymlHash.YNABTransactionID,
+		}
+		if ignoredHash.Kind == "" {
+			inferKind(ignoredHash, ymlHash.Processed)
 		}
+
 		ignoreList.hashes = append(ignoreList.hashes, *ignoredHash)
 	}
 
 	return ignoreList, nil
 }
 
-// ToYAML writes an IgnoreList to a YAML representation.
-func ToYAML(ignoreList *IgnoreList, writer io.Writer) error {
-	var ymlList yamlIgnoreList
-	for _, hash := range ignoreList.hashes {
-		ymlHash := &yamlIgnoredHash{
-			Hash:    hash.Hash,
-			Reason:  hash.Reason,
-			AddedOn: hash.addedOn,
-		}
-		ymlList.IgnoredHashes = append(ymlList.IgnoredHashes, *ymlHash)
+// inferKind sets the kind of the given hash, read from an ignore list written before kinds were
+// recorded, from whether it was marked as processed or, failing that, from its reason.
+// The ID of the YNAB transaction for which a processed hash was processed is read from its reason.
+func inferKind(ignoredHash *IgnoredHash, markedProcessed bool) {
+	reasonSuffix, hasProcessedReason := strings.CutPrefix(ignoredHash.Reason, processedReasonPrefix)
+	if !markedProcessed && !hasProcessedReason {
+		ignoredHash.Kind = IgnoredHashKindIgnored
+
+		return
 	}
 
+	ignoredHash.Kind = IgnoredHashKindProcessed
+	if hasProcessedReason && ignoredHash.YNABTransactionID == "" {
+		ignoredHash.YNABTransactionID, _, _ = strings.Cut(reasonSuffix, " ")
+	}
+}
+
+// ToYAML writes an IgnoreList to a YAML representation.
+// By default, the reason of each ignored hash is written inline.
+func ToYAML(ignoreList *IgnoreList, writer io.Writer, opts ...YAMLOption) error {
+	ymlList := toYAMLIgnoreList(ignoreList, opts)
+
 	encoder := yaml.NewEncoder(writer)
 	defer func() { _ = encoder.Close() }()
 
-	err := encoder.Encode(&ymlList)
+	err := encoder.Encode(ymlList)
 	if err != nil {
 		return fmt.Errorf("failed to encode ignore list to YAML: %w", err)
 	}
@@ -126,14 +251,84 @@ func ToYAML(ignoreList *IgnoreList, writer io.Writer) error {
 	return nil
 }
 
-// yamlIgnoreList is an internal struct for YAML serialization.
+// ToJSON writes an IgnoreList to a JSON representation, which has the same structure
+// as the YAML representation written by ToYAML and accepts the same options.
+func ToJSON(ignoreList *IgnoreList, writer io.Writer, opts ...YAMLOption) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(toYAMLIgnoreList(ignoreList, opts)); err != nil {
+		return fmt.Errorf("failed to encode ignore list to JSON: %w", err)
+	}
+
+	return nil
+}
+
+// toYAMLIgnoreList builds the serialized form of the given IgnoreList.
+func toYAMLIgnoreList(ignoreList *IgnoreList, opts []YAMLOption) *yamlIgnoreList {
+	var options yamlOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	ymlList := &yamlIgnoreList{IgnoredHashes: []yamlIgnoredHash{}}
+	reasonIDs := make(map[string]int)
+	for _, hash := range ignoreList.hashes {
+		ymlHash := &yamlIgnoredHash{
+			Hash:              hash.Hash,
+			AddedOn:           hash.AddedOn,
+			Kind:              hash.Kind,
+			YNABTransactionID: hash.YNABTransactionID,
+		}
+
+		if options.groupReasons {
+			reasonID, ok := reasonIDs[hash.Reason]
+			if !ok {
+				reasonID = len(ymlList.Reasons) + 1
+				reasonIDs[hash.Reason] = reasonID
+				ymlList.Reasons = append(ymlList.Reasons, yamlReason{
+					ID:     reasonID,
+					Reason: hash.Reason,
+				})
+			}
+
+			ymlHash.ReasonID = &reasonID
+		} else {
+			ymlHash.Reason = hash.Reason
+		}
+
+		ymlList.IgnoredHashes = append(ymlList.IgnoredHashes, *ymlHash)
+	}
+
+	return ymlList
+}
+
+// yamlIgnoredHash is an internal struct for YAML and JSON serialization.
 type yamlIgnoredHash struct {
-	Hash    string `yaml:"hash"`               // transaction hash
-	Reason  string `yaml:"reason"`             // reason for ignoring the transaction
-	AddedOn string `yaml:"added_on,omitempty"` // date this hash was added to the ignore list
+	// transaction hash
+	Hash string `yaml:"hash" json:"hash"`
+	// reason for ignoring the transaction
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"`
+	// ID of the reason in the table of reasons
+	ReasonID *int `yaml:"reason_id,omitempty" json:"reason_id,omitempty"`
+	// date this hash was added to the ignore list
+	AddedOn string `yaml:"added_on,omitempty" json:"added_on,omitempty"`
+	// why the hash was added to the ignore list
+	Kind IgnoredHashKind `yaml:"kind,omitempty" json:"kind,omitempty"`
+	// ID of the YNAB transaction for which the hash was processed
+	YNABTransactionID string `yaml:"ynab_transaction_id,omitempty" json:"ynab_transaction_id,omitempty"`
+	// whether the hash was processed, as recorded before kinds were; it is read but never written
+	Processed bool `yaml:"processed,omitempty" json:"processed,omitempty"`
+}
+
+// yamlReason is an internal struct for YAML and JSON serialization.
+type yamlReason struct {
+	ID     int    `yaml:"id"     json:"id"`     // ID by which ignored hashes refer to the reason
+	Reason string `yaml:"reason" json:"reason"` // reason for ignoring transactions
 }
 
-// yamlIgnoreList is an internal struct for YAML serialization.
+// yamlIgnoreList is an internal struct for YAML and JSON serialization.
 type yamlIgnoreList struct {
-	IgnoredHashes []yamlIgnoredHash `yaml:"ignored_hashes"`
+	Reasons       []yamlReason      `yaml:"reasons,omitempty" json:"reasons,omitempty"`
+	IgnoredHashes []yamlIgnoredHash `yaml:"ignored_hashes"    json:"ignored_hashes"`
 }
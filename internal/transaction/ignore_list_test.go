@@ -3,6 +3,7 @@ package transaction_test
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
@@ -25,6 +26,7 @@ var _ = Describe("IgnoreList", func() {
 			Expect(hashes[0].Hash).To(Equal(hash))
 			Expect(hashes[0].Reason).To(ContainSubstring(txnID))
 			Expect(hashes[0].Reason).To(ContainSubstring(today))
+			Expect(hashes[0].AddedOn).To(Equal(today))
 
 			var buf bytes.Buffer
 			err := transaction.ToYAML(ignoreList, &buf)
@@ -56,6 +58,7 @@ var _ = Describe("IgnoreList", func() {
 			Expect(hashes[0].Hash).To(Equal(hash))
 			Expect(hashes[0].Reason).To(ContainSubstring("Marked as ignored on "))
 			Expect(hashes[0].Reason).To(ContainSubstring(today))
+			Expect(hashes[0].AddedOn).To(Equal(today))
 
 			var buf bytes.Buffer
 			err := transaction.ToYAML(ignoreList, &buf)
@@ -74,6 +77,124 @@ var _ = Describe("IgnoreList", func() {
 		})
 	})
 
+	Context("RemoveHash", func() {
+		It("removes a hash in the ignore list, ignoring case", func() {
+			ignoreList := transaction.NewIgnoreList()
+			ignoreList.AddIgnoredHash("0xabc")
+			ignoreList.AddProcessedHash("0xdef", "tx-1")
+
+			Expect(ignoreList.RemoveHash("0xABC")).To(BeTrue())
+			Expect(ignoreList.IsHashIgnored("0xabc")).To(BeFalse())
+			Expect(ignoreList.IsHashIgnored("0xdef")).To(BeTrue())
+			Expect(ignoreList.GetHashCount()).To(Equal(1))
+		})
+
+		It("reports that a hash not in the ignore list was not removed", func() {
+			ignoreList := transaction.NewIgnoreList()
+			ignoreList.AddIgnoredHash("0xabc")
+
+			Expect(ignoreList.RemoveHash("0xdef")).To(BeFalse())
+			Expect(ignoreList.GetHashCount()).To(Equal(1))
+		})
+	})
+
+	Context("Prune", func() {
+		It("removes only the processed hashes added before the cutoff", func() {
+			longAgo := time.Now().AddDate(0, 0, -90).Format(time.DateOnly)
+			ignoreList, err := transaction.FromYAML(strings.NewReader(fmt.Sprintf(`ignored_hashes:
+  - hash: "0xoldprocessed"
+    reason: "Processed for transaction ID tx-1 on %[1]s"
+    added_on: "%[1]s"
+  - hash: "0xoldignored"
+    reason: "Marked as ignored on %[1]s"
+    added_on: "%[1]s"
+  - hash: "0xundated"
+    reason: "Processed for transaction ID tx-2"
+  - hash: "0xmarkedprocessed"
+    reason: "Cleared"
+    added_on: "%[1]s"
+    processed: true
+`, longAgo)))
+			Expect(err).NotTo(HaveOccurred())
+			ignoreList.AddProcessedHash("0xrecent", "tx-3")
+
+			Expect(ignoreList.Prune(30 * 24 * time.Hour)).To(Equal(2))
+			Expect(ignoreList.IsHashIgnored("0xoldprocessed")).To(BeFalse())
+			Expect(ignoreList.IsHashIgnored("0xmarkedprocessed")).To(BeFalse())
+			Expect(ignoreList.IsHashIgnored("0xoldignored")).To(BeTrue())
+			Expect(ignoreList.IsHashIgnored("0xundated")).To(BeTrue())
+			Expect(ignoreList.IsHashIgnored("0xrecent")).To(BeTrue())
+		})
+
+	})
+
+	Context("Kind", func() {
+		It("records the kind and YNAB transaction ID of each added hash", func() {
+			ignoreList := transaction.NewIgnoreList()
+			ignoreList.AddProcessedHash("0xaa", "tx-1")
+			ignoreList.AddIgnoredHash("0xbb")
+
+			hashes := ignoreList.GetHashes()
+			Expect(hashes[0].Kind).To(Equal(transaction.IgnoredHashKindProcessed))
+			Expect(hashes[0].YNABTransactionID).To(Equal("tx-1"))
+			Expect(hashes[1].Kind).To(Equal(transaction.IgnoredHashKindIgnored))
+			Expect(hashes[1].YNABTransactionID).To(BeEmpty())
+		})
+
+		DescribeTable("round-trips the kind of each hash through YAML",
+			func(opts ...transaction.YAMLOption) {
+				ignoreList := transaction.NewIgnoreList()
+				ignoreList.AddProcessedHash("0xaa", "tx-1")
+				ignoreList.AddIgnoredHash("0xbb")
+
+				var buf bytes.Buffer
+				Expect(transaction.ToYAML(ignoreList, &buf, opts...)).To(Succeed())
+				Expect(buf.String()).To(ContainSubstring("kind: processed"))
+				Expect(buf.String()).To(ContainSubstring("kind: ignored"))
+				Expect(buf.String()).To(ContainSubstring("ynab_transaction_id: tx-1"))
+				Expect(buf.String()).NotTo(ContainSubstring("processed: true"))
+
+				roundTripped, err := transaction.FromYAML(&buf)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(roundTripped.GetHashes()).To(Equal(ignoreList.GetHashes()))
+			},
+			Entry("with inline reasons"),
+			Entry("with grouped reasons", transaction.WithGroupedReasons()),
+		)
+
+		It("infers the kind of hashes written before kinds were recorded", func() {
+			ignoreList, err := transaction.FromYAML(strings.NewReader(`ignored_hashes:
+  - hash: "0xprocessed"
+    reason: "Processed for transaction ID tx-1 on 2025-12-10"
+  - hash: "0xmarkedprocessed"
+    reason: "Cleared"
+    processed: true
+  - hash: "0xignored"
+    reason: "Marked as ignored on 2025-12-10"
+`))
+			Expect(err).NotTo(HaveOccurred())
+
+			hashes := ignoreList.GetHashes()
+			Expect(hashes).To(HaveLen(3))
+			Expect(hashes[0].Kind).To(Equal(transaction.IgnoredHashKindProcessed))
+			Expect(hashes[0].YNABTransactionID).To(Equal("tx-1"))
+			Expect(hashes[1].Kind).To(Equal(transaction.IgnoredHashKindProcessed))
+			Expect(hashes[1].YNABTransactionID).To(BeEmpty())
+			Expect(hashes[2].Kind).To(Equal(transaction.IgnoredHashKindIgnored))
+		})
+
+		It("prefers a recorded kind to the one its reason suggests", func() {
+			ignoreList, err := transaction.FromYAML(strings.NewReader(`ignored_hashes:
+  - hash: "0xaa"
+    reason: "Processed for transaction ID tx-1, but ignored since"
+    kind: ignored
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ignoreList.GetHashes()[0].Kind).To(Equal(transaction.IgnoredHashKindIgnored))
+			Expect(ignoreList.Prune(0)).To(BeZero())
+		})
+	})
+
 	Context("FromYAML", func() {
 		It("parses a valid YAML ignore list", func() {
 			yaml := `ignored_hashes:
@@ -110,6 +231,38 @@ var _ = Describe("IgnoreList", func() {
 			Expect(ignoreList).NotTo(BeNil())
 		})
 
+		It("resolves reasons referenced by ID and falls back to inline reasons", func() {
+			yaml := `reasons:
+  - id: 1
+    reason: "bulk ignored"
+ignored_hashes:
+  - hash: "0xaa"
+    reason_id: 1
+  - hash: "0xbb"
+    reason: "inline reason"
+  - hash: "0xcc"
+    reason_id: 1`
+			ignoreList, err := transaction.FromYAML(bytes.NewReader([]byte(yaml)))
+			Expect(err).NotTo(HaveOccurred())
+
+			hashes := ignoreList.GetHashes()
+			Expect(hashes).To(HaveLen(3))
+			Expect(hashes[0].Reason).To(Equal("bulk ignored"))
+			Expect(hashes[1].Reason).To(Equal("inline reason"))
+			Expect(hashes[2].Reason).To(Equal("bulk ignored"))
+		})
+
+		It("returns an error for a reference to an unknown reason ID", func() {
+			yaml := `ignored_hashes:
+  - hash: "0xaa"
+    reason_id: 7`
+			ignoreList, err := transaction.FromYAML(bytes.NewReader([]byte(yaml)))
+
+			Expect(err).To(HaveOccurred())
+			Expect(ignoreList).To(BeNil())
+			Expect(err.Error()).To(ContainSubstring("unknown reason ID 7"))
+		})
+
 		It("handles YAML with no ignored_hashes field", func() {
 			yaml := `some_other_field: value`
 			reader := bytes.NewReader([]byte(yaml))
@@ -131,6 +284,41 @@ var _ = Describe("IgnoreList", func() {
 			Expect(buf.String()).To(ContainSubstring("ignored_hashes"))
 		})
 
+		It("writes each distinct reason once when grouping reasons", func() {
+			ignoreList := transaction.NewIgnoreList()
+			ignoreList.AddProcessedHash("0xaa", "ynab-1")
+			ignoreList.AddProcessedHash("0xbb", "ynab-2")
+			ignoreList.AddIgnoredHash("0xcc")
+			ignoreList.AddIgnoredHash("0xdd")
+
+			var buf bytes.Buffer
+			err := transaction.ToYAML(ignoreList, &buf, transaction.WithGroupedReasons())
+			Expect(err).NotTo(HaveOccurred())
+
+			output := buf.String()
+			Expect(output).To(ContainSubstring("reasons:"))
+			Expect(output).To(ContainSubstring("reason_id: 1"))
+			Expect(output).To(ContainSubstring("reason_id: 2"))
+			Expect(output).To(ContainSubstring("reason_id: 3"))
+			Expect(output).NotTo(ContainSubstring("reason_id: 4"))
+			Expect(strings.Count(output, "Marked as ignored on")).To(Equal(1))
+		})
+
+		It("does not write a table of reasons by default", func() {
+			ignoreList := transaction.NewIgnoreList()
+			ignoreList.AddIgnoredHash("0xcc")
+			ignoreList.AddIgnoredHash("0xdd")
+
+			var buf bytes.Buffer
+			err := transaction.ToYAML(ignoreList, &buf)
+			Expect(err).NotTo(HaveOccurred())
+
+			output := buf.String()
+			Expect(output).NotTo(ContainSubstring("reasons:"))
+			Expect(output).NotTo(ContainSubstring("reason_id"))
+			Expect(strings.Count(output, "Marked as ignored on")).To(Equal(2))
+		})
+
 		It("round-trips data from YAML to object and back to YAML", func() {
 			originalYAML := `ignored_hashes:
   - hash: "0x1234567890abcdef"
@@ -160,6 +348,7 @@ var _ = Describe("IgnoreList", func() {
 			yaml := `ignored_hashes:
   - hash: "0xaabbccdd"
     reason: "first"
+    added_on: "2025-12-10"
   - hash: "0xddeeffaa"
     reason: "second"`
 			// Deserialize
@@ -178,6 +367,91 @@ var _ = Describe("IgnoreList", func() {
 			Expect(output).To(ContainSubstring("0xddeeffaa"))
 			Expect(output).To(ContainSubstring("first"))
 			Expect(output).To(ContainSubstring("second"))
+			Expect(output).To(ContainSubstring(`added_on: "2025-12-10"`))
+
+			roundTripped, err := transaction.FromYAML(&buf)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roundTripped.GetHashes()).To(Equal([]transaction.IgnoredHash{
+				{
+					Hash:    "0xaabbccdd",
+					Reason:  "first",
+					AddedOn: "2025-12-10",
+					Kind:    transaction.IgnoredHashKindIgnored,
+				},
+				{Hash: "0xddeeffaa", Reason: "second", Kind: transaction.IgnoredHashKindIgnored},
+			}))
+		})
+
+		It("preserves reasons through grouped YAML serialization and deserialization", func() {
+			ignoreList := transaction.NewIgnoreList()
+			ignoreList.AddProcessedHash("0xaa", "ynab-1")
+			ignoreList.AddIgnoredHash("0xbb")
+			ignoreList.AddIgnoredHash("0xcc")
+
+			var buf bytes.Buffer
+			err := transaction.ToYAML(ignoreList, &buf, transaction.WithGroupedReasons())
+			Expect(err).NotTo(HaveOccurred())
+
+			roundTripped, err := transaction.FromYAML(&buf)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roundTripped.GetHashes()).To(Equal(ignoreList.GetHashes()))
+		})
+	})
+
+	Context("JSON", func() {
+		DescribeTable("reloads a JSON-written ignore list identically",
+			func(opts ...transaction.YAMLOption) {
+				ignoreList := transaction.NewIgnoreList()
+				ignoreList.AddProcessedHash("0xaa", "ynab-1")
+				ignoreList.AddIgnoredHash("0xbb")
+				ignoreList.AddIgnoredHash("0xcc")
+
+				var buf bytes.Buffer
+				Expect(transaction.ToJSON(ignoreList, &buf, opts...)).To(Succeed())
+				Expect(buf.String()).To(ContainSubstring(`"kind": "processed"`))
+
+				roundTripped, err := transaction.FromJSON(&buf)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(roundTripped.GetHashes()).To(Equal(ignoreList.GetHashes()))
+			},
+			Entry("with inline reasons"),
+			Entry("with grouped reasons", transaction.WithGroupedReasons()),
+		)
+
+		It("writes an empty ignore list as an empty array", func() {
+			var buf bytes.Buffer
+			Expect(transaction.ToJSON(transaction.NewIgnoreList(), &buf)).To(Succeed())
+			Expect(buf.String()).To(MatchJSON(`{"ignored_hashes": []}`))
+		})
+
+		It("reads the same structure as YAML", func() {
+			ignoreList, err := transaction.FromJSON(strings.NewReader(`{
+  "reasons": [{"id": 1, "reason": "bulk ignored"}],
+  "ignored_hashes": [
+    {"hash": "0xaa", "reason_id": 1, "added_on": "2025-12-10", "kind": "ignored"},
+    {"hash": "0xbb", "reason": "Processed for transaction ID tx-1 on 2025-12-11"}
+  ]
+}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ignoreList.GetHashes()).To(Equal([]transaction.IgnoredHash{
+				{
+					Hash:    "0xaa",
+					Reason:  "bulk ignored",
+					AddedOn: "2025-12-10",
+					Kind:    transaction.IgnoredHashKindIgnored,
+				},
+				{
+					Hash:              "0xbb",
+					Reason:            "Processed for transaction ID tx-1 on 2025-12-11",
+					Kind:              transaction.IgnoredHashKindProcessed,
+					YNABTransactionID: "tx-1",
+				},
+			}))
+		})
+
+		It("returns an error for invalid JSON", func() {
+			_, err := transaction.FromJSON(strings.NewReader(`{"ignored_hashes": [`))
+			Expect(err).To(MatchError(ContainSubstring("failed to decode ignore list from JSON")))
 		})
 	})
 })
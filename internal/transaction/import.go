@@ -6,13 +6,15 @@ import (
 	"fmt"
 	"log/slog"
 	"math/big"
-	"net/http"
 	"strings"
 	"time"
 
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/addressbook"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/eth"
+	ctshttp "github.com/jrh3k5/cryptonabber-txn-sync/internal/http"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/prompt"
 	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
 	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
-	"github.com/manifoldco/promptui"
 )
 
 type importTransferAction string
@@ -23,25 +25,129 @@ const (
 	importTransferActionIgnore importTransferAction = "ignore"
 )
 
+// ImportOptions configures how remaining transfers are imported into YNAB.
+type ImportOptions struct {
+	// Prompter is used to ask the user how to handle each transfer; if nil, prompts are shown on the terminal.
+	Prompter prompt.Prompter
+	// SkipOnCancel, if true, causes a canceled (Ctrl-C) prompt to skip only the current transfer.
+	// Canceling the prompts of two transfers in a row still aborts the import.
+	SkipOnCancel bool
+	// AddressFormat determines how counterparty addresses are rendered in prompts and default payee names.
+	AddressFormat eth.AddressFormat
+	// Categories, if not nil, are offered to the user as choices of category for each created transaction.
+	// The user can also leave a transaction uncategorized, optionally with a reminder in its memo.
+	Categories []*client.Category
+	// AddressBook, if not nil, provides the category of each transaction created for a transfer
+	// to or from one of its addresses that has a category; that category is then not prompted for.
+	AddressBook *addressbook.AddressBook
+	// DryRun, if true, causes the transactions the user chooses to create to be logged rather than created in YNAB.
+	// Such transfers are still reported as created, but are not recorded as processed.
+	DryRun bool
+	// FailFast, if true, causes the import to stop at the first transfer that fails to be processed.
+	// By default, such failures are logged and the import continues with the next transfer.
+	FailFast bool
+	// MinimumAmount, if not nil, is the smallest amount, in the token's base unit, of a transfer to be imported.
+	// If nil, transfers of less than 0.01 tokens are not imported.
+	MinimumAmount *big.Int
+	// NoMinimumInbound, if true, causes the minimum amount to apply only to outbound transfers,
+	// so that inbound transfers of any amount are imported.
+	NoMinimumInbound bool
+	// IncludeSelfTransfers, if true, causes transfers from a wallet to itself to be offered for
+	// import. By default, such transfers are skipped, as they do not change the wallet's balance.
+	IncludeSelfTransfers bool
+	// DefaultInboundPayee and DefaultOutboundPayee, if not empty, are offered as the payee of
+	// the transaction created for an inbound or outbound transfer, respectively, in place of
+	// the transfer's counterparty.
+	DefaultInboundPayee  string
+	DefaultOutboundPayee string
+	// SelectTransfers, if true, causes the user to be asked up front, in a single multi-select prompt,
+	// which transfers to import. Only the details of the chosen transfers are then prompted for,
+	// and the transfers that were not chosen are skipped.
+	SelectTransfers bool
+	// BatchCreate, if true, causes the transactions the user chooses to create to be created
+	// together, in a single request, once every transfer has been handled, rather than
+	// one at a time as each is chosen.
+	BatchCreate bool
+	// RoundedDecimalDigits, if not nil, is the number of decimal digits (e.g., those of the
+	// budget's currency) to which the amount of each transfer is rounded for display in prompts,
+	// alongside its full precision.
+	RoundedDecimalDigits *int
+	// Journal, if not nil, records the decision made for each transfer. A transfer for which
+	// it already holds a decision, e.g., from an interrupted import, is handled as recorded
+	// rather than prompted for again.
+	Journal *DecisionJournal
+	// SaveJournal, if not nil, is called with the Journal each time a decision is recorded in it,
+	// so that the decisions made so far outlast an import that is interrupted, e.g., by a crash.
+	// A journal that fails to be saved is saved again with the next decision.
+	SaveJournal func(journal *DecisionJournal) error
+	// AutoCreate, if true, causes a transaction to be created for every transfer offered for
+	// import without prompting, for unattended runs. Its payee is the one that would have been
+	// offered, its category that of its counterparty in the address book, if any, and its memo
+	// the transfer's transaction hash. SelectTransfers is then disregarded.
+	AutoCreate bool
+	// AccountType determines the sign of the amount of each created transaction; if empty,
+	// the account is an asset account, in which transfers from the wallets are outflows.
+	AccountType AccountType
+}
+
+// ImportResult describes the outcome of importing transfers into YNAB.
+type ImportResult struct {
+	Created []*ImportedTransfer // transfers for which a YNAB transaction was created
+	Skipped []*Transfer         // transfers the user chose to skip for now
+	Ignored []*Transfer         // transfers the user chose to ignore permanently
+	// BelowMinimum are the transfers that were not offered for import because their amount
+	// is less than the minimum amount.
+	BelowMinimum []*Transfer
+}
+
+// ImportedTransfer pairs a transfer with the YNAB transaction created for it.
+type ImportedTransfer struct {
+	Transfer    *Transfer           // the imported transfer
+	Transaction *client.Transaction // the YNAB transaction created for the transfer
+}
+
 type transferImporter struct {
-	httpClient      *http.Client
+	httpClient      ctshttp.Doer
 	ynabAccessToken string
 	budgetID        string
 	accountID       string
 	tokenDetails    *token.Details
-	walletAddress   string
+	wallets         *Wallets
 	ignoreList      *IgnoreList
 	minimumAmount   *big.Int
+	minOutboundOnly bool // whether the minimum amount applies only to outbound transfers
+	includeSelf     bool // whether transfers from a wallet to itself are offered for import
+	inboundPayee    string
+	outboundPayee   string
+	prompter        prompt.Prompter
+	skipOnCancel    bool
+	addressFormat   eth.AddressFormat
+	categories      []*client.Category
+	lastCategory    *client.Category // the category most recently chosen; nil if none has been
+	addressBook     *addressbook.AddressBook
+	dryRun          bool
+	failFast        bool
+	selectTransfers bool
+	autoCreate      bool
+	batchCreate     bool
+	pending         []*pendingTransaction // transactions awaiting creation in a batch
+	roundedDigits   *int
+	accountType     AccountType
+	journal         *DecisionJournal
+	saveJournal     func(journal *DecisionJournal) error
+	occurrences     map[*Transfer]int // see countOccurrences
+	result          *ImportResult
 }
 
 func newTransferImporter(
-	httpClient *http.Client,
+	httpClient ctshttp.Doer,
 	ynabAccessToken string,
 	budgetID string,
 	accountID string,
 	tokenDetails *token.Details,
-	walletAddress string,
+	wallets *Wallets,
 	ignoreList *IgnoreList,
+	options ImportOptions,
 ) (*transferImporter, error) {
 	decimalPrecision := 2
 
@@ -62,15 +168,49 @@ func newTransferImporter(
 		nil,
 	)
 
+	if options.MinimumAmount != nil {
+		minimumAmount = options.MinimumAmount
+	}
+
+	prompter := options.Prompter
+	if prompter == nil {
+		prompter = prompt.NewTerminalPrompter()
+	}
+
+	journal := options.Journal
+	if journal == nil {
+		journal = NewDecisionJournal()
+	}
+
 	return &transferImporter{
 		httpClient:      httpClient,
 		ynabAccessToken: ynabAccessToken,
 		budgetID:        budgetID,
 		accountID:       accountID,
 		tokenDetails:    tokenDetails,
-		walletAddress:   walletAddress,
+		wallets:         wallets,
 		ignoreList:      ignoreList,
 		minimumAmount:   minimumAmount,
+		minOutboundOnly: options.NoMinimumInbound,
+		includeSelf:     options.IncludeSelfTransfers,
+		inboundPayee:    options.DefaultInboundPayee,
+		outboundPayee:   options.DefaultOutboundPayee,
+		prompter:        prompter,
+		skipOnCancel:    options.SkipOnCancel,
+		addressFormat:   options.AddressFormat,
+		categories:      options.Categories,
+		addressBook:     options.AddressBook,
+		dryRun:          options.DryRun,
+		failFast:        options.FailFast,
+		selectTransfers: options.SelectTransfers,
+		autoCreate:      options.AutoCreate,
+		batchCreate:     options.BatchCreate,
+		roundedDigits:   options.RoundedDecimalDigits,
+		accountType:     options.AccountType,
+		journal:         journal,
+		saveJournal:     options.SaveJournal,
+		occurrences:     make(map[*Transfer]int),
+		result:          &ImportResult{},
 	}, nil
 }
 
@@ -78,46 +218,116 @@ func (p *transferImporter) processTransfers(
 	ctx context.Context,
 	transfers []*Transfer,
 ) error {
+	previousInterrupted := false
 	for _, xfr := range transfers {
-		if err := p.processTransfer(ctx, xfr); err != nil {
-			if errors.Is(err, errUserCanceled) {
-				return err
+		err := p.processTransfer(ctx, xfr)
+		switch {
+		case err == nil:
+			previousInterrupted = false
+		case errors.Is(err, errTransferInterrupted):
+			if !p.skipOnCancel || previousInterrupted {
+				return errUserCanceled
 			}
+
+			previousInterrupted = true
+			p.result.Skipped = append(p.result.Skipped, xfr)
+
+			slog.InfoContext(
+				ctx,
+				"Skipping transfer after cancellation; cancel the next prompt as well to abort the import",
+				"transaction_hash",
+				xfr.TransactionHash,
+			)
+		case errors.Is(err, errUserCanceled):
+			return err
+		default:
+			if p.failFast {
+				return fmt.Errorf(
+					"failed to process transfer with hash '%s': %w",
+					xfr.TransactionHash,
+					err,
+				)
+			}
+
+			previousInterrupted = false
+
 			// Log error and continue with next transfer
 			slog.ErrorContext(ctx, "Failed to process transfer", "error", err)
-
-			continue
 		}
 	}
 
 	return nil
 }
 
-var errUserCanceled = errors.New("user canceled operation")
+// ErrAmountOutOfRange indicates that the amount of a transfer, once converted to milliunits and
+// given the sign of its direction, cannot be represented as a YNAB amount.
+var ErrAmountOutOfRange = errors.New("transfer amount is out of the range of YNAB amounts")
+
+var (
+	errUserCanceled = errors.New("user canceled operation")
+	// errTransferInterrupted indicates that the user interrupted (Ctrl-C) a prompt for a single transfer.
+	errTransferInterrupted = errors.New("user interrupted transfer prompt")
+)
+
+// resolvePromptError translates a prompt failure into the error to be returned for the current transfer.
+func resolvePromptError(err error, description string) error {
+	switch {
+	case errors.Is(err, prompt.ErrInterrupt):
+		return errTransferInterrupted
+	case errors.Is(err, prompt.ErrEOF):
+		return errUserCanceled
+	default:
+		return fmt.Errorf("%s prompt failed: %w", description, err)
+	}
+}
 
 func (p *transferImporter) processTransfer(
 	ctx context.Context,
 	xfr *Transfer,
 ) error {
 	isOutbound, counterparty, ok := p.determineDirection(xfr)
-	if !ok {
-		// Not related to the wallet; skip
+	if !ok || p.isSkippedSelfTransfer(ctx, xfr) {
+		// Not related to the wallet, or not moving any tokens out of it; skip
 		return nil
 	}
 
-	if p.isBelowMinimum(ctx, xfr) {
+	if p.isBelowMinimum(ctx, xfr, isOutbound) {
+		p.result.BelowMinimum = append(p.result.BelowMinimum, xfr)
+
 		return nil
 	}
 
-	// Ask user if they want to create a transaction
-	importAction, err := p.promptCreateTransaction(xfr, isOutbound, counterparty)
-	if err != nil {
-		return err
+	// Ask user if they want to create a transaction, unless they already chose the transfer
+	// or a decision was recorded for it
+	importAction := importTransferActionCreate
+	journaled, isJournaled := p.journal.decisionFor(xfr, p.occurrences[xfr])
+	if isJournaled {
+		slog.InfoContext(
+			ctx,
+			fmt.Sprintf("Replaying recorded decision to %s transfer", journaled.action),
+			"transaction_hash",
+			xfr.TransactionHash,
+		)
+
+		importAction = journaled.action
+	} else if !p.selectTransfers && !p.autoCreate {
+		var err error
+
+		importAction, err = p.promptCreateTransaction(ctx, xfr, isOutbound, counterparty)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !isJournaled && importAction != importTransferActionCreate {
+		p.recordDecision(ctx, xfr, importAction, nil)
 	}
 
 	switch importAction {
 	case importTransferActionSkip:
 		// User chose to skip; do nothing
+		p.result.Skipped = append(p.result.Skipped, xfr)
+
 		return nil
 	case importTransferActionIgnore:
 		// User chose to ignore; add to ignore list
@@ -129,6 +339,7 @@ func (p *transferImporter) processTransfer(
 		)
 
 		p.ignoreList.AddIgnoredHash(xfr.TransactionHash)
+		p.result.Ignored = append(p.result.Ignored, xfr)
 
 		return nil
 	case importTransferActionCreate:
@@ -137,19 +348,50 @@ func (p *transferImporter) processTransfer(
 		return fmt.Errorf("unknown import action: %s", importAction)
 	}
 
-	// Get transaction details from user
-	payeeName, memoText, err := p.promptTransactionDetails(xfr, counterparty)
-	if err != nil {
-		return err
+	// Get transaction details from user, unless they were recorded
+	var details *transactionDetails
+	if isJournaled {
+		details = journaled.details
+	} else {
+		var err error
+
+		details, err = p.promptTransactionDetails(
+			ctx,
+			xfr,
+			p.defaultPayee(isOutbound, counterparty),
+		)
+		if err != nil {
+			return err
+		}
+
+		p.recordDecision(ctx, xfr, importAction, details)
+	}
+
+	if p.batchCreate && !p.dryRun {
+		req, err := p.newCreateTransactionRequest(xfr, isOutbound, details)
+		if err != nil {
+			return err
+		}
+
+		p.pending = append(p.pending, &pendingTransaction{transfer: xfr, request: req})
+
+		return nil
 	}
 
 	// Create the YNAB transaction
-	createdID, err := p.createYNABTransaction(ctx, xfr, isOutbound, payeeName, memoText)
+	created, err := p.createYNABTransaction(ctx, xfr, isOutbound, details)
 	if err != nil {
 		return err
 	}
 
-	p.ignoreList.AddProcessedHash(xfr.TransactionHash, createdID)
+	if !p.dryRun {
+		p.ignoreList.AddProcessedHash(xfr.TransactionHash, created.ID)
+	}
+
+	p.result.Created = append(p.result.Created, &ImportedTransfer{
+		Transfer:    xfr,
+		Transaction: created,
+	})
 
 	return nil
 }
@@ -157,20 +399,45 @@ func (p *transferImporter) processTransfer(
 func (p *transferImporter) determineDirection(
 	xfr *Transfer,
 ) (bool, string, bool) {
+	isOutbound, _, ok := p.wallets.Direction(xfr)
 	switch {
-	case strings.EqualFold(xfr.FromAddress, p.walletAddress):
-		return true, xfr.ToAddress, true
-	case strings.EqualFold(xfr.ToAddress, p.walletAddress):
-		return false, xfr.FromAddress, true
-	default:
+	case !ok:
 		return false, "", false
+	case isOutbound:
+		return true, p.addressFormat.Format(xfr.ToAddress), true
+	default:
+		return false, p.addressFormat.Format(xfr.FromAddress), true
 	}
 }
 
+// isSkippedSelfTransfer determines whether the given transfer is one from a wallet to itself
+// that is not to be imported.
+func (p *transferImporter) isSkippedSelfTransfer(ctx context.Context, xfr *Transfer) bool {
+	if p.includeSelf || !strings.EqualFold(xfr.FromAddress, xfr.ToAddress) {
+		return false
+	}
+
+	slog.DebugContext(
+		ctx,
+		"Skipping transfer from a wallet to itself",
+		"transaction_hash",
+		xfr.TransactionHash,
+	)
+
+	return true
+}
+
+// isBelowMinimum determines whether the given transfer is too small to be imported.
+// Inbound transfers are never too small if the minimum applies only to outbound transfers.
 func (p *transferImporter) isBelowMinimum(
 	ctx context.Context,
 	xfr *Transfer,
+	isOutbound bool,
 ) bool {
+	if !isOutbound && p.minOutboundOnly {
+		return false
+	}
+
 	if xfr.Amount.Cmp(p.minimumAmount) < 0 {
 		slog.DebugContext(
 			ctx,
@@ -188,8 +455,179 @@ func (p *transferImporter) isBelowMinimum(
 	return false
 }
 
+// logBelowMinimum logs how many transfers, and how many tokens in total, were not offered for
+// import because they were less than the minimum amount, so that they are not missed.
+func (p *transferImporter) logBelowMinimum(ctx context.Context) {
+	belowMinimum := p.result.BelowMinimum
+	if len(belowMinimum) == 0 {
+		return
+	}
+
+	total := &Transfer{Amount: new(big.Int)}
+	for _, xfr := range belowMinimum {
+		total.Amount.Add(total.Amount, xfr.Amount)
+	}
+
+	minimum := &Transfer{Amount: p.minimumAmount}
+	decimals := p.tokenDetails.Decimals
+
+	slog.InfoContext(
+		ctx,
+		fmt.Sprintf(
+			"Skipped %d transfers below the %s minimum (total %s %s)",
+			len(belowMinimum),
+			minimum.FormatAmount(decimals),
+			total.FormatAmount(decimals),
+			p.tokenDetails.Name,
+		),
+	)
+}
+
+// countOccurrences counts, for each of the given transfers, the transfers before it with the same
+// transaction hash and log index, so that the decision journal can tell such transfers apart.
+func (p *transferImporter) countOccurrences(transfers []*Transfer) {
+	counts := make(map[string]int)
+	for _, xfr := range transfers {
+		key := transferKey(xfr.TransactionHash, xfr.LogIndex)
+		p.occurrences[xfr] = counts[key]
+		counts[key]++
+	}
+}
+
+// excludeIgnoredTransfers returns the given transfers other than those whose transaction hash
+// is already in the ignore list, either because they were ignored or because they were processed.
+// It is consulted once, before any transfer is imported, so that a transaction containing several
+// transfers is not cut short by the processing of the first of them.
+func (p *transferImporter) excludeIgnoredTransfers(
+	ctx context.Context,
+	transfers []*Transfer,
+) []*Transfer {
+	remaining := make([]*Transfer, 0, len(transfers))
+
+	for _, xfr := range transfers {
+		if p.ignoreList.IsHashIgnored(xfr.TransactionHash) {
+			slog.DebugContext(
+				ctx,
+				"Skipping transfer already in the ignore list",
+				"transaction_hash",
+				xfr.TransactionHash,
+			)
+
+			continue
+		}
+
+		remaining = append(remaining, xfr)
+	}
+
+	return remaining
+}
+
+// promptTransferSelection asks the user to choose, in a single prompt, which of the given transfers
+// are to be imported. It returns the chosen transfers, in their given order, and records the rest
+// as skipped; transfers that would not be imported anyway are left out of the prompt.
+// Transfers for which a decision was recorded are also left out of the prompt, but are returned
+// so that the recorded decision is replayed.
+func (p *transferImporter) promptTransferSelection(
+	ctx context.Context,
+	transfers []*Transfer,
+) ([]*Transfer, error) {
+	var eligible []*Transfer
+
+	var candidates []*Transfer
+
+	var items []string
+
+	for _, xfr := range transfers {
+		isOutbound, counterparty, ok := p.determineDirection(xfr)
+		if !ok || p.isSkippedSelfTransfer(ctx, xfr) {
+			continue
+		}
+
+		if p.isBelowMinimum(ctx, xfr, isOutbound) {
+			p.result.BelowMinimum = append(p.result.BelowMinimum, xfr)
+
+			continue
+		}
+
+		eligible = append(eligible, xfr)
+
+		if _, isJournaled := p.journal.decisionFor(xfr, p.occurrences[xfr]); isJournaled {
+			continue
+		}
+
+		candidates = append(candidates, xfr)
+		items = append(items, p.formatTransferDetails(xfr, isOutbound, counterparty))
+	}
+
+	if len(candidates) == 0 {
+		return eligible, nil
+	}
+
+	chosenIndexes, err := p.prompter.MultiSelect("Select the transfers to import", items)
+	if err != nil {
+		switch {
+		case errors.Is(err, prompt.ErrTimeout):
+			slog.InfoContext(ctx, "Transfer selection prompt timed out; skipping all transfers")
+
+			chosenIndexes = nil
+		case errors.Is(err, prompt.ErrInterrupt), errors.Is(err, prompt.ErrEOF):
+			return nil, errUserCanceled
+		default:
+			return nil, fmt.Errorf("transfer selection prompt failed: %w", err)
+		}
+	}
+
+	chosen := make(map[*Transfer]bool, len(chosenIndexes))
+	for _, chosenIndex := range chosenIndexes {
+		if chosenIndex < 0 || chosenIndex >= len(candidates) {
+			return nil, fmt.Errorf("invalid selection index: %d", chosenIndex)
+		}
+
+		chosen[candidates[chosenIndex]] = true
+	}
+
+	var selected []*Transfer
+
+	for _, xfr := range eligible {
+		_, isJournaled := p.journal.decisionFor(xfr, p.occurrences[xfr])
+		if isJournaled || chosen[xfr] {
+			selected = append(selected, xfr)
+		} else {
+			p.recordDecision(ctx, xfr, importTransferActionSkip, nil)
+			p.result.Skipped = append(p.result.Skipped, xfr)
+		}
+	}
+
+	return selected, nil
+}
+
+// recordDecision records the decision made for the given transfer in the journal
+// and, if it is to be saved, saves it.
+func (p *transferImporter) recordDecision(
+	ctx context.Context,
+	xfr *Transfer,
+	action importTransferAction,
+	details *transactionDetails,
+) {
+	p.journal.record(xfr, p.occurrences[xfr], action, details)
+
+	if p.saveJournal == nil {
+		return
+	}
+
+	if err := p.saveJournal(p.journal); err != nil {
+		slog.WarnContext(
+			ctx,
+			"Failed to save decision journal; it will be saved again with the next decision",
+			"error",
+			err,
+		)
+	}
+}
+
 // promptCreateTransaction prompts the user to decide whether to create a YNAB transaction for the given transfer.
 func (p *transferImporter) promptCreateTransaction(
+	ctx context.Context,
 	xfr *Transfer,
 	isOutbound bool,
 	counterparty string,
@@ -200,18 +638,23 @@ func (p *transferImporter) promptCreateTransaction(
 	skipOption := "Skip (for now)"
 	ignoreOption := "Ignore (skip permanently)"
 
-	selector := promptui.Select{
-		Label: "Create YNAB transaction for " + details + "?",
-		Items: []string{createOption, skipOption, ignoreOption},
-	}
-
-	selIdx, _, err := selector.Run()
+	selIdx, err := p.prompter.Select(
+		"Create YNAB transaction for "+details+"?",
+		[]string{createOption, skipOption, ignoreOption},
+	)
 	if err != nil {
-		if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
-			return importTransferActionSkip, errUserCanceled
+		if errors.Is(err, prompt.ErrTimeout) {
+			slog.InfoContext(
+				ctx,
+				"Transaction creation prompt timed out; skipping transfer",
+				"transaction_hash",
+				xfr.TransactionHash,
+			)
+
+			return importTransferActionSkip, nil
 		}
 
-		return importTransferActionSkip, fmt.Errorf("transaction creation prompt failed: %w", err)
+		return importTransferActionSkip, resolvePromptError(err, "transaction creation")
 	}
 
 	switch selIdx {
@@ -236,100 +679,300 @@ func (p *transferImporter) formatTransferDetails(
 		sign = "-"
 	}
 
-	return fmt.Sprintf(
+	amount := xfr.FormatAmount(p.tokenDetails.Decimals)
+	if p.roundedDigits != nil && *p.roundedDigits < p.tokenDetails.Decimals {
+		// only worth showing if rounding actually shortens the amount
+		roundedAmount := xfr.FormatRoundedAmount(p.tokenDetails.Decimals, *p.roundedDigits)
+		if len(amount) > len(roundedAmount) {
+			amount += " (~" + roundedAmount + ")"
+		}
+	}
+
+	details := fmt.Sprintf(
 		"%s %s %s on %s %s %s",
 		sign,
-		xfr.FormatAmount(p.tokenDetails.Decimals),
+		amount,
 		p.tokenDetails.Name,
 		xfr.ExecutionTime.Format(time.RFC3339),
 		ResolveDirection(isOutbound),
 		counterparty,
 	)
+
+	// with several wallets, show which of them the transfer belongs to
+	if p.wallets.Len() > 1 {
+		if _, wallet, ok := p.wallets.Direction(xfr); ok {
+			details += fmt.Sprintf(" (wallet %s)", p.addressFormat.Format(wallet))
+		}
+	}
+
+	return details
+}
+
+// transactionDetails holds the user's choices for a YNAB transaction to be created.
+type transactionDetails struct {
+	payeeName  string
+	categoryID *string // nil if the transaction is to be left uncategorized
+	memo       string
+}
+
+// defaultPayee returns the payee offered for the transaction created for a transfer in the given
+// direction with the given counterparty: the default payee for that direction, if one was given,
+// or else the counterparty.
+func (p *transferImporter) defaultPayee(isOutbound bool, counterparty string) string {
+	switch {
+	case isOutbound && p.outboundPayee != "":
+		return p.outboundPayee
+	case !isOutbound && p.inboundPayee != "":
+		return p.inboundPayee
+	default:
+		return counterparty
+	}
 }
 
 func (p *transferImporter) promptTransactionDetails(
+	ctx context.Context,
 	xfr *Transfer,
-	counterparty string,
-) (string, string, error) {
-	payeeName, err := p.promptPayeeName(counterparty)
+	defaultPayee string,
+) (*transactionDetails, error) {
+	if p.autoCreate {
+		return p.automaticTransactionDetails(ctx, xfr, defaultPayee), nil
+	}
+
+	payeeName, err := p.promptPayeeName(ctx, defaultPayee)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	memoText, err := p.promptMemo(xfr)
+	categoryID := p.counterpartyCategoryID(xfr)
+	addCategorizeTODO := false
+
+	if categoryID == nil {
+		categoryID, addCategorizeTODO, err = p.promptCategory(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	memoText, err := p.promptMemo(ctx, xfr)
 	if err != nil {
-		return "", "", err
+		return nil, err
+	}
+
+	if addCategorizeTODO {
+		memoText += "; " + categorizeTODO
 	}
 
-	return payeeName, memoText, nil
+	return &transactionDetails{
+		payeeName:  payeeName,
+		categoryID: categoryID,
+		memo:       memoText,
+	}, nil
 }
 
-func (p *transferImporter) promptPayeeName(defaultPayee string) (string, error) {
-	payeePrompt := promptui.Prompt{
-		Label:   "Payee name",
-		Default: defaultPayee,
+// automaticTransactionDetails describes, without prompting, the transaction to be created for
+// the given transfer when transactions are created automatically.
+// Its memo is the one given when no memo is entered at the prompt: the transaction hash alone.
+func (p *transferImporter) automaticTransactionDetails(
+	ctx context.Context,
+	xfr *Transfer,
+	defaultPayee string,
+) *transactionDetails {
+	slog.InfoContext(
+		ctx,
+		"Creating YNAB transaction for transfer without prompting",
+		"transaction_hash",
+		xfr.TransactionHash,
+		"payee",
+		defaultPayee,
+	)
+
+	return &transactionDetails{
+		payeeName:  defaultPayee,
+		categoryID: p.counterpartyCategoryID(xfr),
+		memo:       memoWithHash("", xfr),
+	}
+}
+
+// counterpartyCategoryID returns the ID of the category given in the address book for the
+// counterparty of the given transfer, or nil if it has none.
+func (p *transferImporter) counterpartyCategoryID(xfr *Transfer) *string {
+	if p.addressBook == nil {
+		return nil
+	}
+
+	counterparty := xfr.FromAddress
+	if isOutbound, _, ok := p.wallets.Direction(xfr); ok && isOutbound {
+		counterparty = xfr.ToAddress
+	}
+
+	categoryID := p.addressBook.ResolveCategoryID(counterparty)
+	if categoryID == "" {
+		return nil
 	}
 
-	payeeName, err := payeePrompt.Run()
+	return &categoryID
+}
+
+// categorizeTODO is added to the memo of a transaction left uncategorized because its category does not exist yet.
+const categorizeTODO = "TODO: categorize"
+
+// promptCategory prompts the user to choose a category, if categories were provided.
+// Once a category has been chosen, it is offered first, as the default, in later prompts.
+// It returns the ID of the chosen category, or nil if the transaction is to be left uncategorized,
+// and whether a reminder to categorize the transaction should be added to its memo.
+func (p *transferImporter) promptCategory(ctx context.Context) (*string, bool, error) {
+	if p.categories == nil {
+		return nil, false, nil
+	}
+
+	items := make([]string, 0, len(p.categories)+3) //nolint:mnd
+	if p.lastCategory != nil {
+		items = append(items, "Same as last time: "+formatCategory(p.lastCategory))
+	}
+
+	uncategorizedIndex := len(items)
+	uncategorizedTODOIndex := uncategorizedIndex + 1
+	firstCategoryIndex := uncategorizedIndex + 2 //nolint:mnd
+
+	items = append(
+		items,
+		"Leave uncategorized",
+		"Category not listed: leave uncategorized and add a TODO to the memo",
+	)
+
+	for _, category := range p.categories {
+		items = append(items, formatCategory(category))
+	}
+
+	selIdx, err := p.prompter.Select("Category", items)
 	if err != nil {
-		if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
-			return "", errUserCanceled
+		if errors.Is(err, prompt.ErrTimeout) {
+			slog.InfoContext(ctx, "Category prompt timed out; leaving transaction uncategorized")
+
+			return nil, false, nil
 		}
 
-		return "", fmt.Errorf("payee prompt failed: %w", err)
+		return nil, false, resolvePromptError(err, "category")
 	}
 
-	return payeeName, nil
+	switch {
+	case p.lastCategory != nil && selIdx == 0:
+		categoryID := p.lastCategory.ID
+
+		return &categoryID, false, nil
+	case selIdx == uncategorizedIndex:
+		return nil, false, nil
+	case selIdx == uncategorizedTODOIndex:
+		slog.InfoContext(
+			ctx,
+			"Categories cannot be created through the YNAB API; create the category in YNAB and then categorize this transaction there",
+		)
+
+		return nil, true, nil
+	case selIdx >= firstCategoryIndex && selIdx < len(items):
+		p.lastCategory = p.categories[selIdx-firstCategoryIndex]
+		categoryID := p.lastCategory.ID
+
+		return &categoryID, false, nil
+	default:
+		return nil, false, fmt.Errorf("unexpected category selection index: %d", selIdx)
+	}
 }
 
-func (p *transferImporter) promptMemo(xfr *Transfer) (string, error) {
-	memoPrompt := promptui.Prompt{
-		Label: "Memo (will auto-append transaction hash)",
+// formatCategory describes the given category as it is listed in the category prompt.
+func formatCategory(category *client.Category) string {
+	return category.GroupName + ": " + category.Name
+}
+
+func (p *transferImporter) promptPayeeName(
+	ctx context.Context,
+	defaultPayee string,
+) (string, error) {
+	payeeName, err := p.prompter.Input("Payee name", defaultPayee)
+	if err != nil {
+		if errors.Is(err, prompt.ErrTimeout) {
+			slog.InfoContext(
+				ctx,
+				"Payee prompt timed out; using default payee",
+				"payee",
+				defaultPayee,
+			)
+
+			return defaultPayee, nil
+		}
+
+		return "", resolvePromptError(err, "payee")
 	}
 
-	memoText, err := memoPrompt.Run()
+	return payeeName, nil
+}
+
+func (p *transferImporter) promptMemo(ctx context.Context, xfr *Transfer) (string, error) {
+	memoText, err := p.prompter.Input("Memo (will auto-append transaction hash)", "")
 	if err != nil {
-		if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
-			return "", errUserCanceled
+		if !errors.Is(err, prompt.ErrTimeout) {
+			return "", resolvePromptError(err, "memo")
 		}
 
-		return "", fmt.Errorf("memo prompt failed: %w", err)
+		slog.InfoContext(ctx, "Memo prompt timed out; using only the transaction hash as the memo")
 	}
 
 	if !strings.Contains(memoText, xfr.TransactionHash) {
-		memoText += "; transaction hash: " + xfr.TransactionHash
+		memoText = memoWithHash(memoText, xfr)
 	}
 
 	return memoText, nil
 }
 
+// memoWithHash appends the transaction hash of the given transfer to the given memo,
+// separating the two only if the memo is not empty.
+func memoWithHash(memo string, xfr *Transfer) string {
+	if memo == "" {
+		return "Transaction hash: " + xfr.TransactionHash
+	}
+
+	return memo + "; transaction hash: " + xfr.TransactionHash
+}
+
 // createYNABTransaction creates a YNAB transaction for the given transfer.
-// If the creation is successful, it returns the created transaction ID.
+// If the creation is successful, it returns the created transaction.
+// In a dry run, nothing is created and a description of the transaction that would have been created,
+// without an ID, is returned.
 func (p *transferImporter) createYNABTransaction(
 	ctx context.Context,
 	xfr *Transfer,
 	isOutbound bool,
-	payeeName string,
-	memoText string,
-) (string, error) {
-	amountInt64, err := p.convertToYNABAmount(xfr.Amount, isOutbound)
+	details *transactionDetails,
+) (*client.Transaction, error) {
+	req, err := p.newCreateTransactionRequest(xfr, isOutbound, details)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	cleared := "uncleared"
-	req := client.CreateTransactionRequest{
-		AccountID: p.accountID,
-		Date:      xfr.ExecutionTime,
-		Amount:    amountInt64,
-		PayeeName: &payeeName,
-		Memo:      &memoText,
-		Cleared:   &cleared,
+	if p.dryRun {
+		slog.InfoContext(
+			ctx,
+			"Dry run: would create YNAB transaction",
+			"amount",
+			client.FormatMilliunits(req.Amount),
+			"payee",
+			details.payeeName,
+			"memo",
+			details.memo,
+		)
+
+		return &client.Transaction{
+			Payee:       details.payeeName,
+			Amount:      req.Amount,
+			Date:        xfr.ExecutionTime,
+			Description: details.memo,
+			ImportID:    *req.ImportID,
+		}, nil
 	}
 
 	created, err := client.CreateTransaction(ctx, p.httpClient, p.ynabAccessToken, p.budgetID, req)
 	if err != nil {
-		return "", fmt.Errorf("failed to create transaction: %w", err)
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
 	slog.InfoContext(
@@ -343,7 +986,100 @@ func (p *transferImporter) createYNABTransaction(
 		created.Payee,
 	)
 
-	return created.ID, nil
+	return created, nil
+}
+
+// newCreateTransactionRequest describes the YNAB transaction to be created for the given transfer.
+func (p *transferImporter) newCreateTransactionRequest(
+	xfr *Transfer,
+	isOutbound bool,
+	details *transactionDetails,
+) (client.CreateTransactionRequest, error) {
+	amountInt64, err := p.convertToYNABAmount(xfr.Amount, isOutbound)
+	if err != nil {
+		return client.CreateTransactionRequest{}, err
+	}
+
+	cleared := "uncleared"
+	importID := ImportIDFor(xfr)
+
+	return client.CreateTransactionRequest{
+		AccountID:  p.accountID,
+		Date:       xfr.ExecutionTime,
+		Amount:     amountInt64,
+		PayeeName:  &details.payeeName,
+		CategoryID: details.categoryID,
+		Memo:       &details.memo,
+		Cleared:    &cleared,
+		ImportID:   &importID,
+	}, nil
+}
+
+// pendingTransaction is a transaction awaiting creation in a batch, along with the transfer
+// for which it is to be created.
+type pendingTransaction struct {
+	transfer *Transfer
+	request  client.CreateTransactionRequest
+}
+
+// createPendingTransactions creates, in a single request, the transactions awaiting creation
+// in a batch. A transaction that YNAB reports as a duplicate of one already imported is not
+// created, and its transfer is reported as skipped.
+func (p *transferImporter) createPendingTransactions(ctx context.Context) error {
+	if len(p.pending) == 0 {
+		return nil
+	}
+
+	requests := make([]client.CreateTransactionRequest, 0, len(p.pending))
+	for _, pending := range p.pending {
+		requests = append(requests, pending.request)
+	}
+
+	created, err := client.CreateTransactions(
+		ctx,
+		p.httpClient,
+		p.ynabAccessToken,
+		p.budgetID,
+		requests,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create %d transactions: %w", len(requests), err)
+	}
+
+	createdByImportID := make(map[string]*client.Transaction, len(created.Transactions))
+	for _, txn := range created.Transactions {
+		createdByImportID[txn.ImportID] = txn
+	}
+
+	for _, pending := range p.pending {
+		xfr := pending.transfer
+
+		txn, ok := createdByImportID[*pending.request.ImportID]
+		if !ok {
+			slog.WarnContext(
+				ctx,
+				"Transfer was already imported into YNAB; no transaction was created for it",
+				"transaction_hash",
+				xfr.TransactionHash,
+			)
+
+			p.result.Skipped = append(p.result.Skipped, xfr)
+
+			continue
+		}
+
+		p.ignoreList.AddProcessedHash(xfr.TransactionHash, txn.ID)
+		p.result.Created = append(p.result.Created, &ImportedTransfer{
+			Transfer:    xfr,
+			Transaction: txn,
+		})
+	}
+
+	slog.InfoContext(ctx, fmt.Sprintf("Created %d YNAB transactions", len(created.Transactions)))
+
+	p.pending = nil
+
+	return nil
 }
 
 func (p *transferImporter) convertToYNABAmount(amount *big.Int, isOutbound bool) (int64, error) {
@@ -355,42 +1091,77 @@ func (p *transferImporter) convertToYNABAmount(amount *big.Int, isOutbound bool)
 	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(p.tokenDetails.Decimals)), nil)
 	ynabMilli := new(big.Int).Quo(num, denom)
 
-	if isOutbound {
+	// apply the sign before checking the range: an outflow can reach math.MinInt64,
+	// whose magnitude is one greater than the largest inflow of math.MaxInt64
+	isNegative := p.accountType.IsNegative(isOutbound)
+	if isNegative {
 		ynabMilli.Neg(ynabMilli)
 	}
 
-	// Sanity check: ensure ynabMilli fits in int64
-	//nolint:mnd
-	if ynabMilli.BitLen() > 63 {
-		return 0, fmt.Errorf("computed amount exceeds int64: %s", ynabMilli.String())
+	if !ynabMilli.IsInt64() {
+		direction := "inflow"
+		if isNegative {
+			direction = "outflow"
+		}
+
+		return 0, fmt.Errorf(
+			"%w: %s milliunits cannot be recorded as a YNAB %s",
+			ErrAmountOutOfRange,
+			ynabMilli.String(),
+			direction,
+		)
 	}
 
 	return ynabMilli.Int64(), nil
 }
 
+// ImportRemainingTransfers prompts the user to import each of the given transfers into YNAB.
+// It returns a description of how each transfer presented to the user was handled.
 func ImportRemainingTransfers(
 	ctx context.Context,
-	httpClient *http.Client,
+	httpClient ctshttp.Doer,
 	ynabAccessToken string,
 	budgetID string,
 	accountID string,
 	transfers []*Transfer,
 	tokenDetails *token.Details,
-	walletAddress string,
+	wallets *Wallets,
 	ignoreList *IgnoreList,
-) error {
+	options ImportOptions,
+) (*ImportResult, error) {
 	processor, err := newTransferImporter(
 		httpClient,
 		ynabAccessToken,
 		budgetID,
 		accountID,
 		tokenDetails,
-		walletAddress,
+		wallets,
 		ignoreList,
+		options,
 	)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	processor.countOccurrences(transfers)
+
+	transfers = processor.excludeIgnoredTransfers(ctx, transfers)
+
+	if options.SelectTransfers && !options.AutoCreate {
+		transfers, err = processor.promptTransferSelection(ctx, transfers)
+		if err != nil {
+			return processor.result, err
+		}
+	}
+
+	// the transactions chosen before an import is aborted are still created, as they would have
+	// been had they been created one at a time
+	err = processor.processTransfers(ctx, transfers)
+	processor.logBelowMinimum(ctx)
+
+	if createErr := processor.createPendingTransactions(ctx); createErr != nil {
+		return processor.result, errors.Join(err, createErr)
 	}
 
-	return processor.processTransfers(ctx, transfers)
+	return processor.result, err
 }
@@ -0,0 +1,45 @@
+package transaction
+
+import (
+	"strconv"
+	"strings"
+)
+
+// importIDPrefix identifies import IDs generated by this tool.
+const importIDPrefix = "CTS:"
+
+// maxImportIDLength is the maximum length of an import ID accepted by YNAB.
+const maxImportIDLength = 36
+
+// ImportIDFor returns the deterministic YNAB import ID for the given transfer:
+// its transaction hash behind a prefix identifying this tool, followed by its log index
+// (e.g., "CTS:0xabc...:3") or, if that is unknown, its position within the transaction
+// (e.g., "CTS:0xabc...#1"), so that the transfers of one transaction get different IDs.
+// The hash is cut so that the ID fits the length YNAB accepts.
+func ImportIDFor(xfr *Transfer) string {
+	suffix := "#" + strconv.Itoa(xfr.Position)
+	if xfr.LogIndex != nil {
+		suffix = ":" + strconv.Itoa(*xfr.LogIndex)
+	}
+
+	hash := strings.ToLower(xfr.TransactionHash)
+	maxHashLength := maxImportIDLength - len(importIDPrefix) - len(suffix)
+	if len(hash) > maxHashLength {
+		hash = hash[:maxHashLength]
+	}
+
+	return importIDPrefix + hash + suffix
+}
+
+// NumberTransfers sets the Position of each of the given transfers to the number of transfers
+// before it with the same transaction hash.
+// The transfers should be numbered as read, before any are filtered out, so that each keeps
+// the same position, and so the same import ID, whichever of the others are left.
+func NumberTransfers(transfers []*Transfer) {
+	counts := make(map[string]int)
+	for _, xfr := range transfers {
+		hash := strings.ToLower(xfr.TransactionHash)
+		xfr.Position = counts[hash]
+		counts[hash]++
+	}
+}
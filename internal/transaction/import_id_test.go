@@ -0,0 +1,74 @@
+package transaction_test
+
+import (
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ImportIDFor", func() {
+	It("prefixes the transaction hash, regardless of its case", func() {
+		importID := transaction.ImportIDFor(&transaction.Transfer{TransactionHash: "0xABC123"})
+		Expect(importID).To(Equal("CTS:0xabc123#0"))
+		Expect(transaction.ImportIDFor(&transaction.Transfer{TransactionHash: "0xabc123"})).
+			To(Equal(importID))
+	})
+
+	It("tells apart the transfers of a transaction by their log indexes", func() {
+		logIndex3 := 3
+		logIndex12 := 12
+
+		Expect(transaction.ImportIDFor(&transaction.Transfer{
+			TransactionHash: "0xabc123",
+			LogIndex:        &logIndex3,
+		})).To(Equal("CTS:0xabc123:3"))
+		Expect(transaction.ImportIDFor(&transaction.Transfer{
+			TransactionHash: "0xabc123",
+			LogIndex:        &logIndex12,
+			Position:        1,
+		})).To(Equal("CTS:0xabc123:12"))
+	})
+
+	It("tells apart the transfers of a transaction by their positions without log indexes", func() {
+		Expect(transaction.ImportIDFor(&transaction.Transfer{
+			TransactionHash: "0xabc123",
+			Position:        1,
+		})).To(Equal("CTS:0xabc123#1"))
+	})
+
+	It("cuts the hash to keep the import ID within YNAB's limit of 36 characters", func() {
+		logIndex := 123
+		xfr := &transaction.Transfer{
+			TransactionHash: "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			LogIndex:        &logIndex,
+		}
+
+		importID := transaction.ImportIDFor(xfr)
+		Expect(importID).To(Equal("CTS:0x0123456789abcdef0123456789:123"))
+		Expect(importID).To(HaveLen(36))
+
+		xfr.LogIndex = nil
+		xfr.Position = 2
+		Expect(transaction.ImportIDFor(xfr)).To(Equal("CTS:0x0123456789abcdef0123456789ab#2"))
+	})
+})
+
+var _ = Describe("NumberTransfers", func() {
+	It("numbers the transfers of each transaction in the order given", func() {
+		transfers := []*transaction.Transfer{
+			{TransactionHash: "0xabc"},
+			{TransactionHash: "0xdef"},
+			{TransactionHash: "0xABC"},
+			{TransactionHash: "0xabc"},
+		}
+
+		transaction.NumberTransfers(transfers)
+
+		positions := make([]int, 0, len(transfers))
+		for _, xfr := range transfers {
+			positions = append(positions, xfr.Position)
+		}
+
+		Expect(positions).To(Equal([]int{0, 0, 1, 2}))
+	})
+})
@@ -0,0 +1,1351 @@
+package transaction_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/addressbook"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/eth"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/prompt"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ImportRemainingTransfers", func() {
+	const walletAddress = "0xwallet"
+
+	var ctx context.Context
+	var httpClient *http.Client
+	var mockTransport *httpmock.MockTransport
+	var tokenDetails *token.Details
+	var ignoreList *transaction.IgnoreList
+
+	newInboundTransfer := func(hash string) *transaction.Transfer {
+		return &transaction.Transfer{
+			FromAddress:     "0xcounterparty",
+			ToAddress:       walletAddress,
+			Amount:          big.NewInt(1000000),
+			ExecutionTime:   time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC),
+			TransactionHash: hash,
+		}
+	}
+
+	importTransfers := func(
+		transfers []*transaction.Transfer,
+		options transaction.ImportOptions,
+	) (*transaction.ImportResult, error) {
+		return transaction.ImportRemainingTransfers(
+			ctx,
+			httpClient,
+			"tokengoeshere",
+			"budget1",
+			"acct1",
+			transfers,
+			tokenDetails,
+			transaction.NewWallets(walletAddress),
+			ignoreList,
+			options,
+		)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockTransport = httpmock.NewMockTransport()
+		httpClient = &http.Client{Transport: mockTransport}
+		tokenDetails = &token.Details{Name: "USD Coin", Decimals: 6}
+		ignoreList = transaction.NewIgnoreList()
+	})
+
+	Context("user cancellation", func() {
+		var transfers []*transaction.Transfer
+
+		BeforeEach(func() {
+			transfers = []*transaction.Transfer{
+				newInboundTransfer("0xhash1"),
+				newInboundTransfer("0xhash2"),
+				newInboundTransfer("0xhash3"),
+			}
+		})
+
+		When("skipping on cancel is disabled", func() {
+			It("aborts the import on the first cancellation", func() {
+				prompter := &scriptedPrompter{answers: []scriptedAnswer{
+					errorAnswer(prompt.ErrInterrupt),
+				}}
+
+				_, err := importTransfers(transfers, transaction.ImportOptions{Prompter: prompter})
+				Expect(err).To(MatchError(ContainSubstring("user canceled operation")))
+				Expect(prompter.labels).To(HaveLen(1))
+			})
+		})
+
+		When("skipping on cancel is enabled", func() {
+			It("skips the canceled transfer and continues with the next", func() {
+				prompter := &scriptedPrompter{answers: []scriptedAnswer{
+					errorAnswer(prompt.ErrInterrupt),
+					selectAnswer(1), // skip
+					selectAnswer(1), // skip
+				}}
+
+				result, err := importTransfers(transfers, transaction.ImportOptions{
+					Prompter:     prompter,
+					SkipOnCancel: true,
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(prompter.labels).To(HaveLen(3))
+				Expect(result.Skipped).To(HaveLen(3))
+				Expect(prompter.labels[1]).To(ContainSubstring("0xcounterparty"))
+			})
+
+			It("aborts when two transfers in a row are canceled", func() {
+				prompter := &scriptedPrompter{answers: []scriptedAnswer{
+					errorAnswer(prompt.ErrInterrupt),
+					errorAnswer(prompt.ErrInterrupt),
+				}}
+
+				_, err := importTransfers(transfers, transaction.ImportOptions{
+					Prompter:     prompter,
+					SkipOnCancel: true,
+				})
+				Expect(err).To(MatchError(ContainSubstring("user canceled operation")))
+				Expect(prompter.labels).To(HaveLen(2))
+			})
+
+			It("aborts when the input is closed", func() {
+				prompter := &scriptedPrompter{answers: []scriptedAnswer{
+					errorAnswer(prompt.ErrEOF),
+				}}
+
+				_, err := importTransfers(transfers, transaction.ImportOptions{
+					Prompter:     prompter,
+					SkipOnCancel: true,
+				})
+				Expect(err).To(MatchError(ContainSubstring("user canceled operation")))
+				Expect(prompter.labels).To(HaveLen(1))
+			})
+		})
+	})
+
+	Context("address format", func() {
+		const counterparty = "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"
+
+		DescribeTable("renders the counterparty in the chosen format",
+			func(format eth.AddressFormat, expectedAddress string) {
+				xfr := newInboundTransfer("0xhash1")
+				xfr.FromAddress = counterparty
+
+				prompter := &scriptedPrompter{answers: []scriptedAnswer{
+					selectAnswer(1), // skip
+				}}
+
+				_, err := importTransfers([]*transaction.Transfer{xfr}, transaction.ImportOptions{
+					Prompter:      prompter,
+					AddressFormat: format,
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(prompter.labels).To(HaveLen(1))
+				Expect(prompter.labels[0]).To(ContainSubstring("from " + expectedAddress + "?"))
+			},
+			Entry("unchanged", eth.AddressFormatUnchanged, counterparty),
+			Entry(
+				"checksum",
+				eth.AddressFormatChecksum,
+				"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			),
+		)
+	})
+
+	Context("rounded amounts", func() {
+		var highPrecision *transaction.Transfer
+
+		BeforeEach(func() {
+			tokenDetails = &token.Details{Name: "Wrapped Ether", Decimals: 18}
+
+			highPrecision = newInboundTransfer("0xhash1")
+			highPrecision.Amount = new(big.Int).SetUint64(12345678901234567890)
+		})
+
+		It("shows the amount rounded to the given digits alongside its full precision", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(1), // skip
+			}}
+
+			roundedDigits := 2
+			transfers := []*transaction.Transfer{highPrecision}
+			_, err := importTransfers(transfers, transaction.ImportOptions{
+				Prompter:             prompter,
+				RoundedDecimalDigits: &roundedDigits,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.labels).To(HaveLen(1))
+			Expect(prompter.labels[0]).To(
+				ContainSubstring("+ 12.34567890123456789 (~12.35) Wrapped Ether"),
+			)
+		})
+
+		It("shows only the full precision by default", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(1), // skip
+			}}
+
+			transfers := []*transaction.Transfer{highPrecision}
+			_, err := importTransfers(transfers, transaction.ImportOptions{
+				Prompter: prompter,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.labels).To(HaveLen(1))
+			Expect(prompter.labels[0]).To(ContainSubstring("+ 12.34567890123456789 Wrapped Ether"))
+		})
+
+		It("does not repeat an amount that needs no rounding", func() {
+			highPrecision.Amount = new(big.Int).Mul(big.NewInt(125), big.NewInt(10000000000000000))
+
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(1), // skip
+			}}
+
+			roundedDigits := 2
+			transfers := []*transaction.Transfer{highPrecision}
+			_, err := importTransfers(transfers, transaction.ImportOptions{
+				Prompter:             prompter,
+				RoundedDecimalDigits: &roundedDigits,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.labels).To(HaveLen(1))
+			Expect(prompter.labels[0]).To(ContainSubstring("+ 1.25 Wrapped Ether"))
+		})
+	})
+
+	Context("category selection", func() {
+		var categories []*client.Category
+		var createdPayload map[string]any
+
+		BeforeEach(func() {
+			categories = []*client.Category{
+				{ID: "cat1", Name: "Groceries", GroupName: "Everyday"},
+				{ID: "cat2", Name: "Rent", GroupName: "Bills"},
+			}
+			createdPayload = nil
+
+			mockTransport.RegisterResponder(
+				"POST",
+				"https://api.ynab.com/v1/budgets/budget1/transactions",
+				func(req *http.Request) (*http.Response, error) {
+					var payload struct {
+						Transaction map[string]any `json:"transaction"`
+					}
+					Expect(json.NewDecoder(req.Body).Decode(&payload)).To(Succeed())
+					createdPayload = payload.Transaction
+
+					return httpmock.NewStringResponse(
+						http.StatusCreated,
+						`{"data":{"transaction":{"id":"created1","amount":1000,"date":"2025-12-10"}}}`,
+					), nil
+				},
+			)
+		})
+
+		It("leaves the transaction uncategorized with a TODO in the memo", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Employer"), // payee
+				selectAnswer(1),         // category not listed
+				inputAnswer("Paycheck"), // memo
+			}}
+
+			result, err := importTransfers(
+				[]*transaction.Transfer{newInboundTransfer("0xhash1")},
+				transaction.ImportOptions{
+					Prompter:   prompter,
+					Categories: categories,
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Created).To(HaveLen(1))
+
+			Expect(createdPayload).ToNot(HaveKey("category_id"))
+			Expect(createdPayload).To(HaveKeyWithValue(
+				"memo",
+				"Paycheck; transaction hash: 0xhash1; TODO: categorize",
+			))
+		})
+
+		It("assigns the chosen category", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Landlord"), // payee
+				selectAnswer(3),         // Bills: Rent
+				inputAnswer("December"), // memo
+			}}
+
+			_, err := importTransfers(
+				[]*transaction.Transfer{newInboundTransfer("0xhash1")},
+				transaction.ImportOptions{
+					Prompter:   prompter,
+					Categories: categories,
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(createdPayload).To(HaveKeyWithValue("category_id", "cat2"))
+			Expect(createdPayload).To(HaveKeyWithValue(
+				"import_id",
+				transaction.ImportIDFor(newInboundTransfer("0xhash1")),
+			))
+			Expect(createdPayload).To(
+				HaveKeyWithValue("memo", "December; transaction hash: 0xhash1"),
+			)
+		})
+
+		It("offers the last chosen category first", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Landlord"), // payee
+				selectAnswer(3),         // Bills: Rent
+				inputAnswer("December"), // memo
+				selectAnswer(0),         // create
+				inputAnswer("Landlord"), // payee
+				selectAnswer(0),         // same as last time
+				inputAnswer("January"),  // memo
+			}}
+
+			result, err := importTransfers(
+				[]*transaction.Transfer{
+					newInboundTransfer("0xhash1"),
+					newInboundTransfer("0xhash2"),
+				},
+				transaction.ImportOptions{
+					Prompter:   prompter,
+					Categories: categories,
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Created).To(HaveLen(2))
+
+			// the create and category prompts of each transfer
+			Expect(prompter.selectItems).To(HaveLen(4))
+			Expect(prompter.selectItems[1][0]).To(Equal("Leave uncategorized"))
+			Expect(prompter.selectItems[3]).To(HaveLen(5))
+			Expect(prompter.selectItems[3][0]).To(Equal("Same as last time: Bills: Rent"))
+
+			Expect(createdPayload).To(HaveKeyWithValue("category_id", "cat2"))
+			Expect(createdPayload).To(
+				HaveKeyWithValue("memo", "January; transaction hash: 0xhash2"),
+			)
+		})
+
+		It("assigns the counterparty's category from the address book without prompting", func() {
+			addressBook := addressbook.NewAddressBook()
+			addressBook.Add(&addressbook.Entry{Address: "0xCounterparty", CategoryID: "cat1"})
+
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Employer"), // payee
+				inputAnswer("Paycheck"), // memo
+			}}
+
+			_, err := importTransfers(
+				[]*transaction.Transfer{newInboundTransfer("0xhash1")},
+				transaction.ImportOptions{
+					Prompter:    prompter,
+					Categories:  categories,
+					AddressBook: addressBook,
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			// only the create prompt
+			Expect(prompter.selectItems).To(HaveLen(1))
+			Expect(createdPayload).To(HaveKeyWithValue("category_id", "cat1"))
+		})
+
+		It("prompts for the category of a counterparty without one in the address book", func() {
+			addressBook := addressbook.NewAddressBook()
+			addressBook.Add(&addressbook.Entry{Address: "0xcounterparty", Label: "Employer"})
+
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Employer"), // payee
+				selectAnswer(3),         // Bills: Rent
+				inputAnswer("Paycheck"), // memo
+			}}
+
+			_, err := importTransfers(
+				[]*transaction.Transfer{newInboundTransfer("0xhash1")},
+				transaction.ImportOptions{
+					Prompter:    prompter,
+					Categories:  categories,
+					AddressBook: addressBook,
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(createdPayload).To(HaveKeyWithValue("category_id", "cat2"))
+		})
+	})
+
+	Context("dry run", func() {
+		It("creates no YNAB transactions", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Employer"), // payee
+				inputAnswer("Paycheck"), // memo
+				selectAnswer(1),         // skip
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{
+				newInboundTransfer("0xhash1"),
+				newInboundTransfer("0xhash2"),
+			}, transaction.ImportOptions{
+				Prompter: prompter,
+				DryRun:   true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mockTransport.GetTotalCallCount()).To(BeZero())
+
+			Expect(result.Created).To(HaveLen(1))
+			created := result.Created[0]
+			Expect(created.Transfer.TransactionHash).To(Equal("0xhash1"))
+			Expect(created.Transaction.ID).To(BeEmpty())
+			Expect(created.Transaction.Payee).To(Equal("Employer"))
+			Expect(created.Transaction.Amount).To(Equal(int64(1000)))
+			Expect(created.Transaction.Description).To(Equal("Paycheck; transaction hash: 0xhash1"))
+			Expect(result.Skipped).To(HaveLen(1))
+
+			Expect(ignoreList.IsHashIgnored("0xhash1")).To(BeFalse())
+		})
+
+		It("uses the transaction hash alone as the memo when none is entered", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Employer"), // payee
+				inputAnswer(""),         // memo
+			}}
+
+			result, err := importTransfers(
+				[]*transaction.Transfer{newInboundTransfer("0xhash1")},
+				transaction.ImportOptions{
+					Prompter: prompter,
+					DryRun:   true,
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Created).To(HaveLen(1))
+			Expect(result.Created[0].Transaction.Description).To(Equal("Transaction hash: 0xhash1"))
+		})
+	})
+
+	Context("batch creation", func() {
+		var payloads []map[string][]map[string]any
+		var duplicateImportIDs []string
+
+		BeforeEach(func() {
+			payloads = nil
+			duplicateImportIDs = []string{}
+
+			// echo back every requested transaction that is not a duplicate as created;
+			// like YNAB, only the first of several transactions sharing an import ID is created
+			mockTransport.RegisterResponder(
+				"POST",
+				"https://api.ynab.com/v1/budgets/budget1/transactions",
+				func(req *http.Request) (*http.Response, error) {
+					var payload map[string][]map[string]any
+					Expect(json.NewDecoder(req.Body).Decode(&payload)).To(Succeed())
+					payloads = append(payloads, payload)
+
+					var created []map[string]any
+					requestedImportIDs := make(map[string]bool)
+					for i, txn := range payload["transactions"] {
+						importID := txn["import_id"].(string)
+						if slices.Contains(duplicateImportIDs, importID) ||
+							requestedImportIDs[importID] {
+							continue
+						}
+
+						requestedImportIDs[importID] = true
+
+						created = append(created, map[string]any{
+							"id":        fmt.Sprintf("created%d", i+1),
+							"amount":    txn["amount"],
+							"date":      txn["date"],
+							"memo":      txn["memo"],
+							"import_id": txn["import_id"],
+						})
+					}
+
+					return httpmock.NewJsonResponse(http.StatusCreated, map[string]any{
+						"data": map[string]any{
+							"transactions":         created,
+							"duplicate_import_ids": duplicateImportIDs,
+						},
+					})
+				},
+			)
+		})
+
+		It("creates every chosen transaction in a single request once all are chosen", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Employer"), // payee
+				inputAnswer("Paycheck"), // memo
+				selectAnswer(1),         // skip
+				selectAnswer(0),         // create
+				inputAnswer("Friend"),   // payee
+				inputAnswer("Lunch"),    // memo
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{
+				newInboundTransfer("0xhash1"),
+				newInboundTransfer("0xhash2"),
+				newInboundTransfer("0xhash3"),
+			}, transaction.ImportOptions{
+				Prompter:    prompter,
+				BatchCreate: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(payloads).To(HaveLen(1))
+			Expect(payloads[0]["transactions"]).To(HaveLen(2))
+			Expect(payloads[0]["transactions"][1]).To(
+				HaveKeyWithValue("memo", "Lunch; transaction hash: 0xhash3"),
+			)
+
+			Expect(result.Created).To(HaveLen(2))
+			Expect(result.Created[0].Transfer.TransactionHash).To(Equal("0xhash1"))
+			Expect(result.Created[0].Transaction.ID).To(Equal("created1"))
+			Expect(result.Created[1].Transfer.TransactionHash).To(Equal("0xhash3"))
+			Expect(result.Created[1].Transaction.ID).To(Equal("created2"))
+			Expect(result.Skipped).To(HaveLen(1))
+
+			Expect(ignoreList.IsHashIgnored("0xhash1")).To(BeTrue())
+			Expect(ignoreList.IsHashIgnored("0xhash2")).To(BeFalse())
+			Expect(ignoreList.IsHashIgnored("0xhash3")).To(BeTrue())
+		})
+
+		It("creates a transaction for each of several transfers sharing a hash", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Employer"), // payee
+				inputAnswer("Paycheck"), // memo
+				selectAnswer(0),         // create
+				inputAnswer("Employer"), // payee
+				inputAnswer("Bonus"),    // memo
+			}}
+
+			paycheck := newInboundTransfer("0xhash1")
+			bonus := newInboundTransfer("0xhash1")
+			bonus.Amount = big.NewInt(2000000)
+			transfers := []*transaction.Transfer{paycheck, bonus}
+			transaction.NumberTransfers(transfers)
+
+			result, err := importTransfers(transfers, transaction.ImportOptions{
+				Prompter:    prompter,
+				BatchCreate: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(payloads).To(HaveLen(1))
+			Expect(payloads[0]["transactions"]).To(HaveLen(2))
+			Expect(payloads[0]["transactions"][0]["import_id"]).
+				ToNot(Equal(payloads[0]["transactions"][1]["import_id"]))
+
+			Expect(result.Skipped).To(BeEmpty())
+			Expect(result.Created).To(HaveLen(2))
+			Expect(result.Created[0].Transfer).To(BeIdenticalTo(paycheck))
+			Expect(result.Created[1].Transfer).To(BeIdenticalTo(bonus))
+			Expect(result.Created[1].Transaction.ID).To(Equal("created2"))
+		})
+
+		It("skips a transfer that YNAB reports was already imported", func() {
+			duplicateImportIDs = []string{transaction.ImportIDFor(newInboundTransfer("0xhash1"))}
+
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Employer"), // payee
+				inputAnswer("Paycheck"), // memo
+			}}
+
+			result, err := importTransfers(
+				[]*transaction.Transfer{newInboundTransfer("0xhash1")},
+				transaction.ImportOptions{
+					Prompter:    prompter,
+					BatchCreate: true,
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Created).To(BeEmpty())
+			Expect(result.Skipped).To(HaveLen(1))
+			Expect(ignoreList.IsHashIgnored("0xhash1")).To(BeFalse())
+		})
+
+		It("still creates the transactions chosen before the import is aborted", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Employer"), // payee
+				inputAnswer("Paycheck"), // memo
+				errorAnswer(prompt.ErrInterrupt),
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{
+				newInboundTransfer("0xhash1"),
+				newInboundTransfer("0xhash2"),
+			}, transaction.ImportOptions{
+				Prompter:    prompter,
+				BatchCreate: true,
+			})
+			Expect(err).To(MatchError(ContainSubstring("user canceled operation")))
+			Expect(payloads).To(HaveLen(1))
+			Expect(result.Created).To(HaveLen(1))
+			Expect(ignoreList.IsHashIgnored("0xhash1")).To(BeTrue())
+		})
+	})
+
+	Context("amounts at the limits of int64", func() {
+		var maxInt64 *big.Int
+
+		importAmount := func(amount *big.Int, outbound bool) (*transaction.ImportResult, error) {
+			xfr := newInboundTransfer("0xhash1")
+			xfr.Amount = amount
+			if outbound {
+				xfr.FromAddress, xfr.ToAddress = walletAddress, "0xcounterparty"
+			}
+
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Employer"), // payee
+				inputAnswer("Paycheck"), // memo
+			}}
+
+			return importTransfers([]*transaction.Transfer{xfr}, transaction.ImportOptions{
+				Prompter: prompter,
+				DryRun:   true,
+				FailFast: true,
+			})
+		}
+
+		BeforeEach(func() {
+			// with three decimals, a token's base units are exactly YNAB milliunits
+			tokenDetails = &token.Details{Name: "Milli Coin", Decimals: 3}
+			maxInt64 = big.NewInt(math.MaxInt64)
+		})
+
+		It("imports an inflow of the largest int64", func() {
+			result, err := importAmount(maxInt64, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Created).To(HaveLen(1))
+			Expect(result.Created[0].Transaction.Amount).To(Equal(int64(math.MaxInt64)))
+		})
+
+		It("rejects an inflow one greater than the largest int64", func() {
+			_, err := importAmount(new(big.Int).Add(maxInt64, big.NewInt(1)), false)
+			Expect(err).To(MatchError(transaction.ErrAmountOutOfRange))
+			Expect(err).To(MatchError(ContainSubstring("inflow")))
+		})
+
+		It("imports an outflow of the smallest int64", func() {
+			result, err := importAmount(new(big.Int).Add(maxInt64, big.NewInt(1)), true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Created).To(HaveLen(1))
+			Expect(result.Created[0].Transaction.Amount).To(Equal(int64(math.MinInt64)))
+		})
+
+		It("rejects an outflow one less than the smallest int64", func() {
+			_, err := importAmount(new(big.Int).Add(maxInt64, big.NewInt(2)), true)
+			Expect(err).To(MatchError(transaction.ErrAmountOutOfRange))
+			Expect(err).To(MatchError(ContainSubstring("outflow")))
+		})
+	})
+
+	Context("account type", func() {
+		importTransfer := func(
+			outbound bool,
+			accountType transaction.AccountType,
+		) *transaction.ImportResult {
+			xfr := newInboundTransfer("0xhash1")
+			if outbound {
+				xfr.FromAddress, xfr.ToAddress = walletAddress, "0xcounterparty"
+			}
+
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Merchant"), // payee
+				inputAnswer(""),         // memo
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{xfr}, transaction.ImportOptions{
+				Prompter:    prompter,
+				DryRun:      true,
+				AccountType: accountType,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Created).To(HaveLen(1))
+
+			return result
+		}
+
+		DescribeTable("signs the amount of the created transaction",
+			func(outbound bool, accountType transaction.AccountType, expected int64) {
+				result := importTransfer(outbound, accountType)
+				Expect(result.Created[0].Transaction.Amount).To(Equal(expected))
+			},
+			Entry("asset outflow", true, transaction.AccountTypeAsset, int64(-1000)),
+			Entry("asset inflow", false, transaction.AccountTypeAsset, int64(1000)),
+			Entry("liability charge", true, transaction.AccountTypeLiability, int64(1000)),
+			Entry("liability payment", false, transaction.AccountTypeLiability, int64(-1000)),
+		)
+	})
+
+	Context("transfer creation failure", func() {
+		var transfers []*transaction.Transfer
+
+		BeforeEach(func() {
+			transfers = []*transaction.Transfer{
+				newInboundTransfer("0xhash1"),
+				newInboundTransfer("0xhash2"),
+			}
+
+			mockTransport.RegisterResponder(
+				"POST",
+				"https://api.ynab.com/v1/budgets/budget1/transactions",
+				httpmock.NewStringResponder(http.StatusInternalServerError, `{"error":{}}`),
+			)
+		})
+
+		When("failing fast", func() {
+			It("stops at the first failed transfer", func() {
+				prompter := &scriptedPrompter{answers: []scriptedAnswer{
+					selectAnswer(0),         // create
+					inputAnswer("Employer"), // payee
+					inputAnswer("Paycheck"), // memo
+				}}
+
+				_, err := importTransfers(transfers, transaction.ImportOptions{
+					Prompter: prompter,
+					FailFast: true,
+				})
+				Expect(err).To(MatchError(ContainSubstring("0xhash1")))
+				Expect(err).To(MatchError(ContainSubstring("failed to create transaction")))
+				Expect(mockTransport.GetTotalCallCount()).To(Equal(1))
+			})
+		})
+
+		When("not failing fast", func() {
+			It("continues with the next transfer", func() {
+				prompter := &scriptedPrompter{answers: []scriptedAnswer{
+					selectAnswer(0),         // create
+					inputAnswer("Employer"), // payee
+					inputAnswer("Paycheck"), // memo
+					selectAnswer(1),         // skip
+				}}
+
+				result, err := importTransfers(transfers, transaction.ImportOptions{
+					Prompter: prompter,
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mockTransport.GetTotalCallCount()).To(Equal(1))
+				Expect(result.Created).To(BeEmpty())
+				Expect(result.Skipped).To(HaveLen(1))
+				Expect(result.Skipped[0].TransactionHash).To(Equal("0xhash2"))
+			})
+		})
+	})
+
+	Context("several wallets", func() {
+		It("offers transfers of every wallet except those between them", func() {
+			toSecond := newInboundTransfer("0xhash2")
+			toSecond.ToAddress = "0xSecond"
+			betweenWallets := newInboundTransfer("0xhash3")
+			betweenWallets.FromAddress = "0xsecond"
+
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(1), // skip
+				selectAnswer(1), // skip
+			}}
+
+			result, err := transaction.ImportRemainingTransfers(
+				ctx,
+				httpClient,
+				"tokengoeshere",
+				"budget1",
+				"acct1",
+				[]*transaction.Transfer{newInboundTransfer("0xhash1"), toSecond, betweenWallets},
+				tokenDetails,
+				transaction.NewWallets(walletAddress, "0xsecond"),
+				ignoreList,
+				transaction.ImportOptions{Prompter: prompter},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.labels).To(HaveLen(2))
+			Expect(prompter.labels[0]).To(ContainSubstring("(wallet " + walletAddress + ")"))
+			Expect(prompter.labels[1]).To(ContainSubstring("(wallet 0xSecond)"))
+			Expect(result.Skipped).To(HaveLen(2))
+		})
+	})
+
+	Context("minimum amount", func() {
+		It("does not prompt for transfers below the given minimum amount", func() {
+			small := newInboundTransfer("0xsmall")
+			small.Amount = big.NewInt(499999)
+
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(1), // skip
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{
+				small,
+				newInboundTransfer("0xlarge"),
+			}, transaction.ImportOptions{
+				Prompter:      prompter,
+				MinimumAmount: big.NewInt(500000),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.labels).To(HaveLen(1))
+			Expect(result.Skipped).To(HaveLen(1))
+			Expect(result.Skipped[0].TransactionHash).To(Equal("0xlarge"))
+			Expect(result.BelowMinimum).To(HaveLen(1))
+			Expect(result.BelowMinimum[0].TransactionHash).To(Equal("0xsmall"))
+		})
+
+		It("applies the minimum only to outbound transfers if asked to", func() {
+			inboundDust := newInboundTransfer("0xinbounddust")
+			inboundDust.Amount = big.NewInt(1)
+
+			outboundDust := newInboundTransfer("0xoutbounddust")
+			outboundDust.FromAddress = walletAddress
+			outboundDust.ToAddress = "0xrecipient"
+			outboundDust.Amount = big.NewInt(1)
+
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(1), // skip
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{
+				inboundDust,
+				outboundDust,
+			}, transaction.ImportOptions{
+				Prompter:         prompter,
+				MinimumAmount:    big.NewInt(500000),
+				NoMinimumInbound: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.labels).To(HaveLen(1))
+			Expect(result.Skipped).To(HaveLen(1))
+			Expect(result.Skipped[0].TransactionHash).To(Equal("0xinbounddust"))
+			Expect(result.BelowMinimum).To(HaveLen(1))
+			Expect(result.BelowMinimum[0].TransactionHash).To(Equal("0xoutbounddust"))
+		})
+	})
+
+	Context("self-transfers", func() {
+		var selfTransfer *transaction.Transfer
+
+		BeforeEach(func() {
+			selfTransfer = newInboundTransfer("0xself")
+			selfTransfer.FromAddress = walletAddress
+			selfTransfer.ToAddress = "0xWALLET"
+		})
+
+		It("skips a transfer from the wallet to itself without prompting", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(1), // skip
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{
+				selfTransfer,
+				newInboundTransfer("0xinbound"),
+			}, transaction.ImportOptions{Prompter: prompter})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.labels).To(HaveLen(1))
+			Expect(result.Skipped).To(HaveLen(1))
+			Expect(result.Skipped[0].TransactionHash).To(Equal("0xinbound"))
+		})
+
+		It("offers a transfer from the wallet to itself if asked to", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(1), // skip
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{
+				selfTransfer,
+			}, transaction.ImportOptions{
+				Prompter:             prompter,
+				IncludeSelfTransfers: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.labels).To(HaveLen(1))
+			Expect(result.Skipped).To(HaveLen(1))
+			Expect(result.Skipped[0].TransactionHash).To(Equal("0xself"))
+		})
+	})
+
+	Context("default payees", func() {
+		var outbound *transaction.Transfer
+
+		BeforeEach(func() {
+			outbound = newInboundTransfer("0xoutbound")
+			outbound.FromAddress = walletAddress
+			outbound.ToAddress = "0xrecipient"
+		})
+
+		It("offers the default payee of each transfer's direction", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),                // create
+				errorAnswer(prompt.ErrTimeout), // payee
+				inputAnswer(""),                // memo
+				selectAnswer(0),                // create
+				errorAnswer(prompt.ErrTimeout), // payee
+				inputAnswer(""),                // memo
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{
+				newInboundTransfer("0xinbound"),
+				outbound,
+			}, transaction.ImportOptions{
+				Prompter:             prompter,
+				DryRun:               true,
+				DefaultInboundPayee:  "Employer",
+				DefaultOutboundPayee: "Spending",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.defaults).To(Equal([]string{"Employer", "", "Spending", ""}))
+			Expect(result.Created).To(HaveLen(2))
+			Expect(result.Created[0].Transaction.Payee).To(Equal("Employer"))
+			Expect(result.Created[1].Transaction.Payee).To(Equal("Spending"))
+		})
+
+		It("offers the counterparty for a direction without a default payee", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),                // create
+				errorAnswer(prompt.ErrTimeout), // payee
+				inputAnswer(""),                // memo
+				selectAnswer(0),                // create
+				errorAnswer(prompt.ErrTimeout), // payee
+				inputAnswer(""),                // memo
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{
+				newInboundTransfer("0xinbound"),
+				outbound,
+			}, transaction.ImportOptions{
+				Prompter:            prompter,
+				DryRun:              true,
+				DefaultInboundPayee: "Employer",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Created).To(HaveLen(2))
+			Expect(result.Created[0].Transaction.Payee).To(Equal("Employer"))
+			Expect(result.Created[1].Transaction.Payee).To(Equal("0xrecipient"))
+		})
+	})
+
+	Context("prompt timeout", func() {
+		It("skips each transfer whose creation prompt times out", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				errorAnswer(prompt.ErrTimeout),
+				errorAnswer(prompt.ErrTimeout),
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{
+				newInboundTransfer("0xhash1"),
+				newInboundTransfer("0xhash2"),
+			}, transaction.ImportOptions{Prompter: prompter})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.labels).To(HaveLen(2))
+			Expect(result.Created).To(BeEmpty())
+			Expect(result.Skipped).To(HaveLen(2))
+		})
+	})
+
+	Context("automatic creation", func() {
+		var createdPayloads []map[string]any
+
+		BeforeEach(func() {
+			createdPayloads = nil
+			mockTransport.RegisterResponder(
+				"POST",
+				"https://api.ynab.com/v1/budgets/budget1/transactions",
+				func(req *http.Request) (*http.Response, error) {
+					var payload struct {
+						Transaction map[string]any `json:"transaction"`
+					}
+					Expect(json.NewDecoder(req.Body).Decode(&payload)).To(Succeed())
+					createdPayloads = append(createdPayloads, payload.Transaction)
+
+					return httpmock.NewStringResponse(
+						http.StatusCreated,
+						`{"data":{"transaction":{"id":"created1","amount":1000,"date":"2025-12-10"}}}`,
+					), nil
+				},
+			)
+		})
+
+		It("creates a transaction for every transfer without prompting", func() {
+			addressBook := addressbook.NewAddressBook()
+			addressBook.Add(&addressbook.Entry{Address: "0xcounterparty", CategoryID: "cat1"})
+
+			result, err := importTransfers([]*transaction.Transfer{
+				newInboundTransfer("0xhash1"),
+				newInboundTransfer("0xhash2"),
+			}, transaction.ImportOptions{
+				Prompter:        &scriptedPrompter{},
+				AddressBook:     addressBook,
+				SelectTransfers: true,
+				AutoCreate:      true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Created).To(HaveLen(2))
+
+			Expect(createdPayloads).To(HaveLen(2))
+			Expect(createdPayloads[0]).To(HaveKeyWithValue("payee_name", "0xcounterparty"))
+			Expect(createdPayloads[0]).To(HaveKeyWithValue("category_id", "cat1"))
+			Expect(createdPayloads[0]).To(HaveKeyWithValue("memo", "Transaction hash: 0xhash1"))
+			Expect(createdPayloads[1]).To(HaveKeyWithValue("memo", "Transaction hash: 0xhash2"))
+		})
+	})
+
+	Context("ignored transfers", func() {
+		BeforeEach(func() {
+			ignoreList.AddIgnoredHash("0xignored")
+			ignoreList.AddProcessedHash("0xprocessed", "txn1")
+		})
+
+		It("never prompts for a transfer already in the ignore list", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(1), // skip
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{
+				newInboundTransfer("0xignored"),
+				newInboundTransfer("0xnew"),
+				newInboundTransfer("0xprocessed"),
+			}, transaction.ImportOptions{Prompter: prompter})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.labels).To(HaveLen(1))
+			Expect(result.Skipped).To(HaveLen(1))
+			Expect(result.Skipped[0].TransactionHash).To(Equal("0xnew"))
+		})
+
+		It("never offers a transfer already in the ignore list for selection", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				multiSelectAnswer(),
+			}}
+
+			result, err := importTransfers([]*transaction.Transfer{
+				newInboundTransfer("0xignored"),
+				newInboundTransfer("0xnew"),
+			}, transaction.ImportOptions{
+				Prompter:        prompter,
+				SelectTransfers: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.items).To(HaveLen(1))
+			Expect(prompter.items[0]).To(HaveLen(1))
+			Expect(result.Skipped).To(HaveLen(1))
+			Expect(result.Skipped[0].TransactionHash).To(Equal("0xnew"))
+		})
+
+		It("prompts for every transfer of a transaction processed during the import", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(0),         // create
+				inputAnswer("Employer"), // payee
+				inputAnswer("Paycheck"), // memo
+				selectAnswer(1),         // skip
+			}}
+
+			mockTransport.RegisterResponder(
+				"POST",
+				"https://api.ynab.com/v1/budgets/budget1/transactions",
+				httpmock.NewStringResponder(
+					http.StatusCreated,
+					`{"data":{"transaction":{"id":"created1","amount":1000,"date":"2025-12-10"}}}`,
+				),
+			)
+
+			result, err := importTransfers([]*transaction.Transfer{
+				newInboundTransfer("0xshared"),
+				newInboundTransfer("0xshared"),
+			}, transaction.ImportOptions{Prompter: prompter})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Created).To(HaveLen(1))
+			Expect(result.Skipped).To(HaveLen(1))
+		})
+	})
+
+	Context("transfer selection", func() {
+		var transfers []*transaction.Transfer
+
+		BeforeEach(func() {
+			unrelated := newInboundTransfer("0xunrelated")
+			unrelated.ToAddress = "0xsomeoneelse"
+
+			transfers = []*transaction.Transfer{
+				newInboundTransfer("0xhash1"),
+				unrelated,
+				newInboundTransfer("0xhash2"),
+				newInboundTransfer("0xhash3"),
+			}
+		})
+
+		It("prompts only for the details of the chosen transfers", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				multiSelectAnswer(0, 2), // 0xhash1 and 0xhash3
+				inputAnswer("Employer"), // payee
+				inputAnswer("Paycheck"), // memo
+				inputAnswer("Friend"),   // payee
+				inputAnswer("Lunch"),    // memo
+			}}
+
+			result, err := importTransfers(transfers, transaction.ImportOptions{
+				Prompter:        prompter,
+				DryRun:          true,
+				SelectTransfers: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.labels).To(HaveLen(5))
+			Expect(prompter.items[0]).To(HaveLen(3))
+
+			Expect(result.Created).To(HaveLen(2))
+			Expect(result.Created[0].Transfer.TransactionHash).To(Equal("0xhash1"))
+			Expect(result.Created[0].Transaction.Payee).To(Equal("Employer"))
+			Expect(result.Created[1].Transfer.TransactionHash).To(Equal("0xhash3"))
+			Expect(result.Created[1].Transaction.Payee).To(Equal("Friend"))
+			Expect(result.Skipped).To(HaveLen(1))
+			Expect(result.Skipped[0].TransactionHash).To(Equal("0xhash2"))
+		})
+
+		It("skips every transfer when none are chosen", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				multiSelectAnswer(),
+			}}
+
+			result, err := importTransfers(transfers, transaction.ImportOptions{
+				Prompter:        prompter,
+				SelectTransfers: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Created).To(BeEmpty())
+			Expect(result.Skipped).To(HaveLen(3))
+		})
+
+		It("rejects a selection of a transfer that was not offered", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				multiSelectAnswer(3),
+			}}
+
+			_, err := importTransfers(transfers, transaction.ImportOptions{
+				Prompter:        prompter,
+				SelectTransfers: true,
+			})
+			Expect(err).To(MatchError(ContainSubstring("invalid selection index: 3")))
+		})
+
+		It("aborts the import when the selection is canceled", func() {
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				errorAnswer(prompt.ErrInterrupt),
+			}}
+
+			_, err := importTransfers(transfers, transaction.ImportOptions{
+				Prompter:        prompter,
+				SelectTransfers: true,
+			})
+			Expect(err).To(MatchError(ContainSubstring("user canceled operation")))
+		})
+	})
+
+	Context("decision journal", func() {
+		var transfers []*transaction.Transfer
+
+		// roundTrip writes the given journal to YAML and reads it back, as when resuming an import.
+		roundTrip := func(journal *transaction.DecisionJournal) *transaction.DecisionJournal {
+			var buffer bytes.Buffer
+			Expect(transaction.DecisionJournalToYAML(journal, &buffer)).To(Succeed())
+
+			restored, err := transaction.DecisionJournalFromYAML(&buffer)
+			Expect(err).ToNot(HaveOccurred())
+
+			return restored
+		}
+
+		BeforeEach(func() {
+			transfers = []*transaction.Transfer{
+				newInboundTransfer("0xhash1"),
+				newInboundTransfer("0xhash2"),
+				newInboundTransfer("0xhash3"),
+			}
+		})
+
+		It("replays the decisions of an interrupted import without prompting again", func() {
+			journal := transaction.NewDecisionJournal()
+			_, err := importTransfers(transfers, transaction.ImportOptions{
+				Prompter: &scriptedPrompter{answers: []scriptedAnswer{
+					selectAnswer(0),         // create
+					inputAnswer("Employer"), // payee
+					inputAnswer("Paycheck"), // memo
+					selectAnswer(2),         // ignore
+					errorAnswer(prompt.ErrInterrupt),
+				}},
+				DryRun:  true,
+				Journal: journal,
+			})
+			Expect(err).To(MatchError(ContainSubstring("user canceled operation")))
+			Expect(journal.Len()).To(Equal(2))
+
+			// as if the run had stopped before the ignore list was written
+			ignoreList = transaction.NewIgnoreList()
+
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				selectAnswer(1), // skip
+			}}
+			result, err := importTransfers(transfers, transaction.ImportOptions{
+				Prompter: prompter,
+				DryRun:   true,
+				Journal:  roundTrip(journal),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.labels).To(HaveLen(1))
+			Expect(prompter.labels[0]).To(ContainSubstring("0xcounterparty"))
+
+			Expect(result.Created).To(HaveLen(1))
+			Expect(result.Created[0].Transfer.TransactionHash).To(Equal("0xhash1"))
+			Expect(result.Created[0].Transaction.Payee).To(Equal("Employer"))
+			Expect(result.Created[0].Transaction.Description).To(
+				Equal("Paycheck; transaction hash: 0xhash1"),
+			)
+			Expect(result.Ignored).To(HaveLen(1))
+			Expect(result.Ignored[0].TransactionHash).To(Equal("0xhash2"))
+			Expect(ignoreList.IsHashIgnored("0xhash2")).To(BeTrue())
+			Expect(result.Skipped).To(HaveLen(1))
+			Expect(result.Skipped[0].TransactionHash).To(Equal("0xhash3"))
+		})
+
+		It("leaves transfers with a recorded decision out of the selection prompt", func() {
+			journal := transaction.NewDecisionJournal()
+			_, err := importTransfers(transfers[:1], transaction.ImportOptions{
+				Prompter: &scriptedPrompter{answers: []scriptedAnswer{
+					selectAnswer(1), // skip
+				}},
+				Journal: journal,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			prompter := &scriptedPrompter{answers: []scriptedAnswer{
+				multiSelectAnswer(1),  // 0xhash3
+				inputAnswer("Friend"), // payee
+				inputAnswer("Lunch"),  // memo
+			}}
+			result, err := importTransfers(transfers, transaction.ImportOptions{
+				Prompter:        prompter,
+				DryRun:          true,
+				SelectTransfers: true,
+				Journal:         journal,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prompter.items[0]).To(HaveLen(2))
+
+			Expect(result.Created).To(HaveLen(1))
+			Expect(result.Created[0].Transfer.TransactionHash).To(Equal("0xhash3"))
+			Expect(result.Skipped).To(HaveLen(2))
+			Expect(journal.Len()).To(Equal(3))
+		})
+
+		It("saves the journal as each decision is recorded", func() {
+			journal := transaction.NewDecisionJournal()
+
+			var savedLens []int
+			_, err := importTransfers(transfers, transaction.ImportOptions{
+				Prompter: &scriptedPrompter{answers: []scriptedAnswer{
+					selectAnswer(0),         // create
+					inputAnswer("Employer"), // payee
+					inputAnswer("Paycheck"), // memo
+					selectAnswer(2),         // ignore
+					errorAnswer(prompt.ErrInterrupt),
+				}},
+				DryRun:  true,
+				Journal: journal,
+				SaveJournal: func(saved *transaction.DecisionJournal) error {
+					Expect(saved).To(BeIdenticalTo(journal))
+					savedLens = append(savedLens, saved.Len())
+
+					return errors.New("disk full")
+				},
+			})
+			Expect(err).To(MatchError(ContainSubstring("user canceled operation")))
+			Expect(savedLens).To(Equal([]int{1, 2}))
+		})
+	})
+})
+
+// scriptedPrompter is a prompt.Prompter that answers prompts, in order, from a list of scripted answers.
+type scriptedPrompter struct {
+	answers []scriptedAnswer
+	labels  []string   // the labels of all prompts shown, in order
+	items   [][]string // the items offered by all multi-select prompts shown, in order
+
+	selectItems [][]string // the items offered by all select prompts shown, in order
+	defaults    []string   // the default values offered by all input prompts shown, in order
+}
+
+type scriptedAnswer struct {
+	index   int
+	text    string
+	indexes []int
+	err     error
+}
+
+func selectAnswer(index int) scriptedAnswer {
+	return scriptedAnswer{index: index}
+}
+
+func inputAnswer(text string) scriptedAnswer {
+	return scriptedAnswer{text: text}
+}
+
+func multiSelectAnswer(indexes ...int) scriptedAnswer {
+	return scriptedAnswer{indexes: indexes}
+}
+
+func errorAnswer(err error) scriptedAnswer {
+	return scriptedAnswer{err: err}
+}
+
+func (s *scriptedPrompter) Select(label string, items []string) (int, error) {
+	s.selectItems = append(s.selectItems, items)
+	answer := s.next(label)
+
+	return answer.index, answer.err
+}
+
+func (s *scriptedPrompter) Input(label string, defaultValue string) (string, error) {
+	s.defaults = append(s.defaults, defaultValue)
+	answer := s.next(label)
+
+	return answer.text, answer.err
+}
+
+func (s *scriptedPrompter) MultiSelect(label string, items []string) ([]int, error) {
+	s.items = append(s.items, items)
+	answer := s.next(label)
+
+	return answer.indexes, answer.err
+}
+
+func (s *scriptedPrompter) next(label string) scriptedAnswer {
+	s.labels = append(s.labels, label)
+	ExpectWithOffset(2, s.answers).ToNot(BeEmpty(), "unexpected prompt: %s", label)
+
+	answer := s.answers[0]
+	s.answers = s.answers[1:]
+
+	return answer
+}
@@ -0,0 +1,197 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// DecisionJournal records the decision made for each transfer during an import,
+// so that an interrupted import can be resumed without asking again about
+// the transfers that were already decided.
+// Decisions are keyed by the transaction hash of the transfer and, if known, its log index;
+// transfers sharing both are told apart by the order in which they are given to the import.
+type DecisionJournal struct {
+	decisions []*journalDecision
+	byKey     map[string]*journalDecision
+}
+
+// journalDecision is the decision made for a single transfer.
+type journalDecision struct {
+	hash       string
+	logIndex   *int
+	occurrence int // the number of transfers with the same hash and log index given before it
+	action     importTransferAction
+	details    *transactionDetails // the details entered to create the transfer, if any
+	decidedOn  string
+}
+
+// NewDecisionJournal creates a new, empty DecisionJournal.
+func NewDecisionJournal() *DecisionJournal {
+	return &DecisionJournal{
+		byKey: make(map[string]*journalDecision),
+	}
+}
+
+// Len returns the number of transfers for which a decision has been recorded.
+func (j *DecisionJournal) Len() int {
+	return len(j.decisions)
+}
+
+// decisionFor returns the decision recorded for the given transfer, if any,
+// given the number of transfers with the same hash and log index that preceded it.
+func (j *DecisionJournal) decisionFor(xfr *Transfer, occurrence int) (*journalDecision, bool) {
+	decision, ok := j.byKey[journalKey(xfr.TransactionHash, xfr.LogIndex, occurrence)]
+
+	return decision, ok
+}
+
+// record records the decision made for the given transfer, replacing any decision
+// already recorded for it. The details are only kept for transfers to be created.
+func (j *DecisionJournal) record(
+	xfr *Transfer,
+	occurrence int,
+	action importTransferAction,
+	details *transactionDetails,
+) {
+	if action != importTransferActionCreate {
+		details = nil
+	}
+
+	j.add(&journalDecision{
+		hash:       xfr.TransactionHash,
+		logIndex:   xfr.LogIndex,
+		occurrence: occurrence,
+		action:     action,
+		details:    details,
+		decidedOn:  time.Now().Format(time.DateOnly),
+	})
+}
+
+func (j *DecisionJournal) add(decision *journalDecision) {
+	key := journalKey(decision.hash, decision.logIndex, decision.occurrence)
+	if existing, ok := j.byKey[key]; ok {
+		*existing = *decision
+
+		return
+	}
+
+	j.decisions = append(j.decisions, decision)
+	j.byKey[key] = decision
+}
+
+// journalKey identifies the decision for a transfer within a DecisionJournal.
+func journalKey(transactionHash string, logIndex *int, occurrence int) string {
+	key := transferKey(transactionHash, logIndex)
+	if occurrence > 0 {
+		key += "#" + strconv.Itoa(occurrence)
+	}
+
+	return key
+}
+
+// transferKey identifies a transfer by its transaction hash and, if known, its log index.
+func transferKey(transactionHash string, logIndex *int) string {
+	key := strings.ToLower(transactionHash)
+	if logIndex != nil {
+		key += ":" + strconv.Itoa(*logIndex)
+	}
+
+	return key
+}
+
+// DecisionJournalFromYAML reads a DecisionJournal from a YAML representation.
+func DecisionJournalFromYAML(reader io.Reader) (*DecisionJournal, error) {
+	var ymlJournal yamlDecisionJournal
+	if err := yaml.NewDecoder(reader).Decode(&ymlJournal); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode decision journal from YAML: %w", err)
+	}
+
+	journal := NewDecisionJournal()
+	for _, ymlDecision := range ymlJournal.Decisions {
+		action := importTransferAction(ymlDecision.Action)
+
+		var details *transactionDetails
+
+		switch action {
+		case importTransferActionCreate:
+			details = &transactionDetails{
+				payeeName:  ymlDecision.Payee,
+				categoryID: ymlDecision.CategoryID,
+				memo:       ymlDecision.Memo,
+			}
+		case importTransferActionSkip, importTransferActionIgnore:
+			// nothing further was decided
+		default:
+			return nil, fmt.Errorf(
+				"unknown action '%s' for transfer with hash '%s' in decision journal",
+				ymlDecision.Action,
+				ymlDecision.Hash,
+			)
+		}
+
+		journal.add(&journalDecision{
+			hash:       ymlDecision.Hash,
+			logIndex:   ymlDecision.LogIndex,
+			occurrence: ymlDecision.Occurrence,
+			action:     action,
+			details:    details,
+			decidedOn:  ymlDecision.DecidedOn,
+		})
+	}
+
+	return journal, nil
+}
+
+// DecisionJournalToYAML writes a DecisionJournal to a YAML representation.
+func DecisionJournalToYAML(journal *DecisionJournal, writer io.Writer) error {
+	var ymlJournal yamlDecisionJournal
+	for _, decision := range journal.decisions {
+		ymlDecision := yamlDecision{
+			Hash:       decision.hash,
+			LogIndex:   decision.logIndex,
+			Occurrence: decision.occurrence,
+			Action:     string(decision.action),
+			DecidedOn:  decision.decidedOn,
+		}
+
+		if decision.details != nil {
+			ymlDecision.Payee = decision.details.payeeName
+			ymlDecision.CategoryID = decision.details.categoryID
+			ymlDecision.Memo = decision.details.memo
+		}
+
+		ymlJournal.Decisions = append(ymlJournal.Decisions, ymlDecision)
+	}
+
+	encoder := yaml.NewEncoder(writer)
+	defer func() { _ = encoder.Close() }()
+
+	if err := encoder.Encode(&ymlJournal); err != nil {
+		return fmt.Errorf("failed to encode decision journal to YAML: %w", err)
+	}
+
+	return nil
+}
+
+// yamlDecision is an internal struct for YAML serialization.
+type yamlDecision struct {
+	Hash       string  `yaml:"hash"`                  // transaction hash of the transfer
+	LogIndex   *int    `yaml:"log_index,omitempty"`   // log index of the transfer, if known
+	Occurrence int     `yaml:"occurrence,omitempty"`  // see journalDecision.occurrence
+	Action     string  `yaml:"action"`                // create, skip, or ignore
+	Payee      string  `yaml:"payee,omitempty"`       // payee entered for a created transfer
+	CategoryID *string `yaml:"category_id,omitempty"` // category chosen for a created transfer
+	Memo       string  `yaml:"memo,omitempty"`        // memo entered for a created transfer
+	DecidedOn  string  `yaml:"decided_on,omitempty"`  // date on which the decision was made
+}
+
+// yamlDecisionJournal is an internal struct for YAML serialization.
+type yamlDecisionJournal struct {
+	Decisions []yamlDecision `yaml:"decisions"`
+}
@@ -0,0 +1,53 @@
+package transaction_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DecisionJournal", func() {
+	Context("DecisionJournalFromYAML", func() {
+		It("reads the decision for each transfer", func() {
+			journal, err := transaction.DecisionJournalFromYAML(strings.NewReader(`
+decisions:
+  - hash: "0xhash1"
+    log_index: 3
+    action: create
+    payee: Employer
+    category_id: cat1
+    memo: "Paycheck; transaction hash: 0xhash1"
+    decided_on: "2025-12-10"
+  - hash: "0xhash2"
+    action: ignore
+`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(journal.Len()).To(Equal(2))
+
+			var buffer bytes.Buffer
+			Expect(transaction.DecisionJournalToYAML(journal, &buffer)).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("log_index: 3"))
+			Expect(buffer.String()).To(ContainSubstring("category_id: cat1"))
+			Expect(buffer.String()).To(ContainSubstring("action: ignore"))
+			Expect(buffer.String()).To(ContainSubstring(`decided_on: "2025-12-10"`))
+		})
+
+		It("reads an empty journal", func() {
+			journal, err := transaction.DecisionJournalFromYAML(strings.NewReader(""))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(journal.Len()).To(BeZero())
+		})
+
+		It("rejects an unknown action", func() {
+			_, err := transaction.DecisionJournalFromYAML(strings.NewReader(`
+decisions:
+  - hash: "0xhash1"
+    action: postpone
+`))
+			Expect(err).To(MatchError(ContainSubstring("unknown action 'postpone'")))
+		})
+	})
+})
@@ -0,0 +1,20 @@
+package transaction
+
+import (
+	"time"
+)
+
+// TransfersExecutedSince returns the transfers, in their given order, that were executed at or after
+// the given cutoff; a transfer executed exactly at the cutoff is kept.
+func TransfersExecutedSince(transfers []*Transfer, cutoff time.Time) []*Transfer {
+	recentTransfers := make([]*Transfer, 0, len(transfers))
+	for _, xfr := range transfers {
+		if xfr.ExecutionTime.Before(cutoff) {
+			continue
+		}
+
+		recentTransfers = append(recentTransfers, xfr)
+	}
+
+	return recentTransfers
+}
@@ -0,0 +1,51 @@
+package transaction_test
+
+import (
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TransfersExecutedSince", func() {
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC)
+	})
+
+	It("drops only the transfers executed before the cutoff", func() {
+		cutoff := now.AddDate(0, 0, -30)
+
+		tooOld := &transaction.Transfer{
+			TransactionHash: "0xtooold",
+			ExecutionTime:   cutoff.Add(-time.Second),
+		}
+		exactlyAtCutoff := &transaction.Transfer{
+			TransactionHash: "0xboundary",
+			ExecutionTime:   cutoff,
+		}
+		recent := &transaction.Transfer{
+			TransactionHash: "0xrecent",
+			ExecutionTime:   now.Add(-time.Hour),
+		}
+
+		Expect(transaction.TransfersExecutedSince(
+			[]*transaction.Transfer{recent, tooOld, exactlyAtCutoff},
+			cutoff,
+		)).To(Equal([]*transaction.Transfer{recent, exactlyAtCutoff}))
+	})
+
+	It("returns no transfers when all are older than the cutoff", func() {
+		old := &transaction.Transfer{
+			TransactionHash: "0xold",
+			ExecutionTime:   now.AddDate(-1, 0, 0),
+		}
+
+		Expect(transaction.TransfersExecutedSince(
+			[]*transaction.Transfer{old},
+			now.AddDate(0, 0, -30),
+		)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,143 @@
+package rpcsource
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+)
+
+const (
+	wordHexLength    = 64 // the length, in hex characters, of a 32-byte ABI word
+	addressHexLength = 40 // the length, in hex characters, of a 20-byte address
+)
+
+// Log is an event log entry as returned by eth_getLogs.
+type Log struct {
+	Address         string   `json:"address"`
+	Topics          []string `json:"topics"`
+	Data            string   `json:"data"`
+	BlockNumber     string   `json:"blockNumber"`
+	TransactionHash string   `json:"transactionHash"`
+	LogIndex        string   `json:"logIndex"`
+	Removed         bool     `json:"removed"`
+}
+
+// DecodeTransferLog decodes an ERC-20 Transfer(address indexed from, address indexed to, uint256 value) event log.
+// The sender and recipient are read from the second and third topics, each an address left-padded to 32 bytes,
+// and the amount is read from the data, a single 32-byte unsigned integer.
+// The execution time of the returned transfer is not resolved and is left as the zero time.
+func DecodeTransferLog(log *Log) (*transaction.Transfer, error) {
+	const topicCount = 3
+	if len(log.Topics) != topicCount {
+		return nil, fmt.Errorf(
+			"log in transaction %s has %d topics; expected %d for an ERC-20 transfer",
+			log.TransactionHash,
+			len(log.Topics),
+			topicCount,
+		)
+	}
+
+	if !strings.EqualFold(log.Topics[0], TransferEventTopic) {
+		return nil, fmt.Errorf(
+			"log in transaction %s is not an ERC-20 transfer event: %s",
+			log.TransactionHash,
+			log.Topics[0],
+		)
+	}
+
+	fromAddress, err := decodeAddressWord(log.Topics[1])
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to decode sender of log in transaction %s: %w",
+			log.TransactionHash,
+			err,
+		)
+	}
+
+	toAddress, err := decodeAddressWord(log.Topics[2])
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to decode recipient of log in transaction %s: %w",
+			log.TransactionHash,
+			err,
+		)
+	}
+
+	amount, err := decodeUint256Word(log.Data)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to decode amount of log in transaction %s: %w",
+			log.TransactionHash,
+			err,
+		)
+	}
+
+	logIndex, err := parseQuantity(log.LogIndex)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse log index of log in transaction %s: %w",
+			log.TransactionHash,
+			err,
+		)
+	}
+
+	logIndexInt := int(logIndex) //nolint:gosec
+
+	return &transaction.Transfer{
+		FromAddress:     fromAddress,
+		ToAddress:       toAddress,
+		Amount:          amount,
+		TransactionHash: log.TransactionHash,
+		LogIndex:        &logIndexInt,
+	}, nil
+}
+
+// decodeWord decodes a 0x-prefixed hex string holding exactly one 32-byte ABI word.
+func decodeWord(word string) ([]byte, error) {
+	wordHex := strings.TrimPrefix(word, "0x")
+	if len(wordHex) != wordHexLength {
+		return nil, fmt.Errorf(
+			"'%s' is %d hex characters long; expected %d",
+			word,
+			len(wordHex),
+			wordHexLength,
+		)
+	}
+
+	decoded, err := hex.DecodeString(wordHex)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not valid hex: %w", word, err)
+	}
+
+	return decoded, nil
+}
+
+// decodeAddressWord decodes an address left-padded with zeros to a 32-byte ABI word into a lowercase hex address.
+func decodeAddressWord(word string) (string, error) {
+	decoded, err := decodeWord(word)
+	if err != nil {
+		return "", err
+	}
+
+	const paddingLength = (wordHexLength - addressHexLength) / 2 //nolint:mnd
+	for _, b := range decoded[:paddingLength] {
+		if b != 0 {
+			return "", fmt.Errorf("'%s' is not a zero-padded address", word)
+		}
+	}
+
+	return "0x" + hex.EncodeToString(decoded[paddingLength:]), nil
+}
+
+// decodeUint256Word decodes a 32-byte big-endian ABI word into an unsigned integer.
+func decodeUint256Word(word string) (*big.Int, error) {
+	decoded, err := decodeWord(word)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(decoded), nil
+}
@@ -0,0 +1,76 @@
+package rpcsource_test
+
+import (
+	"math/big"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction/rpcsource"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DecodeTransferLog", func() {
+	var log *rpcsource.Log
+
+	BeforeEach(func() {
+		// a transfer of 4,157.06 USDC (4157060000 base units) on Base
+		log = &rpcsource.Log{
+			Address: "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913",
+			Topics: []string{
+				rpcsource.TransferEventTopic,
+				"0x0000000000000000000000009134fc7112b478e97ee6f0e6a7bf81ecafef19ed",
+				"0x000000000000000000000000cf6cece7bad73e40e033f8fa6b52c712263d1d68",
+			},
+			Data:            "0x00000000000000000000000000000000000000000000000000000000f7c7b3a0",
+			BlockNumber:     "0x259204a",
+			TransactionHash: "0xb4113e6ccf31511d5907a2dc41826948c8f80212d176ce66868d736e43212bd1",
+			LogIndex:        "0x5",
+		}
+	})
+
+	It("decodes the sender, recipient, and amount", func() {
+		xfr, err := rpcsource.DecodeTransferLog(log)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(xfr.FromAddress).To(Equal("0x9134fc7112b478e97ee6f0e6a7bf81ecafef19ed"))
+		Expect(xfr.ToAddress).To(Equal("0xcf6cece7bad73e40e033f8fa6b52c712263d1d68"))
+		Expect(xfr.Amount).To(Equal(big.NewInt(4157060000)))
+		Expect(xfr.TransactionHash).To(Equal(log.TransactionHash))
+		Expect(xfr.LogIndex).To(HaveValue(Equal(5)))
+	})
+
+	It("decodes amounts larger than 64 bits", func() {
+		log.Data = "0x0000000000000000000000000000000000000000000000056bc75e2d63100000"
+
+		xfr, err := rpcsource.DecodeTransferLog(log)
+		Expect(err).ToNot(HaveOccurred())
+
+		expected, ok := new(big.Int).SetString("100000000000000000000", 10)
+		Expect(ok).To(BeTrue())
+		Expect(xfr.Amount).To(Equal(expected))
+	})
+
+	DescribeTable("malformed logs", func(mutate func(*rpcsource.Log), expectedError string) {
+		mutate(log)
+
+		_, err := rpcsource.DecodeTransferLog(log)
+		Expect(err).To(MatchError(ContainSubstring(expectedError)))
+	},
+		Entry("too few topics", func(l *rpcsource.Log) {
+			l.Topics = l.Topics[:2]
+		}, "has 2 topics"),
+		Entry("a different event", func(l *rpcsource.Log) {
+			l.Topics[0] = "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+		}, "is not an ERC-20 transfer event"),
+		Entry("an unpadded address topic", func(l *rpcsource.Log) {
+			l.Topics[1] = "0x9134fc7112b478e97ee6f0e6a7bf81ecafef19ed"
+		}, "failed to decode sender"),
+		Entry("non-zero address padding", func(l *rpcsource.Log) {
+			l.Topics[2] = "0x000000000000000000000001cf6cece7bad73e40e033f8fa6b52c712263d1d68"
+		}, "is not a zero-padded address"),
+		Entry("short amount data", func(l *rpcsource.Log) {
+			l.Data = "0xf7c7b3a0"
+		}, "failed to decode amount"),
+		Entry("non-hex amount data", func(l *rpcsource.Log) {
+			l.Data = "0x00000000000000000000000000000000000000000000000000000000zzzzzzzz"
+		}, "is not valid hex"),
+	)
+})
@@ -0,0 +1,293 @@
+package rpcsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/eth"
+	ctshttp "github.com/jrh3k5/cryptonabber-txn-sync/internal/http"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/jsonrpc"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+)
+
+// TransferEventTopic is the topic of the ERC-20 Transfer(address,address,uint256) event.
+const TransferEventTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+
+// DefaultMaxBlockRange is the default number of blocks requested in a single eth_getLogs call.
+const DefaultMaxBlockRange uint64 = 2000
+
+// RPCTransferSource reads the ERC-20 transfers into and out of a wallet directly from an RPC node.
+type RPCTransferSource struct {
+	rpcClient     *jsonrpc.Client
+	tokenAddress  string
+	walletAddress string
+	maxBlockRange uint64
+	blockTimes    map[uint64]time.Time // the timestamps of blocks already fetched, by block number
+}
+
+// Option configures an RPCTransferSource.
+type Option func(*RPCTransferSource)
+
+// WithMaxBlockRange sets the largest number of blocks requested in a single eth_getLogs call,
+// for nodes that limit the block range of log queries.
+func WithMaxBlockRange(maxBlockRange uint64) Option {
+	return func(source *RPCTransferSource) {
+		source.maxBlockRange = maxBlockRange
+	}
+}
+
+// NewRPCTransferSource creates a source of the transfers of the given token into and out of the given wallet,
+// read from the RPC node at the given URL.
+func NewRPCTransferSource(
+	doer ctshttp.Doer,
+	rpcURL string,
+	tokenAddress string,
+	walletAddress string,
+	opts ...Option,
+) (*RPCTransferSource, error) {
+	if !eth.IsHexAddress(tokenAddress) {
+		return nil, fmt.Errorf("token address '%s' is not a valid hex address", tokenAddress)
+	}
+
+	if !eth.IsHexAddress(walletAddress) {
+		return nil, fmt.Errorf("wallet address '%s' is not a valid hex address", walletAddress)
+	}
+
+	source := &RPCTransferSource{
+		rpcClient:     jsonrpc.NewClient(doer, rpcURL),
+		tokenAddress:  eth.NormalizeAddress(tokenAddress),
+		walletAddress: eth.NormalizeAddress(walletAddress),
+		maxBlockRange: DefaultMaxBlockRange,
+		blockTimes:    make(map[uint64]time.Time),
+	}
+
+	for _, opt := range opts {
+		opt(source)
+	}
+
+	if source.maxBlockRange == 0 {
+		return nil, errors.New("maximum block range must be positive")
+	}
+
+	return source, nil
+}
+
+// LatestBlockNumber returns the number of the most recent block known to the RPC node.
+func (s *RPCTransferSource) LatestBlockNumber(ctx context.Context) (uint64, error) {
+	var result string
+	if err := s.rpcClient.Call(ctx, "eth_blockNumber", nil, &result); err != nil {
+		return 0, fmt.Errorf("failed to get latest block number: %w", err)
+	}
+
+	blockNumber, err := parseQuantity(result)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse latest block number: %w", err)
+	}
+
+	return blockNumber, nil
+}
+
+// GetTransfers returns the transfers of the token into or out of the wallet between the given blocks, inclusive,
+// ordered by block and then by position within the block.
+// The range is split into chunks of at most the maximum block range, each queried separately.
+// The execution time of each transfer is the timestamp of its block.
+func (s *RPCTransferSource) GetTransfers(
+	ctx context.Context,
+	fromBlock uint64,
+	toBlock uint64,
+) ([]*transaction.Transfer, error) {
+	if fromBlock > toBlock {
+		return nil, fmt.Errorf("from block %d is after to block %d", fromBlock, toBlock)
+	}
+
+	walletTopic := addressTopic(s.walletAddress)
+
+	var logs []*Log
+	for chunkStart := fromBlock; chunkStart <= toBlock; {
+		chunkEnd := min(toBlock, chunkStart+s.maxBlockRange-1)
+
+		// the wallet can be either the sender (topic 1) or the recipient (topic 2), which requires two queries
+		outboundLogs, err := s.getLogs(
+			ctx,
+			chunkStart,
+			chunkEnd,
+			[]any{TransferEventTopic, walletTopic},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		inboundLogs, err := s.getLogs(
+			ctx,
+			chunkStart,
+			chunkEnd,
+			[]any{TransferEventTopic, nil, walletTopic},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		logs = append(logs, outboundLogs...)
+		logs = append(logs, inboundLogs...)
+
+		if chunkEnd == toBlock {
+			break
+		}
+
+		chunkStart = chunkEnd + 1
+	}
+
+	return s.toTransfers(ctx, logs)
+}
+
+func (s *RPCTransferSource) getLogs(
+	ctx context.Context,
+	fromBlock uint64,
+	toBlock uint64,
+	topics []any,
+) ([]*Log, error) {
+	filter := map[string]any{
+		"address":   s.tokenAddress,
+		"fromBlock": formatQuantity(fromBlock),
+		"toBlock":   formatQuantity(toBlock),
+		"topics":    topics,
+	}
+
+	var logs []*Log
+	if err := s.rpcClient.Call(ctx, "eth_getLogs", []any{filter}, &logs); err != nil {
+		return nil, fmt.Errorf(
+			"failed to get logs for blocks %d to %d: %w",
+			fromBlock,
+			toBlock,
+			err,
+		)
+	}
+
+	return logs, nil
+}
+
+// toTransfers decodes the given logs into transfers, dropping removed and duplicate logs.
+func (s *RPCTransferSource) toTransfers(
+	ctx context.Context,
+	logs []*Log,
+) ([]*transaction.Transfer, error) {
+	type decodedLog struct {
+		blockNumber uint64
+		logIndex    int
+		transfer    *transaction.Transfer
+	}
+
+	seen := make(map[string]struct{}, len(logs))
+	decodedLogs := make([]decodedLog, 0, len(logs))
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+
+		// a transfer from the wallet to itself is returned by both queries
+		key := strings.ToLower(log.TransactionHash) + ":" + log.LogIndex
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		blockNumber, err := parseQuantity(log.BlockNumber)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to parse block number of log in transaction %s: %w",
+				log.TransactionHash,
+				err,
+			)
+		}
+
+		xfr, err := DecodeTransferLog(log)
+		if err != nil {
+			return nil, err
+		}
+
+		xfr.ExecutionTime, err = s.blockTime(ctx, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		decodedLogs = append(decodedLogs, decodedLog{
+			blockNumber: blockNumber,
+			logIndex:    *xfr.LogIndex,
+			transfer:    xfr,
+		})
+	}
+
+	sort.Slice(decodedLogs, func(i, j int) bool {
+		if decodedLogs[i].blockNumber != decodedLogs[j].blockNumber {
+			return decodedLogs[i].blockNumber < decodedLogs[j].blockNumber
+		}
+
+		return decodedLogs[i].logIndex < decodedLogs[j].logIndex
+	})
+
+	transfers := make([]*transaction.Transfer, len(decodedLogs))
+	for i, decoded := range decodedLogs {
+		transfers[i] = decoded.transfer
+	}
+
+	return transfers, nil
+}
+
+// blockTime returns the timestamp of the given block, fetching it with eth_getBlockByNumber
+// only if it has not already been fetched.
+func (s *RPCTransferSource) blockTime(ctx context.Context, blockNumber uint64) (time.Time, error) {
+	if blockTime, ok := s.blockTimes[blockNumber]; ok {
+		return blockTime, nil
+	}
+
+	var block *struct {
+		Timestamp string `json:"timestamp"`
+	}
+
+	// false requests only the hashes of the block's transactions rather than the full transactions
+	params := []any{formatQuantity(blockNumber), false}
+	if err := s.rpcClient.Call(ctx, "eth_getBlockByNumber", params, &block); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get block %d: %w", blockNumber, err)
+	}
+
+	if block == nil {
+		return time.Time{}, fmt.Errorf("block %d was not found", blockNumber)
+	}
+
+	timestamp, err := parseQuantity(block.Timestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(
+			"failed to parse timestamp of block %d: %w",
+			blockNumber,
+			err,
+		)
+	}
+
+	blockTime := time.Unix(int64(timestamp), 0).UTC() //nolint:gosec
+	s.blockTimes[blockNumber] = blockTime
+
+	return blockTime, nil
+}
+
+// addressTopic left-pads the given lowercase address to a 32-byte topic.
+func addressTopic(address string) string {
+	return "0x" + strings.Repeat("0", 24) + strings.TrimPrefix(address, "0x") //nolint:mnd
+}
+
+func formatQuantity(quantity uint64) string {
+	return "0x" + strconv.FormatUint(quantity, 16) //nolint:mnd
+}
+
+func parseQuantity(quantity string) (uint64, error) {
+	parsed, err := strconv.ParseUint(strings.TrimPrefix(quantity, "0x"), 16, 64) //nolint:mnd
+	if err != nil {
+		return 0, fmt.Errorf("invalid hex quantity '%s': %w", quantity, err)
+	}
+
+	return parsed, nil
+}
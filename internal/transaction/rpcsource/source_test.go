@@ -0,0 +1,177 @@
+package rpcsource_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction/rpcsource"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RPCTransferSource", func() {
+	const (
+		rpcURL        = "http://example.local"
+		tokenAddress  = "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+		walletAddress = "0xC8B0C609712aa852B1E390deD058276fa9bc36f1"
+		walletTopic   = "0x000000000000000000000000c8b0c609712aa852b1e390ded058276fa9bc36f1"
+		senderTopic   = "0x0000000000000000000000009134fc7112b478e97ee6f0e6a7bf81ecafef19ed"
+		txHash        = "0x3fe67569dfcce1fe4afca58819da01f423b2cb67d61ee3ba1ed413d2612717c7"
+	)
+
+	// a transfer of 101.5 USDC (101500000 base units) from the sender to the wallet
+	sampleLog := map[string]any{
+		"address":         "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913",
+		"topics":          []string{rpcsource.TransferEventTopic, senderTopic, walletTopic},
+		"data":            "0x00000000000000000000000000000000000000000000000000000000060cc460",
+		"blockNumber":     "0x2577ff8",
+		"transactionHash": txHash,
+		"logIndex":        "0x1f",
+		"removed":         false,
+	}
+
+	// a second transfer, of 2 USDC, in the same block
+	secondLog := map[string]any{
+		"address":         "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913",
+		"topics":          []string{rpcsource.TransferEventTopic, senderTopic, walletTopic},
+		"data":            "0x00000000000000000000000000000000000000000000000000000000001e8480",
+		"blockNumber":     "0x2577ff8",
+		"transactionHash": "0xb4113e6ccf31511d5907a2dc41826948c8f80212d176ce66868d736e43212bd1",
+		"logIndex":        "0x20",
+		"removed":         false,
+	}
+
+	type getLogsCall struct {
+		filter map[string]any
+	}
+
+	var ctx context.Context
+	var calls []getLogsCall
+	var blockRequests []any
+
+	// registerGetLogs responds to eth_getLogs calls, returning the sample log for any query on the recipient topic,
+	// and to eth_getBlockByNumber calls, returning the block of the sample log.
+	registerGetLogs := func() {
+		httpmock.RegisterResponder("POST", rpcURL, func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			var payload map[string]any
+			Expect(json.Unmarshal(body, &payload)).To(Succeed())
+
+			params, ok := payload["params"].([]any)
+			Expect(ok).To(BeTrue())
+
+			var result any
+			switch payload["method"] {
+			case "eth_getBlockByNumber":
+				blockRequests = append(blockRequests, params[0])
+				Expect(params).To(Equal([]any{"0x2577ff8", false}))
+				result = map[string]any{"number": "0x2577ff8", "timestamp": "0x69395f33"}
+			case "eth_getLogs":
+				filter, ok := params[0].(map[string]any)
+				Expect(ok).To(BeTrue())
+				calls = append(calls, getLogsCall{filter: filter})
+
+				logs := []any{}
+				topics, _ := filter["topics"].([]any)
+				if len(topics) == 3 && filter["fromBlock"] == "0x2577ff8" {
+					logs = append(logs, sampleLog, secondLog)
+				}
+				result = logs
+			default:
+				Fail(fmt.Sprintf("unexpected method: %v", payload["method"]))
+			}
+
+			response, err := json.Marshal(
+				map[string]any{"jsonrpc": "2.0", "id": payload["id"], "result": result},
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			return httpmock.NewBytesResponse(200, response), nil
+		})
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		calls = nil
+		blockRequests = nil
+	})
+
+	It("decodes a sample Transfer log", func() {
+		registerGetLogs()
+
+		source, err := rpcsource.NewRPCTransferSource(
+			http.DefaultClient,
+			rpcURL,
+			tokenAddress,
+			walletAddress,
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		transfers, err := source.GetTransfers(ctx, 0x2577ff8, 0x2577ff8)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(HaveLen(2))
+
+		xfr := transfers[0]
+		Expect(xfr.FromAddress).To(Equal("0x9134fc7112b478e97ee6f0e6a7bf81ecafef19ed"))
+		Expect(xfr.ToAddress).To(Equal("0xc8b0c609712aa852b1e390ded058276fa9bc36f1"))
+		Expect(xfr.Amount).To(Equal(big.NewInt(101500000)))
+		Expect(xfr.TransactionHash).To(Equal(txHash))
+		Expect(xfr.LogIndex).To(HaveValue(Equal(31)))
+		Expect(xfr.ExecutionTime).To(
+			Equal(time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC)),
+		)
+
+		Expect(transfers[1].Amount).To(Equal(big.NewInt(2000000)))
+		Expect(transfers[1].ExecutionTime).To(Equal(xfr.ExecutionTime))
+
+		// the block is only fetched once for both of its transfers
+		Expect(blockRequests).To(HaveLen(1))
+
+		Expect(calls).To(HaveLen(2))
+		Expect(calls[0].filter["address"]).To(Equal("0x833589fcd6edb6e08f4c7c32d4f71b54bda02913"))
+		Expect(calls[0].filter["topics"]).To(
+			Equal([]any{rpcsource.TransferEventTopic, walletTopic}),
+		)
+		Expect(calls[1].filter["topics"]).To(
+			Equal([]any{rpcsource.TransferEventTopic, nil, walletTopic}),
+		)
+	})
+
+	It("splits the block range into chunks", func() {
+		registerGetLogs()
+
+		source, err := rpcsource.NewRPCTransferSource(
+			http.DefaultClient,
+			rpcURL,
+			tokenAddress,
+			walletAddress,
+			rpcsource.WithMaxBlockRange(10),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = source.GetTransfers(ctx, 100, 125)
+		Expect(err).ToNot(HaveOccurred())
+
+		var ranges [][2]any
+		for _, call := range calls {
+			ranges = append(ranges, [2]any{call.filter["fromBlock"], call.filter["toBlock"]})
+		}
+
+		Expect(ranges).To(Equal([][2]any{
+			{"0x64", "0x6d"}, {"0x64", "0x6d"},
+			{"0x6e", "0x77"}, {"0x6e", "0x77"},
+			{"0x78", "0x7d"}, {"0x78", "0x7d"},
+		}))
+	})
+
+	It("rejects an invalid wallet address", func() {
+		_, err := rpcsource.NewRPCTransferSource(http.DefaultClient, rpcURL, tokenAddress, "0x1234")
+		Expect(err).To(MatchError(ContainSubstring("wallet address")))
+	})
+})
@@ -0,0 +1,34 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrResumeHashNotFound is returned when the transaction hash from which to resume matches none of the transfers.
+var ErrResumeHashNotFound = errors.New("transaction hash to resume from was not found")
+
+// TransfersSinceHash returns the transfers, ordered by execution time, that follow the transfers with the
+// given transaction hash. The transfers with that hash are themselves excluded, as is every transfer before them.
+// The hash is compared ignoring case.
+func TransfersSinceHash(transfers []*Transfer, transactionHash string) ([]*Transfer, error) {
+	sortedTransfers := slices.Clone(transfers)
+	slices.SortStableFunc(sortedTransfers, func(a, b *Transfer) int {
+		return a.ExecutionTime.Compare(b.ExecutionTime)
+	})
+
+	lastIndex := -1
+	for index, xfr := range sortedTransfers {
+		if strings.EqualFold(xfr.TransactionHash, transactionHash) {
+			lastIndex = index
+		}
+	}
+
+	if lastIndex < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrResumeHashNotFound, transactionHash)
+	}
+
+	return sortedTransfers[lastIndex+1:], nil
+}
@@ -0,0 +1,65 @@
+package transaction_test
+
+import (
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TransfersSinceHash", func() {
+	var first, second, secondSibling, third *transaction.Transfer
+	var transfers []*transaction.Transfer
+
+	BeforeEach(func() {
+		start := time.Date(2025, time.December, 10, 11, 53, 23, 0, time.UTC)
+
+		first = &transaction.Transfer{TransactionHash: "0xfirst", ExecutionTime: start}
+		second = &transaction.Transfer{
+			TransactionHash: "0xSECOND",
+			ExecutionTime:   start.Add(time.Hour),
+		}
+		secondSibling = &transaction.Transfer{
+			TransactionHash: "0xsecond",
+			ExecutionTime:   start.Add(time.Hour),
+		}
+		third = &transaction.Transfer{
+			TransactionHash: "0xthird",
+			ExecutionTime:   start.Add(2 * time.Hour),
+		}
+
+		// deliberately out of execution order
+		transfers = []*transaction.Transfer{third, second, first, secondSibling}
+	})
+
+	It("drops every transfer up to and including those with the hash", func() {
+		remaining, err := transaction.TransfersSinceHash(transfers, "0xsecond")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(Equal([]*transaction.Transfer{third}))
+	})
+
+	It("orders the remaining transfers by execution time", func() {
+		remaining, err := transaction.TransfersSinceHash(transfers, "0xFIRST")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(Equal([]*transaction.Transfer{second, secondSibling, third}))
+	})
+
+	It("returns no transfers when resuming from the last hash", func() {
+		remaining, err := transaction.TransfersSinceHash(transfers, "0xthird")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(BeEmpty())
+	})
+
+	It("does not reorder the given transfers", func() {
+		_, err := transaction.TransfersSinceHash(transfers, "0xfirst")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfers).To(Equal([]*transaction.Transfer{third, second, first, secondSibling}))
+	})
+
+	It("rejects a hash that is not found", func() {
+		_, err := transaction.TransfersSinceHash(transfers, "0xmissing")
+		Expect(err).To(MatchError(transaction.ErrResumeHashNotFound))
+		Expect(err).To(MatchError(ContainSubstring("0xmissing")))
+	})
+})
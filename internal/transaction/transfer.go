@@ -1,7 +1,9 @@
 package transaction
 
 import (
+	"fmt"
 	"math/big"
+	"strings"
 	"time"
 )
 
@@ -12,6 +14,9 @@ type Transfer struct {
 	Amount          *big.Int  // the amount of tokens transferred, in the token's base unit
 	ExecutionTime   time.Time // the time the transaction was executed
 	TransactionHash string    // the hash of the transaction, encoded in hex
+	LogIndex        *int      // the index of the transfer's log entry within the transaction; nil if unknown
+	Position        int       // its position in its transaction; see NumberTransfers
+	Failed          bool      // true if the transaction failed and so transferred no value
 }
 
 func (t *Transfer) FormatAmount(decimals int) string {
@@ -35,3 +40,55 @@ func (t *Transfer) FormatAmount(decimals int) string {
 
 	return s
 }
+
+// FormatRoundedAmount formats the amount of the transfer in whole tokens, given the token's number
+// of decimals, rounded half away from zero to the given number of decimal digits (e.g., 2 to match
+// a budget's currency). Unlike FormatAmount, it keeps trailing zeros.
+func (t *Transfer) FormatRoundedAmount(decimals int, digits int) string {
+	if t.Amount == nil {
+		return new(big.Rat).FloatString(digits)
+	}
+
+	//nolint:mnd
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+
+	return new(big.Rat).SetFrac(t.Amount, denom).FloatString(digits)
+}
+
+// ExcludeFailedTransfers returns the given transfers without those belonging to failed transactions.
+func ExcludeFailedTransfers(transfers []*Transfer) []*Transfer {
+	succeeded := make([]*Transfer, 0, len(transfers))
+	for _, xfr := range transfers {
+		if xfr.Failed {
+			continue
+		}
+
+		succeeded = append(succeeded, xfr)
+	}
+
+	return succeeded
+}
+
+// DescribeWithinTransaction describes what distinguishes this transfer from the other transfers
+// that share its transaction hash, such as "log index 3" or "amount 1.5".
+// It returns an empty string if no other transfer in the given list shares the transaction hash.
+func (t *Transfer) DescribeWithinTransaction(transfers []*Transfer, decimals int) string {
+	sharesHash := false
+	for _, other := range transfers {
+		if other != t && strings.EqualFold(other.TransactionHash, t.TransactionHash) {
+			sharesHash = true
+
+			break
+		}
+	}
+
+	if !sharesHash {
+		return ""
+	}
+
+	if t.LogIndex != nil {
+		return fmt.Sprintf("log index %d", *t.LogIndex)
+	}
+
+	return "amount " + t.FormatAmount(decimals)
+}
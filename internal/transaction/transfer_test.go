@@ -25,4 +25,55 @@ var _ = Describe("Transfer", func() {
 			Entry("small fractional part", big.NewInt(1), "0.000001"),
 		)
 	})
+
+	Context("FormatRoundedAmount", func() {
+		DescribeTable("rounded amount formatting", func(
+			amount *big.Int,
+			decimals int,
+			expectedFormatted string,
+		) {
+			tr := &transaction.Transfer{
+				Amount: amount,
+			}
+			Expect(tr.FormatRoundedAmount(decimals, 2)).To(Equal(expectedFormatted))
+		}, Entry("nil amount", nil, 6, "0.00"),
+			Entry("rounded down", big.NewInt(1234567), 6, "1.23"),
+			Entry("rounded up", big.NewInt(1235000), 6, "1.24"),
+			Entry("whole number", big.NewInt(5000000), 6, "5.00"),
+			Entry(
+				"high-precision amount",
+				new(big.Int).SetUint64(12345678901234567890),
+				18,
+				"12.35",
+			),
+			Entry("amount rounding to zero", big.NewInt(4999), 6, "0.00"),
+		)
+	})
+
+	Context("DescribeWithinTransaction", func() {
+		logIndex := func(i int) *int {
+			return &i
+		}
+
+		It("returns an empty description when the hash is not shared", func() {
+			tr := &transaction.Transfer{TransactionHash: "0xabc", Amount: big.NewInt(1000000), LogIndex: logIndex(3)}
+			other := &transaction.Transfer{TransactionHash: "0xdef", Amount: big.NewInt(2000000)}
+
+			Expect(tr.DescribeWithinTransaction([]*transaction.Transfer{tr, other}, 6)).To(BeEmpty())
+		})
+
+		It("describes the log index when the hash is shared", func() {
+			tr := &transaction.Transfer{TransactionHash: "0xabc", Amount: big.NewInt(1000000), LogIndex: logIndex(3)}
+			other := &transaction.Transfer{TransactionHash: "0xABC", Amount: big.NewInt(2000000), LogIndex: logIndex(4)}
+
+			Expect(tr.DescribeWithinTransaction([]*transaction.Transfer{tr, other}, 6)).To(Equal("log index 3"))
+		})
+
+		It("describes the amount when the hash is shared and the log index is unknown", func() {
+			tr := &transaction.Transfer{TransactionHash: "0xabc", Amount: big.NewInt(1500000)}
+			other := &transaction.Transfer{TransactionHash: "0xabc", Amount: big.NewInt(2000000)}
+
+			Expect(tr.DescribeWithinTransaction([]*transaction.Transfer{tr, other}, 6)).To(Equal("amount 1.5"))
+		})
+	})
 })
@@ -0,0 +1,134 @@
+package transaction
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/eth"
+)
+
+// Wallets is the set of wallet addresses whose transfers are synchronized into a single YNAB
+// account. Addresses are compared ignoring case.
+type Wallets struct {
+	addresses []string
+}
+
+// NewWallets creates a set of the given wallet addresses, in the order given.
+// An address given more than once, ignoring case, is kept only once.
+func NewWallets(addresses ...string) *Wallets {
+	wallets := &Wallets{}
+	for _, address := range addresses {
+		if !wallets.Contains(address) {
+			wallets.addresses = append(wallets.addresses, address)
+		}
+	}
+
+	return wallets
+}
+
+// ParseWallets parses a comma-separated list of wallet addresses, e.g., "0xabc,0xdef".
+func ParseWallets(value string) (*Wallets, error) {
+	var addresses []string
+	for address := range strings.SplitSeq(value, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+
+	if len(addresses) == 0 {
+		return nil, errors.New("no wallet addresses given")
+	}
+
+	return NewWallets(addresses...), nil
+}
+
+// Addresses returns the addresses of the wallets, in the order given.
+func (w *Wallets) Addresses() []string {
+	return slices.Clone(w.addresses)
+}
+
+// Len returns the number of wallets.
+func (w *Wallets) Len() int {
+	return len(w.addresses)
+}
+
+// Contains determines whether the given address is that of one of the wallets.
+func (w *Wallets) Contains(address string) bool {
+	return slices.ContainsFunc(w.addresses, func(wallet string) bool {
+		return strings.EqualFold(wallet, address)
+	})
+}
+
+// Format renders the addresses of the wallets in the given format, separated by commas.
+func (w *Wallets) Format(addressFormat eth.AddressFormat) string {
+	formatted := make([]string, 0, len(w.addresses))
+	for _, address := range w.addresses {
+		formatted = append(formatted, addressFormat.Format(address))
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+// Direction determines whether the given transfer was sent from one of the wallets (outbound)
+// or received by one of them (inbound), returning the wallet's address.
+// A transfer from a wallet to itself is outbound.
+// ok is false if the transfer involves none of the wallets, or moves tokens between two
+// different wallets and so does not change the total held across them.
+func (w *Wallets) Direction(xfr *Transfer) (isOutbound bool, wallet string, ok bool) {
+	fromWallet := w.Contains(xfr.FromAddress)
+	toWallet := w.Contains(xfr.ToAddress)
+
+	switch {
+	case fromWallet && toWallet && !strings.EqualFold(xfr.FromAddress, xfr.ToAddress):
+		return false, "", false
+	case fromWallet:
+		return true, xfr.FromAddress, true
+	case toWallet:
+		return false, xfr.ToAddress, true
+	default:
+		return false, "", false
+	}
+}
+
+// MergeTransfers combines the transfers fetched for each of several wallets into one list,
+// in order of execution time; the transfers fetched for a single wallet are returned as-is.
+// A transfer between two of the wallets is fetched for each of them, so a transfer is left out
+// if one with the same transaction hash, log index, addresses and amount was fetched
+// for an earlier wallet. The transfers left out are returned as duplicates, in the order fetched.
+func MergeTransfers(transfersByWallet ...[]*Transfer) (merged []*Transfer, duplicates []*Transfer) {
+	if len(transfersByWallet) == 1 {
+		return transfersByWallet[0], nil
+	}
+
+	seen := make(map[string]bool)
+	for _, transfers := range transfersByWallet {
+		fetched := make(map[string]bool)
+		for _, xfr := range transfers {
+			key := strings.Join([]string{
+				transferKey(xfr.TransactionHash, xfr.LogIndex),
+				strings.ToLower(xfr.FromAddress),
+				strings.ToLower(xfr.ToAddress),
+				xfr.Amount.String(),
+			}, "|")
+			if seen[key] {
+				duplicates = append(duplicates, xfr)
+
+				continue
+			}
+
+			fetched[key] = true
+			merged = append(merged, xfr)
+		}
+
+		for key := range fetched {
+			seen[key] = true
+		}
+	}
+
+	slices.SortStableFunc(merged, func(a, b *Transfer) int {
+		return a.ExecutionTime.Compare(b.ExecutionTime)
+	})
+
+	return merged, duplicates
+}
@@ -0,0 +1,123 @@
+package transaction_test
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/eth"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Wallets", func() {
+	Context("ParseWallets", func() {
+		It("parses a comma-separated list, dropping blanks and repeats", func() {
+			wallets, err := transaction.ParseWallets(" 0xabc, ,0xdef,0xABC ")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(wallets.Addresses()).To(Equal([]string{"0xabc", "0xdef"}))
+			Expect(wallets.Format(eth.AddressFormatUnchanged)).To(Equal("0xabc, 0xdef"))
+		})
+
+		It("rejects a list without any address", func() {
+			_, err := transaction.ParseWallets(" , ")
+			Expect(err).To(MatchError("no wallet addresses given"))
+		})
+	})
+
+	Context("Direction", func() {
+		wallets := transaction.NewWallets("0xabc", "0xdef")
+
+		It("determines the wallet that sent or received a transfer", func() {
+			isOutbound, wallet, ok := wallets.Direction(
+				&transaction.Transfer{FromAddress: "0xDEF", ToAddress: "0xother"},
+			)
+			Expect(ok).To(BeTrue())
+			Expect(isOutbound).To(BeTrue())
+			Expect(wallet).To(Equal("0xDEF"))
+
+			isOutbound, wallet, ok = wallets.Direction(
+				&transaction.Transfer{FromAddress: "0xother", ToAddress: "0xabc"},
+			)
+			Expect(ok).To(BeTrue())
+			Expect(isOutbound).To(BeFalse())
+			Expect(wallet).To(Equal("0xabc"))
+		})
+
+		It("treats a transfer from a wallet to itself as outbound", func() {
+			isOutbound, _, ok := wallets.Direction(
+				&transaction.Transfer{FromAddress: "0xabc", ToAddress: "0xABC"},
+			)
+			Expect(ok).To(BeTrue())
+			Expect(isOutbound).To(BeTrue())
+		})
+
+		It("does not attribute transfers between wallets or involving none of them", func() {
+			_, _, ok := wallets.Direction(
+				&transaction.Transfer{FromAddress: "0xabc", ToAddress: "0xdef"},
+			)
+			Expect(ok).To(BeFalse())
+
+			_, _, ok = wallets.Direction(
+				&transaction.Transfer{FromAddress: "0xother", ToAddress: "0xanother"},
+			)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("MergeTransfers", func() {
+		date := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+		It("merges the transfers of each wallet in order, keeping each transfer once", func() {
+			logIndex := 1
+			between := &transaction.Transfer{
+				FromAddress:     "0xabc",
+				ToAddress:       "0xdef",
+				Amount:          big.NewInt(100),
+				ExecutionTime:   date.Add(time.Hour),
+				TransactionHash: "0xhash2",
+				LogIndex:        &logIndex,
+			}
+			betweenAgain := *between
+			first := &transaction.Transfer{
+				FromAddress:     "0xother",
+				ToAddress:       "0xabc",
+				Amount:          big.NewInt(5),
+				ExecutionTime:   date,
+				TransactionHash: "0xhash1",
+			}
+			last := &transaction.Transfer{
+				FromAddress:     "0xdef",
+				ToAddress:       "0xother",
+				Amount:          big.NewInt(7),
+				ExecutionTime:   date.Add(2 * time.Hour),
+				TransactionHash: "0xhash3",
+			}
+
+			merged, duplicates := transaction.MergeTransfers(
+				[]*transaction.Transfer{between, first},
+				[]*transaction.Transfer{last, &betweenAgain},
+			)
+			Expect(merged).To(Equal([]*transaction.Transfer{first, between, last}))
+			Expect(duplicates).To(HaveExactElements(BeIdenticalTo(&betweenAgain)))
+		})
+
+		It("keeps repeated transfers fetched for the same wallet", func() {
+			transfer := &transaction.Transfer{
+				FromAddress:     "0xother",
+				ToAddress:       "0xabc",
+				Amount:          big.NewInt(5),
+				ExecutionTime:   date,
+				TransactionHash: "0xhash1",
+			}
+			repeat := *transfer
+
+			merged, duplicates := transaction.MergeTransfers(
+				[]*transaction.Transfer{transfer, &repeat},
+				nil,
+			)
+			Expect(merged).To(HaveLen(2))
+			Expect(duplicates).To(BeEmpty())
+		})
+	})
+})
@@ -40,7 +40,7 @@ func GetAccounts(
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ynab API returned status %d", resp.StatusCode)
+		return nil, unexpectedStatusError(resp.StatusCode)
 	}
 
 	var envelope struct {
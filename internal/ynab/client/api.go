@@ -1,7 +1,27 @@
 package client
 
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
 const (
 	apiURL = "https://api.ynab.com/v1/"
 
 	transactionClearedStatusUncleared = "uncleared"
 )
+
+// ErrUnauthorized is returned when the YNAB API rejects the access token,
+// e.g., because it is invalid or expired.
+var ErrUnauthorized = errors.New("YNAB access token is invalid or expired")
+
+// unexpectedStatusError describes a response with an unexpected status code,
+// wrapping ErrUnauthorized if the access token was rejected.
+func unexpectedStatusError(statusCode int) error {
+	if statusCode == http.StatusUnauthorized {
+		return fmt.Errorf("%w (status %d)", ErrUnauthorized, statusCode)
+	}
+
+	return fmt.Errorf("ynab API returned status %d", statusCode)
+}
@@ -6,13 +6,48 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 
 	ctshttp "github.com/jrh3k5/cryptonabber-txn-sync/internal/http"
 )
 
 type Budget struct {
-	ID   string
-	Name string
+	ID           string
+	Name         string
+	CurrencyCode string // the ISO 4217 code of the budget's currency, e.g., "USD"; empty if unknown
+	// the number of decimal digits shown in amounts of the budget's currency, e.g., 2; nil if unknown
+	DecimalDigits *int
+}
+
+// VerifyCurrency ensures that transfers valued in the given expected currency can safely be synchronized
+// into this budget. If the budget's currency differs from the expected currency, the user must have
+// explicitly confirmed the budget's currency by providing its ISO code.
+func (b *Budget) VerifyCurrency(expectedCurrencyCode string, confirmedCurrencyCode string) error {
+	if b.CurrencyCode == "" || strings.EqualFold(b.CurrencyCode, expectedCurrencyCode) {
+		return nil
+	}
+
+	if strings.EqualFold(b.CurrencyCode, confirmedCurrencyCode) {
+		return nil
+	}
+
+	if confirmedCurrencyCode != "" {
+		return fmt.Errorf(
+			"budget '%s' uses currency %s, but %s was confirmed; rerun with --confirm-currency=%s to synchronize into this budget anyway",
+			b.Name,
+			b.CurrencyCode,
+			strings.ToUpper(confirmedCurrencyCode),
+			b.CurrencyCode,
+		)
+	}
+
+	return fmt.Errorf(
+		"budget '%s' uses currency %s rather than %s, so transfer amounts would be recorded incorrectly; rerun with --confirm-currency=%s to synchronize into this budget anyway",
+		b.Name,
+		b.CurrencyCode,
+		expectedCurrencyCode,
+		b.CurrencyCode,
+	)
 }
 
 func GetBudgets(ctx context.Context, client ctshttp.Doer, accessToken string) ([]*Budget, error) {
@@ -35,14 +70,18 @@ func GetBudgets(ctx context.Context, client ctshttp.Doer, accessToken string) ([
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ynab API returned status %d", resp.StatusCode)
+		return nil, unexpectedStatusError(resp.StatusCode)
 	}
 
 	var envelope struct {
 		Data struct {
 			Budgets []struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
+				ID             string `json:"id"`
+				Name           string `json:"name"`
+				CurrencyFormat *struct {
+					ISOCode       string `json:"iso_code"`
+					DecimalDigits *int   `json:"decimal_digits"`
+				} `json:"currency_format"`
 			} `json:"budgets"`
 		} `json:"data"`
 	}
@@ -53,7 +92,13 @@ func GetBudgets(ctx context.Context, client ctshttp.Doer, accessToken string) ([
 
 	out := make([]*Budget, 0, len(envelope.Data.Budgets))
 	for _, b := range envelope.Data.Budgets {
-		out = append(out, &Budget{ID: b.ID, Name: b.Name})
+		budget := &Budget{ID: b.ID, Name: b.Name}
+		if b.CurrencyFormat != nil {
+			budget.CurrencyCode = b.CurrencyFormat.ISOCode
+			budget.DecimalDigits = b.CurrencyFormat.DecimalDigits
+		}
+
+		out = append(out, budget)
 	}
 
 	return out, nil
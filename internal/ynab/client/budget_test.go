@@ -12,7 +12,7 @@ import (
 
 var _ = Describe("GetBudgets", func() {
 	It("returns parsed budgets and sends Authorization header", func() {
-		respBody := `{"data":{"budgets":[{"id":"b1","name":"Main Budget"}]}}`
+		respBody := `{"data":{"budgets":[{"id":"b1","name":"Main Budget","currency_format":{"iso_code":"USD","decimal_digits":2}}]}}`
 
 		httpmock.RegisterResponder(
 			"GET",
@@ -33,6 +33,8 @@ var _ = Describe("GetBudgets", func() {
 		Expect(budgets).To(HaveLen(1))
 		Expect(budgets[0].ID).To(Equal("b1"))
 		Expect(budgets[0].Name).To(Equal("Main Budget"))
+		Expect(budgets[0].CurrencyCode).To(Equal("USD"))
+		Expect(budgets[0].DecimalDigits).To(HaveValue(Equal(2)))
 	})
 
 	It("returns an error on non-200 response", func() {
@@ -45,4 +47,50 @@ var _ = Describe("GetBudgets", func() {
 		_, err := clientpkg.GetBudgets(context.Background(), http.DefaultClient, "tokengoeshere")
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("reports a rejected access token", func() {
+		httpmock.RegisterResponder(
+			"GET",
+			"https://api.ynab.com/v1/budgets",
+			httpmock.NewStringResponder(http.StatusUnauthorized, ""),
+		)
+
+		_, err := clientpkg.GetBudgets(context.Background(), http.DefaultClient, "tokengoeshere")
+		Expect(err).To(MatchError(clientpkg.ErrUnauthorized))
+		Expect(err).To(MatchError(ContainSubstring("YNAB access token is invalid or expired")))
+	})
+})
+
+var _ = Describe("Budget", func() {
+	Context("VerifyCurrency", func() {
+		DescribeTable("currency verification",
+			func(budgetCurrency string, confirmedCurrency string, expectedError string) {
+				budget := &clientpkg.Budget{
+					ID:           "b1",
+					Name:         "Main Budget",
+					CurrencyCode: budgetCurrency,
+				}
+
+				err := budget.VerifyCurrency("USD", confirmedCurrency)
+				if expectedError == "" {
+					Expect(err).ToNot(HaveOccurred())
+
+					return
+				}
+
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			},
+			Entry("matching currency", "USD", "", ""),
+			Entry("matching currency in a different case", "usd", "", ""),
+			Entry("unknown currency", "", "", ""),
+			Entry("mismatching currency without confirmation", "EUR", "", "--confirm-currency=EUR"),
+			Entry("mismatching currency with confirmation", "EUR", "eur", ""),
+			Entry(
+				"mismatching currency with a wrong confirmation",
+				"EUR",
+				"GBP",
+				"but GBP was confirmed",
+			),
+		)
+	})
 })
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	ctshttp "github.com/jrh3k5/cryptonabber-txn-sync/internal/http"
+)
+
+// Category is a YNAB category that transactions can be assigned to.
+type Category struct {
+	ID        string
+	Name      string
+	GroupName string // the name of the category group containing the category
+}
+
+// GetCategories fetches the categories of the given budget that are neither hidden nor deleted.
+func GetCategories(
+	ctx context.Context,
+	client ctshttp.Doer,
+	accessToken string,
+	budgetID string,
+) ([]*Category, error) {
+	requestPath, err := url.JoinPath(apiURL, "budgets", budgetID, "categories")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request path for fetching categories: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for fetching categories: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request for fetching categories: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, unexpectedStatusError(resp.StatusCode)
+	}
+
+	var envelope struct {
+		Data struct {
+			CategoryGroups []struct {
+				Name       string `json:"name"`
+				Hidden     bool   `json:"hidden"`
+				Deleted    bool   `json:"deleted"`
+				Categories []struct {
+					ID      string `json:"id"`
+					Name    string `json:"name"`
+					Hidden  bool   `json:"hidden"`
+					Deleted bool   `json:"deleted"`
+				} `json:"categories"`
+			} `json:"category_groups"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode categories response: %w", err)
+	}
+
+	var out []*Category
+	for _, group := range envelope.Data.CategoryGroups {
+		if group.Hidden || group.Deleted {
+			continue
+		}
+
+		for _, c := range group.Categories {
+			if c.Hidden || c.Deleted {
+				continue
+			}
+
+			out = append(out, &Category{ID: c.ID, Name: c.Name, GroupName: group.Name})
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jarcoal/httpmock"
+	clientpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetCategories", func() {
+	It("returns the visible categories and sends Authorization header", func() {
+		respBody := `{"data":{"category_groups":[` +
+			`{"name":"Bills","hidden":false,"deleted":false,"categories":[` +
+			`{"id":"c1","name":"Rent","hidden":false,"deleted":false},` +
+			`{"id":"c2","name":"Old Phone","hidden":true,"deleted":false}]},` +
+			`{"name":"Archived","hidden":true,"deleted":false,"categories":[` +
+			`{"id":"c3","name":"Gym","hidden":false,"deleted":false}]}]}}`
+
+		httpmock.RegisterResponder(
+			"GET",
+			"https://api.ynab.com/v1/budgets/budget1/categories",
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("Authorization")).To(Equal("Bearer tokengoeshere"))
+
+				return httpmock.NewStringResponse(http.StatusOK, respBody), nil
+			},
+		)
+
+		categories, err := clientpkg.GetCategories(
+			context.Background(),
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(categories).To(Equal([]*clientpkg.Category{
+			{ID: "c1", Name: "Rent", GroupName: "Bills"},
+		}))
+	})
+
+	It("returns an error on non-200 response", func() {
+		httpmock.RegisterResponder(
+			"GET",
+			"https://api.ynab.com/v1/budgets/budget1/categories",
+			httpmock.NewStringResponder(http.StatusInternalServerError, ""),
+		)
+
+		_, err := clientpkg.GetCategories(
+			context.Background(),
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+		)
+		Expect(err).To(HaveOccurred())
+	})
+})
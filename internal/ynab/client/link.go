@@ -0,0 +1,12 @@
+package client
+
+import "net/url"
+
+// webURL is the base URL of the YNAB web application.
+const webURL = "https://app.ynab.com/"
+
+// TransactionURL returns a link that opens the transaction with the given ID
+// of the given budget in the YNAB web application.
+func TransactionURL(budgetID string, transactionID string) string {
+	return webURL + url.PathEscape(budgetID) + "/transactions/" + url.PathEscape(transactionID)
+}
@@ -0,0 +1,21 @@
+package client_test
+
+import (
+	clientpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TransactionURL", func() {
+	It("links to the transaction within its budget", func() {
+		Expect(clientpkg.TransactionURL("budget1", "tx1")).To(
+			Equal("https://app.ynab.com/budget1/transactions/tx1"),
+		)
+	})
+
+	It("escapes the budget and transaction IDs", func() {
+		Expect(clientpkg.TransactionURL("budget/1", "tx 1")).To(
+			Equal("https://app.ynab.com/budget%2F1/transactions/tx%201"),
+		)
+	})
+})
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FindAccountsByName returns the accounts with the given name. If no account has exactly that
+// name, the accounts whose names match it loosely, as determined by NamesMatch, are returned.
+func FindAccountsByName(accounts []*Account, name string) []*Account {
+	return findByName(accounts, name, func(account *Account) string { return account.Name })
+}
+
+// FindBudgetsByName returns the budgets with the given name. If no budget has exactly that
+// name, the budgets whose names match it loosely, as determined by NamesMatch, are returned.
+func FindBudgetsByName(budgets []*Budget, name string) []*Budget {
+	return findByName(budgets, name, func(budget *Budget) string { return budget.Name })
+}
+
+// NamesMatch determines whether the given names of YNAB budgets or accounts are the same
+// ignoring case, whitespace at either end or repeated within them, and emoji and other symbols,
+// so that, e.g., "crypto wallet" matches " 💰 Crypto  Wallet".
+// A name made up only of such characters matches nothing.
+func NamesMatch(a string, b string) bool {
+	normalizedA := normalizeName(a)
+
+	return normalizedA != "" && normalizedA == normalizeName(b)
+}
+
+func findByName[T any](items []T, name string, nameOf func(T) string) []T {
+	var exact []T
+
+	var loose []T
+
+	for _, item := range items {
+		switch {
+		case nameOf(item) == name:
+			exact = append(exact, item)
+		case NamesMatch(nameOf(item), name):
+			loose = append(loose, item)
+		}
+	}
+
+	if len(exact) > 0 {
+		return exact
+	}
+
+	return loose
+}
+
+// normalizeName reduces the given name to what NamesMatch compares.
+func normalizeName(name string) string {
+	withoutSymbols := strings.Map(func(r rune) rune {
+		// emoji are symbols, possibly with a variation selector or joined by a zero-width joiner
+		if unicode.Is(unicode.So, r) || unicode.Is(unicode.Sk, r) ||
+			unicode.Is(unicode.Variation_Selector, r) || r == '\u200d' {
+			return -1
+		}
+
+		return unicode.ToLower(r)
+	}, name)
+
+	return strings.Join(strings.Fields(withoutSymbols), " ")
+}
@@ -0,0 +1,80 @@
+package client_test
+
+import (
+	clientpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NamesMatch", func() {
+	DescribeTable("compares names loosely", func(a string, b string, expected bool) {
+		Expect(clientpkg.NamesMatch(a, b)).To(Equal(expected))
+	},
+		Entry("identical names", "Crypto", "Crypto", true),
+		Entry("names differing in case", "crypto wallet", "Crypto Wallet", true),
+		Entry("names differing in whitespace", " Crypto  Wallet ", "Crypto Wallet", true),
+		Entry("names differing in emoji", "💰 Crypto", "Crypto", true),
+		Entry("emoji with a variation selector", "Crypto ❤️", "crypto", true),
+		Entry("different names", "Crypto", "Checking", false),
+		Entry("names made up only of emoji", "💰", "💰", false),
+	)
+})
+
+var _ = Describe("FindAccountsByName", func() {
+	It("finds an account by a trimmed, case-insensitive name", func() {
+		accounts := []*clientpkg.Account{
+			{ID: "a1", Name: "Checking"},
+			{ID: "a2", Name: "💰 USDC Wallet"},
+		}
+
+		Expect(clientpkg.FindAccountsByName(accounts, " usdc wallet ")).To(Equal(accounts[1:]))
+	})
+
+	It("prefers an account with exactly the given name", func() {
+		accounts := []*clientpkg.Account{
+			{ID: "a1", Name: "crypto"},
+			{ID: "a2", Name: "Crypto"},
+		}
+
+		Expect(clientpkg.FindAccountsByName(accounts, "Crypto")).To(Equal(accounts[1:]))
+	})
+
+	It("returns every account matching an ambiguous name", func() {
+		accounts := []*clientpkg.Account{
+			{ID: "a1", Name: "Crypto"},
+			{ID: "a2", Name: "Checking"},
+			{ID: "a3", Name: "🪙 crypto"},
+		}
+
+		Expect(clientpkg.FindAccountsByName(accounts, "CRYPTO")).To(Equal([]*clientpkg.Account{
+			accounts[0],
+			accounts[2],
+		}))
+	})
+
+	It("returns nothing if no account matches", func() {
+		accounts := []*clientpkg.Account{{ID: "a1", Name: "Checking"}}
+
+		Expect(clientpkg.FindAccountsByName(accounts, "Savings")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("FindBudgetsByName", func() {
+	It("finds a budget by a trimmed, case-insensitive name", func() {
+		budgets := []*clientpkg.Budget{
+			{ID: "b1", Name: "Household"},
+			{ID: "b2", Name: "Side Business"},
+		}
+
+		Expect(clientpkg.FindBudgetsByName(budgets, "side business ")).To(Equal(budgets[1:]))
+	})
+
+	It("returns every budget matching an ambiguous name", func() {
+		budgets := []*clientpkg.Budget{
+			{ID: "b1", Name: "Household"},
+			{ID: "b2", Name: "household"},
+		}
+
+		Expect(clientpkg.FindBudgetsByName(budgets, "HOUSEHOLD")).To(Equal(budgets))
+	})
+})
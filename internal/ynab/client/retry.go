@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	ctshttp "github.com/jrh3k5/cryptonabber-txn-sync/internal/http"
+)
+
+const (
+	// DefaultMaxRetries is the default number of times a rate-limited or failed request
+	// is retried.
+	DefaultMaxRetries = 3
+
+	defaultRetryBaseDelay = time.Second
+
+	// maxRetryAfter is the longest wait asked for by a Retry-After header that is honored;
+	// a request asked to wait longer fails instead.
+	maxRetryAfter = time.Minute
+)
+
+// importIDsKey marks the context of a request that creates only transactions with import IDs.
+type importIDsKey struct{}
+
+// withImportIDs marks the given context as that of a request that creates only transactions
+// with import IDs. YNAB does not create a transaction whose import ID is already used,
+// so such a request can be retried after a server error without creating any transaction twice.
+func withImportIDs(ctx context.Context) context.Context {
+	return context.WithValue(ctx, importIDsKey{}, true)
+}
+
+// RetryingDoer is a Doer that retries requests the YNAB API rejected for exceeding its rate limit
+// (status 429) or failed to serve (status 5xx).
+// It waits for as long as a response's Retry-After header asks, up to a minute; otherwise, it backs
+// off exponentially, doubling the wait after each attempt.
+// A POST or PATCH request that failed on the server may have taken effect, so it is retried only
+// if it creates transactions with import IDs; a rate-limited request is always retried.
+type RetryingDoer struct {
+	delegate   ctshttp.Doer
+	maxRetries int
+	baseDelay  time.Duration
+}
+
+var _ ctshttp.Doer = (*RetryingDoer)(nil)
+
+// RetryOption configures a RetryingDoer.
+type RetryOption func(*RetryingDoer)
+
+// WithMaxRetries sets the number of times a request is retried before its response is returned
+// as-is. Zero disables retries.
+func WithMaxRetries(maxRetries int) RetryOption {
+	return func(doer *RetryingDoer) {
+		doer.maxRetries = maxRetries
+	}
+}
+
+// WithRetryBaseDelay sets the wait before the first retry of a response
+// without a Retry-After header.
+func WithRetryBaseDelay(baseDelay time.Duration) RetryOption {
+	return func(doer *RetryingDoer) {
+		doer.baseDelay = baseDelay
+	}
+}
+
+// NewRetryingDoer returns a Doer that sends requests through the given Doer,
+// retrying those that were rate-limited or failed on the server.
+func NewRetryingDoer(delegate ctshttp.Doer, opts ...RetryOption) *RetryingDoer {
+	doer := &RetryingDoer{
+		delegate:   delegate,
+		maxRetries: DefaultMaxRetries,
+		baseDelay:  defaultRetryBaseDelay,
+	}
+
+	for _, opt := range opts {
+		opt(doer)
+	}
+
+	return doer
+}
+
+// Do sends the given request, retrying it while the response is retryable and retries remain.
+// A request whose body cannot be replayed is sent only once.
+func (r *RetryingDoer) Do(req *http.Request) (*http.Response, error) {
+	delay := r.baseDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := r.delegate.Do(req)
+		if err != nil || !isRetryable(req, resp.StatusCode) || attempt >= r.maxRetries {
+			return resp, err
+		}
+
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		_ = resp.Body.Close()
+
+		wait := delay
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if retryAfter > maxRetryAfter {
+				return nil, fmt.Errorf(
+					"%s %s was asked to retry after %s, longer than the %s allowed (status %d)",
+					req.Method,
+					req.URL.Path,
+					retryAfter,
+					maxRetryAfter,
+					resp.StatusCode,
+				)
+			}
+
+			wait = retryAfter
+		}
+
+		slog.WarnContext(
+			req.Context(),
+			fmt.Sprintf(
+				"%s %s failed with status %d; retrying in %s (retry %d of %d)",
+				req.Method,
+				req.URL.Path,
+				resp.StatusCode,
+				wait,
+				attempt+1,
+				r.maxRetries,
+			),
+		)
+
+		if err := sleepForRequest(req, wait); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to replay request body for retry: %w", err)
+			}
+
+			req.Body = body
+		}
+
+		delay *= 2
+	}
+}
+
+// isRetryable determines whether the given request can be retried after receiving a response
+// with the given status.
+func isRetryable(req *http.Request, statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if statusCode < http.StatusInternalServerError {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodPost, http.MethodPatch:
+		hasImportIDs, _ := req.Context().Value(importIDsKey{}).(bool)
+
+		return hasImportIDs
+	default:
+		return true
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either as a number of seconds or as a date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if retryAt, err := http.ParseTime(value); err == nil {
+		return max(time.Until(retryAt), 0), true
+	}
+
+	return 0, false
+}
+
+// sleepForRequest waits for the given delay, stopping early if the request's context is done.
+func sleepForRequest(req *http.Request, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return fmt.Errorf("interrupted while waiting to retry request: %w", req.Context().Err())
+	}
+}
@@ -0,0 +1,266 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	clientpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RetryingDoer", func() {
+	const (
+		transactionsURL       = "https://api.ynab.com/v1/budgets/budget1/accounts/acct1/transactions"
+		createTransactionsURL = "https://api.ynab.com/v1/budgets/budget1/transactions"
+	)
+
+	var ctx context.Context
+	var doer *clientpkg.RetryingDoer
+
+	rateLimited := func() *http.Response {
+		resp := httpmock.NewStringResponse(http.StatusTooManyRequests, `{"error":{}}`)
+		resp.Header.Set("Retry-After", "0")
+
+		return resp
+	}
+
+	getTransactions := func() ([]*clientpkg.Transaction, error) {
+		return clientpkg.GetTransactions(
+			ctx,
+			doer,
+			"tokengoeshere",
+			"budget1",
+			"acct1",
+			time.Time{},
+		)
+	}
+
+	BeforeEach(func() {
+		httpmock.ZeroCallCounters()
+
+		ctx = context.Background()
+		doer = clientpkg.NewRetryingDoer(
+			http.DefaultClient,
+			clientpkg.WithRetryBaseDelay(time.Millisecond),
+		)
+	})
+
+	It("retries a rate-limited request", func() {
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			httpmock.ResponderFromMultipleResponses([]*http.Response{
+				rateLimited(),
+				httpmock.NewStringResponse(
+					http.StatusOK,
+					`{"data":{"transactions":[{"id":"tx1","amount":1000,"date":"2025-12-01"}]}}`,
+				),
+			}),
+		)
+
+		txns, err := getTransactions()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(txns).To(HaveLen(1))
+		Expect(httpmock.GetCallCountInfo()["GET "+transactionsURL]).To(Equal(2))
+	})
+
+	It("backs off when a server error gives no Retry-After header", func() {
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			httpmock.ResponderFromMultipleResponses([]*http.Response{
+				httpmock.NewStringResponse(http.StatusBadGateway, ""),
+				httpmock.NewStringResponse(http.StatusServiceUnavailable, ""),
+				httpmock.NewStringResponse(http.StatusOK, `{"data":{"transactions":[]}}`),
+			}),
+		)
+
+		_, err := getTransactions()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(httpmock.GetCallCountInfo()["GET "+transactionsURL]).To(Equal(3))
+	})
+
+	It("gives up once the retries are exhausted", func() {
+		doer = clientpkg.NewRetryingDoer(
+			http.DefaultClient,
+			clientpkg.WithMaxRetries(2),
+			clientpkg.WithRetryBaseDelay(time.Millisecond),
+		)
+
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			func(*http.Request) (*http.Response, error) {
+				return rateLimited(), nil
+			},
+		)
+
+		_, err := getTransactions()
+		Expect(err).To(MatchError(ContainSubstring("status 429")))
+		Expect(httpmock.GetCallCountInfo()["GET "+transactionsURL]).To(Equal(3))
+	})
+
+	It("does not retry other client errors", func() {
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			httpmock.NewStringResponder(http.StatusNotFound, ""),
+		)
+
+		_, err := getTransactions()
+		Expect(err).To(HaveOccurred())
+		Expect(httpmock.GetCallCountInfo()["GET "+transactionsURL]).To(Equal(1))
+	})
+
+	It("resends the body of a retried request", func() {
+		var bodies []string
+		httpmock.RegisterResponder(
+			"POST",
+			createTransactionsURL,
+			func(req *http.Request) (*http.Response, error) {
+				body, err := io.ReadAll(req.Body)
+				Expect(err).ToNot(HaveOccurred())
+				bodies = append(bodies, string(body))
+
+				if len(bodies) == 1 {
+					return rateLimited(), nil
+				}
+
+				return httpmock.NewStringResponse(
+					http.StatusCreated,
+					`{"data":{"transaction":{"id":"tx-created","date":"2025-12-24","amount":5000}}}`,
+				), nil
+			},
+		)
+
+		txn, err := clientpkg.CreateTransaction(
+			ctx,
+			doer,
+			"tokengoeshere",
+			"budget1",
+			clientpkg.CreateTransactionRequest{
+				AccountID: "acct1",
+				Date:      time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC),
+				Amount:    5000,
+			},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(txn.ID).To(Equal("tx-created"))
+		Expect(bodies).To(HaveLen(2))
+		Expect(bodies[1]).To(Equal(bodies[0]))
+		Expect(bodies[0]).To(ContainSubstring(`"amount":5000`))
+	})
+
+	It("logs each retry with its delay", func() {
+		logOutput := &bytes.Buffer{}
+		defaultLogger := slog.Default()
+		slog.SetDefault(slog.New(slog.NewTextHandler(logOutput, nil)))
+		DeferCleanup(func() {
+			slog.SetDefault(defaultLogger)
+		})
+
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			httpmock.ResponderFromMultipleResponses([]*http.Response{
+				httpmock.NewStringResponse(http.StatusBadGateway, ""),
+				httpmock.NewStringResponse(http.StatusOK, `{"data":{"transactions":[]}}`),
+			}),
+		)
+
+		_, err := getTransactions()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(logOutput.String()).To(ContainSubstring(
+			"GET /v1/budgets/budget1/accounts/acct1/transactions failed with status 502; " +
+				"retrying in 1ms (retry 1 of 3)",
+		))
+	})
+
+	It("fails rather than wait longer than a minute for a Retry-After", func() {
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			func(*http.Request) (*http.Response, error) {
+				resp := httpmock.NewStringResponse(http.StatusTooManyRequests, `{"error":{}}`)
+				resp.Header.Set("Retry-After", "120")
+
+				return resp, nil
+			},
+		)
+
+		_, err := getTransactions()
+		Expect(err).To(MatchError(ContainSubstring("asked to retry after 2m0s")))
+		Expect(httpmock.GetCallCountInfo()["GET "+transactionsURL]).To(Equal(1))
+	})
+
+	Context("when a POST request fails on the server", func() {
+		createTransaction := func(importID *string) error {
+			_, err := clientpkg.CreateTransaction(
+				ctx,
+				doer,
+				"tokengoeshere",
+				"budget1",
+				clientpkg.CreateTransactionRequest{
+					AccountID: "acct1",
+					Date:      time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC),
+					Amount:    5000,
+					ImportID:  importID,
+				},
+			)
+
+			return err
+		}
+
+		BeforeEach(func() {
+			httpmock.RegisterResponder(
+				"POST",
+				createTransactionsURL,
+				httpmock.ResponderFromMultipleResponses([]*http.Response{
+					httpmock.NewStringResponse(http.StatusBadGateway, ""),
+					httpmock.NewStringResponse(
+						http.StatusCreated,
+						`{"data":{"transaction":`+
+							`{"id":"tx-created","date":"2025-12-24","amount":5000}}}`,
+					),
+				}),
+			)
+		})
+
+		It("does not retry a request creating a transaction without an import ID", func() {
+			Expect(createTransaction(nil)).To(HaveOccurred())
+			Expect(httpmock.GetCallCountInfo()["POST "+createTransactionsURL]).To(Equal(1))
+		})
+
+		It("retries a request creating a transaction with an import ID", func() {
+			importID := "CTS:0xabc"
+			Expect(createTransaction(&importID)).To(Succeed())
+			Expect(httpmock.GetCallCountInfo()["POST "+createTransactionsURL]).To(Equal(2))
+		})
+	})
+
+	It("stops waiting when the request's context is canceled", func() {
+		doer = clientpkg.NewRetryingDoer(
+			http.DefaultClient,
+			clientpkg.WithRetryBaseDelay(time.Hour),
+		)
+
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			httpmock.NewStringResponder(http.StatusInternalServerError, ""),
+		)
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		_, err := getTransactions()
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+})
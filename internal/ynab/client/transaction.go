@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,15 +23,29 @@ type Transaction struct {
 	Date        time.Time
 	Description string
 	Cleared     bool
+	ImportID    string // the import ID of the transaction; empty if it has none
+	Deleted     bool   // true if the transaction was deleted; only reported by delta requests
+}
+
+// TransactionsDelta holds the transactions returned by a delta request, along with the server
+// knowledge that a later request can give to fetch only the transactions changed after this one.
+type TransactionsDelta struct {
+	Transactions    []*Transaction // the transactions that changed, including any that were deleted
+	ServerKnowledge int64          // the server knowledge of the returned transactions
 }
 
 // GetFormattedAmount returns the transaction amount formatted as a string in dollars and cents.
 func (t *Transaction) GetFormattedAmount() string {
-	if t.Amount == 0 {
+	return FormatMilliunits(t.Amount)
+}
+
+// FormatMilliunits formats the given amount of YNAB milliunits as a string in dollars and cents.
+func FormatMilliunits(amount int64) string {
+	if amount == 0 {
 		return "$0.00"
 	}
 
-	toFormat := t.Amount
+	toFormat := amount
 	isNegative := toFormat < 0
 	if isNegative {
 		toFormat = -toFormat
@@ -61,6 +78,29 @@ func GetTransactions(
 	accountID string,
 	sinceDate time.Time,
 ) ([]*Transaction, error) {
+	delta, err := GetTransactionsDelta(ctx, client, accessToken, budgetID, accountID, sinceDate, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(delta.Transactions, func(txn *Transaction) bool {
+		return txn.Deleted
+	}), nil
+}
+
+// GetTransactionsDelta fetches from the YNAB API the transactions of a given budget and account
+// that changed after the given server knowledge, as returned by an earlier request.
+// If lastKnowledge is zero, every transaction is fetched;
+// if sinceDate is non-zero, the `since_date` query parameter will be set.
+func GetTransactionsDelta(
+	ctx context.Context,
+	client ctshttp.Doer,
+	accessToken string,
+	budgetID string,
+	accountID string,
+	sinceDate time.Time,
+	lastKnowledge int64,
+) (*TransactionsDelta, error) {
 	requestPath, err := url.JoinPath(
 		apiURL,
 		"budgets",
@@ -82,9 +122,14 @@ func GetTransactions(
 	q := reqURL.Query()
 	if !sinceDate.IsZero() {
 		q.Set("since_date", sinceDate.Format("2006-01-02"))
-		reqURL.RawQuery = q.Encode()
 	}
 
+	if lastKnowledge != 0 {
+		q.Set("last_knowledge_of_server", strconv.FormatInt(lastKnowledge, 10))
+	}
+
+	reqURL.RawQuery = q.Encode()
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request for fetching transactions: %w", err)
@@ -99,14 +144,14 @@ func GetTransactions(
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ynab API returned status %d", resp.StatusCode)
+		return nil, unexpectedStatusError(resp.StatusCode)
 	}
 
-	// Expected response: { "data": { "transactions": [ ... ] } }
-	return parseTransactionsFromBody(resp.Body)
+	// Expected response: { "data": { "transactions": [ ... ], "server_knowledge": 123 } }
+	return parseTransactionsFromBody(ctx, resp.Body)
 }
 
-func parseTransactionsFromBody(body io.Reader) ([]*Transaction, error) {
+func parseTransactionsFromBody(ctx context.Context, body io.Reader) (*TransactionsDelta, error) {
 	var envelope struct {
 		Data struct {
 			Transactions []struct {
@@ -116,7 +161,10 @@ func parseTransactionsFromBody(body io.Reader) ([]*Transaction, error) {
 				Date      string `json:"date"`
 				Memo      string `json:"memo"`
 				Cleared   string `json:"cleared"`
+				ImportID  string `json:"import_id"`
+				Deleted   bool   `json:"deleted"`
 			} `json:"transactions"`
+			ServerKnowledge int64 `json:"server_knowledge"`
 		} `json:"data"`
 	}
 
@@ -126,13 +174,9 @@ func parseTransactionsFromBody(body io.Reader) ([]*Transaction, error) {
 
 	txns := make([]*Transaction, 0, len(envelope.Data.Transactions))
 	for _, t := range envelope.Data.Transactions {
-		var dt time.Time
-		if t.Date != "" {
-			var err error
-			dt, err = time.Parse("2006-01-02", t.Date)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse transaction date '%s': %w", t.Date, err)
-			}
+		dt, err := parseTransactionDate(ctx, t.ID, t.Date)
+		if err != nil {
+			return nil, err
 		}
 
 		txns = append(txns, &Transaction{
@@ -142,14 +186,46 @@ func parseTransactionsFromBody(body io.Reader) ([]*Transaction, error) {
 			Date:        dt,
 			Description: t.Memo,
 			Cleared:     !strings.EqualFold(t.Cleared, transactionClearedStatusUncleared),
+			ImportID:    t.ImportID,
+			Deleted:     t.Deleted,
 		})
 	}
 
-	return txns, nil
+	return &TransactionsDelta{
+		Transactions:    txns,
+		ServerKnowledge: envelope.Data.ServerKnowledge,
+	}, nil
+}
+
+// parseTransactionDate parses the date of the YNAB transaction with the given ID.
+// Dates are expected as YYYY-MM-DD, but RFC 3339 timestamps are also accepted.
+// An empty date is treated as the zero time.
+func parseTransactionDate(ctx context.Context, transactionID string, date string) (time.Time, error) {
+	if date == "" {
+		slog.DebugContext(
+			ctx,
+			fmt.Sprintf("Transaction %s has no date; treating it as the zero time", transactionID),
+		)
+
+		return time.Time{}, nil
+	}
+
+	if parsed, err := time.Parse(time.DateOnly, date); err == nil {
+		return parsed, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse transaction date '%s': %w", date, err)
+	}
+
+	return parsed, nil
 }
 
 // MarkTransactionClearedAndAppendMemo fetches the transaction, marks it as cleared,
 // and appends the given transaction hash to the memo if not already present.
+// If detail is not empty, it is appended in parentheses after the hash (e.g., to identify
+// one of several transfers within the same transaction).
 func MarkTransactionClearedAndAppendMemo(
 	ctx context.Context,
 	client ctshttp.Doer,
@@ -157,6 +233,56 @@ func MarkTransactionClearedAndAppendMemo(
 	budgetID string,
 	transactionID string,
 	txHash string,
+	detail string,
+) error {
+	return markTransactionCleared(
+		ctx,
+		client,
+		accessToken,
+		budgetID,
+		transactionID,
+		func(existing string) string {
+			return computeMemo(existing, txHash, detail)
+		},
+	)
+}
+
+// MarkTransactionClearedAndAppendHashes fetches the transaction, marks it as cleared,
+// and appends each of the given transaction hashes to the memo if not already present.
+func MarkTransactionClearedAndAppendHashes(
+	ctx context.Context,
+	client ctshttp.Doer,
+	accessToken string,
+	budgetID string,
+	transactionID string,
+	txHashes []string,
+) error {
+	return markTransactionCleared(
+		ctx,
+		client,
+		accessToken,
+		budgetID,
+		transactionID,
+		func(existing string) string {
+			memo := existing
+			for _, txHash := range txHashes {
+				memo = computeMemo(memo, txHash, "")
+			}
+
+			return memo
+		},
+	)
+}
+
+// markTransactionCleared fetches the transaction, marks it as cleared,
+// and replaces its memo with the result of the given function applied to its trimmed existing memo.
+func markTransactionCleared(
+	ctx context.Context,
+	client ctshttp.Doer,
+	accessToken string,
+	budgetID string,
+	transactionID string,
+	memoFn func(existing string) string,
 ) error {
 	reqPath, err := url.JoinPath(apiURL, "budgets", budgetID, "transactions", transactionID)
 	if err != nil {
@@ -168,7 +294,7 @@ func MarkTransactionClearedAndAppendMemo(
 		return err
 	}
 
-	memo := computeMemo(strings.TrimSpace(txn.Memo), txHash)
+	memo := memoFn(strings.TrimSpace(txn.Memo))
 
 	payload := struct {
 		Transaction struct {
@@ -211,7 +337,7 @@ func fetchTransaction(
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ynab API returned status %d", resp.StatusCode)
+		return nil, unexpectedStatusError(resp.StatusCode)
 	}
 
 	var envelope struct {
@@ -227,20 +353,25 @@ func fetchTransaction(
 	return &envelope.Data.Transaction, nil
 }
 
-func computeMemo(existing, txHash string) string {
+func computeMemo(existing, txHash, detail string) string {
 	if txHash == "" {
 		return existing
 	}
 
+	reference := txHash
+	if detail != "" {
+		reference += " (" + detail + ")"
+	}
+
 	if existing == "" {
-		return "Transaction hash: " + txHash
+		return "Transaction hash: " + reference
 	}
 
-	if strings.Contains(existing, txHash) {
+	if strings.Contains(existing, reference) {
 		return existing
 	}
 
-	return existing + "; transaction hash: " + txHash
+	return existing + "; transaction hash: " + reference
 }
 
 func updateTransaction(
@@ -273,7 +404,7 @@ func updateTransaction(
 	defer func() { _ = putResp.Body.Close() }()
 
 	if putResp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ynab API returned status %d on update", putResp.StatusCode)
+		return fmt.Errorf("%w on update", unexpectedStatusError(putResp.StatusCode))
 	}
 
 	return nil
@@ -291,6 +422,41 @@ type CreateTransactionRequest struct {
 	Cleared    *string
 	Approved   *bool
 	FlagColor  *string
+	// ImportID, if set, identifies the transaction to YNAB so that it is not created twice: YNAB
+	// rejects a transaction whose import ID is already used by a transaction in the same account,
+	// even one that was since deleted. An import ID can be at most 36 characters long.
+	ImportID *string
+}
+
+// createTransactionPayload is the representation of a transaction to be created in a request body.
+type createTransactionPayload struct {
+	AccountID  string  `json:"account_id"`
+	Date       string  `json:"date"`
+	Amount     int64   `json:"amount"`
+	PayeeID    *string `json:"payee_id,omitempty"`
+	PayeeName  *string `json:"payee_name,omitempty"`
+	CategoryID *string `json:"category_id,omitempty"`
+	Memo       *string `json:"memo,omitempty"`
+	Cleared    *string `json:"cleared,omitempty"`
+	Approved   *bool   `json:"approved,omitempty"`
+	FlagColor  *string `json:"flag_color,omitempty"`
+	ImportID   *string `json:"import_id,omitempty"`
+}
+
+func newCreateTransactionPayload(req CreateTransactionRequest) createTransactionPayload {
+	return createTransactionPayload{
+		AccountID:  req.AccountID,
+		Date:       req.Date.Format("2006-01-02"),
+		Amount:     req.Amount,
+		PayeeID:    req.PayeeID,
+		PayeeName:  req.PayeeName,
+		CategoryID: req.CategoryID,
+		Memo:       req.Memo,
+		Cleared:    req.Cleared,
+		Approved:   req.Approved,
+		FlagColor:  req.FlagColor,
+		ImportID:   req.ImportID,
+	}
 }
 
 // CreateTransaction creates a new transaction in YNAB.
@@ -310,36 +476,20 @@ func CreateTransaction(
 
 	// Build the request payload
 	payload := struct {
-		Transaction struct {
-			AccountID  string  `json:"account_id"`
-			Date       string  `json:"date"`
-			Amount     int64   `json:"amount"`
-			PayeeID    *string `json:"payee_id,omitempty"`
-			PayeeName  *string `json:"payee_name,omitempty"`
-			CategoryID *string `json:"category_id,omitempty"`
-			Memo       *string `json:"memo,omitempty"`
-			Cleared    *string `json:"cleared,omitempty"`
-			Approved   *bool   `json:"approved,omitempty"`
-			FlagColor  *string `json:"flag_color,omitempty"`
-		} `json:"transaction"`
-	}{}
-
-	payload.Transaction.AccountID = req.AccountID
-	payload.Transaction.Date = req.Date.Format("2006-01-02")
-	payload.Transaction.Amount = req.Amount
-	payload.Transaction.PayeeID = req.PayeeID
-	payload.Transaction.PayeeName = req.PayeeName
-	payload.Transaction.CategoryID = req.CategoryID
-	payload.Transaction.Memo = req.Memo
-	payload.Transaction.Cleared = req.Cleared
-	payload.Transaction.Approved = req.Approved
-	payload.Transaction.FlagColor = req.FlagColor
+		Transaction createTransactionPayload `json:"transaction"`
+	}{
+		Transaction: newCreateTransactionPayload(req),
+	}
 
 	bodyBytes, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal transaction create request: %w", err)
 	}
 
+	if req.ImportID != nil {
+		ctx = withImportIDs(ctx)
+	}
+
 	httpReq, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
@@ -360,7 +510,7 @@ func CreateTransaction(
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("ynab API returned status %d on create", resp.StatusCode)
+		return nil, fmt.Errorf("%w on create", unexpectedStatusError(resp.StatusCode))
 	}
 
 	// Parse the response
@@ -378,6 +528,7 @@ func CreateTransaction(
 				Approved   bool    `json:"approved"`
 				CategoryID *string `json:"category_id"`
 				FlagColor  *string `json:"flag_color"`
+				ImportID   *string `json:"import_id"`
 			} `json:"transaction"`
 		} `json:"data"`
 	}
@@ -387,13 +538,15 @@ func CreateTransaction(
 	}
 
 	t := envelope.Data.Transaction
-	var dt time.Time
-	if t.Date != "" {
-		var err error
-		dt, err = time.Parse("2006-01-02", t.Date)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse transaction date '%s': %w", t.Date, err)
-		}
+
+	var importID string
+	if t.ImportID != nil {
+		importID = *t.ImportID
+	}
+
+	dt, err := parseTransactionDate(ctx, t.ID, t.Date)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Transaction{
@@ -403,5 +556,38 @@ func CreateTransaction(
 		Date:        dt,
 		Description: t.Memo,
 		Cleared:     !strings.EqualFold(t.Cleared, transactionClearedStatusUncleared),
+		ImportID:    importID,
 	}, nil
 }
+
+// DeleteTransaction deletes the transaction with the given ID from the given budget.
+func DeleteTransaction(
+	ctx context.Context,
+	client ctshttp.Doer,
+	accessToken string,
+	budgetID string,
+	transactionID string,
+) error {
+	requestPath, err := url.JoinPath(apiURL, "budgets", budgetID, "transactions", transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to build request path for deleting transaction: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, requestPath, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request for deleting transaction: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request for deleting transaction: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w on delete", unexpectedStatusError(resp.StatusCode))
+	}
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	ctshttp "github.com/jrh3k5/cryptonabber-txn-sync/internal/http"
+)
+
+// statusUpdated is the status with which the YNAB API answers a successful bulk update.
+const statusUpdated = 209
+
+// TransactionUpdate describes the changes to make to an existing transaction in a bulk update.
+// Fields left nil are not changed.
+type TransactionUpdate struct {
+	ID      string  // the ID of the transaction to update
+	Memo    *string // the new memo of the transaction
+	Cleared *string // the new cleared status of the transaction, e.g., "cleared"
+}
+
+// NewClearingUpdate returns an update marking the given transaction as cleared and appending each
+// of the given transaction hashes to its memo if not already present.
+// If detail is not empty, it is appended in parentheses after each hash (e.g., to identify one of
+// several transfers within the same transaction).
+func NewClearingUpdate(txn *Transaction, txHashes []string, detail string) TransactionUpdate {
+	memo := strings.TrimSpace(txn.Description)
+	for _, txHash := range txHashes {
+		memo = computeMemo(memo, txHash, detail)
+	}
+
+	cleared := "cleared"
+
+	return TransactionUpdate{
+		ID:      txn.ID,
+		Memo:    &memo,
+		Cleared: &cleared,
+	}
+}
+
+// UpdateTransactions applies the given updates to transactions of the given budget
+// in a single request, returning the transactions as updated.
+// A transaction that YNAB did not update is absent from the returned transactions.
+func UpdateTransactions(
+	ctx context.Context,
+	client ctshttp.Doer,
+	accessToken string,
+	budgetID string,
+	updates []TransactionUpdate,
+) ([]*Transaction, error) {
+	if len(updates) == 0 {
+		return nil, nil
+	}
+
+	requestPath, err := url.JoinPath(apiURL, "budgets", budgetID, "transactions")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request path for updating transactions: %w", err)
+	}
+
+	type transactionPayload struct {
+		ID      string  `json:"id"`
+		Memo    *string `json:"memo,omitempty"`
+		Cleared *string `json:"cleared,omitempty"`
+	}
+
+	var payload struct {
+		Transactions []transactionPayload `json:"transactions"`
+	}
+
+	for _, update := range updates {
+		payload.Transactions = append(payload.Transactions, transactionPayload{
+			ID:      update.ID,
+			Memo:    update.Memo,
+			Cleared: update.Cleared,
+		})
+	}
+
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transactions update: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPatch,
+		requestPath,
+		strings.NewReader(string(bodyBytes)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for updating transactions: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request for updating transactions: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != statusUpdated {
+		return nil, fmt.Errorf("%w on bulk update", unexpectedStatusError(resp.StatusCode))
+	}
+
+	// Expected response: { "data": { "transaction_ids": [ ... ], "transactions": [ ... ] } }
+	updated, err := parseTransactionsFromBody(ctx, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.Transactions, nil
+}
+
+// CreatedTransactions describes the outcome of creating several transactions in a single request.
+type CreatedTransactions struct {
+	Transactions []*Transaction // the transactions that were created
+	// DuplicateImportIDs are the import IDs of the requested transactions that were not created
+	// because a transaction with the same import ID already exists in the account.
+	DuplicateImportIDs []string
+}
+
+// CreateTransactions creates the given transactions in the given budget in a single request.
+// Required fields of each transaction: AccountID, Date, Amount.
+func CreateTransactions(
+	ctx context.Context,
+	client ctshttp.Doer,
+	accessToken string,
+	budgetID string,
+	reqs []CreateTransactionRequest,
+) (*CreatedTransactions, error) {
+	if len(reqs) == 0 {
+		return &CreatedTransactions{}, nil
+	}
+
+	requestPath, err := url.JoinPath(apiURL, "budgets", budgetID, "transactions")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request path for creating transactions: %w", err)
+	}
+
+	var payload struct {
+		Transactions []createTransactionPayload `json:"transactions"`
+	}
+
+	for _, req := range reqs {
+		payload.Transactions = append(payload.Transactions, newCreateTransactionPayload(req))
+	}
+
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transactions create request: %w", err)
+	}
+
+	if allHaveImportIDs(reqs) {
+		ctx = withImportIDs(ctx)
+	}
+
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		requestPath,
+		strings.NewReader(string(bodyBytes)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request for creating transactions: %w", err)
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+accessToken)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request for creating transactions: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("%w on bulk create", unexpectedStatusError(resp.StatusCode))
+	}
+
+	// Expected response: { "data": { "transactions": [ ... ], "duplicate_import_ids": [ ... ] } }
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read create transactions response: %w", err)
+	}
+
+	created, err := parseTransactionsFromBody(ctx, bytes.NewReader(respBody))
+	if err != nil {
+		return nil, err
+	}
+
+	var duplicatesEnvelope struct {
+		Data struct {
+			DuplicateImportIDs []string `json:"duplicate_import_ids"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(respBody, &duplicatesEnvelope); err != nil {
+		return nil, fmt.Errorf("failed to decode duplicate import IDs: %w", err)
+	}
+
+	return &CreatedTransactions{
+		Transactions:       created.Transactions,
+		DuplicateImportIDs: duplicatesEnvelope.Data.DuplicateImportIDs,
+	}, nil
+}
+
+// allHaveImportIDs determines whether every one of the given transactions has an import ID.
+func allHaveImportIDs(reqs []CreateTransactionRequest) bool {
+	for _, req := range reqs {
+		if req.ImportID == nil {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,239 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	clientpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewClearingUpdate", func() {
+	It("clears the transaction and appends each hash to its memo", func() {
+		update := clientpkg.NewClearingUpdate(
+			&clientpkg.Transaction{ID: "tx1", Description: " Paycheck "},
+			[]string{"0xhash1", "0xhash2"},
+			"",
+		)
+		Expect(update.ID).To(Equal("tx1"))
+		Expect(*update.Cleared).To(Equal("cleared"))
+		Expect(*update.Memo).To(
+			Equal("Paycheck; transaction hash: 0xhash1; transaction hash: 0xhash2"),
+		)
+	})
+
+	It("does not repeat a hash already in the memo and includes the detail", func() {
+		update := clientpkg.NewClearingUpdate(
+			&clientpkg.Transaction{ID: "tx1", Description: "Transaction hash: 0xhash1 (2 of 3)"},
+			[]string{"0xhash1"},
+			"2 of 3",
+		)
+		Expect(*update.Memo).To(Equal("Transaction hash: 0xhash1 (2 of 3)"))
+	})
+})
+
+var _ = Describe("UpdateTransactions", func() {
+	const transactionsURL = "https://api.ynab.com/v1/budgets/budget1/transactions"
+
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("sends every update in one request and parses the updated transactions", func() {
+		var payload map[string][]map[string]any
+		httpmock.RegisterResponder(
+			"PATCH",
+			transactionsURL,
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("Authorization")).To(Equal("Bearer tokengoeshere"))
+				Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+				Expect(json.NewDecoder(req.Body).Decode(&payload)).To(Succeed())
+
+				return httpmock.NewStringResponse(
+					209,
+					`{"data":{"transaction_ids":["tx1","tx2"],"transactions":[`+
+						`{"id":"tx1","amount":1000,"date":"2025-12-01","memo":"a",`+
+						`"cleared":"cleared"},`+
+						`{"id":"tx2","amount":-2000,"date":"2025-12-02","memo":"b",`+
+						`"cleared":"cleared"}`+
+						`],"server_knowledge":12}}`,
+				), nil
+			},
+		)
+
+		memo := "a"
+		cleared := "cleared"
+		updated, err := clientpkg.UpdateTransactions(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			[]clientpkg.TransactionUpdate{
+				{ID: "tx1", Memo: &memo, Cleared: &cleared},
+				{ID: "tx2", Cleared: &cleared},
+			},
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(payload["transactions"]).To(Equal([]map[string]any{
+			{"id": "tx1", "memo": "a", "cleared": "cleared"},
+			{"id": "tx2", "cleared": "cleared"},
+		}))
+
+		Expect(updated).To(HaveLen(2))
+		Expect(updated[0].ID).To(Equal("tx1"))
+		Expect(updated[0].Description).To(Equal("a"))
+		Expect(updated[0].Cleared).To(BeTrue())
+		Expect(updated[1].ID).To(Equal("tx2"))
+		Expect(updated[1].Amount).To(Equal(int64(-2000)))
+	})
+
+	It("sends nothing when there are no updates", func() {
+		httpmock.ZeroCallCounters()
+
+		updated, err := clientpkg.UpdateTransactions(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			nil,
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeEmpty())
+		Expect(httpmock.GetTotalCallCount()).To(BeZero())
+	})
+
+	It("returns an error on an unexpected status", func() {
+		httpmock.RegisterResponder(
+			"PATCH",
+			transactionsURL,
+			httpmock.NewStringResponder(http.StatusBadRequest, `{"error":{}}`),
+		)
+
+		cleared := "cleared"
+		_, err := clientpkg.UpdateTransactions(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			[]clientpkg.TransactionUpdate{{ID: "tx1", Cleared: &cleared}},
+		)
+		Expect(err).To(MatchError(ContainSubstring("status 400 on bulk update")))
+	})
+})
+
+var _ = Describe("CreateTransactions", func() {
+	const transactionsURL = "https://api.ynab.com/v1/budgets/budget1/transactions"
+
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("sends every transaction in one request and reports duplicate import IDs", func() {
+		var payload map[string][]map[string]any
+		httpmock.RegisterResponder(
+			"POST",
+			transactionsURL,
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("Authorization")).To(Equal("Bearer tokengoeshere"))
+				Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+				Expect(json.NewDecoder(req.Body).Decode(&payload)).To(Succeed())
+
+				return httpmock.NewStringResponse(
+					http.StatusCreated,
+					`{"data":{"transaction_ids":["tx1"],"transactions":[`+
+						`{"id":"tx1","amount":1000,"date":"2025-12-01","memo":"a",`+
+						`"cleared":"uncleared","import_id":"import1"}`+
+						`],"duplicate_import_ids":["import2"],"server_knowledge":12}}`,
+				), nil
+			},
+		)
+
+		memo := "a"
+		importID1 := "import1"
+		importID2 := "import2"
+		created, err := clientpkg.CreateTransactions(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			[]clientpkg.CreateTransactionRequest{
+				{
+					AccountID: "acct1",
+					Date:      time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+					Amount:    1000,
+					Memo:      &memo,
+					ImportID:  &importID1,
+				},
+				{
+					AccountID: "acct1",
+					Date:      time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC),
+					Amount:    -2000,
+					ImportID:  &importID2,
+				},
+			},
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(payload["transactions"]).To(Equal([]map[string]any{
+			{
+				"account_id": "acct1",
+				"date":       "2025-12-01",
+				"amount":     float64(1000),
+				"memo":       "a",
+				"import_id":  "import1",
+			},
+			{
+				"account_id": "acct1",
+				"date":       "2025-12-02",
+				"amount":     float64(-2000),
+				"import_id":  "import2",
+			},
+		}))
+
+		Expect(created.Transactions).To(HaveLen(1))
+		Expect(created.Transactions[0].ID).To(Equal("tx1"))
+		Expect(created.Transactions[0].ImportID).To(Equal("import1"))
+		Expect(created.DuplicateImportIDs).To(ConsistOf("import2"))
+	})
+
+	It("sends nothing when there are no transactions", func() {
+		httpmock.ZeroCallCounters()
+
+		created, err := clientpkg.CreateTransactions(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			nil,
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(created.Transactions).To(BeEmpty())
+		Expect(httpmock.GetTotalCallCount()).To(BeZero())
+	})
+
+	It("returns an error on an unexpected status", func() {
+		httpmock.RegisterResponder(
+			"POST",
+			transactionsURL,
+			httpmock.NewStringResponder(http.StatusBadRequest, `{"error":{}}`),
+		)
+
+		_, err := clientpkg.CreateTransactions(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			[]clientpkg.CreateTransactionRequest{{AccountID: "acct1", Amount: 1000}},
+		)
+		Expect(err).To(MatchError(ContainSubstring("status 400 on bulk create")))
+	})
+})
@@ -0,0 +1,241 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	ctshttp "github.com/jrh3k5/cryptonabber-txn-sync/internal/http"
+	"go.yaml.in/yaml/v3"
+)
+
+const transactionCacheDateLayout = "2006-01-02"
+
+// TransactionCache holds previously-fetched YNAB transactions, keyed by budget and account,
+// along with the server knowledge needed to fetch only the transactions that changed since.
+type TransactionCache struct {
+	entries map[string]*cachedTransactions
+}
+
+type cachedTransactions struct {
+	budgetID        string
+	accountID       string
+	sinceDate       time.Time
+	serverKnowledge int64
+	transactions    map[string]*Transaction
+}
+
+// NewTransactionCache creates an empty transaction cache.
+func NewTransactionCache() *TransactionCache {
+	return &TransactionCache{
+		entries: make(map[string]*cachedTransactions),
+	}
+}
+
+// GetTransactions fetches the transactions of the given budget and account dated on or after
+// the given date.
+// If the cache already holds the transactions from that date, only the transactions that changed
+// since they were cached are fetched from the YNAB API; otherwise, all of them are fetched.
+// Either way, the cache is updated with the fetched transactions, and the cached transactions
+// dated before the given date are dropped.
+func (c *TransactionCache) GetTransactions(
+	ctx context.Context,
+	client ctshttp.Doer,
+	accessToken string,
+	budgetID string,
+	accountID string,
+	sinceDate time.Time,
+) ([]*Transaction, error) {
+	key := transactionCacheKey(budgetID, accountID)
+	sinceDay := sinceDate.Format(transactionCacheDateLayout)
+
+	entry, ok := c.entries[key]
+	if !ok || entry.serverKnowledge == 0 ||
+		entry.sinceDate.Format(transactionCacheDateLayout) > sinceDay {
+		entry = &cachedTransactions{
+			budgetID:     budgetID,
+			accountID:    accountID,
+			sinceDate:    sinceDate,
+			transactions: make(map[string]*Transaction),
+		}
+	} else {
+		slog.DebugContext(
+			ctx,
+			fmt.Sprintf(
+				"Fetching transactions changed since server knowledge %d",
+				entry.serverKnowledge,
+			),
+		)
+	}
+
+	delta, err := GetTransactionsDelta(
+		ctx,
+		client,
+		accessToken,
+		budgetID,
+		accountID,
+		entry.sinceDate,
+		entry.serverKnowledge,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, txn := range delta.Transactions {
+		if txn.Deleted {
+			delete(entry.transactions, txn.ID)
+		} else {
+			entry.transactions[txn.ID] = txn
+		}
+	}
+
+	// transactions dated before the requested date are dropped, along with the cache's claim to
+	// hold them, so that the cache does not grow with every run
+	for id, txn := range entry.transactions {
+		if txn.Date.Format(transactionCacheDateLayout) < sinceDay {
+			delete(entry.transactions, id)
+		}
+	}
+
+	entry.sinceDate = sinceDate
+	entry.serverKnowledge = delta.ServerKnowledge
+	c.entries[key] = entry
+
+	txns := make([]*Transaction, 0, len(entry.transactions))
+	for _, txn := range entry.transactions {
+		txns = append(txns, txn)
+	}
+
+	// return the transactions in a stable order
+	slices.SortFunc(txns, compareTransactions)
+
+	return txns, nil
+}
+
+func transactionCacheKey(budgetID string, accountID string) string {
+	return budgetID + ":" + accountID
+}
+
+func compareTransactions(a, b *Transaction) int {
+	if c := a.Date.Compare(b.Date); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a.ID, b.ID)
+}
+
+// TransactionCacheFromYAML reads a TransactionCache from a YAML representation.
+func TransactionCacheFromYAML(reader io.Reader) (*TransactionCache, error) {
+	var ymlCache yamlTransactionCache
+	if err := yaml.NewDecoder(reader).Decode(&ymlCache); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode transaction cache from YAML: %w", err)
+	}
+
+	cache := NewTransactionCache()
+	for _, ymlAccount := range ymlCache.Accounts {
+		entry := &cachedTransactions{
+			budgetID:        ymlAccount.BudgetID,
+			accountID:       ymlAccount.AccountID,
+			sinceDate:       ymlAccount.SinceDate,
+			serverKnowledge: ymlAccount.ServerKnowledge,
+			transactions:    make(map[string]*Transaction, len(ymlAccount.Transactions)),
+		}
+
+		for _, ymlTxn := range ymlAccount.Transactions {
+			entry.transactions[ymlTxn.ID] = &Transaction{
+				ID:          ymlTxn.ID,
+				Payee:       ymlTxn.Payee,
+				Amount:      ymlTxn.Amount,
+				Date:        ymlTxn.Date,
+				Description: ymlTxn.Description,
+				Cleared:     ymlTxn.Cleared,
+				ImportID:    ymlTxn.ImportID,
+			}
+		}
+
+		cache.entries[transactionCacheKey(entry.budgetID, entry.accountID)] = entry
+	}
+
+	return cache, nil
+}
+
+// TransactionCacheToYAML writes a TransactionCache to a YAML representation.
+func TransactionCacheToYAML(cache *TransactionCache, writer io.Writer) error {
+	var ymlCache yamlTransactionCache
+	for _, entry := range cache.entries {
+		ymlAccount := yamlCachedAccount{
+			BudgetID:        entry.budgetID,
+			AccountID:       entry.accountID,
+			SinceDate:       entry.sinceDate,
+			ServerKnowledge: entry.serverKnowledge,
+		}
+
+		txns := make([]*Transaction, 0, len(entry.transactions))
+		for _, txn := range entry.transactions {
+			txns = append(txns, txn)
+		}
+
+		slices.SortFunc(txns, compareTransactions)
+
+		for _, txn := range txns {
+			ymlAccount.Transactions = append(ymlAccount.Transactions, yamlCachedTransaction{
+				ID:          txn.ID,
+				Payee:       txn.Payee,
+				Amount:      txn.Amount,
+				Date:        txn.Date,
+				Description: txn.Description,
+				Cleared:     txn.Cleared,
+				ImportID:    txn.ImportID,
+			})
+		}
+
+		ymlCache.Accounts = append(ymlCache.Accounts, ymlAccount)
+	}
+
+	// write the entries in a stable order
+	slices.SortFunc(ymlCache.Accounts, func(a, b yamlCachedAccount) int {
+		return strings.Compare(
+			transactionCacheKey(a.BudgetID, a.AccountID),
+			transactionCacheKey(b.BudgetID, b.AccountID),
+		)
+	})
+
+	encoder := yaml.NewEncoder(writer)
+	defer func() { _ = encoder.Close() }()
+
+	if err := encoder.Encode(&ymlCache); err != nil {
+		return fmt.Errorf("failed to encode transaction cache to YAML: %w", err)
+	}
+
+	return nil
+}
+
+// yamlTransactionCache is an internal struct for YAML serialization.
+type yamlTransactionCache struct {
+	Accounts []yamlCachedAccount `yaml:"accounts"`
+}
+
+// yamlCachedAccount is an internal struct for YAML serialization.
+type yamlCachedAccount struct {
+	BudgetID        string                  `yaml:"budget_id"`
+	AccountID       string                  `yaml:"account_id"`
+	SinceDate       time.Time               `yaml:"since_date"`
+	ServerKnowledge int64                   `yaml:"server_knowledge"`
+	Transactions    []yamlCachedTransaction `yaml:"transactions,omitempty"`
+}
+
+// yamlCachedTransaction is an internal struct for YAML serialization.
+type yamlCachedTransaction struct {
+	ID          string    `yaml:"id"`
+	Payee       string    `yaml:"payee,omitempty"`
+	Amount      int64     `yaml:"amount"`
+	Date        time.Time `yaml:"date"`
+	Description string    `yaml:"description,omitempty"`
+	Cleared     bool      `yaml:"cleared"`
+	ImportID    string    `yaml:"import_id,omitempty"`
+}
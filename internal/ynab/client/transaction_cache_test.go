@@ -0,0 +1,222 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	clientpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TransactionCache", func() {
+	const transactionsURL = "https://api.ynab.com/v1/budgets/budget1/accounts/acct1/transactions"
+
+	var ctx context.Context
+	var cache *clientpkg.TransactionCache
+	var since time.Time
+
+	getTransactions := func() []*clientpkg.Transaction {
+		txns, err := cache.GetTransactions(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			"acct1",
+			since,
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		return txns
+	}
+
+	transactionIDs := func(txns []*clientpkg.Transaction) []string {
+		ids := make([]string, 0, len(txns))
+		for _, txn := range txns {
+			ids = append(ids, txn.ID)
+		}
+
+		return ids
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cache = clientpkg.NewTransactionCache()
+		since = time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	It("fetches all transactions and then only those that changed", func() {
+		var knowledgeParams []string
+		responses := []string{
+			`{"data":{"transactions":[` +
+				`{"id":"tx1","amount":1000,"date":"2025-12-01","cleared":"uncleared"},` +
+				`{"id":"tx2","amount":2000,"date":"2025-12-02","cleared":"uncleared"}` +
+				`],"server_knowledge":10}}`,
+			`{"data":{"transactions":[` +
+				`{"id":"tx1","amount":1000,"date":"2025-12-01","deleted":true},` +
+				`{"id":"tx2","amount":2500,"date":"2025-12-02","cleared":"cleared"},` +
+				`{"id":"tx3","amount":3000,"date":"2025-12-03","cleared":"uncleared"}` +
+				`],"server_knowledge":12}}`,
+		}
+
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.URL.Query().Get("since_date")).To(Equal("2025-12-01"))
+				knowledgeParams = append(
+					knowledgeParams,
+					req.URL.Query().Get("last_knowledge_of_server"),
+				)
+
+				return httpmock.NewStringResponse(200, responses[len(knowledgeParams)-1]), nil
+			},
+		)
+
+		Expect(transactionIDs(getTransactions())).To(Equal([]string{"tx1", "tx2"}))
+
+		txns := getTransactions()
+		Expect(transactionIDs(txns)).To(Equal([]string{"tx2", "tx3"}))
+		Expect(txns[0].Amount).To(Equal(int64(2500)))
+		Expect(txns[0].Cleared).To(BeTrue())
+
+		Expect(knowledgeParams).To(Equal([]string{"", "10"}))
+	})
+
+	It("fetches all transactions when an earlier date is requested than is cached", func() {
+		var knowledgeParams []string
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			func(req *http.Request) (*http.Response, error) {
+				knowledgeParams = append(
+					knowledgeParams,
+					req.URL.Query().Get("last_knowledge_of_server"),
+				)
+
+				return httpmock.NewStringResponse(
+					200,
+					`{"data":{"transactions":[],"server_knowledge":10}}`,
+				), nil
+			},
+		)
+
+		getTransactions()
+		since = since.AddDate(0, 0, -1)
+		getTransactions()
+
+		Expect(knowledgeParams).To(Equal([]string{"", ""}))
+	})
+
+	It("omits cached transactions from before the requested date", func() {
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			httpmock.NewStringResponder(
+				200,
+				`{"data":{"transactions":[`+
+					`{"id":"tx1","amount":1000,"date":"2025-12-01","cleared":"uncleared"},`+
+					`{"id":"tx2","amount":2000,"date":"2025-12-02","cleared":"uncleared"}`+
+					`],"server_knowledge":10}}`,
+			),
+		)
+
+		getTransactions()
+		since = since.AddDate(0, 0, 1)
+
+		Expect(transactionIDs(getTransactions())).To(Equal([]string{"tx2"}))
+	})
+
+	It("drops cached transactions from before the requested date", func() {
+		var knowledgeParams []string
+		responses := []string{
+			`{"data":{"transactions":[` +
+				`{"id":"tx1","amount":1000,"date":"2025-12-01","cleared":"uncleared"},` +
+				`{"id":"tx2","amount":2000,"date":"2025-12-02","cleared":"uncleared"}` +
+				`],"server_knowledge":10}}`,
+			`{"data":{"transactions":[` +
+				`{"id":"tx3","amount":3000,"date":"2025-12-03","cleared":"uncleared"}` +
+				`],"server_knowledge":12}}`,
+		}
+
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			func(req *http.Request) (*http.Response, error) {
+				knowledgeParams = append(
+					knowledgeParams,
+					req.URL.Query().Get("last_knowledge_of_server"),
+				)
+
+				return httpmock.NewStringResponse(200, responses[len(knowledgeParams)-1]), nil
+			},
+		)
+
+		getTransactions()
+		since = since.AddDate(0, 0, 1)
+		Expect(transactionIDs(getTransactions())).To(Equal([]string{"tx2", "tx3"}))
+		Expect(knowledgeParams).To(Equal([]string{"", "10"}))
+
+		var buf bytes.Buffer
+		Expect(clientpkg.TransactionCacheToYAML(cache, &buf)).To(Succeed())
+		Expect(buf.String()).ToNot(ContainSubstring("tx1"))
+		Expect(buf.String()).To(ContainSubstring("since_date: 2025-12-02T00:00:00Z"))
+	})
+
+	It("round-trips through YAML", func() {
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			httpmock.NewStringResponder(
+				200,
+				`{"data":{"transactions":[`+
+					`{"id":"tx1","payee_name":"John Doe","amount":1000,"date":"2025-12-01",`+
+					`"memo":"test memo","cleared":"uncleared","import_id":"CTS:0xabc"}`+
+					`],"server_knowledge":10}}`,
+			),
+		)
+
+		getTransactions()
+
+		var buf bytes.Buffer
+		Expect(clientpkg.TransactionCacheToYAML(cache, &buf)).To(Succeed())
+
+		var err error
+		cache, err = clientpkg.TransactionCacheFromYAML(&buf)
+		Expect(err).ToNot(HaveOccurred())
+
+		var knowledgeParam string
+		httpmock.RegisterResponder(
+			"GET",
+			transactionsURL,
+			func(req *http.Request) (*http.Response, error) {
+				knowledgeParam = req.URL.Query().Get("last_knowledge_of_server")
+
+				return httpmock.NewStringResponse(
+					200,
+					`{"data":{"transactions":[],"server_knowledge":10}}`,
+				), nil
+			},
+		)
+
+		txns := getTransactions()
+		Expect(knowledgeParam).To(Equal("10"))
+		Expect(txns).To(HaveLen(1))
+		Expect(txns[0].ID).To(Equal("tx1"))
+		Expect(txns[0].Payee).To(Equal("John Doe"))
+		Expect(txns[0].Amount).To(Equal(int64(1000)))
+		Expect(txns[0].Description).To(Equal("test memo"))
+		Expect(txns[0].Cleared).To(BeFalse())
+		Expect(txns[0].ImportID).To(Equal("CTS:0xabc"))
+		Expect(txns[0].Date.Equal(since)).To(BeTrue())
+	})
+
+	It("reads an empty cache", func() {
+		cache, err := clientpkg.TransactionCacheFromYAML(bytes.NewBufferString(""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cache).ToNot(BeNil())
+	})
+})
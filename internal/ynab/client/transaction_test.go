@@ -2,6 +2,7 @@ package client_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"time"
 
@@ -21,7 +22,7 @@ var _ = Describe("GetTransactions", func() {
 	It("returns parsed transactions on success and sets since_date", func() {
 		since := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
 
-		respBody := `{"data":{"transactions":[{"id":"tx1","payee_name":"John Doe","amount":1000,"date":"2025-12-01","memo":"test memo","cleared":"uncleared"}]}}`
+		respBody := `{"data":{"transactions":[{"id":"tx1","payee_name":"John Doe","amount":1000,"date":"2025-12-01","memo":"test memo","cleared":"uncleared","import_id":"CTS:0xabc"}]}}`
 
 		httpmock.RegisterResponder(
 			"GET",
@@ -51,6 +52,7 @@ var _ = Describe("GetTransactions", func() {
 		Expect(txn.Amount).To(Equal(int64(1000)))
 		Expect(txn.Description).To(Equal("test memo"))
 		Expect(txn.Cleared).To(BeFalse())
+		Expect(txn.ImportID).To(Equal("CTS:0xabc"))
 		Expect(txn.Date.Year()).To(Equal(2025))
 		Expect(txn.Date.Month()).To(Equal(time.December))
 		Expect(txn.Date.Day()).To(Equal(1))
@@ -77,6 +79,82 @@ var _ = Describe("GetTransactions", func() {
 		)
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("omits deleted transactions", func() {
+		httpmock.RegisterResponder(
+			"GET",
+			"https://api.ynab.com/v1/budgets/budget1/accounts/acct1/transactions",
+			httpmock.NewStringResponder(
+				http.StatusOK,
+				`{"data":{"transactions":[`+
+					`{"id":"tx1","amount":1000,"date":"2025-12-01","deleted":true},`+
+					`{"id":"tx2","amount":2000,"date":"2025-12-02"}`+
+					`]}}`,
+			),
+		)
+
+		txns, err := clientpkg.GetTransactions(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			"acct1",
+			time.Time{},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(txns).To(HaveLen(1))
+		Expect(txns[0].ID).To(Equal("tx2"))
+	})
+
+	Context("transaction dates", func() {
+		getTransactionWithDate := func(date string) (*clientpkg.Transaction, error) {
+			httpmock.RegisterResponder(
+				"GET",
+				"https://api.ynab.com/v1/budgets/budget1/accounts/acct1/transactions",
+				httpmock.NewStringResponder(
+					http.StatusOK,
+					`{"data":{"transactions":[{"id":"tx1","amount":1000,"date":`+date+`}]}}`,
+				),
+			)
+
+			txns, err := clientpkg.GetTransactions(
+				ctx,
+				http.DefaultClient,
+				"tokengoeshere",
+				"budget1",
+				"acct1",
+				time.Time{},
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			Expect(txns).To(HaveLen(1))
+
+			return txns[0], nil
+		}
+
+		DescribeTable("parses each supported date format",
+			func(date string, expected time.Time) {
+				txn, err := getTransactionWithDate(date)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(txn.Date.Equal(expected)).To(BeTrue(), "unexpected date: %v", txn.Date)
+			},
+			Entry("date only", `"2025-12-01"`, time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)),
+			Entry(
+				"RFC 3339 timestamp",
+				`"2025-12-01T15:04:05Z"`,
+				time.Date(2025, 12, 1, 15, 4, 5, 0, time.UTC),
+			),
+			Entry("empty", `""`, time.Time{}),
+			Entry("null", `null`, time.Time{}),
+		)
+
+		It("returns an error for an unrecognized date", func() {
+			_, err := getTransactionWithDate(`"12/01/2025"`)
+			Expect(err).To(MatchError(ContainSubstring("failed to parse transaction date '12/01/2025'")))
+		})
+	})
 })
 
 var _ = Describe("CreateTransaction", func() {
@@ -129,6 +207,7 @@ var _ = Describe("CreateTransaction", func() {
 		cleared := "cleared"
 		approved := true
 		flagColor := "red"
+		importID := "CTS:0x0123456789abcdef0123456789abcd"
 
 		respBody := `{"data":{"transaction":{"id":"tx-full","account_id":"acct1","date":"2025-12-24","amount":-10000,"payee_id":"payee-123","payee_name":"Test Payee","category_id":"cat-456","memo":"Test transaction memo","cleared":"cleared","approved":true,"flag_color":"red"}}}`
 
@@ -139,6 +218,13 @@ var _ = Describe("CreateTransaction", func() {
 				Expect(req.Header.Get("Authorization")).To(Equal("Bearer tokengoeshere"))
 				Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
 
+				var payload struct {
+					Transaction map[string]any `json:"transaction"`
+				}
+				Expect(json.NewDecoder(req.Body).Decode(&payload)).To(Succeed())
+				Expect(payload.Transaction).To(HaveKeyWithValue("import_id", importID))
+				Expect(payload.Transaction).To(HaveKeyWithValue("flag_color", "red"))
+
 				return httpmock.NewStringResponse(http.StatusCreated, respBody), nil
 			},
 		)
@@ -159,6 +245,7 @@ var _ = Describe("CreateTransaction", func() {
 				Cleared:    &cleared,
 				Approved:   &approved,
 				FlagColor:  &flagColor,
+				ImportID:   &importID,
 			},
 		)
 		Expect(err).ToNot(HaveOccurred())
@@ -197,3 +284,72 @@ var _ = Describe("CreateTransaction", func() {
 		Expect(err.Error()).To(ContainSubstring("400"))
 	})
 })
+
+var _ = Describe("DeleteTransaction", func() {
+	const transactionURL = "https://api.ynab.com/v1/budgets/budget1/transactions/tx1"
+
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("deletes the transaction", func() {
+		httpmock.ZeroCallCounters()
+		httpmock.RegisterResponder(
+			"DELETE",
+			transactionURL,
+			func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("Authorization")).To(Equal("Bearer tokengoeshere"))
+
+				return httpmock.NewStringResponse(
+					http.StatusOK,
+					`{"data":{"transaction":{"id":"tx1","deleted":true}}}`,
+				), nil
+			},
+		)
+
+		Expect(clientpkg.DeleteTransaction(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			"tx1",
+		)).To(Succeed())
+		Expect(httpmock.GetCallCountInfo()["DELETE "+transactionURL]).To(Equal(1))
+	})
+
+	It("returns an error on non-200 response", func() {
+		httpmock.RegisterResponder(
+			"DELETE",
+			transactionURL,
+			httpmock.NewStringResponder(http.StatusNotFound, `{"error":{}}`),
+		)
+
+		err := clientpkg.DeleteTransaction(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			"tx1",
+		)
+		Expect(err).To(MatchError(ContainSubstring("status 404 on delete")))
+	})
+
+	It("reports a rejected access token", func() {
+		httpmock.RegisterResponder(
+			"DELETE",
+			transactionURL,
+			httpmock.NewStringResponder(http.StatusUnauthorized, `{"error":{}}`),
+		)
+
+		err := clientpkg.DeleteTransaction(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			"tx1",
+		)
+		Expect(err).To(MatchError(clientpkg.ErrUnauthorized))
+	})
+})
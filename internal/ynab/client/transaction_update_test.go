@@ -59,6 +59,7 @@ var _ = Describe("MarkTransactionClearedAndAppendMemo", func() {
 			"budget1",
 			"tx1",
 			"txhash123",
+			"",
 		)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(sawPut).To(BeTrue())
@@ -95,10 +96,81 @@ var _ = Describe("MarkTransactionClearedAndAppendMemo", func() {
 			"budget1",
 			"tx1",
 			"txhash123",
+			"",
 		)
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("appends the detail alongside the hash when given", func() {
+		getResp := `{"data":{"transaction":{"id":"tx1","memo":"","cleared":"uncleared"}}}`
+
+		httpmock.RegisterResponder(
+			"GET",
+			"https://api.ynab.com/v1/budgets/budget1/transactions/tx1",
+			httpmock.NewStringResponder(http.StatusOK, getResp),
+		)
+
+		var putBody string
+		httpmock.RegisterResponder(
+			"PUT",
+			"https://api.ynab.com/v1/budgets/budget1/transactions/tx1",
+			func(req *http.Request) (*http.Response, error) {
+				body, _ := io.ReadAll(req.Body)
+				putBody = string(body)
+
+				return httpmock.NewStringResponse(200, `{"data":{}}`), nil
+			},
+		)
+
+		err := clientpkg.MarkTransactionClearedAndAppendMemo(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			"tx1",
+			"txhash123",
+			"log index 3",
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(putBody).To(ContainSubstring(`"memo":"Transaction hash: txhash123 (log index 3)"`))
+	})
+
+	It("appends the hash with detail when the memo references the hash with a different detail", func() {
+		getResp := `{"data":{"transaction":{"id":"tx1","memo":"Transaction hash: txhash123 (log index 2)","cleared":"uncleared"}}}`
+
+		httpmock.RegisterResponder(
+			"GET",
+			"https://api.ynab.com/v1/budgets/budget1/transactions/tx1",
+			httpmock.NewStringResponder(http.StatusOK, getResp),
+		)
+
+		var putBody string
+		httpmock.RegisterResponder(
+			"PUT",
+			"https://api.ynab.com/v1/budgets/budget1/transactions/tx1",
+			func(req *http.Request) (*http.Response, error) {
+				body, _ := io.ReadAll(req.Body)
+				putBody = string(body)
+
+				return httpmock.NewStringResponse(200, `{"data":{}}`), nil
+			},
+		)
+
+		err := clientpkg.MarkTransactionClearedAndAppendMemo(
+			ctx,
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			"tx1",
+			"txhash123",
+			"log index 3",
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(putBody).To(ContainSubstring(
+			`"memo":"Transaction hash: txhash123 (log index 2); transaction hash: txhash123 (log index 3)"`,
+		))
+	})
+
 	It("returns an error when GET returns non-200", func() {
 		httpmock.RegisterResponder(
 			"GET",
@@ -113,7 +185,46 @@ var _ = Describe("MarkTransactionClearedAndAppendMemo", func() {
 			"budget1",
 			"tx1",
 			"txhash123",
+			"",
 		)
 		Expect(err).To(HaveOccurred())
 	})
 })
+
+var _ = Describe("MarkTransactionClearedAndAppendHashes", func() {
+	It("appends each missing hash and marks cleared", func() {
+		getResp := `{"data":{"transaction":{"id":"tx1","memo":"daily total txhash1","cleared":"uncleared"}}}`
+
+		httpmock.RegisterResponder(
+			"GET",
+			"https://api.ynab.com/v1/budgets/budget1/transactions/tx1",
+			httpmock.NewStringResponder(http.StatusOK, getResp),
+		)
+
+		var putBody string
+		httpmock.RegisterResponder(
+			"PUT",
+			"https://api.ynab.com/v1/budgets/budget1/transactions/tx1",
+			func(req *http.Request) (*http.Response, error) {
+				body, _ := io.ReadAll(req.Body)
+				putBody = string(body)
+
+				return httpmock.NewStringResponse(200, `{"data":{}}`), nil
+			},
+		)
+
+		err := clientpkg.MarkTransactionClearedAndAppendHashes(
+			context.Background(),
+			http.DefaultClient,
+			"tokengoeshere",
+			"budget1",
+			"tx1",
+			[]string{"txhash1", "txhash2", "txhash3"},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(putBody).To(ContainSubstring(
+			`"memo":"daily total txhash1; transaction hash: txhash2; transaction hash: txhash3"`,
+		))
+		Expect(putBody).To(ContainSubstring(`"cleared":"cleared"`))
+	})
+})
@@ -0,0 +1,52 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/prompt"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+)
+
+// ConfirmClearing asks the user whether the given YNAB transaction, matched to the transfer
+// described by matchedTransfer, is to be cleared.
+// The direction of the transaction is described according to the given account type.
+// Clearing it is the first choice offered, and is assumed if the prompt times out.
+func ConfirmClearing(
+	ctx context.Context,
+	prompter prompt.Prompter,
+	txn *client.Transaction,
+	accountType transaction.AccountType,
+	matchedTransfer string,
+) (bool, error) {
+	selIdx, err := prompter.Select(
+		fmt.Sprintf(
+			"Clear the YNAB transaction of %s %s %s with memo '%s' on %s, matched to %s?",
+			txn.GetFormattedAmount(),
+			transaction.ResolveDirection(accountType.IsOutbound(txn.Amount)),
+			txn.Payee,
+			txn.Description,
+			txn.Date.Format(time.DateOnly),
+			matchedTransfer,
+		),
+		[]string{"Yes, clear it", "No, leave it uncleared"},
+	)
+	if err != nil {
+		if errors.Is(err, prompt.ErrTimeout) {
+			slog.InfoContext(
+				ctx,
+				fmt.Sprintf("Clearing prompt timed out; clearing transaction ID %s", txn.ID),
+			)
+
+			return true, nil
+		}
+
+		return false, fmt.Errorf("clearing confirmation prompt failed: %w", err)
+	}
+
+	return selIdx == 0, nil
+}
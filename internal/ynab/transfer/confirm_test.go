@@ -0,0 +1,122 @@
+package transfer_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/prompt"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/transfer"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConfirmClearing", func() {
+	const asset = transaction.AccountTypeAsset
+
+	var ctx context.Context
+	var txn *client.Transaction
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		txn = &client.Transaction{
+			ID:          "tx1",
+			Payee:       "Coffee Shop",
+			Amount:      -4500,
+			Date:        time.Date(2025, time.December, 10, 0, 0, 0, 0, time.UTC),
+			Description: "Latte",
+		}
+	})
+
+	It("shows both sides of the match and clears on the first choice", func() {
+		prompter := &selectPrompter{answer: 0}
+
+		confirmed, err := transfer.ConfirmClearing(ctx, prompter, txn, asset, "4.5 USDC (0xhash1)")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(confirmed).To(BeTrue())
+
+		Expect(prompter.label).To(ContainSubstring("to Coffee Shop"))
+		Expect(prompter.label).To(ContainSubstring("'Latte'"))
+		Expect(prompter.label).To(ContainSubstring("2025-12-10"))
+		Expect(prompter.label).To(ContainSubstring("4.5 USDC (0xhash1)"))
+		Expect(prompter.items).To(HaveLen(2))
+	})
+
+	It("describes the direction of the transaction according to the account type", func() {
+		prompter := &selectPrompter{answer: 0}
+
+		_, err := transfer.ConfirmClearing(
+			ctx,
+			prompter,
+			txn,
+			transaction.AccountTypeLiability,
+			"4.5 USDC",
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(prompter.label).To(ContainSubstring("from Coffee Shop"))
+	})
+
+	It("leaves the transaction uncleared on the second choice", func() {
+		confirmed, err := transfer.ConfirmClearing(
+			ctx,
+			&selectPrompter{answer: 1},
+			txn,
+			asset,
+			"4.5 USDC",
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(confirmed).To(BeFalse())
+	})
+
+	It("clears the transaction if the prompt times out", func() {
+		confirmed, err := transfer.ConfirmClearing(
+			ctx,
+			&selectPrompter{err: prompt.ErrTimeout},
+			txn,
+			asset,
+			"4.5 USDC",
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(confirmed).To(BeTrue())
+	})
+
+	It("returns an error if the prompt is canceled", func() {
+		confirmed, err := transfer.ConfirmClearing(
+			ctx,
+			&selectPrompter{err: prompt.ErrInterrupt},
+			txn,
+			asset,
+			"4.5 USDC",
+		)
+		Expect(err).To(MatchError(prompt.ErrInterrupt))
+		Expect(confirmed).To(BeFalse())
+	})
+})
+
+// selectPrompter is a prompt.Prompter that answers a single select prompt.
+type selectPrompter struct {
+	answer int
+	err    error
+	label  string
+	items  []string
+}
+
+func (s *selectPrompter) Select(label string, items []string) (int, error) {
+	s.label = label
+	s.items = items
+
+	return s.answer, s.err
+}
+
+func (*selectPrompter) Input(string, string) (string, error) {
+	Fail("unexpected input prompt")
+
+	return "", nil
+}
+
+func (*selectPrompter) MultiSelect(string, []string) ([]int, error) {
+	Fail("unexpected multi-select prompt")
+
+	return nil, nil
+}
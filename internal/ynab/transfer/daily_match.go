@@ -0,0 +1,51 @@
+package transfer
+
+import (
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+)
+
+// MatchDailyTotals attempts to find daily totals that correspond to the given YNAB transaction.
+// A daily total matches if it falls on (or within a day of) the transaction's date and its net amount
+// equals the transaction's amount, with inflows to the wallet matching positive transaction amounts
+// unless the account type set with WithAccountType is a liability.
+// Of the given options, only the account type is applied.
+func MatchDailyTotals(
+	ynabTransaction *client.Transaction,
+	tokenDetails *token.Details,
+	totals []*transaction.DailyTotal,
+	opts ...MatchOption,
+) []*transaction.DailyTotal {
+	if tokenDetails == nil {
+		return nil
+	}
+
+	options := &matchOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	expected := milliunitsToBaseUnits(ynabTransaction.Amount, tokenDetails.Decimals)
+	if options.accountType == transaction.AccountTypeLiability {
+		expected.Neg(expected)
+	}
+
+	var matches []*transaction.DailyTotal
+
+	for _, total := range totals {
+		if !sameDate(total.Date, ynabTransaction.Date) {
+			continue
+		}
+
+		if total.NetAmount == nil {
+			continue
+		}
+
+		if expected.Cmp(total.NetAmount) == 0 {
+			matches = append(matches, total)
+		}
+	}
+
+	return matches
+}
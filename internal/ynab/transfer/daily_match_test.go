@@ -0,0 +1,61 @@
+package transfer_test
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	ttx "github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	clientpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/transfer"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MatchDailyTotals", func() {
+	date := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	tokenDetails := &token.Details{Decimals: 6}
+
+	It("matches a net inflow to a positive transaction", func() {
+		total := &ttx.DailyTotal{Date: date, NetAmount: big.NewInt(3500000)}
+		ynabTxn := &clientpkg.Transaction{ID: "test-txn", Amount: 3500, Date: date}
+
+		matches := transfer.MatchDailyTotals(ynabTxn, tokenDetails, []*ttx.DailyTotal{total})
+		Expect(matches).To(Equal([]*ttx.DailyTotal{total}))
+	})
+
+	It("matches a net outflow to a negative transaction", func() {
+		total := &ttx.DailyTotal{Date: date, NetAmount: big.NewInt(-2000000)}
+		ynabTxn := &clientpkg.Transaction{ID: "test-txn", Amount: -2000, Date: date}
+
+		matches := transfer.MatchDailyTotals(ynabTxn, tokenDetails, []*ttx.DailyTotal{total})
+		Expect(matches).To(Equal([]*ttx.DailyTotal{total}))
+	})
+
+	It("does not match a total flowing the other direction", func() {
+		total := &ttx.DailyTotal{Date: date, NetAmount: big.NewInt(2000000)}
+		ynabTxn := &clientpkg.Transaction{ID: "test-txn", Amount: -2000, Date: date}
+
+		Expect(transfer.MatchDailyTotals(ynabTxn, tokenDetails, []*ttx.DailyTotal{total})).To(BeEmpty())
+	})
+
+	It("does not match a total more than a day away", func() {
+		total := &ttx.DailyTotal{Date: date, NetAmount: big.NewInt(2000000)}
+		ynabTxn := &clientpkg.Transaction{ID: "test-txn", Amount: 2000, Date: date.AddDate(0, 0, 2)}
+
+		Expect(transfer.MatchDailyTotals(ynabTxn, tokenDetails, []*ttx.DailyTotal{total})).To(BeEmpty())
+	})
+
+	It("matches a net outflow to a positive transaction of a liability account", func() {
+		total := &ttx.DailyTotal{Date: date, NetAmount: big.NewInt(-2000000)}
+		ynabTxn := &clientpkg.Transaction{ID: "test-txn", Amount: 2000, Date: date}
+
+		matches := transfer.MatchDailyTotals(
+			ynabTxn,
+			tokenDetails,
+			[]*ttx.DailyTotal{total},
+			transfer.WithAccountType(ttx.AccountTypeLiability),
+		)
+		Expect(matches).To(Equal([]*ttx.DailyTotal{total}))
+	})
+})
@@ -2,7 +2,6 @@ package transfer
 
 import (
 	"math/big"
-	"strings"
 	"time"
 
 	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
@@ -10,18 +9,90 @@ import (
 	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
 )
 
+// MatchOption configures how transfers are matched to YNAB transactions.
+type MatchOption func(*matchOptions)
+
+type matchOptions struct {
+	amountTolerance int64       // the largest difference, in milliunits, between matching amounts
+	prices          PriceSource // the prices at which transfers are valued; nil to value tokens 1:1
+	// timeTolerance is the longest time between a transfer and the date of a matching
+	// transaction; if zero, their dates must be within a day of each other
+	timeTolerance time.Duration
+	accountType   transaction.AccountType // how the signs of amounts relate to directions
+}
+
+// WithAmountTolerance allows a transfer to match a YNAB transaction whose amount differs
+// from the transfer's by no more than the given number of YNAB milliunits, e.g., to absorb
+// rounding differences.
+// By default, amounts must match exactly.
+func WithAmountTolerance(milliunits int64) MatchOption {
+	return func(opts *matchOptions) {
+		opts.amountTolerance = milliunits
+	}
+}
+
+// WithTimeTolerance allows a transfer to match a YNAB transaction only if it was executed within
+// the given duration, before or after, of the transaction's date.
+// As YNAB transactions have no time of day, the duration is measured from midnight UTC at the
+// start of the transaction's date: with a tolerance of 12 hours, a transaction dated December 10
+// matches transfers executed from 12:00 on December 9 to 12:00 on December 10.
+// By default, the dates of a transfer and a transaction must be within a day of each other.
+func WithTimeTolerance(tolerance time.Duration) MatchOption {
+	return func(opts *matchOptions) {
+		opts.timeTolerance = tolerance
+	}
+}
+
+// WithPrices compares the amount of a YNAB transaction against the value of each transfer
+// at the price given by the given source on the transfer's date, rounded to the nearest milliunit,
+// rather than against the transfer's token quantity.
+// A transfer on a date for which the source has no price matches nothing.
+// By default, a whole token is worth one unit of the budget's currency.
+func WithPrices(prices PriceSource) MatchOption {
+	return func(opts *matchOptions) {
+		opts.prices = prices
+	}
+}
+
+// WithAccountType interprets the sign of the amount of a YNAB transaction according to the given
+// type of its account: in a liability account, such as a credit card, a positive amount is matched
+// to transfers from the wallets and a negative one to transfers to them.
+// By default, the account is an asset account, in which negative amounts are outbound.
+func WithAccountType(accountType transaction.AccountType) MatchOption {
+	return func(opts *matchOptions) {
+		opts.accountType = accountType
+	}
+}
+
 // MatchTransfers attempts to find transfers that correspond to the given YNAB transaction.
+// If the transaction has an import ID that was generated for one of the transfers, only that transfer is matched;
+// otherwise, transfers are matched by date, wallet address, and amount.
+// A transaction with a zero amount is matched by import ID alone, as whether it is inbound or
+// outbound cannot be told from its amount.
+// Every transfer whose amount is within the tolerance set with WithAmountTolerance is returned.
 func MatchTransfers(
 	ynabTransaction *client.Transaction,
-	address string,
+	wallets *transaction.Wallets,
 	tokenDetails *token.Details,
 	transfers []*transaction.Transfer,
+	opts ...MatchOption,
 ) []*transaction.Transfer {
 	if tokenDetails == nil {
 		return nil
 	}
 
-	addr := strings.ToLower(address)
+	options := &matchOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if importMatch := matchTransferByImportID(ynabTransaction, transfers); importMatch != nil {
+		return []*transaction.Transfer{importMatch}
+	}
+
+	if ynabTransaction.Amount == 0 {
+		return nil
+	}
 
 	// ynabTransaction.Amount is in tenths of cents (1000 == $1)
 	absAmt := ynabTransaction.Amount
@@ -29,29 +100,24 @@ func MatchTransfers(
 		absAmt = -absAmt
 	}
 
-	// scale = 10^decimals
-	//nolint:mnd
-	scale := new(
-		big.Int,
-	).Exp(big.NewInt(10), big.NewInt(int64(tokenDetails.Decimals)), nil)
-	tmp := new(big.Int).Mul(big.NewInt(absAmt), scale)
-	expected := new(big.Int).Div(tmp, big.NewInt(1000)) //nolint:mnd
+	expected := milliunitsToBaseUnits(absAmt, tokenDetails.Decimals)
+	tolerance := milliunitsToBaseUnits(options.amountTolerance, tokenDetails.Decimals)
 
 	var matches []*transaction.Transfer
 
 	for _, tr := range transfers {
-		if !sameDate(tr.ExecutionTime, ynabTransaction.Date) {
+		if !options.withinTimeTolerance(tr.ExecutionTime, ynabTransaction.Date) {
 			continue
 		}
 
-		if ynabTransaction.Amount < 0 {
+		if options.accountType.IsOutbound(ynabTransaction.Amount) {
 			// outbound: match from address
-			if strings.ToLower(tr.FromAddress) != addr {
+			if !wallets.Contains(tr.FromAddress) {
 				continue
 			}
 		} else {
 			// inbound: match to address
-			if strings.ToLower(tr.ToAddress) != addr {
+			if !wallets.Contains(tr.ToAddress) {
 				continue
 			}
 		}
@@ -60,7 +126,23 @@ func MatchTransfers(
 			continue
 		}
 
-		if expected.Cmp(tr.Amount) == 0 {
+		if options.prices != nil {
+			price, ok := options.prices.PriceOn(tr.ExecutionTime)
+			if !ok {
+				continue
+			}
+
+			value := valueInMilliunits(tr.Amount, tokenDetails.Decimals, price)
+			difference := new(big.Int).Sub(big.NewInt(absAmt), value)
+			if difference.Abs(difference).Cmp(big.NewInt(options.amountTolerance)) <= 0 {
+				matches = append(matches, tr)
+			}
+
+			continue
+		}
+
+		difference := new(big.Int).Sub(expected, tr.Amount)
+		if difference.Abs(difference).Cmp(tolerance) <= 0 {
 			matches = append(matches, tr)
 		}
 	}
@@ -68,6 +150,74 @@ func MatchTransfers(
 	return matches
 }
 
+// matchTransferByImportID finds the transfer whose import ID equals that of the given YNAB transaction.
+// As an import ID includes the log index or position of its transfer, a transaction created for
+// one of several transfers sharing a hash matches only that transfer.
+// It returns nil if the transaction has no import ID or no transfer has a matching import ID.
+func matchTransferByImportID(
+	ynabTransaction *client.Transaction,
+	transfers []*transaction.Transfer,
+) *transaction.Transfer {
+	if ynabTransaction.ImportID == "" {
+		return nil
+	}
+
+	for _, tr := range transfers {
+		if transaction.ImportIDFor(tr) == ynabTransaction.ImportID {
+			return tr
+		}
+	}
+
+	return nil
+}
+
+// milliunitsToBaseUnits converts the given amount of YNAB milliunits to the base unit of a token
+// with the given number of decimals, preserving its sign.
+func milliunitsToBaseUnits(milliunits int64, decimals int) *big.Int {
+	// scale = 10^decimals
+	//nolint:mnd
+	scale := new(
+		big.Int,
+	).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	tmp := new(big.Int).Mul(big.NewInt(milliunits), scale)
+
+	return new(big.Int).Quo(tmp, big.NewInt(1000)) //nolint:mnd
+}
+
+// valueInMilliunits returns the value, in YNAB milliunits rounded half away from zero,
+// of the given amount in the base unit of a token with the given number of decimals
+// at the given price of one whole token.
+func valueInMilliunits(amount *big.Int, decimals int, price *big.Rat) *big.Int {
+	//nolint:mnd
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	value := new(big.Rat).SetFrac(amount, scale)
+	value.Mul(value, price)
+	value.Mul(value, big.NewRat(1000, 1)) //nolint:mnd
+
+	// add half of the denominator before truncating to round half away from zero
+	num := new(big.Int).Abs(value.Num())
+	denom := value.Denom()
+	num.Add(num, new(big.Int).Quo(denom, big.NewInt(2))) //nolint:mnd
+	rounded := num.Quo(num, denom)
+	if value.Sign() < 0 {
+		rounded.Neg(rounded)
+	}
+
+	return rounded
+}
+
+// withinTimeTolerance determines whether a transfer executed at the given time may match
+// a YNAB transaction on the given date.
+func (o *matchOptions) withinTimeTolerance(executionTime time.Time, date time.Time) bool {
+	if o.timeTolerance <= 0 {
+		return sameDate(executionTime, date)
+	}
+
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	return executionTime.Sub(midnight).Abs() <= o.timeTolerance
+}
+
 func sameDate(a, b time.Time) bool {
 	// To handle timezone differences between YNAB and Etherscan,
 	// we allow matching if dates are within ±1 day of each other.
@@ -33,7 +33,12 @@ var _ = Describe("MatchTransfers", func() {
 
 			tokenDetails := &token.Details{Decimals: 6}
 
-			matches := transfer.MatchTransfers(ynabTxn, "0xABC", tokenDetails, []*ttx.Transfer{tr})
+			matches := transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xABC"),
+				tokenDetails,
+				[]*ttx.Transfer{tr},
+			)
 			Expect(matches).To(And(
 				HaveLen(1),
 				ContainElement(tr),
@@ -61,7 +66,7 @@ var _ = Describe("MatchTransfers", func() {
 
 				matches := transfer.MatchTransfers(
 					ynabTxn,
-					"0xabc",
+					ttx.NewWallets("0xabc"),
 					tokenDetails,
 					[]*ttx.Transfer{tr},
 				)
@@ -72,6 +77,29 @@ var _ = Describe("MatchTransfers", func() {
 			})
 		})
 
+		When("there are several wallets", func() {
+			It("matches a transfer received by any of them", func() {
+				date := time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC)
+				ynabTxn := &clientpkg.Transaction{ID: "test-txn", Amount: 2000, Date: date}
+
+				tr := &ttx.Transfer{
+					FromAddress:     "0xother",
+					ToAddress:       "0xDEF",
+					Amount:          big.NewInt(2000000),
+					ExecutionTime:   date,
+					TransactionHash: "0xhash2",
+				}
+
+				matches := transfer.MatchTransfers(
+					ynabTxn,
+					ttx.NewWallets("0xabc", "0xdef"),
+					&token.Details{Decimals: 6},
+					[]*ttx.Transfer{tr},
+				)
+				Expect(matches).To(ConsistOf(tr))
+			})
+		})
+
 		When("multiple transfers match", func() {
 			It("returns all matching transfers", func() {
 				date := time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC)
@@ -109,7 +137,7 @@ var _ = Describe("MatchTransfers", func() {
 
 				matches := transfer.MatchTransfers(
 					ynabTxn,
-					"0xabc",
+					ttx.NewWallets("0xabc"),
 					tokenDetails,
 					[]*ttx.Transfer{match0, nonMatch, match1},
 				)
@@ -122,6 +150,40 @@ var _ = Describe("MatchTransfers", func() {
 		})
 	})
 
+	When("the transaction's amount is zero", func() {
+		It("matches neither inbound nor outbound transfers", func() {
+			date := time.Date(2025, 12, 3, 0, 0, 0, 0, time.UTC)
+			ynabTxn := &clientpkg.Transaction{
+				ID:     "test-txn",
+				Amount: 0,
+				Date:   date,
+			}
+
+			inbound := &ttx.Transfer{
+				FromAddress:     "0xother",
+				ToAddress:       "0xabc",
+				Amount:          big.NewInt(0),
+				ExecutionTime:   date,
+				TransactionHash: "0xinbound",
+			}
+			outbound := &ttx.Transfer{
+				FromAddress:     "0xabc",
+				ToAddress:       "0xother",
+				Amount:          big.NewInt(1),
+				ExecutionTime:   date,
+				TransactionHash: "0xoutbound",
+			}
+
+			Expect(transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				&token.Details{Decimals: 6},
+				[]*ttx.Transfer{inbound, outbound},
+				transfer.WithAmountTolerance(10),
+			)).To(BeEmpty())
+		})
+	})
+
 	When("there is no matching transfer", func() {
 		It("returns an empty slice", func() {
 			date := time.Date(2025, 12, 3, 0, 0, 0, 0, time.UTC)
@@ -140,9 +202,10 @@ var _ = Describe("MatchTransfers", func() {
 			}
 
 			tokenDetails := &token.Details{Decimals: 6}
+			wallets := ttx.NewWallets("0xabc")
 
 			Expect(
-				transfer.MatchTransfers(ynabTxn, "0xabc", tokenDetails, []*ttx.Transfer{tr}),
+				transfer.MatchTransfers(ynabTxn, wallets, tokenDetails, []*ttx.Transfer{tr}),
 			).To(BeEmpty())
 		})
 	})
@@ -163,8 +226,9 @@ var _ = Describe("MatchTransfers", func() {
 				}
 
 				tokenDetails := &token.Details{Decimals: 6}
+				wallets := ttx.NewWallets("0xabc")
 				Expect(
-					transfer.MatchTransfers(ynabTxn, "0xabc", tokenDetails, []*ttx.Transfer{tr}),
+					transfer.MatchTransfers(ynabTxn, wallets, tokenDetails, []*ttx.Transfer{tr}),
 				).To(BeEmpty())
 			})
 		})
@@ -203,7 +267,7 @@ var _ = Describe("MatchTransfers", func() {
 					tokenDetails := &token.Details{Decimals: 6}
 					matches := transfer.MatchTransfers(
 						ynabTxn,
-						"0xabc",
+						ttx.NewWallets("0xabc"),
 						tokenDetails,
 						[]*ttx.Transfer{tr},
 					)
@@ -247,7 +311,7 @@ var _ = Describe("MatchTransfers", func() {
 					tokenDetails := &token.Details{Decimals: 6}
 					matches := transfer.MatchTransfers(
 						ynabTxn,
-						"0xabc",
+						ttx.NewWallets("0xabc"),
 						tokenDetails,
 						[]*ttx.Transfer{tr},
 					)
@@ -281,7 +345,7 @@ var _ = Describe("MatchTransfers", func() {
 				tokenDetails := &token.Details{Decimals: 6}
 				matches := transfer.MatchTransfers(
 					ynabTxn,
-					"0xabc",
+					ttx.NewWallets("0xabc"),
 					tokenDetails,
 					[]*ttx.Transfer{tr},
 				)
@@ -289,4 +353,361 @@ var _ = Describe("MatchTransfers", func() {
 			})
 		})
 	})
+
+	Context("import ID matching", func() {
+		var date time.Time
+		var tokenDetails *token.Details
+		var amountMatch *ttx.Transfer
+		var importMatch *ttx.Transfer
+
+		BeforeEach(func() {
+			date = time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+			tokenDetails = &token.Details{Decimals: 6}
+
+			amountMatch = &ttx.Transfer{
+				FromAddress:     "0xabc",
+				ToAddress:       "0xother",
+				Amount:          big.NewInt(1000000),
+				ExecutionTime:   date,
+				TransactionHash: "0xamount",
+			}
+
+			// differs in both date and amount from the YNAB transaction
+			importMatch = &ttx.Transfer{
+				FromAddress:     "0xabc",
+				ToAddress:       "0xother",
+				Amount:          big.NewInt(2500000),
+				ExecutionTime:   date.AddDate(0, 0, -5),
+				TransactionHash: "0ximported",
+			}
+		})
+
+		It("prefers the transfer whose import ID matches over amount and date matches", func() {
+			ynabTxn := &clientpkg.Transaction{
+				ID:       "test-txn",
+				Amount:   -1000,
+				Date:     date,
+				ImportID: ttx.ImportIDFor(importMatch),
+			}
+
+			matches := transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				tokenDetails,
+				[]*ttx.Transfer{amountMatch, importMatch},
+			)
+			Expect(matches).To(Equal([]*ttx.Transfer{importMatch}))
+		})
+
+		It("matches the transfer created for the transaction among those sharing its hash", func() {
+			logIndex1 := 1
+			logIndex2 := 2
+			paycheck := &ttx.Transfer{
+				FromAddress:     "0xabc",
+				ToAddress:       "0xother",
+				Amount:          big.NewInt(1000000),
+				ExecutionTime:   date,
+				TransactionHash: "0xshared",
+				LogIndex:        &logIndex1,
+			}
+			bonus := &ttx.Transfer{
+				FromAddress:     "0xabc",
+				ToAddress:       "0xother",
+				Amount:          big.NewInt(2500000),
+				ExecutionTime:   date,
+				TransactionHash: "0xshared",
+				LogIndex:        &logIndex2,
+			}
+
+			ynabTxn := &clientpkg.Transaction{
+				ID:       "test-txn",
+				Amount:   -2500,
+				Date:     date,
+				ImportID: "CTS:0xshared:2",
+			}
+
+			matches := transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				tokenDetails,
+				[]*ttx.Transfer{paycheck, bonus},
+			)
+			Expect(matches).To(Equal([]*ttx.Transfer{bonus}))
+
+			// without log indexes, the transfers are told apart by their positions
+			paycheck.LogIndex = nil
+			bonus.LogIndex = nil
+			ttx.NumberTransfers([]*ttx.Transfer{paycheck, bonus})
+			ynabTxn.ImportID = "CTS:0xshared#1"
+
+			matches = transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				tokenDetails,
+				[]*ttx.Transfer{paycheck, bonus},
+			)
+			Expect(matches).To(Equal([]*ttx.Transfer{bonus}))
+		})
+
+		It("falls back to amount and date matching when no import ID matches", func() {
+			ynabTxn := &clientpkg.Transaction{
+				ID:       "test-txn",
+				Amount:   -1000,
+				Date:     date,
+				ImportID: "YNAB:-1000:2025-12-01:1",
+			}
+
+			matches := transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				tokenDetails,
+				[]*ttx.Transfer{amountMatch, importMatch},
+			)
+			Expect(matches).To(Equal([]*ttx.Transfer{amountMatch}))
+		})
+	})
+
+	When("an amount tolerance is given", func() {
+		var (
+			date         time.Time
+			tokenDetails *token.Details
+			ynabTxn      *clientpkg.Transaction
+		)
+
+		outbound := func(hash string, amount int64) *ttx.Transfer {
+			return &ttx.Transfer{
+				FromAddress:     "0xabc",
+				ToAddress:       "0xother",
+				Amount:          big.NewInt(amount),
+				ExecutionTime:   date,
+				TransactionHash: hash,
+			}
+		}
+
+		BeforeEach(func() {
+			date = time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+			tokenDetails = &token.Details{Decimals: 6}
+			// $10.00
+			ynabTxn = &clientpkg.Transaction{ID: "test-txn", Amount: -10000, Date: date}
+		})
+
+		It("does not match a differing amount without a tolerance", func() {
+			oneCentShort := outbound("0xshort", 9990000)
+
+			matches := transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				tokenDetails,
+				[]*ttx.Transfer{oneCentShort},
+			)
+			Expect(matches).To(BeEmpty())
+		})
+
+		It("matches amounts within the tolerance in either direction", func() {
+			oneCentShort := outbound("0xshort", 9990000)
+			oneCentOver := outbound("0xover", 10010000)
+			twoCentsOver := outbound("0xfar", 10020000)
+
+			matches := transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				tokenDetails,
+				[]*ttx.Transfer{oneCentShort, oneCentOver, twoCentsOver},
+				transfer.WithAmountTolerance(10),
+			)
+			Expect(matches).To(Equal([]*ttx.Transfer{oneCentShort, oneCentOver}))
+		})
+	})
+
+	When("a time tolerance is given", func() {
+		var (
+			date    time.Time
+			ynabTxn *clientpkg.Transaction
+		)
+
+		executedAt := func(hash string, executionTime time.Time) *ttx.Transfer {
+			return &ttx.Transfer{
+				FromAddress:     "0xabc",
+				ToAddress:       "0xother",
+				Amount:          big.NewInt(10000000),
+				ExecutionTime:   executionTime,
+				TransactionHash: hash,
+			}
+		}
+
+		BeforeEach(func() {
+			date = time.Date(2025, 12, 10, 0, 0, 0, 0, time.UTC)
+			ynabTxn = &clientpkg.Transaction{ID: "test-txn", Amount: -10000, Date: date}
+		})
+
+		It("matches transfers within the tolerance of midnight at the start of the date", func() {
+			atLatest := executedAt("0xlatest", date.Add(6*time.Hour))
+			atEarliest := executedAt("0xearliest", date.Add(-6*time.Hour))
+			tooLate := executedAt("0xlate", date.Add(6*time.Hour+time.Second))
+			tooEarly := executedAt("0xearly", date.Add(-6*time.Hour-time.Second))
+
+			matches := transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				&token.Details{Decimals: 6},
+				[]*ttx.Transfer{atLatest, atEarliest, tooLate, tooEarly},
+				transfer.WithTimeTolerance(6*time.Hour),
+			)
+			Expect(matches).To(Equal([]*ttx.Transfer{atLatest, atEarliest}))
+		})
+
+		It("does not match a transfer later on the same date beyond the tolerance", func() {
+			lateInTheDay := executedAt("0xevening", date.Add(20*time.Hour))
+
+			transfers := []*ttx.Transfer{lateInTheDay}
+			Expect(transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				&token.Details{Decimals: 6},
+				transfers,
+			)).To(HaveLen(1))
+			Expect(transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				&token.Details{Decimals: 6},
+				transfers,
+				transfer.WithTimeTolerance(12*time.Hour),
+			)).To(BeEmpty())
+		})
+	})
+
+	When("prices are given", func() {
+		var (
+			date         time.Time
+			tokenDetails *token.Details
+			ynabTxn      *clientpkg.Transaction
+		)
+
+		inbound := func(hash string, amount *big.Int) *ttx.Transfer {
+			return &ttx.Transfer{
+				FromAddress:     "0xother",
+				ToAddress:       "0xabc",
+				Amount:          amount,
+				ExecutionTime:   date.Add(15 * time.Hour),
+				TransactionHash: hash,
+			}
+		}
+
+		// wei returns the given number of thousandths of a token with 18 decimals
+		wei := func(thousandths int64) *big.Int {
+			return new(big.Int).Mul(big.NewInt(thousandths), big.NewInt(1_000_000_000_000_000))
+		}
+
+		BeforeEach(func() {
+			date = time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+			tokenDetails = &token.Details{Decimals: 18}
+			// $1,500.00
+			ynabTxn = &clientpkg.Transaction{ID: "test-txn", Amount: 1500000, Date: date}
+		})
+
+		It("matches the value of a transfer at a fixed price", func() {
+			halfToken := inbound("0xhalf", wei(500))
+			oneAndAHalfTokens := inbound("0xoneandahalf", wei(1500))
+
+			matches := transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				tokenDetails,
+				[]*ttx.Transfer{halfToken, oneAndAHalfTokens},
+				transfer.WithPrices(transfer.NewFixedPrice(big.NewRat(3000, 1))),
+			)
+			Expect(matches).To(Equal([]*ttx.Transfer{halfToken}))
+		})
+
+		It("matches the value of a transfer at the price on its date", func() {
+			prices, err := transfer.ParseDailyPrices(strings.NewReader(
+				"date,price\n2025-11-30,2000\n2025-12-01,2500.50\n",
+			))
+			Expect(err).ToNot(HaveOccurred())
+
+			// 0.6 tokens at $2,500.50 is $1,500.30
+			ynabTxn.Amount = 1500300
+			matched := inbound("0xmatched", wei(600))
+			unpriced := inbound("0xunpriced", wei(600))
+			unpriced.ExecutionTime = date.AddDate(0, 0, 1)
+
+			matches := transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				tokenDetails,
+				[]*ttx.Transfer{matched, unpriced},
+				transfer.WithPrices(prices),
+			)
+			Expect(matches).To(Equal([]*ttx.Transfer{matched}))
+		})
+
+		It("applies the amount tolerance to the value", func() {
+			// 0.333 tokens at $4,504.51 is $1,500.00183, which rounds to 1,500,002 milliunits
+			third := inbound("0xthird", wei(333))
+			price, err := transfer.ParsePrice("4504.51")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				tokenDetails,
+				[]*ttx.Transfer{third},
+				transfer.WithPrices(transfer.NewFixedPrice(price)),
+			)).To(BeEmpty())
+
+			Expect(transfer.MatchTransfers(
+				ynabTxn,
+				ttx.NewWallets("0xabc"),
+				tokenDetails,
+				[]*ttx.Transfer{third},
+				transfer.WithPrices(transfer.NewFixedPrice(price)),
+				transfer.WithAmountTolerance(2),
+			)).To(Equal([]*ttx.Transfer{third}))
+		})
+	})
+
+	Context("account type", func() {
+		var date time.Time
+		var tokenDetails *token.Details
+		var outbound, inbound *ttx.Transfer
+
+		BeforeEach(func() {
+			date = time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+			tokenDetails = &token.Details{Decimals: 6}
+			outbound = &ttx.Transfer{
+				FromAddress:     "0xabc",
+				ToAddress:       "0xmerchant",
+				Amount:          big.NewInt(1000000),
+				ExecutionTime:   date,
+				TransactionHash: "0xoutbound",
+			}
+			inbound = &ttx.Transfer{
+				FromAddress:     "0xmerchant",
+				ToAddress:       "0xabc",
+				Amount:          big.NewInt(1000000),
+				ExecutionTime:   date,
+				TransactionHash: "0xinbound",
+			}
+		})
+
+		DescribeTable("matches the transfers in the direction of the amount's sign",
+			func(accountType ttx.AccountType, amount int64, expected string) {
+				ynabTxn := &clientpkg.Transaction{ID: "test-txn", Amount: amount, Date: date}
+
+				matches := transfer.MatchTransfers(
+					ynabTxn,
+					ttx.NewWallets("0xabc"),
+					tokenDetails,
+					[]*ttx.Transfer{outbound, inbound},
+					transfer.WithAccountType(accountType),
+				)
+				Expect(matches).To(HaveLen(1))
+				Expect(matches[0].TransactionHash).To(Equal(expected))
+			},
+			Entry("asset outflow", ttx.AccountTypeAsset, int64(-1000), "0xoutbound"),
+			Entry("asset inflow", ttx.AccountTypeAsset, int64(1000), "0xinbound"),
+			Entry("liability charge", ttx.AccountTypeLiability, int64(1000), "0xoutbound"),
+			Entry("liability payment", ttx.AccountTypeLiability, int64(-1000), "0xinbound"),
+		)
+	})
 })
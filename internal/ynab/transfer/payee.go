@@ -0,0 +1,39 @@
+package transfer
+
+import (
+	"strings"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/addressbook"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+)
+
+// FilterByPayee returns the transfers whose counterparty corresponds to the payee of the given
+// YNAB transaction. A counterparty is labeled by its entry in the given address book, or by its
+// address if it has none; the label must equal the payee, ignoring case and surrounding whitespace.
+func FilterByPayee(
+	ynabTransaction *client.Transaction,
+	wallets *transaction.Wallets,
+	addressBook *addressbook.AddressBook,
+	transfers []*transaction.Transfer,
+) []*transaction.Transfer {
+	payee := strings.TrimSpace(ynabTransaction.Payee)
+	if payee == "" {
+		return nil
+	}
+
+	var matches []*transaction.Transfer
+	for _, tr := range transfers {
+		// outbound transfers pay the recipient; inbound transfers are paid by the sender
+		counterparty := tr.FromAddress
+		if wallets.Contains(tr.FromAddress) {
+			counterparty = tr.ToAddress
+		}
+
+		if strings.EqualFold(strings.TrimSpace(addressBook.ResolveLabel(counterparty)), payee) {
+			matches = append(matches, tr)
+		}
+	}
+
+	return matches
+}
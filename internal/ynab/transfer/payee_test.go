@@ -0,0 +1,67 @@
+package transfer_test
+
+import (
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/addressbook"
+	ttx "github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	clientpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/transfer"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FilterByPayee", func() {
+	const walletAddress = "0xwallet"
+
+	wallets := ttx.NewWallets(walletAddress)
+
+	var addressBook *addressbook.AddressBook
+	var toLandlord, toGrocer, fromEmployer, toUnknown *ttx.Transfer
+	var transfers []*ttx.Transfer
+
+	BeforeEach(func() {
+		addressBook = addressbook.NewAddressBook()
+		addressBook.Add(&addressbook.Entry{Address: "0xlandlord", Label: "Landlord"})
+		addressBook.Add(&addressbook.Entry{Address: "0xgrocer", Label: "Grocer"})
+		addressBook.Add(&addressbook.Entry{Address: "0xemployer", Label: "Employer"})
+
+		toLandlord = &ttx.Transfer{FromAddress: walletAddress, ToAddress: "0xLANDLORD"}
+		toGrocer = &ttx.Transfer{FromAddress: walletAddress, ToAddress: "0xgrocer"}
+		fromEmployer = &ttx.Transfer{FromAddress: "0xemployer", ToAddress: walletAddress}
+		toUnknown = &ttx.Transfer{FromAddress: walletAddress, ToAddress: "0xunknown"}
+
+		transfers = []*ttx.Transfer{toLandlord, toGrocer, fromEmployer, toUnknown}
+	})
+
+	It("keeps only transfers to the payee of an outbound transaction", func() {
+		ynabTxn := &clientpkg.Transaction{Payee: " landlord ", Amount: -1000}
+
+		matches := transfer.FilterByPayee(ynabTxn, wallets, addressBook, transfers)
+		Expect(matches).To(Equal([]*ttx.Transfer{toLandlord}))
+	})
+
+	It("keeps only transfers from the payee of an inbound transaction", func() {
+		ynabTxn := &clientpkg.Transaction{Payee: "Employer", Amount: 1000}
+
+		matches := transfer.FilterByPayee(ynabTxn, wallets, addressBook, transfers)
+		Expect(matches).To(Equal([]*ttx.Transfer{fromEmployer}))
+	})
+
+	It("labels counterparties missing from the address book by their address", func() {
+		ynabTxn := &clientpkg.Transaction{Payee: "0xUnknown", Amount: -1000}
+
+		matches := transfer.FilterByPayee(ynabTxn, wallets, addressBook, transfers)
+		Expect(matches).To(Equal([]*ttx.Transfer{toUnknown}))
+	})
+
+	It("returns no transfers when no counterparty corresponds to the payee", func() {
+		ynabTxn := &clientpkg.Transaction{Payee: "Coffee Shop", Amount: -1000}
+
+		Expect(transfer.FilterByPayee(ynabTxn, wallets, addressBook, transfers)).To(BeEmpty())
+	})
+
+	It("returns no transfers when the transaction has no payee", func() {
+		ynabTxn := &clientpkg.Transaction{Amount: -1000}
+
+		Expect(transfer.FilterByPayee(ynabTxn, wallets, addressBook, transfers)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,99 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// PriceSource provides the value of one whole token in the budget's currency.
+type PriceSource interface {
+	// PriceOn returns the price of one whole token on the given date,
+	// or false if the price on that date is not known.
+	PriceOn(date time.Time) (*big.Rat, bool)
+}
+
+// FixedPrice is a PriceSource whose price is the same on every date.
+type FixedPrice struct {
+	price *big.Rat
+}
+
+var _ PriceSource = (*FixedPrice)(nil)
+
+// NewFixedPrice creates a PriceSource that prices a whole token at the given price on every date.
+func NewFixedPrice(price *big.Rat) *FixedPrice {
+	return &FixedPrice{price: price}
+}
+
+// PriceOn returns the fixed price, whatever the date.
+func (f *FixedPrice) PriceOn(time.Time) (*big.Rat, bool) {
+	return f.price, true
+}
+
+// DailyPrices is a PriceSource with a price for each of a set of UTC dates.
+type DailyPrices struct {
+	prices map[string]*big.Rat
+}
+
+var _ PriceSource = (*DailyPrices)(nil)
+
+// PriceOn returns the price on the UTC date of the given time, if known.
+func (d *DailyPrices) PriceOn(date time.Time) (*big.Rat, bool) {
+	price, ok := d.prices[date.UTC().Format(time.DateOnly)]
+
+	return price, ok
+}
+
+// ParsePrice parses a price of one whole token given as a positive decimal number, e.g., "1234.56".
+func ParsePrice(value string) (*big.Rat, error) {
+	price, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok {
+		return nil, fmt.Errorf("invalid price '%s'", value)
+	}
+
+	if price.Sign() <= 0 {
+		return nil, fmt.Errorf("price must be positive, got '%s'", value)
+	}
+
+	return price, nil
+}
+
+// ParseDailyPrices reads daily prices from CSV rows of a date (e.g., "2025-12-01") and the price of
+// one whole token on that date. A first row whose date cannot be parsed is treated as a header.
+func ParseDailyPrices(reader io.Reader) (*DailyPrices, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = 2
+	csvReader.TrimLeadingSpace = true
+
+	prices := &DailyPrices{prices: make(map[string]*big.Rat)}
+	for row := 1; ; row++ {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read price row %d: %w", row, err)
+		}
+
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(record[0]))
+		if err != nil {
+			if row == 1 {
+				continue
+			}
+
+			return nil, fmt.Errorf("invalid date on price row %d: %w", row, err)
+		}
+
+		price, err := ParsePrice(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid price on row %d: %w", row, err)
+		}
+
+		prices.prices[date.Format(time.DateOnly)] = price
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,64 @@
+package transfer_test
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/transfer"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParsePrice", func() {
+	It("parses a decimal price", func() {
+		price, err := transfer.ParsePrice(" 1234.56 ")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(price.Cmp(big.NewRat(123456, 100))).To(BeZero())
+	})
+
+	DescribeTable("rejects an invalid price", func(value string) {
+		_, err := transfer.ParsePrice(value)
+		Expect(err).To(HaveOccurred())
+	},
+		Entry("not a number", "abc"),
+		Entry("zero", "0"),
+		Entry("negative", "-1.5"),
+	)
+})
+
+var _ = Describe("ParseDailyPrices", func() {
+	It("reads a price for each date, with or without a header", func() {
+		prices, err := transfer.ParseDailyPrices(
+			strings.NewReader("2025-12-01,2500\n2025-12-02, 2600.5\n"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		price, ok := prices.PriceOn(time.Date(2025, 12, 2, 23, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeTrue())
+		Expect(price.Cmp(big.NewRat(26005, 10))).To(BeZero())
+
+		_, ok = prices.PriceOn(time.Date(2025, 12, 3, 0, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("looks up prices by UTC date", func() {
+		prices, err := transfer.ParseDailyPrices(strings.NewReader("date,price\n2025-12-02,2600\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		// 8 PM on December 1 in New York is December 2 in UTC
+		est := time.FixedZone("EST", -5*60*60)
+		_, ok := prices.PriceOn(time.Date(2025, 12, 1, 20, 0, 0, 0, est))
+		Expect(ok).To(BeTrue())
+	})
+
+	It("rejects an invalid date after the first row", func() {
+		_, err := transfer.ParseDailyPrices(strings.NewReader("2025-12-01,2500\n12/02/2025,2600\n"))
+		Expect(err).To(MatchError(ContainSubstring("invalid date on price row 2")))
+	})
+
+	It("rejects an invalid price", func() {
+		_, err := transfer.ParseDailyPrices(strings.NewReader("2025-12-01,free\n"))
+		Expect(err).To(MatchError(ContainSubstring("invalid price on row 1")))
+	})
+})
@@ -0,0 +1,90 @@
+package transfer
+
+import (
+	"slices"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+)
+
+// Reconciliation describes how a set of YNAB transactions corresponds to a set of transfers.
+type Reconciliation struct {
+	Matched               []*ReconciledPair       // YNAB transactions paired with their transfers
+	UnmatchedTransfers    []*transaction.Transfer // transfers with no YNAB transaction
+	UnmatchedTransactions []*client.Transaction   // YNAB transactions with no transfer
+}
+
+// ReconciledPair pairs a YNAB transaction with the transfer it corresponds to.
+type ReconciledPair struct {
+	Transaction *client.Transaction
+	Transfer    *transaction.Transfer
+}
+
+// Reconcile pairs each of the given YNAB transactions with a transfer using the same rules
+// and options as MatchTransfers, without prompting or making any changes; each transfer is paired
+// with at most one transaction.
+// Transactions with a single candidate transfer are paired first, after which each remaining
+// transaction is paired with the earliest-listed of its candidates that has not yet been paired.
+// Matched pairs and unmatched transactions are listed in the order of the given transactions,
+// and unmatched transfers in the order of the given transfers.
+func Reconcile(
+	ynabTransactions []*client.Transaction,
+	wallets *transaction.Wallets,
+	tokenDetails *token.Details,
+	transfers []*transaction.Transfer,
+	opts ...MatchOption,
+) *Reconciliation {
+	paired := make(map[*transaction.Transfer]bool)
+	pairings := make([]*transaction.Transfer, len(ynabTransactions))
+
+	candidates := make([][]*transaction.Transfer, len(ynabTransactions))
+	for i, ynabTransaction := range ynabTransactions {
+		candidates[i] = MatchTransfers(ynabTransaction, wallets, tokenDetails, transfers, opts...)
+	}
+
+	pairTransactions := func(singleCandidate bool) {
+		for i := range ynabTransactions {
+			if pairings[i] != nil || (len(candidates[i]) == 1) != singleCandidate {
+				continue
+			}
+
+			for _, candidate := range candidates[i] {
+				if !paired[candidate] {
+					pairings[i] = candidate
+					paired[candidate] = true
+
+					break
+				}
+			}
+		}
+	}
+	pairTransactions(true)
+	pairTransactions(false)
+
+	reconciliation := &Reconciliation{}
+	for i, ynabTransaction := range ynabTransactions {
+		if pairings[i] == nil {
+			reconciliation.UnmatchedTransactions = append(
+				reconciliation.UnmatchedTransactions,
+				ynabTransaction,
+			)
+
+			continue
+		}
+
+		reconciliation.Matched = append(reconciliation.Matched, &ReconciledPair{
+			Transaction: ynabTransaction,
+			Transfer:    pairings[i],
+		})
+	}
+
+	reconciliation.UnmatchedTransfers = slices.DeleteFunc(
+		slices.Clone(transfers),
+		func(xfr *transaction.Transfer) bool {
+			return paired[xfr]
+		},
+	)
+
+	return reconciliation
+}
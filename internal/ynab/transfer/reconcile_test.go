@@ -0,0 +1,110 @@
+package transfer_test
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	ttx "github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	clientpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/transfer"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reconcile", func() {
+	const wallet = "0xwallet"
+
+	var (
+		tokenDetails *token.Details
+		date         time.Time
+	)
+
+	BeforeEach(func() {
+		tokenDetails = &token.Details{Decimals: 6}
+		date = time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	outbound := func(hash string, amount int64, executionTime time.Time) *ttx.Transfer {
+		return &ttx.Transfer{
+			TransactionHash: hash,
+			FromAddress:     wallet,
+			ToAddress:       "0xother",
+			Amount:          big.NewInt(amount),
+			ExecutionTime:   executionTime,
+		}
+	}
+
+	It("sorts transactions and transfers into matched pairs and unmatched entries", func() {
+		coffee := outbound("0xcoffee", 5000000, date.Add(2*time.Hour))
+		rent := outbound("0xrent", 1200000000, date.Add(3*time.Hour))
+		stray := outbound("0xstray", 7000000, date.Add(10*24*time.Hour))
+
+		coffeeTxn := &clientpkg.Transaction{ID: "coffee", Amount: -5000, Date: date}
+		rentTxn := &clientpkg.Transaction{ID: "rent", Amount: -1200000, Date: date}
+		groceriesTxn := &clientpkg.Transaction{ID: "groceries", Amount: -42000, Date: date}
+
+		reconciliation := transfer.Reconcile(
+			[]*clientpkg.Transaction{coffeeTxn, groceriesTxn, rentTxn},
+			ttx.NewWallets(wallet),
+			tokenDetails,
+			[]*ttx.Transfer{coffee, stray, rent},
+		)
+
+		Expect(reconciliation.Matched).To(HaveLen(2))
+		Expect(reconciliation.Matched[0].Transaction).To(Equal(coffeeTxn))
+		Expect(reconciliation.Matched[0].Transfer).To(Equal(coffee))
+		Expect(reconciliation.Matched[1].Transaction).To(Equal(rentTxn))
+		Expect(reconciliation.Matched[1].Transfer).To(Equal(rent))
+		Expect(reconciliation.UnmatchedTransactions).To(ConsistOf(groceriesTxn))
+		Expect(reconciliation.UnmatchedTransfers).To(ConsistOf(stray))
+	})
+
+	It("pairs each transfer with at most one transaction", func() {
+		first := outbound("0xfirst", 5000000, date.Add(time.Hour))
+
+		firstTxn := &clientpkg.Transaction{ID: "first", Amount: -5000, Date: date}
+		duplicateTxn := &clientpkg.Transaction{ID: "duplicate", Amount: -5000, Date: date}
+
+		reconciliation := transfer.Reconcile(
+			[]*clientpkg.Transaction{firstTxn, duplicateTxn},
+			ttx.NewWallets(wallet),
+			tokenDetails,
+			[]*ttx.Transfer{first},
+		)
+
+		Expect(reconciliation.Matched).To(HaveLen(1))
+		Expect(reconciliation.Matched[0].Transfer).To(Equal(first))
+		Expect(reconciliation.UnmatchedTransactions).To(ConsistOf(duplicateTxn))
+		Expect(reconciliation.UnmatchedTransfers).To(BeEmpty())
+	})
+
+	It("pairs transactions with a single candidate before those with several", func() {
+		early := outbound("0xearly", 5000000, date.Add(time.Hour))
+		late := outbound("0xlate", 5000000, date.Add(25*time.Hour))
+
+		// this transaction is within a day of both transfers...
+		ambiguousTxn := &clientpkg.Transaction{ID: "ambiguous", Amount: -5000, Date: date}
+		// ...while this one is only within a day of the early transfer
+		uniqueTxn := &clientpkg.Transaction{
+			ID:     "unique",
+			Amount: -5000,
+			Date:   date.Add(-24 * time.Hour),
+		}
+
+		reconciliation := transfer.Reconcile(
+			[]*clientpkg.Transaction{ambiguousTxn, uniqueTxn},
+			ttx.NewWallets(wallet),
+			tokenDetails,
+			[]*ttx.Transfer{early, late},
+		)
+
+		Expect(reconciliation.Matched).To(HaveLen(2))
+		Expect(reconciliation.Matched[0].Transaction).To(Equal(ambiguousTxn))
+		Expect(reconciliation.Matched[0].Transfer).To(Equal(late))
+		Expect(reconciliation.Matched[1].Transaction).To(Equal(uniqueTxn))
+		Expect(reconciliation.Matched[1].Transfer).To(Equal(early))
+		Expect(reconciliation.UnmatchedTransactions).To(BeEmpty())
+		Expect(reconciliation.UnmatchedTransfers).To(BeEmpty())
+	})
+})
@@ -0,0 +1,163 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/prompt"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+)
+
+// RefundedTransfer pairs a transfer with a smaller transfer in the opposite direction,
+// between the same addresses, that partially refunded it.
+type RefundedTransfer struct {
+	Transfer *transaction.Transfer // the transfer that was partially refunded
+	Refund   *transaction.Transfer // the transfer that refunded part of it
+}
+
+// NetAmount returns the amount of the transfer less that of its refund, in the token's base unit.
+func (r *RefundedTransfer) NetAmount() *big.Int {
+	return new(big.Int).Sub(r.Transfer.Amount, r.Refund.Amount)
+}
+
+// MatchRefundedTransfers finds the partially-refunded transfers whose net amount corresponds to
+// the given YNAB transaction, for a transaction recorded in YNAB net of a refund.
+// The refunded transfer must be in the direction of the transaction and near its date, as with
+// MatchTransfers; its refund must be a smaller transfer in the opposite direction, with the same
+// counterparty, executed no earlier than the refunded transfer.
+// Of the given options, only the amount and time tolerances and the account type are applied;
+// as token prices are not, nothing is matched when prices are given.
+func MatchRefundedTransfers(
+	ynabTransaction *client.Transaction,
+	wallets *transaction.Wallets,
+	tokenDetails *token.Details,
+	transfers []*transaction.Transfer,
+	opts ...MatchOption,
+) []*RefundedTransfer {
+	if tokenDetails == nil {
+		return nil
+	}
+
+	options := &matchOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.prices != nil {
+		return nil
+	}
+
+	absAmt := ynabTransaction.Amount
+	if absAmt < 0 {
+		absAmt = -absAmt
+	}
+
+	expected := milliunitsToBaseUnits(absAmt, tokenDetails.Decimals)
+	tolerance := milliunitsToBaseUnits(options.amountTolerance, tokenDetails.Decimals)
+	isOutbound := options.accountType.IsOutbound(ynabTransaction.Amount)
+
+	var matches []*RefundedTransfer
+
+	for _, refunded := range transfers {
+		if refunded.Amount == nil ||
+			!options.withinTimeTolerance(refunded.ExecutionTime, ynabTransaction.Date) {
+			continue
+		}
+
+		transferOutbound, _, ok := wallets.Direction(refunded)
+		if !ok || transferOutbound != isOutbound {
+			continue
+		}
+
+		for _, refund := range transfers {
+			if !isRefundOf(refund, refunded) {
+				continue
+			}
+
+			pair := &RefundedTransfer{Transfer: refunded, Refund: refund}
+			difference := new(big.Int).Sub(expected, pair.NetAmount())
+			if difference.Abs(difference).Cmp(tolerance) <= 0 {
+				matches = append(matches, pair)
+			}
+		}
+	}
+
+	return matches
+}
+
+// ChooseRefundedTransfer asks the user which, if any, of the given partially-refunded transfers
+// the given YNAB transaction is to be matched to, by its net amount.
+// Not matching it is the first choice offered, and is assumed if the prompt times out.
+// The direction of the transaction is described according to the given account type.
+func ChooseRefundedTransfer(
+	ctx context.Context,
+	prompter prompt.Prompter,
+	txn *client.Transaction,
+	accountType transaction.AccountType,
+	tokenDetails *token.Details,
+	candidates []*RefundedTransfer,
+) (*RefundedTransfer, error) {
+	items := make([]string, 0, len(candidates)+1)
+	items = append(items, "Skip match")
+
+	for _, candidate := range candidates {
+		items = append(items, fmt.Sprintf(
+			"%s %s on %s (%s) less the refund of %s on %s (%s)",
+			candidate.Transfer.FormatAmount(tokenDetails.Decimals),
+			tokenDetails.Name,
+			candidate.Transfer.ExecutionTime.Format(time.RFC3339),
+			candidate.Transfer.TransactionHash,
+			candidate.Refund.FormatAmount(tokenDetails.Decimals),
+			candidate.Refund.ExecutionTime.Format(time.RFC3339),
+			candidate.Refund.TransactionHash,
+		))
+	}
+
+	selIdx, err := prompter.Select(
+		fmt.Sprintf(
+			"The transaction of %s %s %s with memo '%s' on %s matches the net amount of a "+
+				"partially-refunded transfer; match it?",
+			txn.GetFormattedAmount(),
+			transaction.ResolveDirection(accountType.IsOutbound(txn.Amount)),
+			txn.Payee,
+			txn.Description,
+			txn.Date.Format(time.DateOnly),
+		),
+		items,
+	)
+	if err != nil {
+		if errors.Is(err, prompt.ErrTimeout) {
+			slog.InfoContext(ctx, "Refunded transfer prompt timed out; skipping match")
+
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("refunded transfer prompt failed: %w", err)
+	}
+
+	if selIdx == 0 {
+		return nil, nil
+	}
+
+	return candidates[selIdx-1], nil
+}
+
+// isRefundOf determines whether the given refund could have partially refunded the given transfer:
+// it returns a smaller, positive amount to the transfer's sender from its recipient,
+// no earlier than the transfer.
+func isRefundOf(refund *transaction.Transfer, refunded *transaction.Transfer) bool {
+	return refund != refunded &&
+		refund.Amount != nil &&
+		refund.Amount.Sign() > 0 &&
+		refund.Amount.Cmp(refunded.Amount) < 0 &&
+		strings.EqualFold(refund.FromAddress, refunded.ToAddress) &&
+		strings.EqualFold(refund.ToAddress, refunded.FromAddress) &&
+		!refund.ExecutionTime.Before(refunded.ExecutionTime)
+}
@@ -0,0 +1,189 @@
+package transfer_test
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/prompt"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/token"
+	ttx "github.com/jrh3k5/cryptonabber-txn-sync/internal/transaction"
+	clientpkg "github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/client"
+	"github.com/jrh3k5/cryptonabber-txn-sync/internal/ynab/transfer"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MatchRefundedTransfers", func() {
+	const wallet = "0xwallet"
+
+	var (
+		tokenDetails *token.Details
+		date         time.Time
+		wallets      *ttx.Wallets
+	)
+
+	BeforeEach(func() {
+		tokenDetails = &token.Details{Decimals: 6}
+		date = time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+		wallets = ttx.NewWallets(wallet)
+	})
+
+	newTransfer := func(
+		hash, from, to string,
+		amount int64,
+		executionTime time.Time,
+	) *ttx.Transfer {
+		return &ttx.Transfer{
+			TransactionHash: hash,
+			FromAddress:     from,
+			ToAddress:       to,
+			Amount:          big.NewInt(amount),
+			ExecutionTime:   executionTime,
+		}
+	}
+
+	It("matches a payment less its partial refund to the net amount", func() {
+		payment := newTransfer("0xpayment", wallet, "0xmerchant", 50000000, date.Add(time.Hour))
+		refund := newTransfer("0xrefund", "0xMERCHANT", wallet, 12500000, date.Add(3*time.Hour))
+		unrelated := newTransfer("0xother", "0xelsewhere", wallet, 12500000, date.Add(2*time.Hour))
+
+		ynabTxn := &clientpkg.Transaction{Amount: -37500, Date: date}
+
+		matches := transfer.MatchRefundedTransfers(
+			ynabTxn,
+			wallets,
+			tokenDetails,
+			[]*ttx.Transfer{payment, unrelated, refund},
+		)
+		Expect(matches).To(HaveLen(1))
+		Expect(matches[0].Transfer).To(Equal(payment))
+		Expect(matches[0].Refund).To(Equal(refund))
+		Expect(matches[0].NetAmount()).To(Equal(big.NewInt(37500000)))
+	})
+
+	It("matches an inbound transfer less the part returned to its sender", func() {
+		paycheck := newTransfer("0xpaycheck", "0xemployer", wallet, 2000000000, date)
+		returned := newTransfer("0xreturned", wallet, "0xemployer", 150000000, date.Add(time.Hour))
+
+		ynabTxn := &clientpkg.Transaction{Amount: 1850000, Date: date}
+
+		matches := transfer.MatchRefundedTransfers(
+			ynabTxn,
+			wallets,
+			tokenDetails,
+			[]*ttx.Transfer{paycheck, returned},
+		)
+		Expect(matches).To(HaveLen(1))
+		Expect(matches[0].Transfer).To(Equal(paycheck))
+	})
+
+	It("applies the amount tolerance to the net amount", func() {
+		payment := newTransfer("0xpayment", wallet, "0xmerchant", 50000000, date)
+		refund := newTransfer("0xrefund", "0xmerchant", wallet, 12500000, date)
+
+		ynabTxn := &clientpkg.Transaction{Amount: -37510, Date: date}
+		transfers := []*ttx.Transfer{payment, refund}
+
+		Expect(
+			transfer.MatchRefundedTransfers(ynabTxn, wallets, tokenDetails, transfers),
+		).To(BeEmpty())
+		Expect(transfer.MatchRefundedTransfers(
+			ynabTxn,
+			wallets,
+			tokenDetails,
+			transfers,
+			transfer.WithAmountTolerance(10),
+		)).To(HaveLen(1))
+	})
+
+	It("does not match a refund executed before the payment", func() {
+		payment := newTransfer("0xpayment", wallet, "0xmerchant", 50000000, date.Add(time.Hour))
+		refund := newTransfer("0xrefund", "0xmerchant", wallet, 12500000, date)
+
+		ynabTxn := &clientpkg.Transaction{Amount: -37500, Date: date}
+
+		Expect(transfer.MatchRefundedTransfers(
+			ynabTxn,
+			wallets,
+			tokenDetails,
+			[]*ttx.Transfer{payment, refund},
+		)).To(BeEmpty())
+	})
+
+	It("does not match a refund at least as large as the payment", func() {
+		payment := newTransfer("0xpayment", wallet, "0xmerchant", 50000000, date)
+		refund := newTransfer("0xrefund", "0xmerchant", wallet, 50000000, date)
+
+		ynabTxn := &clientpkg.Transaction{Amount: 0, Date: date}
+
+		Expect(transfer.MatchRefundedTransfers(
+			ynabTxn,
+			wallets,
+			tokenDetails,
+			[]*ttx.Transfer{payment, refund},
+		)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("ChooseRefundedTransfer", func() {
+	var ctx context.Context
+	var txn *clientpkg.Transaction
+	var candidates []*transfer.RefundedTransfer
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		date := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+		txn = &clientpkg.Transaction{Payee: "Merchant", Amount: -37500, Date: date}
+		candidates = []*transfer.RefundedTransfer{
+			{
+				Transfer: &ttx.Transfer{
+					TransactionHash: "0xpayment",
+					Amount:          big.NewInt(50000000),
+					ExecutionTime:   date,
+				},
+				Refund: &ttx.Transfer{
+					TransactionHash: "0xrefund",
+					Amount:          big.NewInt(12500000),
+					ExecutionTime:   date.Add(time.Hour),
+				},
+			},
+		}
+	})
+
+	It("returns the chosen refunded transfer", func() {
+		prompter := &selectPrompter{answer: 1}
+
+		chosen, err := transfer.ChooseRefundedTransfer(
+			ctx,
+			prompter,
+			txn,
+			ttx.AccountTypeAsset,
+			&token.Details{Name: "USDC", Decimals: 6},
+			candidates,
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(chosen).To(Equal(candidates[0]))
+		Expect(prompter.label).To(ContainSubstring("to Merchant"))
+		Expect(prompter.items).To(Equal([]string{
+			"Skip match",
+			"50 USDC on 2025-12-01T00:00:00Z (0xpayment) " +
+				"less the refund of 12.5 on 2025-12-01T01:00:00Z (0xrefund)",
+		}))
+	})
+
+	It("matches nothing when the prompt times out", func() {
+		prompter := &selectPrompter{err: prompt.ErrTimeout}
+
+		chosen, err := transfer.ChooseRefundedTransfer(
+			ctx,
+			prompter,
+			txn,
+			ttx.AccountTypeAsset,
+			&token.Details{Name: "USDC", Decimals: 6},
+			candidates,
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(chosen).To(BeNil())
+	})
+})